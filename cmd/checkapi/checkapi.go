@@ -12,11 +12,20 @@ import (
 	"strings"
 
 	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
+	"github.com/gofed/symbols-extractor/pkg/platforms"
 	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/dep"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/detect"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gitref"
 	"github.com/gofed/symbols-extractor/pkg/snapshots/glide"
 	"github.com/gofed/symbols-extractor/pkg/snapshots/godeps"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/govendor"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/ip2pp"
+	"github.com/gofed/symbols-extractor/pkg/symbols"
 	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 
 	"k8s.io/klog/v2"
 )
@@ -34,6 +43,10 @@ type flags struct {
 	allocated           *string
 	allocatedGlidefile  *string
 	allocatedGodepsfile *string
+	allocatedGomodfile  *string
+	allocatedDepfile    *string
+	allocatedVendorfile *string
+	allocatedRoot       *string
 	packagePrefix       *string
 	packageCommit       *string
 	symbolTablePath     *string
@@ -41,6 +54,14 @@ type flags struct {
 	goVersion           *string
 	glidefile           *string
 	godepsfile          *string
+	gomodfile           *string
+	depfile             *string
+	govendorfile        *string
+	projectRoot         *string
+	ip2ppfile           *string
+	gitMirror           *string
+	resolveRefs         *bool
+	platforms           *string
 }
 
 func (f *flags) parse() error {
@@ -58,6 +79,13 @@ func (f *flags) parse() error {
 		return fmt.Errorf("--package-commit is not set")
 	}
 
+	// a tag, a branch or a short commit
+	if commit, err := gitref.FromGOPATH(*f.gitMirror).Resolve(*f.packagePrefix, *f.packageCommit); err == nil {
+		*(f.packageCommit) = commit
+	} else {
+		klog.Warningf("Using %q as a commit: %v", *f.packageCommit, err)
+	}
+
 	if *(f.symbolTablePath) == "" {
 		return fmt.Errorf("--symbol-table-dir is not set")
 	}
@@ -66,26 +94,69 @@ func (f *flags) parse() error {
 		return fmt.Errorf("--go-version is not set")
 	}
 
-	if *(f.glidefile) == "" && *f.godepsfile == "" {
-		return fmt.Errorf("-glidefile or -godepsfile is not set")
+	if *(f.glidefile) == "" && *f.godepsfile == "" && *f.gomodfile == "" && *f.depfile == "" && *f.govendorfile == "" && *f.projectRoot == "" {
+		return fmt.Errorf("-glidefile, -godepsfile, -gomod, -depfile, -govendorfile or -project-root is not set")
 	}
 
 	return nil
 }
 
+// newGlobalST creates a global symbol table over the snapshot.
+// Packages imported under a different path than their lock entries are resolved as well.
+func (f *flags) newGlobalST(snapshot snapshots.Snapshot) (*global.Table, snapshots.Snapshot, error) {
+	var mapping ip2pp.Mapping
+	if *f.ip2ppfile != "" {
+		m, err := ip2pp.MappingFromFile(*f.ip2ppfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		mapping = m
+	}
+	var sn snapshots.Snapshot = ip2pp.New(snapshot, mapping)
+	if *f.resolveRefs {
+		sn = gitref.NewSnapshot(sn, gitref.FromGOPATH(*f.gitMirror))
+	}
+	return global.New(*(f.symbolTablePath), *(f.goVersion), sn), sn, nil
+}
+
 func initRefGlobaST(f *flags) (*global.Table, snapshots.Snapshot, error) {
 	if *f.allocatedGlidefile != "" {
 		snapshot, err := glide.GlideFromFile(*f.allocatedGlidefile)
 		if err != nil {
 			return nil, nil, err
 		}
-		return global.New(*(f.symbolTablePath), *(f.goVersion), snapshot), snapshot, nil
+		return f.newGlobalST(snapshot)
 	} else if *f.allocatedGodepsfile != "" {
 		snapshot, err := godeps.FromFile(*f.allocatedGodepsfile)
 		if err != nil {
 			return nil, nil, err
 		}
-		return global.New(*(f.symbolTablePath), *(f.goVersion), snapshot), snapshot, nil
+		return f.newGlobalST(snapshot)
+	} else if *f.allocatedGomodfile != "" {
+		snapshot, err := gomod.FromFile(*f.allocatedGomodfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f.newGlobalST(snapshot)
+	} else if *f.allocatedDepfile != "" {
+		snapshot, err := dep.FromFile(*f.allocatedDepfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f.newGlobalST(snapshot)
+	} else if *f.allocatedVendorfile != "" {
+		snapshot, err := govendor.FromFile(*f.allocatedVendorfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f.newGlobalST(snapshot)
+	} else if *f.allocatedRoot != "" {
+		snapshot, file, err := detect.FromProjectRoot(*f.allocatedRoot)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("Reading dependencies of allocated symbol table from %v\n", file)
+		return f.newGlobalST(snapshot)
 	}
 	return global.New(*(f.symbolTablePath), *(f.goVersion), nil), nil, nil
 }
@@ -97,14 +168,47 @@ func initExercisedGlobaST(f *flags) (*global.Table, snapshots.Snapshot, error) {
 			return nil, nil, err
 		}
 		snapshot.MainPackageCommit(*f.packagePrefix, *f.packageCommit)
-		return global.New(*(f.symbolTablePath), *(f.goVersion), snapshot), snapshot, nil
+		return f.newGlobalST(snapshot)
+	}
+	if *f.gomodfile != "" {
+		snapshot, err := gomod.FromFile(*f.gomodfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		snapshot.MainPackageCommit(*f.packagePrefix, *f.packageCommit)
+		return f.newGlobalST(snapshot)
+	}
+	if *f.depfile != "" {
+		snapshot, err := dep.FromFile(*f.depfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		snapshot.MainPackageCommit(*f.packagePrefix, *f.packageCommit)
+		return f.newGlobalST(snapshot)
+	}
+	if *f.govendorfile != "" {
+		snapshot, err := govendor.FromFile(*f.govendorfile)
+		if err != nil {
+			return nil, nil, err
+		}
+		snapshot.MainPackageCommit(*f.packagePrefix, *f.packageCommit)
+		return f.newGlobalST(snapshot)
+	}
+	if *f.godepsfile == "" {
+		snapshot, file, err := detect.FromProjectRoot(*f.projectRoot)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("Reading dependencies from %v\n", file)
+		snapshot.MainPackageCommit(*f.packagePrefix, *f.packageCommit)
+		return f.newGlobalST(snapshot)
 	}
 	snapshot, err := godeps.FromFile(*f.godepsfile)
 	if err != nil {
 		return nil, nil, err
 	}
 	snapshot.MainPackageCommit(*f.packagePrefix, *f.packageCommit)
-	return global.New(*(f.symbolTablePath), *(f.goVersion), snapshot), snapshot, nil
+	return f.newGlobalST(snapshot)
 }
 
 type SymbolInfo struct {
@@ -112,6 +216,14 @@ type SymbolInfo struct {
 	Parent  string
 	Name    string
 	Pos     []string
+	// Platforms the symbol is missing on
+	Platforms []string
+	// Reference and exercised tag of a struct field
+	Tags [2]string
+	// Reference and exercised value of a constant
+	Values [2]string
+	// The exercised data type is an alias
+	Alias bool
 }
 
 func (s *SymbolInfo) str() string {
@@ -121,6 +233,16 @@ func (s *SymbolInfo) str() string {
 	return fmt.Sprintf("%v.%v.%v", s.Package, s.Parent, s.Name)
 }
 
+// testsOnly checks if the symbol is allocated in test files only
+func (s *SymbolInfo) testsOnly() bool {
+	for _, pos := range s.Pos {
+		if !strings.HasSuffix(strings.Split(pos, ":")[0], "_test.go") {
+			return false
+		}
+	}
+	return true
+}
+
 type ApiDiff struct {
 	DatatypesMissing    []SymbolInfo
 	Datatypes           []SymbolInfo
@@ -132,6 +254,35 @@ type ApiDiff struct {
 	Methods             []SymbolInfo
 	StructFieldsMissing []SymbolInfo
 	StructFields        []SymbolInfo
+	// Exported struct fields with changed tags (json/yaml/... encodings change)
+	StructFieldTags []SymbolInfo
+	// Constants with changed values (e.g. protocol codes, enum values)
+	ConstantValues []SymbolInfo
+	// Defined types turned into aliases and vice versa
+	DatatypeAliases []SymbolInfo
+	// Symbols available only on some of the platforms
+	DatatypesPlatformsMissing []SymbolInfo
+	FunctionsPlatformsMissing []SymbolInfo
+	VariablesPlatformsMissing []SymbolInfo
+}
+
+// testsOnly checks if any reported symbol is allocated in test files only
+func (a *ApiDiff) testsOnly() bool {
+	for _, items := range [][]SymbolInfo{
+		a.DatatypesMissing, a.Datatypes, a.DatatypeAliases,
+		a.FunctionsMissing, a.Functions,
+		a.VariablesMissing, a.Variables, a.ConstantValues,
+		a.MethodsMissing, a.Methods,
+		a.StructFieldsMissing, a.StructFields, a.StructFieldTags,
+		a.DatatypesPlatformsMissing, a.FunctionsPlatformsMissing, a.VariablesPlatformsMissing,
+	} {
+		for _, item := range items {
+			if item.testsOnly() {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 type ident struct {
@@ -147,11 +298,97 @@ func (i ident) str() string {
 
 type typeSymbols map[ident][]string
 
-func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix string, refGlobalST, exercisedGlobalST *global.Table) (*ApiDiff, error) {
+// missingPlatforms lists platforms the exercised symbol is not defined on.
+// The platforms are checked against the given list, or against platforms
+// of the allocated symbol if the list is empty.
+// Symbols not extracted per platform are not checked.
+func missingPlatforms(refSDef, exerSDef *symbols.SymbolDef, platforms []string) []string {
+	if len(exerSDef.Platforms) == 0 {
+		return nil
+	}
+	if len(platforms) == 0 {
+		platforms = refSDef.Platforms
+	}
+	available := make(map[string]struct{})
+	for _, platform := range exerSDef.Platforms {
+		available[platform] = struct{}{}
+	}
+	var missing []string
+	for _, platform := range platforms {
+		if _, ok := available[platform]; !ok {
+			missing = append(missing, platform)
+		}
+	}
+	return missing
+}
+
+// hasFieldMetadata checks if a struct carries field tags and declaration order.
+// Artefacts extracted before the tags were recorded have neither of them.
+// A struct with a single untagged field is indistinguishable from such artefacts.
+func hasFieldMetadata(def gotypes.DataType) bool {
+	structDef, ok := def.(*gotypes.Struct)
+	if !ok {
+		return false
+	}
+	for _, field := range structDef.Fields {
+		if field.Tag != "" || field.Order != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutFieldMetadata returns a copy of a struct (including nested anonymous structs)
+// with field tags and declaration order dropped. Tag changes are reported separately.
+func withoutFieldMetadata(def gotypes.DataType) gotypes.DataType {
+	structDef, ok := def.(*gotypes.Struct)
+	if !ok {
+		return def
+	}
+	stripped := &gotypes.Struct{}
+	for _, field := range structDef.Fields {
+		field.Tag, field.Order = "", 0
+		field.Def = withoutFieldMetadata(field.Def)
+		stripped.Fields = append(stripped.Fields, field)
+	}
+	return stripped
+}
+
+// changedFieldTags lists exported fields (present in both structs) with a different tag.
+// Each item holds a field name, the reference and the exercised tag.
+// If the reference struct has no field metadata, no tags are compared.
+func changedFieldTags(refDef, exerDef gotypes.DataType) [][3]string {
+	if !hasFieldMetadata(refDef) {
+		return nil
+	}
+	refStruct := refDef.(*gotypes.Struct)
+	exerStruct, ok := exerDef.(*gotypes.Struct)
+	if !ok {
+		return nil
+	}
+	exerTags := make(map[string]string)
+	for _, field := range exerStruct.Fields {
+		exerTags[field.Name] = field.Tag
+	}
+	var changed [][3]string
+	for _, field := range refStruct.Fields {
+		if !ast.IsExported(field.Name) {
+			continue
+		}
+		if tag, ok := exerTags[field.Name]; ok && tag != field.Tag {
+			changed = append(changed, [3]string{field.Name, field.Tag, tag})
+		}
+	}
+	return changed
+}
+
+func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix string, refGlobalST, exercisedGlobalST *global.Table, platforms []string) (*ApiDiff, error) {
 	apidiff := ApiDiff{}
 
 	refAccessor := accessors.NewAccessor(refGlobalST)
 	exerAccessor := accessors.NewAccessor(exercisedGlobalST)
+	// struct fields with already reported tag changes
+	reportedTags := make(map[ident]struct{})
 
 	dtNames := make(typeSymbols, 0)
 	fncNames := make(typeSymbols, 0)
@@ -274,14 +511,48 @@ func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix s
 			continue
 		}
 
-		// Compare both symbols
-		if !reflect.DeepEqual(refSDef.Def, exerSDef.Def) {
+		if missing := missingPlatforms(refSDef, exerSDef, platforms); len(missing) > 0 {
+			apidiff.DatatypesPlatformsMissing = append(apidiff.DatatypesPlatformsMissing, SymbolInfo{
+				Package:   symbolItem.pkg,
+				Name:      symbolItem.name,
+				Pos:       positions,
+				Platforms: missing,
+			})
+		}
+
+		// Methods of an alias are methods of the aliased type (and not vice versa),
+		// so is the type compatibility
+		if refSDef.Alias != exerSDef.Alias {
+			apidiff.DatatypeAliases = append(apidiff.DatatypeAliases, SymbolInfo{
+				Package: symbolItem.pkg,
+				Name:    symbolItem.name,
+				Pos:     positions,
+				Alias:   exerSDef.Alias,
+			})
+		}
+
+		// Compare both symbols (tag changes are reported separately)
+		if !reflect.DeepEqual(withoutFieldMetadata(refSDef.Def), withoutFieldMetadata(exerSDef.Def)) {
 			apidiff.Datatypes = append(apidiff.Datatypes, SymbolInfo{
 				Package: symbolItem.pkg,
 				Name:    symbolItem.name,
 				Pos:     positions,
 			})
 		}
+		for _, change := range changedFieldTags(refSDef.Def, exerSDef.Def) {
+			key := ident{pkg: symbolItem.pkg, name: symbolItem.name, field: change[0]}
+			if _, ok := reportedTags[key]; ok {
+				continue
+			}
+			reportedTags[key] = struct{}{}
+			apidiff.StructFieldTags = append(apidiff.StructFieldTags, SymbolInfo{
+				Package: symbolItem.pkg,
+				Parent:  symbolItem.name,
+				Name:    change[0],
+				Pos:     positions,
+				Tags:    [2]string{change[1], change[2]},
+			})
+		}
 	}
 
 	for symbolItem, positions := range fncNames {
@@ -314,6 +585,15 @@ func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix s
 			continue
 		}
 
+		if missing := missingPlatforms(refSDef, exerSDef, platforms); len(missing) > 0 {
+			apidiff.FunctionsPlatformsMissing = append(apidiff.FunctionsPlatformsMissing, SymbolInfo{
+				Package:   symbolItem.pkg,
+				Name:      symbolItem.name,
+				Pos:       positions,
+				Platforms: missing,
+			})
+		}
+
 		// Compare both symbols
 		if !reflect.DeepEqual(refSDef.Def, exerSDef.Def) {
 			apidiff.Functions = append(apidiff.Functions, SymbolInfo{
@@ -354,8 +634,39 @@ func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix s
 			continue
 		}
 
+		if missing := missingPlatforms(refSDef, exerSDef, platforms); len(missing) > 0 {
+			apidiff.VariablesPlatformsMissing = append(apidiff.VariablesPlatformsMissing, SymbolInfo{
+				Package:   symbolItem.pkg,
+				Name:      symbolItem.name,
+				Pos:       positions,
+				Platforms: missing,
+			})
+		}
+
+		refDef, exerDef := refSDef.Def, exerSDef.Def
+		refConst, refOk := refDef.(*gotypes.Constant)
+		exerConst, exerOk := exerDef.(*gotypes.Constant)
+		if refOk && exerOk {
+			refCopy, exerCopy := *refConst, *exerConst
+			// values of older artefacts are not known
+			if refConst.Value != "" && exerConst.Value != "" {
+				if refConst.Value != exerConst.Value {
+					apidiff.ConstantValues = append(apidiff.ConstantValues, SymbolInfo{
+						Package: symbolItem.pkg,
+						Name:    symbolItem.name,
+						Pos:     positions,
+						Values:  [2]string{refConst.Value, exerConst.Value},
+					})
+				}
+				// the value is exact, the literal is not
+				refCopy.Literal, exerCopy.Literal = "", ""
+			}
+			refCopy.Value, exerCopy.Value = "", ""
+			refDef, exerDef = &refCopy, &exerCopy
+		}
+
 		// Compare both symbols
-		if !reflect.DeepEqual(refSDef.Def, exerSDef.Def) {
+		if !reflect.DeepEqual(refDef, exerDef) {
 			apidiff.Variables = append(apidiff.Variables, SymbolInfo{
 				Package: symbolItem.pkg,
 				Name:    symbolItem.name,
@@ -482,7 +793,7 @@ func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix s
 		}
 
 		// Compare both symbols
-		if !reflect.DeepEqual(refMethodDef.DataType, exerMethodDef.DataType) {
+		if !reflect.DeepEqual(withoutFieldMetadata(refMethodDef.DataType), withoutFieldMetadata(exerMethodDef.DataType)) {
 			apidiff.StructFields = append(apidiff.StructFields, SymbolInfo{
 				Package: symbolItem.pkg,
 				Parent:  symbolItem.name,
@@ -490,6 +801,21 @@ func collectApiDiffs(tables map[string]allocglobal.PackageTable, packagePrefix s
 				Pos:     positions,
 			})
 		}
+
+		// the field is defined in the first origin
+		refHasTags := len(refMethodDef.Origin) > 0 && hasFieldMetadata(refMethodDef.Origin[0].Def)
+		if refHasTags && ast.IsExported(symbolItem.field) && refMethodDef.Tag != exerMethodDef.Tag {
+			if _, ok := reportedTags[symbolItem]; !ok {
+				reportedTags[symbolItem] = struct{}{}
+				apidiff.StructFieldTags = append(apidiff.StructFieldTags, SymbolInfo{
+					Package: symbolItem.pkg,
+					Parent:  symbolItem.name,
+					Name:    symbolItem.field,
+					Pos:     positions,
+					Tags:    [2]string{refMethodDef.Tag, exerMethodDef.Tag},
+				})
+			}
+		}
 	}
 
 	return &apidiff, nil
@@ -505,13 +831,25 @@ func main() {
 		allocated:           flag.String("allocated", "", "Allocated symbol table"),
 		allocatedGlidefile:  flag.String("allocated-glidefile", "", "Glide.lock with dependencies of allocated symbol table"),
 		allocatedGodepsfile: flag.String("allocated-godepsfile", "", "Godeps.json with dependencies of allocated symbol table"),
+		allocatedGomodfile:  flag.String("allocated-gomod", "", "go.mod (and go.sum next to it) with dependencies of allocated symbol table"),
+		allocatedDepfile:    flag.String("allocated-depfile", "", "Gopkg.lock (dep) with dependencies of allocated symbol table"),
+		allocatedVendorfile: flag.String("allocated-govendorfile", "", "vendor.json (govendor) with dependencies of allocated symbol table"),
+		allocatedRoot:       flag.String("allocated-project-root", "", "Project root directory to detect the lock file of allocated symbol table in"),
 		packagePrefix:       flag.String("package-prefix", "", "Package entry point"),
-		packageCommit:       flag.String("package-commit", "", "Package commit entry point"),
+		packageCommit:       flag.String("package-commit", "", "Package commit entry point (a commit, a short commit, a tag or a branch)"),
 		goVersion:           flag.String("go-version", "", "Go stdlib version"),
 		symbolTablePath:     flag.String("symbol-table-dir", "", "Directory with preprocessed symbol tables"),
 		cgoSymbolsPath:      flag.String("cgo-symbols-path", "", "Symbol table with CGO symbols (per entire project space)"),
 		glidefile:           flag.String("glidefile", "", "Glide.lock with dependencies"),
 		godepsfile:          flag.String("godepsfile", "", "Godeps.json with dependencies"),
+		gomodfile:           flag.String("gomod", "", "go.mod (and go.sum next to it) with dependencies"),
+		depfile:             flag.String("depfile", "", "Gopkg.lock (dep) with dependencies"),
+		govendorfile:        flag.String("govendorfile", "", "vendor.json (govendor) with dependencies"),
+		projectRoot:         flag.String("project-root", "", "Project root directory to detect the lock file in (go.mod, glide.lock, Godeps/Godeps.json, Gopkg.lock or vendor/vendor.json, in this order)"),
+		ip2ppfile:           flag.String("ip2pp-mapping", "", "ip2pp_mapping.json with import path prefixes of repositories provided under a different path"),
+		gitMirror:           flag.String("git-mirror", "", "Directory with git clones (<dir>/<repository import path>) to resolve refs in (GOPATH is searched as well)"),
+		resolveRefs:         flag.Bool("resolve-refs", false, "Resolve tags, short commits and module versions of dependencies into full commits (through local git clones)"),
+		platforms:           flag.String("platform", "", "Comma separated list of GOOS/GOARCH platforms the exercised symbols must be available on (defaults to platforms of the allocated symbols)"),
 	}
 
 	if err := f.parse(); err != nil {
@@ -552,7 +890,18 @@ func main() {
 	// 2. Find each symbol in a global symbol table
 
 	// 3. Compare if the symbol definition is the same as in the allocated
-	apidiff, err := collectApiDiffs(tables, *f.packagePrefix, refGlobalST, exercisedGlobalST)
+	var platformList []string
+	if *f.platforms != "" {
+		list, err := platforms.ParseList(*f.platforms)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		for _, platform := range list {
+			platformList = append(platformList, platform.String())
+		}
+	}
+
+	apidiff, err := collectApiDiffs(tables, *f.packagePrefix, refGlobalST, exercisedGlobalST, platformList)
 	if err != nil {
 		panic(err)
 	}
@@ -564,7 +913,27 @@ func main() {
 	fmt.Printf("Comparing %v:%v with %v:%v\n", pkg, refCommit, pkg, exercisedCommit)
 
 	// 4. Report differences (if there are any)
+	reportApiDiff(apidiff)
+}
+
+// reportApiDiff prints differences of symbols allocated in production code first.
+// Differences of symbols allocated in test files only are listed separately.
+func reportApiDiff(apidiff *ApiDiff) {
+	printApiDiff(apidiff, false)
+
+	if apidiff.testsOnly() {
+		fmt.Printf("Breaking tests only:\n")
+		printApiDiff(apidiff, true)
+	}
+}
+
+// printApiDiff prints differences of symbols allocated in production code,
+// resp. of symbols allocated in test files only.
+func printApiDiff(apidiff *ApiDiff, testsOnly bool) {
 	for _, item := range apidiff.Datatypes {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		// refSTable, _ := refGlobalST.Lookup(pkg)
 		// exerSTable, _ := exercisedGlobalST.Lookup(pkg)
 		// refSDef, _ := refSTable.LookupDataType(item.Name)
@@ -609,39 +978,111 @@ func main() {
 	}
 
 	for _, item := range apidiff.DatatypesMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v-type %q missing%v\n\tused at %v\n", CLR_R, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
+	for _, item := range apidiff.DatatypesPlatformsMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
+		fmt.Printf("%v-type %q missing on %v%v\n\tused at %v\n", CLR_R, item.str(), strings.Join(item.Platforms, ", "), CLR_N, strings.Join(item.Pos, "\n\tused at "))
+	}
+
 	for _, item := range apidiff.VariablesMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v-function %q missing%v\n\tused at %v\n", CLR_R, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
+	for _, item := range apidiff.VariablesPlatformsMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
+		fmt.Printf("%v-variable %q missing on %v%v\n\tused at %v\n", CLR_R, item.str(), strings.Join(item.Platforms, ", "), CLR_N, strings.Join(item.Pos, "\n\tused at "))
+	}
+
 	for _, item := range apidiff.Variables {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v?function %q changed%v\n\tused at %v\n", CLR_B, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
+	for _, item := range apidiff.ConstantValues {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
+		fmt.Printf("%v?constant %q value changed from %v to %v%v\n\tused at %v\n", CLR_B, item.str(), item.Values[0], item.Values[1], CLR_N, strings.Join(item.Pos, "\n\tused at "))
+	}
+
+	for _, item := range apidiff.DatatypeAliases {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
+		change := "an alias into a defined type"
+		if item.Alias {
+			change = "a defined type into an alias"
+		}
+		fmt.Printf("%v?type %q turned from %v%v\n\tused at %v\n", CLR_B, item.str(), change, CLR_N, strings.Join(item.Pos, "\n\tused at "))
+	}
+
 	for _, item := range apidiff.FunctionsMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v-function %q missing%v\n\tused at %v\n", CLR_R, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
+	for _, item := range apidiff.FunctionsPlatformsMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
+		fmt.Printf("%v-function %q missing on %v%v\n\tused at %v\n", CLR_R, item.str(), strings.Join(item.Platforms, ", "), CLR_N, strings.Join(item.Pos, "\n\tused at "))
+	}
+
 	for _, item := range apidiff.Functions {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v?function %q changed%v\n\tused at %v\n", CLR_B, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
 	for _, item := range apidiff.StructFieldsMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v-field %q missing%v\n\tused at %v\n", CLR_R, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
 	for _, item := range apidiff.StructFields {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v?field %q changed%v\n\tused at %v\n", CLR_B, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
+	for _, item := range apidiff.StructFieldTags {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
+		fmt.Printf("%v?field %q tag changed from %q to %q%v\n\tused at %v\n", CLR_B, item.str(), item.Tags[0], item.Tags[1], CLR_N, strings.Join(item.Pos, "\n\tused at "))
+	}
+
 	for _, item := range apidiff.MethodsMissing {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v-method %q missing%v\n\tused at %v\n", CLR_R, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
 
 	for _, item := range apidiff.Methods {
+		if item.testsOnly() != testsOnly {
+			continue
+		}
 		fmt.Printf("%v?method %q changed%v\n\tused at %v\n", CLR_B, item.str(), CLR_N, strings.Join(item.Pos, "\n\tused at "))
 	}
-
 }
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/parser"
+	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
+	"github.com/gofed/symbols-extractor/pkg/symbols"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+func TestMissingPlatforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       []string
+		exer      []string
+		platforms []string
+		missing   []string
+	}{
+		{
+			name: "symbol not extracted per platform",
+			ref:  []string{"linux/amd64"},
+		},
+		{
+			name: "defined on all platforms of the allocated symbol",
+			ref:  []string{"linux/amd64", "windows/amd64"},
+			exer: []string{"darwin/arm64", "linux/amd64", "windows/amd64"},
+		},
+		{
+			name:    "missing platform of the allocated symbol",
+			ref:     []string{"linux/amd64", "windows/amd64"},
+			exer:    []string{"linux/amd64"},
+			missing: []string{"windows/amd64"},
+		},
+		{
+			name:      "platforms given explicitly",
+			ref:       []string{"linux/amd64"},
+			exer:      []string{"linux/amd64"},
+			platforms: []string{"darwin/arm64", "linux/amd64", "linux/arm64"},
+			missing:   []string{"darwin/arm64", "linux/arm64"},
+		},
+		{
+			name: "allocated symbol not extracted per platform",
+			exer: []string{"linux/amd64"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			missing := missingPlatforms(
+				&symbols.SymbolDef{Name: "Fd", Platforms: test.ref},
+				&symbols.SymbolDef{Name: "Fd", Platforms: test.exer},
+				test.platforms,
+			)
+			if !reflect.DeepEqual(missing, test.missing) {
+				t.Errorf("Expected %v missing platforms, got %v", test.missing, missing)
+			}
+		})
+	}
+}
+
+// captureStdout returns everything the function prints to the standard output
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestReportApiDiffTestsOnly(t *testing.T) {
+	apidiff := &ApiDiff{
+		FunctionsMissing: []SymbolInfo{
+			{Package: "example.com/api", Name: "Run", Pos: []string{"example.com/user/user.go"}},
+			{Package: "example.com/api", Name: "Mock", Pos: []string{"example.com/user/user_test.go"}},
+		},
+		// allocated in production code as well
+		Datatypes: []SymbolInfo{
+			{Package: "example.com/api", Name: "Config", Pos: []string{"example.com/user/user_test.go", "example.com/user/user.go"}},
+		},
+		MethodsMissing: []SymbolInfo{
+			{Package: "example.com/api", Parent: "Server", Name: "Reset", Pos: []string{"example.com/user/user_test.go", "example.com/user/ext_test.go"}},
+		},
+	}
+
+	expected := strings.Join([]string{
+		CLR_B + `?type "example.com/api.Config" changed` + CLR_N,
+		"\tused at example.com/user/user_test.go",
+		"\tused at example.com/user/user.go",
+		CLR_R + `-function "example.com/api.Run" missing` + CLR_N,
+		"\tused at example.com/user/user.go",
+		"Breaking tests only:",
+		CLR_R + `-function "example.com/api.Mock" missing` + CLR_N,
+		"\tused at example.com/user/user_test.go",
+		CLR_R + `-method "example.com/api.Server.Reset" missing` + CLR_N,
+		"\tused at example.com/user/user_test.go",
+		"\tused at example.com/user/ext_test.go",
+		"",
+	}, "\n")
+	if out := captureStdout(t, func() { reportApiDiff(apidiff) }); out != expected {
+		t.Errorf("Expected:\n%v\ngot:\n%v", expected, out)
+	}
+
+	// no section of test files if all symbols are allocated in production code
+	apidiff.FunctionsMissing = apidiff.FunctionsMissing[:1]
+	apidiff.MethodsMissing = nil
+	if out := captureStdout(t, func() { reportApiDiff(apidiff) }); strings.Contains(out, "Breaking tests only") {
+		t.Errorf("Expected no breaking tests, got:\n%v", out)
+	}
+}
+
+// testdataResolver locates packages in the testdata directory
+type testdataResolver map[string]string
+
+func (r testdataResolver) PackageDir(pkg string) (string, error) {
+	if dir, ok := r[pkg]; ok {
+		return filepath.Abs(dir)
+	}
+	return "", fmt.Errorf("Package %q not in testdata", pkg)
+}
+
+// extract extracts the package (example.com/api of the version)
+func extract(t *testing.T, version, pkg string) *parser.ProjectParser {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(t.TempDir(), "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetResolver(testdataResolver{
+		"example.com/api":  filepath.Join("testdata", version, "api"),
+		"example.com/user": "testdata/user",
+	})
+	if err := p.Parse(pkg, true); err != nil {
+		t.Fatalf("Unable to parse %q: %v", pkg, err)
+	}
+	return p
+}
+
+func TestChangedFieldTags(t *testing.T) {
+	ref := &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+		{Name: "Name", Tag: `json:"name"`},
+		{Name: "Port", Tag: `json:"port"`},
+		{Name: "Debug"},
+		{Name: "Removed", Tag: `json:"removed"`},
+		{Name: "private", Tag: `json:"private"`},
+	}}
+	exer := &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+		{Name: "Name", Tag: `json:"full_name"`},
+		{Name: "Port", Tag: `json:"port"`},
+		{Name: "Debug", Tag: `json:"debug,omitempty"`},
+		{Name: "private", Tag: `json:"secret"`},
+	}}
+
+	// unexported and removed fields are not reported
+	expected := [][3]string{
+		{"Name", `json:"name"`, `json:"full_name"`},
+		{"Debug", "", `json:"debug,omitempty"`},
+	}
+	if changed := changedFieldTags(ref, exer); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected %v, got %v", expected, changed)
+	}
+	if changed := changedFieldTags(ref, &gotypes.Identifier{Def: "int"}); changed != nil {
+		t.Errorf("Expected no changes of a non-struct data type, got %v", changed)
+	}
+
+	// the reference extracted before the field tags were recorded
+	noMetadata := withoutFieldMetadata(ref)
+	if changed := changedFieldTags(noMetadata, exer); changed != nil {
+		t.Errorf("Expected no changes of a struct with no field metadata, got %v", changed)
+	}
+}
+
+func TestWithoutFieldMetadata(t *testing.T) {
+	intType := &gotypes.Identifier{Def: "int", Package: "builtin"}
+	def := &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+		{Name: "Port", Tag: `json:"port"`, Def: intType},
+		{Name: "Nested", Tag: `json:"nested"`, Order: 1, Def: &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+			{Name: "Host", Tag: `json:"host"`, Def: intType},
+		}}},
+		{Name: "Embedded", Embedded: true, Order: 2, Def: intType},
+	}}
+	expected := &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+		{Name: "Port", Def: intType},
+		{Name: "Nested", Def: &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+			{Name: "Host", Def: intType},
+		}}},
+		{Name: "Embedded", Embedded: true, Def: intType},
+	}}
+
+	if stripped := withoutFieldMetadata(def); !reflect.DeepEqual(stripped, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, stripped)
+	}
+	if !hasFieldMetadata(def) || hasFieldMetadata(expected) {
+		t.Errorf("Expected field metadata in %#v only", def)
+	}
+	// the original struct is kept
+	if def.Fields[0].Tag == "" || def.Fields[1].Def.(*gotypes.Struct).Fields[0].Tag == "" {
+		t.Errorf("Expected the original struct tags to be kept")
+	}
+}
+
+func TestCollectApiDiffsFieldTags(t *testing.T) {
+	ref := extract(t, "v1", "example.com/user")
+	exer := extract(t, "v2", "example.com/api")
+
+	files, err := ref.GlobalAllocTable().LookupPackage("example.com/user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := map[string]allocglobal.PackageTable{"example.com/user": files}
+	apidiff, err := collectApiDiffs(tables, "example.com/api", ref.GlobalSymbolTable(), exer.GlobalSymbolTable(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the tag of Name is reported once (for both the data type and the field allocations),
+	// Debug is changed as well though not used
+	var changes []string
+	for _, item := range apidiff.StructFieldTags {
+		if len(item.Pos) == 0 {
+			t.Errorf("Expected %v to be reported with positions", item.str())
+		}
+		changes = append(changes, fmt.Sprintf("%v: %q -> %q", item.str(), item.Tags[0], item.Tags[1]))
+	}
+	expected := []string{
+		`example.com/api.Config.Name: "json:\"name\"" -> "json:\"full_name\""`,
+		`example.com/api.Config.Debug: "" -> "json:\"debug,omitempty\""`,
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+	if apidiff.testsOnly() {
+		t.Errorf("Expected the tag changes to be allocated in production code")
+	}
+	// tag changes are not reported as data type or field changes
+	if len(apidiff.Datatypes) != 0 || len(apidiff.StructFields) != 0 {
+		t.Errorf("Expected no data type nor field changes, got %v and %v", apidiff.Datatypes, apidiff.StructFields)
+	}
+
+	// no tags are compared if the reference has no field metadata (e.g. an older artefact)
+	refTable, err := ref.GlobalSymbolTable().Lookup("example.com/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := refTable.LookupDataType("Config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Def = withoutFieldMetadata(config.Def)
+	apidiff, err = collectApiDiffs(tables, "example.com/api", ref.GlobalSymbolTable(), exer.GlobalSymbolTable(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apidiff.StructFieldTags) != 0 || len(apidiff.Datatypes) != 0 || len(apidiff.StructFields) != 0 {
+		t.Errorf("Expected no changes, got %v tags, %v data types and %v fields", apidiff.StructFieldTags, apidiff.Datatypes, apidiff.StructFields)
+	}
+}
+
+func TestCollectApiDiffsConstantValues(t *testing.T) {
+	ref := extract(t, "v1", "example.com/user")
+	exer := extract(t, "v2", "example.com/api")
+
+	files, err := ref.GlobalAllocTable().LookupPackage("example.com/user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := map[string]allocglobal.PackageTable{"example.com/user": files}
+	apidiff, err := collectApiDiffs(tables, "example.com/api", ref.GlobalSymbolTable(), exer.GlobalSymbolTable(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// constants written differently with the same value are not reported
+	var changes []string
+	for _, item := range apidiff.ConstantValues {
+		if len(item.Pos) == 0 {
+			t.Errorf("Expected %v to be reported with positions", item.str())
+		}
+		changes = append(changes, fmt.Sprintf("%v: %v -> %v", item.str(), item.Values[0], item.Values[1]))
+	}
+	sort.Strings(changes)
+	expected := []string{
+		"example.com/api.Timeout: 30 -> 60",
+		`example.com/api.Version: "v1" -> "v2"`,
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+	if len(apidiff.Variables) != 0 {
+		t.Errorf("Expected no variable changes, got %v", apidiff.Variables)
+	}
+
+	// no values are compared if the reference has none (e.g. an older artefact)
+	refTable, err := ref.GlobalSymbolTable().Lookup("example.com/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Timeout", "Version", "Ratio", "Size"} {
+		sym, err := refTable.LookupVariable(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sym.Def.(*gotypes.Constant).Value = ""
+	}
+	apidiff, err = collectApiDiffs(tables, "example.com/api", ref.GlobalSymbolTable(), exer.GlobalSymbolTable(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apidiff.ConstantValues) != 0 {
+		t.Errorf("Expected no value changes, got %v", apidiff.ConstantValues)
+	}
+}
+
+func TestCollectApiDiffsDatatypeAliases(t *testing.T) {
+	ref := extract(t, "v1", "example.com/user")
+	exer := extract(t, "v2", "example.com/api")
+
+	files, err := ref.GlobalAllocTable().LookupPackage("example.com/user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := map[string]allocglobal.PackageTable{"example.com/user": files}
+	apidiff, err := collectApiDiffs(tables, "example.com/api", ref.GlobalSymbolTable(), exer.GlobalSymbolTable(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := map[string]bool{}
+	for _, item := range apidiff.DatatypeAliases {
+		if len(item.Pos) == 0 {
+			t.Errorf("Expected %v to be reported with positions", item.str())
+		}
+		changes[item.str()] = item.Alias
+	}
+	// Header turned into an alias, Options into a defined type
+	expected := map[string]bool{
+		"example.com/api.Header":  true,
+		"example.com/api.Options": false,
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+	// the aliased and the underlying types are the same
+	if len(apidiff.Datatypes) != 0 {
+		t.Errorf("Expected no data type changes, got %v", apidiff.Datatypes)
+	}
+
+	expectedOut := []string{
+		CLR_B + `?type "example.com/api.Header" turned from a defined type into an alias` + CLR_N,
+		CLR_B + `?type "example.com/api.Options" turned from an alias into a defined type` + CLR_N,
+	}
+	out := captureStdout(t, func() { printApiDiff(apidiff, false) })
+	for _, line := range expectedOut {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected %q in:\n%v", line, out)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package user
+
+import "example.com/api"
+
+func Name(c api.Config) string {
+	return c.Name
+}
+
+func Port(c *api.Config) int {
+	return c.Port
+}
+
+func Defaults() (int, string, float64, int) {
+	return api.Timeout, api.Version, api.Ratio, api.Size
+}
+
+func Apply(h api.Header, o *api.Options) {
+	h["name"] = o.Name
+}
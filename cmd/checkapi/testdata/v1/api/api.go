@@ -0,0 +1,19 @@
+package api
+
+type Config struct {
+	Name    string `json:"name"`
+	Port    int    `json:"port"`
+	Debug   bool
+	private string `json:"private"`
+}
+
+const (
+	Timeout = 30
+	Version = "v1"
+	Ratio   = 0.5
+	Size    = 1 << 3
+)
+
+type Header map[string]string
+
+type Options = Config
@@ -0,0 +1,19 @@
+package api
+
+type Config struct {
+	Name    string `json:"full_name"`
+	Port    int    `json:"port"`
+	Debug   bool   `json:"debug,omitempty"`
+	private string `json:"secret"`
+}
+
+const (
+	Timeout = 60
+	Version = "v2"
+	Ratio   = 1.0 / 2
+	Size    = 8
+)
+
+type Header = map[string]string
+
+type Options Config
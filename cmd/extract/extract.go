@@ -13,12 +13,23 @@ import (
 
 	util "github.com/gofed/symbols-extractor/cmd/go"
 	"github.com/gofed/symbols-extractor/pkg/analyzers/type/runner"
+	"github.com/gofed/symbols-extractor/pkg/loader"
 	"github.com/gofed/symbols-extractor/pkg/parser"
+	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
 	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
 	contractglobal "github.com/gofed/symbols-extractor/pkg/parser/contracts/global"
+	"github.com/gofed/symbols-extractor/pkg/platforms"
+	"github.com/gofed/symbols-extractor/pkg/resolvers"
 	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/dep"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/detect"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gitref"
 	"github.com/gofed/symbols-extractor/pkg/snapshots/glide"
 	"github.com/gofed/symbols-extractor/pkg/snapshots/godeps"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/govendor"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/ip2pp"
+	"github.com/gofed/symbols-extractor/pkg/store"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
 	"k8s.io/klog/v2"
@@ -47,17 +58,60 @@ type SymbolsExtractorExtractCommand struct {
 	tojson        bool
 	glidefile     string
 	godepsfile    string
+	gomodfile     string
+	depfile       string
+	govendorfile  string
+	gomodcache    string
+	goworkfile    string
+	// directory to detect the lock file in (if no lock file is set explicitly)
+	projectRoot string
+	// mapping of import path prefixes to provider prefixes (e.g. ip2pp_mapping.json)
+	ip2ppfile string
+	// directory with git clones to resolve refs in (besides GOPATH)
+	gitMirror string
+	// resolve commits of the lock file into full commits
+	resolveRefs bool
+	// comma separated list of GOOS/GOARCH[/nocgo] platforms
+	platforms string
+	// extract _test.go files of entry points as well
+	tests bool
 	// Interpret entry point as a library instead of a reachability tree
 	library bool
+	// format artefacts are saved in (json, gzip or binary)
+	format string
+	// artefacts stored for each package (api, api+allocated or full)
+	level string
+	// check the generated artefacts are up to date instead of extracting
+	verify bool
+	// number of packages extracted concurrently
+	workers int
+	// re-extract packages using changed symbols of changed packages
+	incremental bool
+	// file to save a JSON report of contracts that could not be evaluated into
+	diagnostics string
 }
 
 func (command *SymbolsExtractorExtractCommand) Run() error {
-	if !command.stdlib && command.packagePath == "" {
+	if !command.stdlib && !command.verify && command.packagePath == "" {
 		return fmt.Errorf("--package-path is not set")
 	}
 
+	format, err := store.ParseFormat(command.format)
+	if err != nil {
+		return err
+	}
+	store.SetFormat(format)
+
+	level, err := parser.ParseLevel(command.level)
+	if err != nil {
+		return err
+	}
+
+	if command.workers < 1 {
+		return fmt.Errorf("--workers must be at least 1")
+	}
 	// Otherwise it can eat all the CPU power
-	runtime.GOMAXPROCS(1)
+	runtime.GOMAXPROCS(command.workers)
 
 	output, err := exec.Command("go", "version").CombinedOutput()
 	if err != nil {
@@ -66,40 +120,210 @@ func (command *SymbolsExtractorExtractCommand) Run() error {
 	// TODO(jchaloup): Check the version is of the form d.d for now (later extend with alpha/beta/rc...)
 	goversion := strings.Split(string(output), " ")[2][2:]
 
+	var platformList []*platforms.Platform
+	if command.platforms != "" {
+		platformList, err = platforms.ParseList(command.platforms)
+		if err != nil {
+			return err
+		}
+		if command.allocated && len(platformList) > 1 {
+			return fmt.Errorf("--allocated can be used with a single --platform only")
+		}
+	}
+
 	// parse the standard library
-	if command.stdlib {
-		processStdlib(command.symbolTablePath, command.cgoSymbolsPath, goversion)
-		return nil
+	if command.stdlib && !command.verify {
+		if len(platformList) == 0 {
+			processStdlib(command.symbolTablePath, command.cgoSymbolsPath, goversion, nil, level, command.workers)
+			return nil
+		}
+		packages := make(map[string]struct{})
+		for _, platform := range platformList {
+			fmt.Printf("Processing %v platform...\n", platform)
+			for _, pkg := range processStdlib(platformSymbolTablePath(command.symbolTablePath, platform), command.cgoSymbolsPath, goversion, platform, level, command.workers) {
+				packages[pkg] = struct{}{}
+			}
+		}
+		return mergePlatformTables(
+			path.Join(command.symbolTablePath, "golang", goversion),
+			func(platform *platforms.Platform) string {
+				return path.Join(platformSymbolTablePath(command.symbolTablePath, platform), "golang", goversion)
+			},
+			goversion,
+			nil,
+			platformList,
+			packages,
+		)
 	}
 
-	snapshot, err := buildSnapshot(command.glidefile, command.godepsfile, command.packagePrefix)
+	refs := gitref.FromGOPATH(command.gitMirror)
+	packagePrefix, err := resolvePackagePrefix(command.packagePrefix, refs)
 	if err != nil {
 		return err
 	}
 
-	p, err := parser.New(command.symbolTablePath, command.cgoSymbolsPath, goversion, snapshot)
+	// artefacts are verified with no lock file as well (the lock file only helps to locate package files)
+	var snapshot snapshots.Snapshot
+	if !command.verify || command.lockFileSet() {
+		snapshot, err = buildSnapshot(command.glidefile, command.godepsfile, command.gomodfile, command.depfile, command.govendorfile, command.projectRoot, packagePrefix)
+		if err != nil {
+			return err
+		}
+	}
+
+	resolver, err := buildResolver(snapshot, command.gomodcache, command.goworkfile)
 	if err != nil {
 		return err
 	}
 
+	if command.verify {
+		if len(platformList) == 0 {
+			return command.verifyArtefacts(command.symbolTablePath, goversion, snapshot, resolver, nil)
+		}
+		for _, platform := range platformList {
+			if err := command.verifyArtefacts(platformSymbolTablePath(command.symbolTablePath, platform), goversion, snapshot, resolver, platform); err != nil {
+				return fmt.Errorf("Platform %v: %v", platform, err)
+			}
+		}
+		return nil
+	}
+
+	// packages imported under a different path than their lock entries
+	var mapping ip2pp.Mapping
+	if command.ip2ppfile != "" {
+		if mapping, err = ip2pp.MappingFromFile(command.ip2ppfile); err != nil {
+			return err
+		}
+	}
+	snapshot = ip2pp.New(snapshot, mapping)
+	if command.resolveRefs {
+		snapshot = gitref.NewSnapshot(snapshot, refs)
+	}
+
 	entryPoints, _ := buildEntryPoints(command.packagePath, command.library)
 
+	if len(platformList) == 0 {
+		_, err := command.extract(command.symbolTablePath, goversion, snapshot, resolver, nil, level, entryPoints)
+		return err
+	}
+
+	packages := make(map[string]struct{})
+	for _, platform := range platformList {
+		fmt.Printf("Processing %v platform...\n", platform)
+		p, err := command.extract(platformSymbolTablePath(command.symbolTablePath, platform), goversion, snapshot, resolver, platform, level, entryPoints)
+		if err != nil {
+			return fmt.Errorf("Platform %v: %v", platform, err)
+		}
+		// the standard library is merged when extracted with --stdlib
+		for _, pkg := range p.GlobalSymbolTable().Packages() {
+			if !p.GlobalSymbolTable().Stdlib(pkg) {
+				packages[pkg] = struct{}{}
+			}
+		}
+	}
+
+	return mergePlatformTables(
+		command.symbolTablePath,
+		func(platform *platforms.Platform) string {
+			return platformSymbolTablePath(command.symbolTablePath, platform)
+		},
+		goversion,
+		snapshot,
+		platformList,
+		packages,
+	)
+}
+
+// extract parses all entry points and stores the symbol tables under the symbolTablePath.
+// If platform is set, package files of the platform are parsed.
+// The level sets artefacts stored for each package.
+func (command *SymbolsExtractorExtractCommand) extract(symbolTablePath, goversion string, snapshot snapshots.Snapshot, resolver resolvers.Resolver, platform *platforms.Platform, level parser.Level, entryPoints []string) (*parser.ProjectParser, error) {
+	p, err := parser.New(symbolTablePath, command.cgoSymbolsPath, goversion, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver != nil {
+		p.SetResolver(resolver)
+	}
+	if platform != nil {
+		p.SetPlatform(platform)
+	}
+	p.SetTests(command.tests)
+	p.SetLevel(level)
+	p.SetWorkers(command.workers)
+	p.SetIncremental(command.incremental)
+
 	for _, pkgPath := range entryPoints {
 		if err := p.Parse(pkgPath, command.allocated); err != nil {
-			return fmt.Errorf("Parse error: %v", err)
+			return nil, fmt.Errorf("Parse error: %v", err)
 		}
 	}
 
 	if command.allocated {
-		if err := printPackageAllocTables(p.GlobalAllocTable(), p.GlobalSymbolTable(), p.GlobalContractsTable(), entryPoints, &cmdFlags); err != nil {
-			return err
+		var testAllocTable *allocglobal.Table
+		if command.tests {
+			testAllocTable = p.GlobalTestAllocTable()
+		}
+		if err := printPackageAllocTables(p.GlobalAllocTable(), testAllocTable, p.GlobalSymbolTable(), p.GlobalContractsTable(), p.Diagnostics(), entryPoints, &cmdFlags); err != nil {
+			return nil, err
 		}
 	}
 
+	if command.diagnostics != "" {
+		if err := p.Diagnostics().Save(command.diagnostics); err != nil {
+			return nil, fmt.Errorf("Unable to save diagnostics: %v", err)
+		}
+	}
+
+	return p, nil
+}
+
+func (command *SymbolsExtractorExtractCommand) lockFileSet() bool {
+	return command.glidefile != "" || command.godepsfile != "" || command.gomodfile != "" || command.depfile != "" || command.govendorfile != "" || command.projectRoot != ""
+}
+
+// verifyArtefacts checks all artefacts stored under the symbolTablePath were extracted from the current package files.
+// Artefacts of other platforms (stored under the platforms directory) are checked only if the platform is set.
+func (command *SymbolsExtractorExtractCommand) verifyArtefacts(symbolTablePath, goversion string, snapshot snapshots.Snapshot, resolver resolvers.Resolver, platform *platforms.Platform) error {
+	p, err := parser.New(symbolTablePath, command.cgoSymbolsPath, goversion, snapshot)
+	if err != nil {
+		return err
+	}
+	if resolver != nil {
+		p.SetResolver(resolver)
+	}
+	if platform != nil {
+		p.SetPlatform(platform)
+	}
+
+	checked, stale := 0, 0
+	err = p.Verify(func(location string, diffs []string) {
+		if platform == nil && strings.HasPrefix(location, "platforms/") {
+			return
+		}
+		checked++
+		if len(diffs) == 0 {
+			klog.V(1).Infof("%v up to date", location)
+			return
+		}
+		stale++
+		fmt.Printf("%v is stale:\n", location)
+		for _, diff := range diffs {
+			fmt.Printf("\t%v\n", diff)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v of %v packages up to date\n", checked-stale, checked)
+	if stale > 0 {
+		return fmt.Errorf("%v packages with stale artefacts", stale)
+	}
 	return nil
 }
 
-var cmdFlags = SymbolsExtractorExtractCommand{}
+var cmdFlags = SymbolsExtractorExtractCommand{workers: runtime.NumCPU()}
 
 func NewSymbolsExtractorExtractCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -115,7 +339,7 @@ func NewSymbolsExtractorExtractCommand() *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVar(&cmdFlags.packagePath, "package-path", cmdFlags.packagePath, "Package entry point")
-	flags.StringVar(&cmdFlags.packagePrefix, "package-prefix", cmdFlags.packagePrefix, "Package import path prefix in a PACKAGE:COMMIT form")
+	flags.StringVar(&cmdFlags.packagePrefix, "package-prefix", cmdFlags.packagePrefix, "Package import path prefix in a PACKAGE:REF form (REF is a commit, a short commit, a tag or a branch)")
 	flags.StringVar(&cmdFlags.symbolTablePath, "symbol-table-dir", cmdFlags.symbolTablePath, "Directory with preprocessed symbol tables")
 	// TODO(jchaloup): extend it with a hiearchy of cgo symbol files
 	flags.StringVar(&cmdFlags.cgoSymbolsPath, "cgo-symbols-path", cmdFlags.cgoSymbolsPath, "Symbol table with CGO symbols (per entire project space)")
@@ -129,7 +353,116 @@ func NewSymbolsExtractorExtractCommand() *cobra.Command {
 	flags.BoolVar(&cmdFlags.tojson, "json", cmdFlags.tojson, "Display allocated symbols in JSON")
 	flags.StringVar(&cmdFlags.glidefile, "glidefile", cmdFlags.glidefile, "Glide.lock with dependencies")
 	flags.StringVar(&cmdFlags.godepsfile, "godepsfile", cmdFlags.godepsfile, "Godeps.json with dependencies")
+	flags.StringVar(&cmdFlags.gomodfile, "gomod", cmdFlags.gomodfile, "go.mod (and go.sum next to it) with dependencies")
+	flags.StringVar(&cmdFlags.depfile, "depfile", cmdFlags.depfile, "Gopkg.lock (dep) with dependencies")
+	flags.StringVar(&cmdFlags.govendorfile, "govendorfile", cmdFlags.govendorfile, "vendor.json (govendor) with dependencies")
+	flags.StringVar(&cmdFlags.projectRoot, "project-root", cmdFlags.projectRoot, "Project root directory to detect the lock file in (go.mod, glide.lock, Godeps/Godeps.json, Gopkg.lock or vendor/vendor.json, in this order)")
+	flags.StringVar(&cmdFlags.ip2ppfile, "ip2pp-mapping", cmdFlags.ip2ppfile, "ip2pp_mapping.json with import path prefixes of repositories provided under a different path")
+	flags.StringVar(&cmdFlags.gitMirror, "git-mirror", cmdFlags.gitMirror, "Directory with git clones (<dir>/<repository import path>) to resolve refs in (GOPATH is searched as well)")
+	flags.BoolVar(&cmdFlags.resolveRefs, "resolve-refs", cmdFlags.resolveRefs, "Resolve tags, short commits and module versions of dependencies into full commits (through local git clones)")
+	flags.StringVar(&cmdFlags.gomodcache, "gomodcache", cmdFlags.gomodcache, "Module cache directory to locate --gomod dependencies in (defaults to GOMODCACHE of the go command)")
+	flags.StringVar(&cmdFlags.goworkfile, "gowork", cmdFlags.goworkfile, "go.work with workspace modules")
+	flags.StringVar(&cmdFlags.platforms, "platform", cmdFlags.platforms, "Comma separated list of GOOS/GOARCH platforms to extract symbols for (e.g. linux/amd64,windows/arm64,linux/amd64/nocgo)")
+	flags.BoolVar(&cmdFlags.tests, "tests", cmdFlags.tests, "Extract _test.go files (including external _test packages) of entry points as a separate test scope")
 	flags.BoolVar(&cmdFlags.library, "library", cmdFlags.library, "Interpret package entry point as a library")
+	flags.BoolVar(&cmdFlags.verify, "verify", cmdFlags.verify, "Check all artefacts under --symbol-table-dir were extracted from the current package files (no extraction is done)")
+	flags.StringVar(&cmdFlags.level, "level", cmdFlags.level, "Artefacts to extract for each package: api, api+allocated or full (api.json, allocated.json and contracts.json)")
+	flags.BoolVar(&cmdFlags.incremental, "incremental", cmdFlags.incremental, "Re-extract packages changed since extracted and packages using their changed symbols only")
+	flags.IntVar(&cmdFlags.workers, "workers", cmdFlags.workers, "Number of packages extracted concurrently (a package is extracted once all its imported packages are)")
+	flags.StringVar(&cmdFlags.diagnostics, "diagnostics", cmdFlags.diagnostics, "File to save a JSON report of contracts that could not be evaluated into (with --allocated)")
+	flags.StringVar(&cmdFlags.format, "format", cmdFlags.format, "Format to save artefacts in: json, gzip (compressed JSON) or binary (contracts and allocated in a binary encoding, api compressed)")
+
+	cmd.AddCommand(NewConvertCommand())
+	cmd.AddCommand(NewMigrateCommand())
+
+	return cmd
+}
+
+type ConvertCommand struct {
+	// location of the generated artefacts
+	symbolTablePath string
+	// location to save the converted artefacts to (the artefacts are converted in place if empty)
+	output string
+	format string
+}
+
+func (command *ConvertCommand) Run() error {
+	if command.symbolTablePath == "" {
+		return fmt.Errorf("--symbol-table-dir is not set")
+	}
+	format, err := store.ParseFormat(command.format)
+	if err != nil {
+		return err
+	}
+
+	from := store.RawFor(command.symbolTablePath)
+	to := from
+	if command.output != "" {
+		to = store.RawFor(command.output)
+	}
+	converted, err := store.Convert(from, to, format)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v artefacts converted\n", converted)
+	return nil
+}
+
+// NewConvertCommand creates a command rewriting a generated tree into another format
+func NewConvertCommand() *cobra.Command {
+	command := &ConvertCommand{}
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Convert generated artefacts into another format",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := command.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&command.symbolTablePath, "symbol-table-dir", command.symbolTablePath, "Directory (or .kv file) with generated artefacts")
+	flags.StringVar(&command.output, "output", command.output, "Directory (or .kv file) to save the converted artefacts to (defaults to --symbol-table-dir)")
+	flags.StringVar(&command.format, "format", command.format, "Format to convert artefacts into: json, gzip or binary")
+
+	return cmd
+}
+
+type MigrateCommand struct {
+	// location of the generated artefacts
+	symbolTablePath string
+}
+
+func (command *MigrateCommand) Run() error {
+	if command.symbolTablePath == "" {
+		return fmt.Errorf("--symbol-table-dir is not set")
+	}
+	migrated, err := store.Migrate(store.RawFor(command.symbolTablePath))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v artefacts migrated to schema version %v\n", migrated, store.SchemaVersion)
+	return nil
+}
+
+// NewMigrateCommand creates a command upgrading a generated tree to the current schema version
+func NewMigrateCommand() *cobra.Command {
+	command := &MigrateCommand{}
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade generated artefacts in place to the current schema version",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := command.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&command.symbolTablePath, "symbol-table-dir", command.symbolTablePath, "Directory (or .kv file) with generated artefacts")
 
 	return cmd
 }
@@ -150,7 +483,45 @@ func PrintAllocTables(allocTable *allocglobal.Table) {
 	}
 }
 
-func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.Table, contractTable *contractglobal.Table, entryPoints []string, cmdFlags *SymbolsExtractorExtractCommand) error {
+// addTestAllocTables adds symbols allocated in test files of a package (if there are any).
+// Test symbols are evaluated when the package tests are parsed.
+func addTestAllocTables(packageAllocTables map[string]allocglobal.PackageTable, testAllocTable *allocglobal.Table, pkg string, perfile bool) error {
+	if testAllocTable == nil {
+		return nil
+	}
+	tt, err := testAllocTable.LookupPackage(pkg)
+	if err != nil {
+		klog.V(1).Infof("No test files of %v allocated: %v", pkg, err)
+		return nil
+	}
+
+	if !perfile {
+		tt, err = testAllocTable.MergeFiles(pkg)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := packageAllocTables[pkg]; !ok {
+		packageAllocTables[pkg] = *allocglobal.NewPackageTable()
+	}
+	for file, table := range tt {
+		packageAllocTables[pkg][file] = table
+	}
+	return nil
+}
+
+// evaluateContracts evaluates contracts of the package, contracts that can not be evaluated are reported
+func evaluateContracts(r *runner.Runner, report *runner.Report) error {
+	err := r.Run()
+	if diagnostics, ok := err.(*runner.Diagnostics); ok {
+		report.Add(diagnostics)
+		return nil
+	}
+	return err
+}
+
+func printPackageAllocTables(allocTable, testAllocTable *allocglobal.Table, globalTable *global.Table, contractTable *contractglobal.Table, report *runner.Report, entryPoints []string, cmdFlags *SymbolsExtractorExtractCommand) error {
 
 	perfile := cmdFlags.perfile
 	allallocated := cmdFlags.allallocated
@@ -198,7 +569,7 @@ func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.
 				return err
 			}
 			r := runner.New(p, globalTable, allocTable, ct)
-			if err := r.Run(); err != nil {
+			if err := evaluateContracts(r, report); err != nil {
 				return fmt.Errorf("Unable to evaluate contracts for %v: %v", p, err)
 			}
 		}
@@ -214,6 +585,27 @@ func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.
 			}
 		}
 
+		for _, p := range entryPoints {
+			if _, ok := processed[p]; !ok {
+				continue
+			}
+			testTables := make(map[string]allocglobal.PackageTable)
+			if err := addTestAllocTables(testTables, testAllocTable, p, true); err != nil {
+				return err
+			}
+			for _, tt := range testTables {
+				if tt.FilterOut(cmdFlags.filterPrefix) {
+					continue
+				}
+				if _, ok := packageAllocTables[p]; !ok {
+					packageAllocTables[p] = *allocglobal.NewPackageTable()
+				}
+				for file, table := range tt {
+					packageAllocTables[p][file] = table
+				}
+			}
+		}
+
 		// if per-project is set, merge all tables into one
 		if cmdFlags.pertree {
 			cpTable := allocglobal.NewPackageTable()
@@ -232,7 +624,7 @@ func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.
 				return err
 			}
 			r := runner.New(pkg, globalTable, allocTable, ct)
-			if err := r.Run(); err != nil {
+			if err := evaluateContracts(r, report); err != nil {
 				return fmt.Errorf("Unable to evaluate contracts for %v: %v", pkg, err)
 			}
 			if perfile {
@@ -251,6 +643,9 @@ func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.
 				}
 				packageAllocTables[pkg] = tt
 			}
+			if err := addTestAllocTables(packageAllocTables, testAllocTable, pkg, perfile); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -266,11 +661,19 @@ func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.
 	if perfile {
 		for _, pkg := range entryPoints {
 			allocTable.Print(pkg, allallocated)
+			if testAllocTable != nil && len(testAllocTable.Files(pkg)) > 0 {
+				fmt.Printf("\tScope: %v\n", alloctable.TestScope)
+				testAllocTable.Print(pkg, allallocated)
+			}
 		}
 	} else {
 		for p, files := range packageAllocTables {
 			for file, t := range files {
-				fmt.Printf("file: %v\n", path.Join(p, file))
+				if t.Scope != alloctable.ProductionScope {
+					fmt.Printf("file: %v (%v scope)\n", path.Join(p, t.File), t.Scope)
+				} else {
+					fmt.Printf("file: %v\n", path.Join(p, file))
+				}
 				t.Print(allallocated)
 			}
 		}
@@ -278,98 +681,184 @@ func printPackageAllocTables(allocTable *allocglobal.Table, globalTable *global.
 	return nil
 }
 
-func getStdlibPackages() ([]string, error) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("Unable to get current WD: %v", err)
-	}
-
-	defer func() {
-		// ignore the error
-		os.Chdir(cwd)
-	}()
-
-	var packages []string
+// getStdlibPackages lists packages of the standard library built on the platform
+func getStdlibPackages(platform *platforms.Platform) ([]string, error) {
+	return loader.For(platform).Std()
+}
 
-	output, err := exec.Command("go", "env", "GOROOT").CombinedOutput()
+func processStdlib(symbolTablePath, cgoSymbolsPath, goversion string, platform *platforms.Platform, level parser.Level, workers int) []string {
+	packages, err := getStdlibPackages(platform)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to get GOROOT env: %v", err)
+		klog.Fatal(err)
 	}
-	goroot := strings.Split(string(output), "\n")[0]
 
-	err = os.Chdir(path.Join(goroot, "src"))
-	if err != nil {
-		return nil, fmt.Errorf("Unable to change dir to %v: %v", path.Join(goroot, "src"), err)
-	}
+	generatedDir := path.Join(symbolTablePath, "golang", goversion)
 
-	for _, pkg := range []string{"", "vendor/"} {
-		output, err = exec.Command("go", "list", fmt.Sprintf("./%v...", pkg)).CombinedOutput()
+	for _, pkg := range packages {
+		fmt.Printf("Parsing %q...\n", pkg)
+		p, err := parser.New(generatedDir, cgoSymbolsPath, goversion, nil)
 		if err != nil {
-			panic(fmt.Errorf("Unable to list packages under %v: %v", path.Join(goroot, "src"), err))
+			panic(err)
+		}
+		if platform != nil {
+			p.SetPlatform(platform)
+		}
+		p.SetLevel(level)
+		p.SetWorkers(workers)
+		if err := p.Parse(pkg, false); err != nil {
+			klog.Fatalf("Parse error when parsing (%v): %v", pkg, err)
 		}
+	}
+	return packages
+}
+
+// platformSymbolTablePath is a directory the symbol tables of a platform are stored under
+func platformSymbolTablePath(symbolTablePath string, platform *platforms.Platform) string {
+	return path.Join(symbolTablePath, "platforms", platform.Dir())
+}
 
-		for _, line := range strings.Split(string(output), "\n") {
-			if line == "" {
+// mergePlatformTables merges per-platform symbol tables of each package into one table
+// stored under the symbolTablePath. Each symbol of the merged table lists platforms it is defined on.
+func mergePlatformTables(symbolTablePath string, platformPath func(*platforms.Platform) string, goversion string, snapshot snapshots.Snapshot, platformList []*platforms.Platform, packages map[string]struct{}) error {
+	platformTables := make(map[string]*global.Table)
+	for _, platform := range platformList {
+		platformTables[platform.String()] = global.New(platformPath(platform), goversion, snapshot)
+	}
+	mergedTable := global.New(symbolTablePath, goversion, snapshot)
+
+	for pkg := range packages {
+		pkgTables := make(map[string]*tables.Table)
+		for platform, table := range platformTables {
+			st, err := table.Lookup(pkg)
+			if err != nil {
+				// the package is not available on the platform
+				klog.V(1).Infof("Package %q not found for %v platform: %v", pkg, platform, err)
 				continue
 			}
-			packages = append(packages, line)
+			t, ok := st.(*tables.Table)
+			if !ok {
+				continue
+			}
+			pkgTables[platform] = t
+		}
+		if len(pkgTables) == 0 {
+			continue
+		}
+		merged, err := tables.MergePlatforms(pkgTables)
+		if err != nil {
+			return fmt.Errorf("Unable to merge %q symbol tables: %v", pkg, err)
+		}
+		if err := mergedTable.Add(pkg, merged, true); err != nil {
+			return err
 		}
 	}
-
-	return packages, nil
+	return nil
 }
 
-func processStdlib(symbolTablePath, cgoSymbolsPath, goversion string) {
-	packages, err := getStdlibPackages()
+// resolvePackagePrefix turns PACKAGE:REF into PACKAGE:COMMIT.
+// If the ref can not be resolved (e.g. there is no local git clone), it is kept as it is.
+func resolvePackagePrefix(packagePrefix string, refs *gitref.Resolver) (string, error) {
+	if packagePrefix == "" {
+		return "", nil
+	}
+	parts := strings.Split(packagePrefix, ":")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("Expected --package-prefix in a PACKAGE:REF form")
+	}
+	commit, err := refs.Resolve(parts[0], parts[1])
 	if err != nil {
-		klog.Fatal(err)
+		klog.Warningf("Using %q as a commit: %v", parts[1], err)
+		return packagePrefix, nil
 	}
+	if commit != parts[1] {
+		fmt.Printf("Resolved %v of %v to %v\n", parts[1], parts[0], commit)
+	}
+	return fmt.Sprintf("%v:%v", parts[0], commit), nil
+}
 
-	generatedDir := path.Join(symbolTablePath, "golang", goversion)
-
-	for _, pkg := range packages {
-		fmt.Printf("Parsing %q...\n", pkg)
-		p, err := parser.New(generatedDir, cgoSymbolsPath, goversion, nil)
-		if err != nil {
-			panic(err)
-		}
-		if err := p.Parse(pkg, false); err != nil {
-			klog.Fatalf("Parse error when parsing (%v): %v", pkg, err)
+// withMainPackageCommit sets a commit of the main package given
+// by the --package-prefix in a PACKAGE:COMMIT form (if set)
+func withMainPackageCommit(sn snapshots.MainPackageSnapshot, packagePrefix string) (snapshots.Snapshot, error) {
+	if packagePrefix != "" {
+		parts := strings.Split(packagePrefix, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Expected --package-prefix in a PACKAGE:COMMIT form")
 		}
+		sn.MainPackageCommit(parts[0], parts[1])
 	}
-	return
+	return sn, nil
 }
 
-func buildSnapshot(glidefile, godepsfile, packagePrefix string) (snapshots.Snapshot, error) {
+func buildSnapshot(glidefile, godepsfile, gomodfile, depfile, govendorfile, projectRoot, packagePrefix string) (snapshots.Snapshot, error) {
 	if glidefile != "" {
 		sn, err := glide.GlideFromFile(glidefile)
 		if err != nil {
 			return nil, err
 		}
-		if packagePrefix != "" {
-			parts := strings.Split(packagePrefix, ":")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("Expected --package-prefix in a PACKAGE:COMMIT form")
-			}
-			sn.MainPackageCommit(parts[0], parts[1])
-		}
-		return sn, nil
+		return withMainPackageCommit(sn, packagePrefix)
 	}
 	if godepsfile != "" {
 		sn, err := godeps.FromFile(godepsfile)
 		if err != nil {
 			return nil, err
 		}
-		if packagePrefix != "" {
-			parts := strings.Split(packagePrefix, ":")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("Expected --package-prefix in a PACKAGE:COMMIT form")
-			}
-			sn.MainPackageCommit(parts[0], parts[1])
+		return withMainPackageCommit(sn, packagePrefix)
+	}
+	if gomodfile != "" {
+		sn, err := gomod.FromFile(gomodfile)
+		if err != nil {
+			return nil, err
+		}
+		return withMainPackageCommit(sn, packagePrefix)
+	}
+	if depfile != "" {
+		sn, err := dep.FromFile(depfile)
+		if err != nil {
+			return nil, err
+		}
+		return withMainPackageCommit(sn, packagePrefix)
+	}
+	if govendorfile != "" {
+		sn, err := govendor.FromFile(govendorfile)
+		if err != nil {
+			return nil, err
 		}
-		return sn, nil
+		return withMainPackageCommit(sn, packagePrefix)
+	}
+	if projectRoot != "" {
+		sn, file, err := detect.FromProjectRoot(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Reading dependencies from %v\n", file)
+		return withMainPackageCommit(sn, packagePrefix)
+	}
+	return nil, fmt.Errorf("--glidefile, --godepsfile, --gomod, --depfile, --govendorfile or --project-root is not set")
+}
+
+// buildResolver locates packages in workspace modules first,
+// then in the module cache (in case of a go.mod snapshot).
+// Vendor directories and GOPATH are searched last.
+func buildResolver(snapshot snapshots.Snapshot, gomodcache, goworkfile string) (resolvers.Resolver, error) {
+	var chain resolvers.Chain
+	if goworkfile != "" {
+		w, err := resolvers.WorkspaceFromFile(goworkfile)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, w)
+	}
+	if sn, ok := snapshot.(*gomod.GoMod); ok {
+		m, err := resolvers.NewModuleCache(gomodcache, sn)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, m)
+	}
+	if len(chain) == 0 {
+		return nil, nil
 	}
-	panic("glidefile or godepsfile must be nonempty")
+	return chain, nil
 }
 
 func buildEntryPoints(packagePath string, library bool) ([]string, error) {
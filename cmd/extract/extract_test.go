@@ -0,0 +1,129 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/store"
+)
+
+var artefacts = map[store.Key]string{
+	{Package: "example.com/a", Commit: "abcdef", Kind: store.APIKind}:       `{"functions":[{"name":"F"}]}`,
+	{Package: "example.com/a", Commit: "abcdef", Kind: store.ContractsKind}: `{"functions":{"F":[]}}`,
+	{Package: "example.com/b", Commit: "012345", Kind: store.AllocatedKind}: `{"b.go":{"symbols":{}}}`,
+}
+
+// saveArtefacts saves the artefacts with no schema header (as generated before the header was introduced)
+func saveArtefacts(t *testing.T, location string) {
+	for key, data := range artefacts {
+		key.Format = store.JSONFormat
+		if err := store.RawFor(location).Save(key, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// listFormats lists formats of the stored artefacts
+func listFormats(t *testing.T, location string) map[store.Key]store.Format {
+	formats := make(map[store.Key]store.Format)
+	for _, pkg := range []string{"example.com/a", "example.com/b"} {
+		keys, err := store.RawFor(location).List(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range keys {
+			format := key.Format
+			key.Format = ""
+			formats[key] = format
+		}
+	}
+	return formats
+}
+
+// checkArtefacts checks all the artefacts are loaded from the location
+func checkArtefacts(t *testing.T, location string) {
+	s := store.NewEncoded(store.RawFor(location), store.JSONFormat)
+	for key, data := range artefacts {
+		loaded, err := s.Load(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(loaded) != data {
+			t.Errorf("Expected %v to be %s, got %s", key.Path(), data, loaded)
+		}
+	}
+}
+
+func TestConvertCommand(t *testing.T) {
+	binary := map[store.Key]store.Format{
+		{Package: "example.com/a", Commit: "abcdef", Kind: store.APIKind}:       store.GzipFormat,
+		{Package: "example.com/a", Commit: "abcdef", Kind: store.ContractsKind}: store.BinaryFormat,
+		{Package: "example.com/b", Commit: "012345", Kind: store.AllocatedKind}: store.BinaryFormat,
+	}
+
+	t.Run("in place", func(t *testing.T) {
+		dir := t.TempDir()
+		saveArtefacts(t, dir)
+		if err := (&ConvertCommand{symbolTablePath: dir, format: "binary"}).Run(); err != nil {
+			t.Fatal(err)
+		}
+		if formats := listFormats(t, dir); !reflect.DeepEqual(formats, binary) {
+			t.Errorf("Expected %v, got %v", binary, formats)
+		}
+		checkArtefacts(t, dir)
+	})
+
+	t.Run("into a key-value file", func(t *testing.T) {
+		dir := t.TempDir()
+		output := filepath.Join(t.TempDir(), "artefacts.kv")
+		saveArtefacts(t, dir)
+		if err := (&ConvertCommand{symbolTablePath: dir, output: output, format: "gzip"}).Run(); err != nil {
+			t.Fatal(err)
+		}
+		gzip := make(map[store.Key]store.Format)
+		for key := range binary {
+			gzip[key] = store.GzipFormat
+		}
+		if formats := listFormats(t, output); !reflect.DeepEqual(formats, gzip) {
+			t.Errorf("Expected %v, got %v", gzip, formats)
+		}
+		checkArtefacts(t, output)
+		// the source artefacts are kept
+		checkArtefacts(t, dir)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := (&ConvertCommand{symbolTablePath: t.TempDir(), format: "zstd"}).Run(); err == nil {
+			t.Errorf("Expected an unknown format to be rejected")
+		}
+	})
+}
+
+func TestMigrateCommand(t *testing.T) {
+	dir := t.TempDir()
+	saveArtefacts(t, dir)
+	if err := (&MigrateCommand{symbolTablePath: dir}).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for key := range artefacts {
+		key.Format = store.JSONFormat
+		data, err := store.RawFor(dir).Load(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version, err := store.Version(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != store.SchemaVersion {
+			t.Errorf("Expected %v to be migrated to schema version %v, got %v", key.Path(), store.SchemaVersion, version)
+		}
+	}
+	checkArtefacts(t, dir)
+
+	if err := (&MigrateCommand{}).Run(); err == nil {
+		t.Errorf("Expected --symbol-table-dir to be required")
+	}
+}
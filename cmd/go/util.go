@@ -2,22 +2,20 @@ package util
 
 import (
 	"fmt"
+	"go/build"
 	"go/parser"
 	"go/token"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 
-	"github.com/gofed/symbols-extractor/cmd/go/internal/load"
-	"github.com/gofed/symbols-extractor/cmd/go/internal/work"
+	"github.com/gofed/symbols-extractor/pkg/loader"
+	"github.com/gofed/symbols-extractor/pkg/platforms"
+	"github.com/gofed/symbols-extractor/pkg/resolvers"
 	"k8s.io/klog/v2"
-
-	// initialize the load.ModInit function
-	"github.com/gofed/symbols-extractor/cmd/go/internal/modload"
 )
 
 func exists(path string) (bool, error) {
@@ -88,7 +86,8 @@ func (ignore *Ignore) ignore(path string) bool {
 }
 
 type PackageInfoCollector struct {
-	packageInfos   map[string]*load.PackagePublic
+	loader         *loader.Loader
+	packageInfos   map[string]*build.Package
 	mainFiles      map[string][]string
 	packagePath    string
 	ignore         *Ignore
@@ -106,7 +105,8 @@ type OtherResources struct {
 
 func NewPackageInfoCollector(ignore *Ignore, extensions []string) *PackageInfoCollector {
 	return &PackageInfoCollector{
-		packageInfos:  make(map[string]*load.PackagePublic),
+		loader:        loader.For(nil),
+		packageInfos:  make(map[string]*build.Package),
 		mainFiles:     make(map[string][]string),
 		isStdPackages: make(map[string]bool),
 		ignore:        ignore,
@@ -118,14 +118,14 @@ func (p *PackageInfoCollector) isStandard(pkg string) (bool, error) {
 	if is, exists := p.isStdPackages[pkg]; exists {
 		return is, nil
 	}
-	pkgInfo, err := ListPackage(pkg)
+	pkgInfo, err := p.loader.Import(pkg)
 	if err != nil {
 		return false, err
 	}
 
-	p.isStdPackages[pkg] = pkgInfo.Standard
+	p.isStdPackages[pkg] = pkgInfo.Goroot
 
-	return pkgInfo.Standard, nil
+	return pkgInfo.Goroot, nil
 }
 
 func (p *PackageInfoCollector) CollectPackageInfos(packagePath string) error {
@@ -178,17 +178,12 @@ func (p *PackageInfoCollector) CollectPackageInfos(packagePath string) error {
 			return nil
 		}
 
-		pkgInfo, err := ListPackage(relPath)
+		pkgInfo, err := p.loader.ImportDir(path)
 		if err != nil {
-			if strings.Contains(err.Error(), "no Go files in") {
-				return nil
-			}
-			// TODO(jchaloup): remove later
-			if strings.Contains(err.Error(), "build constraints exclude all Go files in") {
+			if _, ok := err.(*build.NoGoError); ok {
 				return nil
 			}
 			panic(err)
-			return nil
 		}
 
 		if len(pkgInfo.GoFiles) > 0 || len(pkgInfo.CgoFiles) > 0 {
@@ -359,9 +354,9 @@ func (p *PackageInfoCollector) CollectProjectDeps(standard bool, skipSelf bool,
 			continue
 		}
 
-		pkgInfo, err := ListPackage(relPath)
+		pkgInfo, err := p.loader.Import(relPath)
 		// assuming the stdlib is always processed properly
-		if !standard && err == nil && pkgInfo.Standard {
+		if !standard && err == nil && pkgInfo.Goroot {
 			continue
 		}
 
@@ -413,81 +408,57 @@ func (p *PackageInfoCollector) BuildPackageTree(includeMain bool, tests bool) ([
 	return entryPoints, nil
 }
 
-func ListPackage(path string) (*load.PackagePublic, error) {
-	// TODO(jchaloup): more things need to be init most likely
-	work.BuildInit()
-	load.ModInit = modload.Init
-	d := load.PackagesAndErrors([]string{path})
-	if d == nil {
-		return nil, fmt.Errorf("No package listing found for %v", path)
-	}
-
-	pkg := d[0]
-	if pkg.Error != nil {
-		return nil, pkg.Error
-	}
-	// Show vendor-expanded paths in listing
-	pkg.TestImports = pkg.Resolve(pkg.TestImports)
-	pkg.XTestImports = pkg.Resolve(pkg.XTestImports)
-	return &pkg.PackagePublic, nil
-}
-
-func ListGoFiles(packagePath string, cgo bool) ([]string, error) {
-
-	collectFiles := func(output string) []string {
-		line := strings.Split(string(output), "\n")[0]
-		line = line[1 : len(line)-1]
-		if line == "" {
-			return nil
+// GetPackageFiles locates package files. If resolver is set, it is tried first.
+// Otherwise, nested vendor directories and GOPATH are searched.
+// If platform is set, only files built on the platform are listed.
+func GetPackageFiles(packageRoot, packagePath string, resolver resolvers.Resolver, platform *platforms.Platform) (files []string, packageLocation string, err error) {
+	if resolver != nil {
+		dir, err := resolver.PackageDir(packagePath)
+		if err == nil {
+			klog.V(1).Infof("Found %v directory", dir)
+			files, err := resolvers.PackageFiles(dir, platform)
+			if err != nil {
+				return nil, "", err
+			}
+			return files, dir, nil
 		}
-		return strings.Split(line, " ")
-	}
-	// check GOPATH/packagePath
-	filter := "{{.GoFiles}}"
-	if cgo {
-		filter = "{{.CgoFiles}}"
-	}
-	cmd := exec.Command("go", "list", "-f", filter, packagePath)
-	output, e := cmd.CombinedOutput()
-	if e == nil {
-		return collectFiles(string(output)), nil
+		klog.V(1).Infof("Resolver failed: %v", err)
 	}
 
-	if strings.Contains(string(output), "no buildable Go source files in") {
-		return nil, nil
+	l := loader.For(platform)
+	// a package with no buildable Go files (for the platform) is found with no files
+	listPackage := func(packagePath string) (*build.Package, error) {
+		pkg, err := l.Import(packagePath)
+		if err != nil {
+			if _, ok := err.(*build.NoGoError); !ok {
+				return nil, err
+			}
+		}
+		return pkg, nil
 	}
 
-	// if strings.Contains(string(output), "no Go files in") {
-	// 	return nil, nil
-	// }
-
-	return nil, fmt.Errorf("%v: %v, %v", strings.Join(cmd.Args, " "), e, string(output))
-}
-
-func GetPackageFiles(packageRoot, packagePath string) (files []string, packageLocation string, err error) {
-
-	files, ppath, e := func() ([]string, string, error) {
+	pkg, e := func() (*build.Package, error) {
 		var searched []string
 		// First searched the vendor directories
 		pathParts := strings.Split(packageRoot, string(os.PathSeparator))
 		for i := len(pathParts); i >= 0; i-- {
 			vendorpath := path.Join(path.Join(pathParts[:i]...), "vendor", packagePath)
 			klog.V(1).Infof("Checking %v directory", vendorpath)
-			if l, e := ListGoFiles(vendorpath, false); e == nil {
+			if pkg, e := listPackage(vendorpath); e == nil {
 				klog.V(1).Infof("Found %v directory", vendorpath)
-				return l, vendorpath, e
+				return pkg, nil
 			}
 			searched = append(searched, vendorpath)
 		}
 
 		klog.V(1).Infof("Checking %v directory", packagePath)
-		if l, e := ListGoFiles(packagePath, false); e == nil {
+		if pkg, e := listPackage(packagePath); e == nil {
 			klog.V(1).Infof("Found %v directory", packagePath)
-			return l, packagePath, e
+			return pkg, nil
 		}
 		searched = append(searched, packagePath)
 
-		return nil, "", fmt.Errorf("Unable to find %q in any of:\n\t\t%v\n", packagePath, strings.Join(searched, "\n\t\t"))
+		return nil, fmt.Errorf("Unable to find %q in any of:\n\t\t%v\n", packagePath, strings.Join(searched, "\n\t\t"))
 	}()
 
 	if e != nil {
@@ -495,26 +466,10 @@ func GetPackageFiles(packageRoot, packagePath string) (files []string, packageLo
 	}
 
 	// cgo files enabled?
-	cgoFiles, e := ListGoFiles(ppath, true)
-	if e != nil {
-		return nil, "", e
-	}
-
-	if len(cgoFiles) > 0 {
-		files = append(files, cgoFiles...)
-	}
-
-	{
-		cmd := exec.Command("go", "list", "-f", "{{.Dir}}", ppath)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return nil, "", fmt.Errorf("go list -f {{.Dir}} %v failed: %v", ppath, err)
-		}
-		lines := strings.Split(string(output), "\n")
-		packageLocation = string(lines[0])
-	}
+	files = append(files, pkg.GoFiles...)
+	files = append(files, pkg.CgoFiles...)
 
-	return files, packageLocation, nil
+	return files, pkg.Dir, nil
 }
 
 type ProjectData struct {
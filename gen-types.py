@@ -9,10 +9,11 @@ class ObjectDefinition:
         self._array_fields = {}
         self._name = name
 
-    def addAtomicField(self, name, type, omit=False):
+    def addAtomicField(self, name, type, omit=False, optional=False):
         self._atomic_fields[name] = {
             "type": type,
             "omit": omit,
+            "optional": optional,
         }
 
     def addNonAtomicField(self, name, type, constraint, omit=False):
@@ -36,7 +37,7 @@ const {{ Name }}Type = "{{ Name|lower }}"
 
 type {{ Name }} struct {
         {% for field in AtomicFields %}
-        {{ field|capitalize }} {{ AtomicFields[field]["type"] }} `json:"{{ '-' if AtomicFields[field]["omit"] else field|lower }}"`
+        {{ field|capitalize }} {{ AtomicFields[field]["type"] }} `json:"{{ '-' if AtomicFields[field]["omit"] else field|lower }}{{ ',omitempty' if AtomicFields[field]["optional"] else '' }}"`
         {%- endfor %}
         {% for field in NonAtomicFields %}
         {{ field|capitalize }} {{ NonAtomicFields[field]["type"] }} `json:"{{ '-' if NonAtomicFields[field]["omit"] else field|lower }}"`
@@ -69,7 +70,15 @@ func (o *{{ Name }}) UnmarshalJSON(b []byte) error {
     }
 
     {% for item in AtomicFields %}
-    {% if not AtomicFields[item]["omit"] %}
+    {% if AtomicFields[item]["optional"] %}
+    // optional field (not set in older artefacts)
+    if objMap[\"{{ item|lower }}\"] != nil {
+        if err := json.Unmarshal(*objMap[\"{{ item|lower }}\"], &o.{{ item|capitalize }}); err != nil {
+            return err
+        }
+    }
+    {% endif %}
+    {% if not AtomicFields[item]["omit"] and not AtomicFields[item]["optional"] %}
     // TODO(jchaloup): check the objMap[\"{{ item|lower }}\"] actually exists
     if err := json.Unmarshal(*objMap[\"{{ item|lower }}\"], &o.{{ item|capitalize }}); err != nil {
         return err
@@ -95,6 +104,11 @@ func (o *{{ Name }}) UnmarshalJSON(b []byte) error {
                 }
                 o.{{ item|capitalize }} = r
             {% endfor %}
+            case nil:
+                // no data type
+
+            default:
+                return fmt.Errorf("Unknown type %q of {{ Name }}.{{ item }} (generated by a newer version?)", dataType)
             }
         }
     }
@@ -128,6 +142,11 @@ func (o *{{ Name }}) UnmarshalJSON(b []byte) error {
                     o.{{ item }} = append(o.{{ item }}, r)
                     {% endif %}
                 {% endfor %}
+                case nil:
+                    // no data type
+
+                default:
+                    return fmt.Errorf("Unknown type %q of an item of {{ Name }}.{{ item }} (generated by a newer version?)", dataType)
                 }
             }
         }
@@ -194,7 +213,7 @@ class DataTypeGenerator(object):
                     if definition["properties"][property]["description"] == "!!omit":
                         obj.addAtomicField(property.capitalize(), itemType, omit=True)
                     else:
-                        obj.addAtomicField(property.capitalize(), itemType, omit=False)
+                        obj.addAtomicField(property.capitalize(), itemType, omit=False, optional="default" in definition["properties"][property])
                 elif itemType == "boolean":
                     # skip all 'type' fields
                     if property == "type":
@@ -202,7 +221,9 @@ class DataTypeGenerator(object):
                     if definition["properties"][property]["description"] == "!!omit":
                         obj.addAtomicField(property.capitalize(), "bool", omit=True)
                     else:
-                        obj.addAtomicField(property.capitalize(), "bool", omit=False)
+                        obj.addAtomicField(property.capitalize(), "bool", omit=False, optional="default" in definition["properties"][property])
+                elif itemType == "integer":
+                    obj.addAtomicField(property.capitalize(), "int", omit=False, optional="default" in definition["properties"][property])
                 # list of permited types
                 elif itemType == "object":
                     ok = False
@@ -242,7 +263,10 @@ class DataTypeGenerator(object):
     def __str__(self):
             return """package types
 
-        import "encoding/json"
+        import (
+        	"encoding/json"
+        	"fmt"
+        )
 
         // DataType is
         type DataType interface {
@@ -256,15 +280,25 @@ package symbols
 
 import (
     "encoding/json"
+    "fmt"
+
+    "github.com/gofed/symbols-extractor/pkg/positions"
     gotypes "github.com/gofed/symbols-extractor/pkg/types"
 )
 
 type SymbolDef struct {
        Pos     string   `json:"pos"`
+       // Structured position (file, line, column and end position)
+       Position *positions.Position `json:"position,omitempty"`
        Name    string   `json:"name"`
        Package string   `json:"package"`
        Def     gotypes.DataType `json:"def"`
        Block   int              `json:"block"`
+       Typeparams []gotypes.DataType `json:"typeparams,omitempty"`
+       // Data type is an alias (type A = B) of its definition
+       Alias bool `json:"alias,omitempty"`
+       // Platforms the symbol is defined on (set in a merged per-platform view only)
+       Platforms []string `json:"platforms,omitempty"`
 }
 
 func (o *SymbolDef) UnmarshalJSON(b []byte) error {
@@ -278,6 +312,15 @@ func (o *SymbolDef) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+    // older artefacts have the pos only
+    if objMap["position"] != nil {
+		if err := json.Unmarshal(*objMap["position"], &o.Position); err != nil {
+			return err
+		}
+    } else {
+		o.Position = positions.Parse(o.Pos)
+    }
+
 	if err := json.Unmarshal(*objMap["name"], &o.Name); err != nil {
 		return err
 	}
@@ -292,6 +335,32 @@ func (o *SymbolDef) UnmarshalJSON(b []byte) error {
 		}
     }
 
+    if objMap["typeparams"] != nil {
+		var l []*json.RawMessage
+		if err := json.Unmarshal(*objMap["typeparams"], &l); err != nil {
+			return err
+		}
+		for _, item := range l {
+			r := &gotypes.Typeparam{}
+			if err := json.Unmarshal(*item, &r); err != nil {
+				return err
+			}
+			o.Typeparams = append(o.Typeparams, r)
+		}
+    }
+
+    if objMap["alias"] != nil {
+		if err := json.Unmarshal(*objMap["alias"], &o.Alias); err != nil {
+			return err
+		}
+    }
+
+    if objMap["platforms"] != nil {
+		if err := json.Unmarshal(*objMap["platforms"], &o.Platforms); err != nil {
+			return err
+		}
+    }
+
 	var m map[string]interface{}
 	if err := json.Unmarshal(*objMap["def"], &m); err != nil {
 		return err
@@ -312,6 +381,12 @@ func (o *SymbolDef) UnmarshalJSON(b []byte) error {
 			return err
 		}
 		o.Def = r
+
+    case nil:
+		// no definition
+
+    default:
+		return fmt.Errorf("Unknown type %q of %q symbol definition (generated by a newer version?)", dataType, o.Name)
     }
 
 	return nil
@@ -328,7 +403,7 @@ if __name__ == "__main__":
     with open("golang-project-exported-api.json", "r") as f:
         data = json.load(f)
 
-    dataTypes = ["identifier", "builtin", "constant", "packagequalifier", "selector", "channel", "slice", "array", "map", "pointer", "ellipsis", "function", "method", "interface", "struct"]
+    dataTypes = ["identifier", "builtin", "constant", "packagequalifier", "selector", "channel", "slice", "array", "map", "pointer", "ellipsis", "function", "method", "interface", "struct", "typeparam", "instance", "union"]
 
     with open("pkg/types/types.go", "w") as file:
         file.write(str(DataTypeGenerator(data["definitions"], dataTypes).parse()))
@@ -2,65 +2,318 @@ package compatibility
 
 import (
 	"fmt"
+	"go/ast"
 	"reflect"
+	"sort"
 
+	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 )
 
-func InterfacesEqual(x, y *gotypes.Interface) bool {
-	fmt.Printf("I1: %#v\n", x)
-	fmt.Printf("I2: %#v\n", y)
+type Config struct {
+	// per subset of packages symbol table
+	symbolsAccessor *accessors.Accessor
+}
 
-	iXMethods := make(map[string]*gotypes.Function)
-	iYMethods := make(map[string]*gotypes.Function)
+func New(symbolsAccessor *accessors.Accessor) *Config {
+	return &Config{
+		symbolsAccessor: symbolsAccessor,
+	}
+}
 
-	if len(x.Methods) != len(y.Methods) {
-		return false
+// Method is a method of a method set
+type Method struct {
+	Name string
+	// package the method is declared in
+	Package   string
+	Signature *gotypes.Function
+	// definition of the method (nil for methods of interfaces)
+	Def *gotypes.Method
+}
+
+// MethodSet is a set of methods keyed by their names.
+// Non-exported names are qualified with the package since
+// non-exported method names from different packages are always different.
+type MethodSet map[string]*Method
+
+func methodKey(name, pkg string) string {
+	if ast.IsExported(name) {
+		return name
 	}
+	return pkg + "." + name
+}
 
-	for _, m := range x.Methods {
-		iXMethods[m.Name] = m.Def.(*gotypes.Function)
+// Names lists sorted keys of the methods
+func (s MethodSet) Names() []string {
+	var names []string
+	for name := range s {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	for _, m := range y.Methods {
-		if _, ok := iXMethods[m.Name]; !ok {
-			return false
+// identifier returns an identifier of a named data type (nil for other data types)
+func identifier(dataType gotypes.DataType) *gotypes.Identifier {
+	switch d := dataType.(type) {
+	case *gotypes.Identifier:
+		return d
+	case *gotypes.Builtin:
+		return &gotypes.Identifier{Package: "builtin", Def: d.Def}
+	case *gotypes.Constant:
+		return &gotypes.Identifier{Package: d.Package, Def: d.Def}
+	case *gotypes.Selector:
+		if qid, ok := d.Prefix.(*gotypes.Packagequalifier); ok {
+			return &gotypes.Identifier{Package: qid.Path, Def: d.Item}
 		}
-		iYMethods[m.Name] = m.Def.(*gotypes.Function)
 	}
+	return nil
+}
 
-	for name := range iXMethods {
-		fmt.Printf("%v:\n\t%#v\n\t%#v\n", name, iXMethods[name], iYMethods[name])
-		if !FunctionSignaturesEqual(iXMethods[name], iYMethods[name]) {
-			return false
+// underlyingType returns the first non-identifier data type of the named data type
+func (c *Config) underlyingType(ident *gotypes.Identifier) (gotypes.DataType, error) {
+	def, _, err := c.symbolsAccessor.LookupDataType(ident)
+	if err != nil {
+		return nil, err
+	}
+	if def.Def == nil {
+		return nil, fmt.Errorf("Symbol %q not yet fully processed", ident.Def)
+	}
+	return c.symbolsAccessor.FindFirstNonidDataType(def.Def)
+}
+
+// InterfaceMethodSet collects methods of the interface incl. methods of embedded interfaces
+func (c *Config) InterfaceMethodSet(i *gotypes.Interface) (MethodSet, error) {
+	set := make(MethodSet)
+	if err := c.interfaceMethods(i, set, make(map[string]struct{})); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func (c *Config) interfaceMethods(i *gotypes.Interface, set MethodSet, visited map[string]struct{}) error {
+	for _, item := range i.Methods {
+		if item.Name != "" {
+			f, ok := item.Def.(*gotypes.Function)
+			if !ok {
+				return fmt.Errorf("Interface method %q expected to be a function, got %#v instead", item.Name, item.Def)
+			}
+			set[methodKey(item.Name, f.Package)] = &Method{Name: item.Name, Package: f.Package, Signature: f}
+			continue
+		}
+		// embedded interface (other embedded data types are type constraints with no methods)
+		ident := identifier(item.Def)
+		if ident == nil {
+			continue
+		}
+		if _, ok := visited[ident.Package+"."+ident.Def]; ok {
+			continue
+		}
+		visited[ident.Package+"."+ident.Def] = struct{}{}
+		dt, err := c.underlyingType(ident)
+		if err != nil {
+			return err
+		}
+		if embedded, ok := dt.(*gotypes.Interface); ok {
+			if err := c.interfaceMethods(embedded, set, visited); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
+}
 
-	// Two interface types are identical if they have the same set of methods with the same names and identical function types. Non-exported method names from different packages are always different. The order of the methods is irrelevant.
-	// TODO(jchaloup): what about embedded interfaces?
+// embeddedType is a data type whose methods are promoted into a method set
+type embeddedType struct {
+	dataType gotypes.DataType
+	// methods with pointer receivers are promoted as well
+	pointer bool
+}
 
-	return true
+// MethodSet collects the method set of the data type:
+// - methods with value receivers of a named data type T
+// - methods with value and pointer receivers of *T
+// - methods of an interface
+// - methods promoted from embedded fields of a struct (the shallowest one wins, ambiguous ones are left out)
+func (c *Config) MethodSet(dataType gotypes.DataType) (MethodSet, error) {
+	set := make(MethodSet)
+	level := []embeddedType{{dataType: dataType}}
+	if pointer, ok := dataType.(*gotypes.Pointer); ok {
+		level = []embeddedType{{dataType: pointer.Def, pointer: true}}
+	}
+	// selectors (fields and methods) found on lower depths shadow the deeper ones
+	shadowed := make(map[string]struct{})
+	// data types embedded on lower depths (a data type embedded more than once
+	// on the same depth makes its selectors ambiguous)
+	visited := make(map[string]struct{})
+
+	for depth := 0; len(level) > 0; depth++ {
+		selectors := make(map[string]int)
+		methods := make(MethodSet)
+		levelVisited := make(map[string]struct{})
+		var next []embeddedType
+
+		for _, embedded := range level {
+			dt := embedded.dataType
+			pkg := ""
+			if ident := identifier(dt); ident != nil {
+				if _, ok := visited[ident.Package+"."+ident.Def]; ok {
+					continue
+				}
+				levelVisited[ident.Package+"."+ident.Def] = struct{}{}
+				pkg = ident.Package
+
+				// a data type with no methods is not listed
+				defs, _ := c.symbolsAccessor.LookupAllMethods(ident)
+				for name, def := range defs {
+					method, ok := def.Def.(*gotypes.Method)
+					if !ok {
+						continue
+					}
+					if _, ok := method.Receiver.(*gotypes.Pointer); ok && !embedded.pointer {
+						continue
+					}
+					f, ok := method.Def.(*gotypes.Function)
+					if !ok {
+						return nil, fmt.Errorf("Method %q expected to be a function, got %#v instead", name, method.Def)
+					}
+					methodPkg := def.Package
+					if methodPkg == "" {
+						methodPkg = ident.Package
+					}
+					key := methodKey(name, methodPkg)
+					selectors[key]++
+					methods[key] = &Method{Name: name, Package: methodPkg, Signature: f, Def: method}
+				}
+
+				underlying, err := c.underlyingType(ident)
+				if err != nil {
+					return nil, err
+				}
+				dt = underlying
+			}
+
+			switch d := dt.(type) {
+			case *gotypes.Interface:
+				// pointers to interfaces have no methods
+				if depth == 0 && embedded.pointer {
+					continue
+				}
+				iMethods, err := c.InterfaceMethodSet(d)
+				if err != nil {
+					return nil, err
+				}
+				for key, method := range iMethods {
+					selectors[key]++
+					methods[key] = method
+				}
+			case *gotypes.Struct:
+				for _, field := range d.Fields {
+					selectors[methodKey(field.Name, pkg)]++
+					if !field.Embedded {
+						continue
+					}
+					// methods of an embedded *T are promoted into both S and *S,
+					// methods of an embedded T with pointer receivers into *S only
+					if pointer, ok := field.Def.(*gotypes.Pointer); ok {
+						next = append(next, embeddedType{dataType: pointer.Def, pointer: true})
+						continue
+					}
+					next = append(next, embeddedType{dataType: field.Def, pointer: embedded.pointer})
+				}
+			}
+		}
+
+		for key, count := range selectors {
+			if _, ok := shadowed[key]; ok {
+				continue
+			}
+			shadowed[key] = struct{}{}
+			if method, ok := methods[key]; ok && count == 1 {
+				set[key] = method
+			}
+		}
+		for key := range levelVisited {
+			visited[key] = struct{}{}
+		}
+		level = next
+	}
+
+	return set, nil
+}
+
+// ImplementingMethods collects methods of the data type implementing methods of the interface.
+// If the data type does not implement the interface, nil is returned.
+func (c *Config) ImplementingMethods(dataType gotypes.DataType, i *gotypes.Interface) (MethodSet, error) {
+	required, err := c.InterfaceMethodSet(i)
+	if err != nil {
+		return nil, err
+	}
+	methods := make(MethodSet)
+	if len(required) == 0 {
+		return methods, nil
+	}
+
+	set, err := c.MethodSet(dataType)
+	if err != nil {
+		return nil, err
+	}
+	for key, method := range required {
+		m, ok := set[key]
+		if !ok || !c.FunctionSignaturesEqual(m.Signature, method.Signature) {
+			return nil, nil
+		}
+		methods[key] = m
+	}
+	return methods, nil
 }
 
-func TypeImplementsInterface(x gotypes.DataType, i *gotypes.Interface) bool {
-	return false
+func (c *Config) TypeImplementsInterface(x gotypes.DataType, i *gotypes.Interface) (bool, error) {
+	methods, err := c.ImplementingMethods(x, i)
+	if err != nil {
+		return false, err
+	}
+	return methods != nil, nil
+}
+
+// Two interface types are identical if they have the same set of methods with the same names and identical function types.
+// Non-exported method names from different packages are always different. The order of the methods is irrelevant.
+func (c *Config) InterfacesEqual(x, y *gotypes.Interface) (bool, error) {
+	xMethods, err := c.InterfaceMethodSet(x)
+	if err != nil {
+		return false, err
+	}
+	yMethods, err := c.InterfaceMethodSet(y)
+	if err != nil {
+		return false, err
+	}
+
+	if len(xMethods) != len(yMethods) {
+		return false, nil
+	}
+	for key, method := range xMethods {
+		yMethod, ok := yMethods[key]
+		if !ok || !c.FunctionSignaturesEqual(method.Signature, yMethod.Signature) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
-func FunctionSignaturesEqual(x, y *gotypes.Function) bool {
-	// compare params
+// FunctionSignaturesEqual compares parameters and results of functions
+// (a package a function is declared in is irrelevant)
+func (c *Config) FunctionSignaturesEqual(x, y *gotypes.Function) bool {
 	if len(x.Params) != len(y.Params) || len(x.Results) != len(y.Results) {
 		return false
 	}
 	for i := range x.Params {
-		fmt.Printf("%v:\n\t%#v\n\t%#v\n", i, x.Params[i], y.Params[i])
-		if !DataTypesEqual(x.Params[i], y.Params[i]) {
+		if !c.DataTypesEqual(x.Params[i], y.Params[i]) {
 			return false
 		}
 	}
 
 	for i := range x.Results {
-		fmt.Printf("%v:\n\t%#v\n\t%#v\n", i, x.Results[i], y.Results[i])
-		if !DataTypesEqual(x.Results[i], y.Results[i]) {
+		if !c.DataTypesEqual(x.Results[i], y.Results[i]) {
 			return false
 		}
 	}
@@ -68,54 +321,84 @@ func FunctionSignaturesEqual(x, y *gotypes.Function) bool {
 	return true
 }
 
-func DataTypesEqual(x, y gotypes.DataType) bool {
-	if x.GetType() != y.GetType() {
-		return false
+// resolve turns named data types into identifiers, aliases are replaced with the aliased data types
+func (c *Config) resolve(dataType gotypes.DataType) gotypes.DataType {
+	ident := identifier(dataType)
+	if ident == nil {
+		return dataType
 	}
-
-	switch x.GetType() {
-	case gotypes.IdentifierType:
-		i1 := x.(*gotypes.Identifier)
-		i2 := x.(*gotypes.Identifier)
-		return i1.Package == i2.Package && i1.Def == i2.Def
-	default:
-		panic(fmt.Errorf("DataTypesEqual: NYI for %v", x.GetType()))
+	def, _, err := c.symbolsAccessor.LookupDataType(ident)
+	if err != nil || def == nil || !def.Alias || def.Def == nil {
+		return ident
 	}
+	if target, ok := def.Def.(*gotypes.Identifier); ok && target.Package == "" {
+		return &gotypes.Identifier{Package: ident.Package, Def: target.Def}
+	}
+	return c.resolve(def.Def)
 }
 
-// Untyped constant propagation translates to warning rather than a real check.
-// Otherwise once would have to implement builtin types evaluation which is
-// a runtime aspect of the analysis, not the static one.
-// Only the syntax checks will get carried.
-// Maybe, extend each warning with a Go code snippet that can be run so the user
-// can check if an untyped constant would fit a types variable.
-
-func IsXCompatibleWithY(xDataType, yDataType gotypes.DataType) (bool, error) {
-	// Two data type are compatible when they are identical
-	if reflect.DeepEqual(xDataType, yDataType) {
-		return true, nil
+func (c *Config) DataTypesEqual(x, y gotypes.DataType) bool {
+	if x == nil || y == nil {
+		return x == nil && y == nil
+	}
+	x, y = c.resolve(x), c.resolve(y)
+	if x.GetType() != y.GetType() {
+		return false
 	}
 
-	// Untyped vs typed builtins
-	if xDataType.GetType() == gotypes.BuiltinType && yDataType.GetType() == gotypes.BuiltinType {
-		xBuiltin := xDataType.(*gotypes.Builtin)
-		yBuiltin := yDataType.(*gotypes.Builtin)
-		// Check all the categories:
-		// - int
-		// - float
-		// - complex
-		// ...
-		if xBuiltin.Def == "float" && xBuiltin.Untyped {
-
+	switch xDef := x.(type) {
+	case *gotypes.Identifier:
+		yDef := y.(*gotypes.Identifier)
+		return xDef.Package == yDef.Package && xDef.Def == yDef.Def
+	case *gotypes.Pointer:
+		return c.DataTypesEqual(xDef.Def, y.(*gotypes.Pointer).Def)
+	case *gotypes.Slice:
+		return c.DataTypesEqual(xDef.Elmtype, y.(*gotypes.Slice).Elmtype)
+	case *gotypes.Ellipsis:
+		return c.DataTypesEqual(xDef.Def, y.(*gotypes.Ellipsis).Def)
+	case *gotypes.Array:
+		yDef := y.(*gotypes.Array)
+		return xDef.Len == yDef.Len && c.DataTypesEqual(xDef.Elmtype, yDef.Elmtype)
+	case *gotypes.Map:
+		yDef := y.(*gotypes.Map)
+		return c.DataTypesEqual(xDef.Keytype, yDef.Keytype) && c.DataTypesEqual(xDef.Valuetype, yDef.Valuetype)
+	case *gotypes.Channel:
+		yDef := y.(*gotypes.Channel)
+		return xDef.Dir == yDef.Dir && c.DataTypesEqual(xDef.Value, yDef.Value)
+	case *gotypes.Function:
+		return c.FunctionSignaturesEqual(xDef, y.(*gotypes.Function))
+	case *gotypes.Struct:
+		yDef := y.(*gotypes.Struct)
+		if len(xDef.Fields) != len(yDef.Fields) {
+			return false
 		}
-		if xBuiltin.Def == yBuiltin.Def {
-			return true, nil
+		for i := range xDef.Fields {
+			xField, yField := xDef.Fields[i], yDef.Fields[i]
+			if xField.Name != yField.Name || xField.Embedded != yField.Embedded || xField.Tag != yField.Tag {
+				return false
+			}
+			if !c.DataTypesEqual(xField.Def, yField.Def) {
+				return false
+			}
+		}
+		return true
+	case *gotypes.Interface:
+		equal, err := c.InterfacesEqual(xDef, y.(*gotypes.Interface))
+		return err == nil && equal
+	case *gotypes.Typeparam:
+		return xDef.Name == y.(*gotypes.Typeparam).Name
+	case *gotypes.Instance:
+		yDef := y.(*gotypes.Instance)
+		if len(xDef.Args) != len(yDef.Args) || !c.DataTypesEqual(xDef.Def, yDef.Def) {
+			return false
 		}
-		return false, fmt.Errorf("Two different builtin types: %v != %v", xBuiltin.Def, yBuiltin.Def)
+		for i := range xDef.Args {
+			if !c.DataTypesEqual(xDef.Args[i], yDef.Args[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(x, y)
 	}
-
-	fmt.Printf("X: %#v\n", xDataType)
-	fmt.Printf("Y: %#v\n", yDataType)
-
-	panic("IsCompatible NYI")
 }
@@ -0,0 +1,229 @@
+package compatibility_test
+
+import (
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/analyzers/type/compatibility"
+	"github.com/gofed/symbols-extractor/pkg/symbols"
+	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+func ident(pkg, name string) *gotypes.Identifier {
+	return &gotypes.Identifier{Package: pkg, Def: name}
+}
+
+func function(pkg string, results ...gotypes.DataType) *gotypes.Function {
+	return &gotypes.Function{Package: pkg, Results: results}
+}
+
+var (
+	intType    = ident("builtin", "int")
+	stringType = ident("builtin", "string")
+)
+
+// newConfig creates a checker over the following packages:
+//
+//	package p
+//
+//	type I interface{ M() int }
+//	type J interface{ I; N() string }
+//	type K interface{ m() }
+//
+//	type T struct{}
+//	func (*T) M() int
+//	func (*T) N() string
+//
+//	type V struct{}
+//	func (V) M() int
+//	func (V) m()
+//
+//	type W struct{}
+//	func (W) M() int
+//
+//	type S struct{}
+//	func (S) M() string
+//
+//	type E struct{ *T }
+//	type D struct{ V }
+//	type A struct{ V; W }
+//
+//	package q
+//
+//	type U struct{}
+//	func (U) M() int
+//	func (U) m()
+func newConfig(t *testing.T) (*compatibility.Config, *accessors.Accessor) {
+	builtin := tables.NewTable()
+	for _, name := range []string{"int", "string"} {
+		if err := builtin.AddDataType(&symbols.SymbolDef{Name: name, Package: "builtin", Def: ident("builtin", name)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := tables.NewTable()
+	dataTypes := map[string]gotypes.DataType{
+		"I": &gotypes.Interface{Methods: []gotypes.InterfaceMethodsItem{
+			{Name: "M", Def: function("p", intType)},
+		}},
+		"J": &gotypes.Interface{Methods: []gotypes.InterfaceMethodsItem{
+			{Name: "", Def: ident("p", "I")},
+			{Name: "N", Def: function("p", stringType)},
+		}},
+		"K": &gotypes.Interface{Methods: []gotypes.InterfaceMethodsItem{
+			{Name: "m", Def: function("p")},
+		}},
+		"T": &gotypes.Struct{},
+		"V": &gotypes.Struct{},
+		"W": &gotypes.Struct{},
+		"S": &gotypes.Struct{},
+		"E": &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+			{Name: "T", Embedded: true, Def: &gotypes.Pointer{Def: ident("p", "T")}},
+		}},
+		"D": &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+			{Name: "V", Embedded: true, Def: ident("p", "V")},
+		}},
+		"A": &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+			{Name: "V", Embedded: true, Def: ident("p", "V")},
+			{Name: "W", Embedded: true, Order: 1, Def: ident("p", "W")},
+		}},
+	}
+	for name, def := range dataTypes {
+		if err := p.AddDataType(&symbols.SymbolDef{Name: name, Package: "p", Def: def}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	methods := []struct {
+		name     string
+		receiver gotypes.DataType
+		def      *gotypes.Function
+	}{
+		{"M", &gotypes.Pointer{Def: ident("p", "T")}, function("p", intType)},
+		{"N", &gotypes.Pointer{Def: ident("p", "T")}, function("p", stringType)},
+		{"M", ident("p", "V"), function("p", intType)},
+		{"m", ident("p", "V"), function("p")},
+		{"M", ident("p", "W"), function("p", intType)},
+		{"M", ident("p", "S"), function("p", stringType)},
+	}
+	for _, m := range methods {
+		if err := p.AddFunction(&symbols.SymbolDef{Name: m.name, Package: "p", Def: &gotypes.Method{Def: m.def, Receiver: m.receiver}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q := tables.NewTable()
+	if err := q.AddDataType(&symbols.SymbolDef{Name: "U", Package: "q", Def: &gotypes.Struct{}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []struct {
+		name string
+		def  *gotypes.Function
+	}{
+		{"M", function("q", intType)},
+		{"m", function("q")},
+	} {
+		if err := q.AddFunction(&symbols.SymbolDef{Name: m.name, Package: "q", Def: &gotypes.Method{Def: m.def, Receiver: ident("q", "U")}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	globalTable := global.New(t.TempDir(), "", nil)
+	globalTable.AddTransient("builtin", builtin)
+	globalTable.AddTransient("p", p)
+	globalTable.AddTransient("q", q)
+	accessor := accessors.NewAccessor(globalTable)
+	return compatibility.New(accessor), accessor
+}
+
+func lookupInterface(t *testing.T, accessor *accessors.Accessor, name string) *gotypes.Interface {
+	def, _, err := accessor.LookupDataType(ident("p", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return def.Def.(*gotypes.Interface)
+}
+
+func TestTypeImplementsInterface(t *testing.T) {
+	c, accessor := newConfig(t)
+	tests := []struct {
+		name       string
+		dataType   gotypes.DataType
+		iface      string
+		implements bool
+	}{
+		{"pointer receivers", &gotypes.Pointer{Def: ident("p", "T")}, "I", true},
+		{"pointer receivers of a value", ident("p", "T"), "I", false},
+		{"embedded interface", &gotypes.Pointer{Def: ident("p", "T")}, "J", true},
+		{"promoted from an embedded pointer", ident("p", "E"), "J", true},
+		{"value receivers", ident("p", "V"), "I", true},
+		{"value receivers of a pointer", &gotypes.Pointer{Def: ident("p", "V")}, "I", true},
+		{"promoted from an embedded value", ident("p", "D"), "I", true},
+		{"unexported method promoted", ident("p", "D"), "K", true},
+		{"different signature", ident("p", "S"), "I", false},
+		{"ambiguous promoted methods", ident("p", "A"), "I", false},
+		{"unexported method of another package", ident("q", "U"), "K", false},
+		{"exported method of another package", ident("q", "U"), "I", true},
+		{"interface", ident("p", "J"), "I", true},
+		{"builtin", intType, "I", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			implements, err := c.TypeImplementsInterface(test.dataType, lookupInterface(t, accessor, test.iface))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if implements != test.implements {
+				t.Errorf("Expected %v, got %v", test.implements, implements)
+			}
+		})
+	}
+}
+
+func TestInterfacesEqual(t *testing.T) {
+	c, accessor := newConfig(t)
+	tests := []struct {
+		name  string
+		x     *gotypes.Interface
+		y     *gotypes.Interface
+		equal bool
+	}{
+		{
+			name: "embedded interface",
+			x:    lookupInterface(t, accessor, "J"),
+			y: &gotypes.Interface{Methods: []gotypes.InterfaceMethodsItem{
+				{Name: "N", Def: function("r", stringType)},
+				{Name: "M", Def: function("r", intType)},
+			}},
+			equal: true,
+		},
+		{
+			name:  "different methods",
+			x:     lookupInterface(t, accessor, "I"),
+			y:     lookupInterface(t, accessor, "J"),
+			equal: false,
+		},
+		{
+			name: "unexported methods of different packages",
+			x:    lookupInterface(t, accessor, "K"),
+			y: &gotypes.Interface{Methods: []gotypes.InterfaceMethodsItem{
+				{Name: "m", Def: function("q")},
+			}},
+			equal: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			equal, err := c.InterfacesEqual(test.x, test.y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if equal != test.equal {
+				t.Errorf("Expected %v, got %v", test.equal, equal)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package propagation
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
@@ -283,7 +284,7 @@ func (c *Config) getOperandType(x gotypes.DataType) (*opr, error) {
 		b := underlyingType.Def.(*gotypes.Identifier)
 		oType.pkg, oType.id = b.Package, b.Def
 	default:
-		panic(fmt.Errorf("Unrecognized underlying TTTType: %#v", underlyingType))
+		return nil, fmt.Errorf("Unrecognized underlying type %#v", underlyingType)
 	}
 
 	if oType.pkg == "builtin" {
@@ -1108,7 +1109,7 @@ func (c *Config) binaryExprStructs(exprOp token.Token, xType, yType *opr, xDataT
 	}
 	// at least one is anonymous => structs must have the same fields of the same type
 
-	panic("binaryExprStructs: NYI")
+	return nil, fmt.Errorf("binaryExprStructs: comparison of anonymous structs %#v and %#v not supported", xDataType, yDataType)
 }
 
 func (c *Config) binaryExprInterfaces(exprOp token.Token, xType, yType *opr, xDataType, yDataType gotypes.DataType) (gotypes.DataType, error) {
@@ -1148,7 +1149,11 @@ func (c *Config) binaryExprInterfaces(exprOp token.Token, xType, yType *opr, xDa
 			}
 
 			if len(i1.Methods) != 0 && len(i2.Methods) != 0 {
-				if !compatibility.InterfacesEqual(i1, i2) {
+				equal, err := compatibility.New(c.symbolsAccessor).InterfacesEqual(i1, i2)
+				if err != nil {
+					return nil, err
+				}
+				if !equal {
 					return nil, fmt.Errorf("mismatched interface types %v.%v %v %v.%v", xType.underlyingType.Package, xType.underlyingType.Id, exprOp, yType.underlyingType.Package, yType.underlyingType.Id)
 				}
 			}
@@ -1166,7 +1171,73 @@ func (c *Config) binaryExprInterfaces(exprOp token.Token, xType, yType *opr, xDa
 	return &gotypes.Builtin{Def: "bool", Untyped: true}, nil
 }
 
+// typeparamOperand returns a core type of an operand of a type parameter type.
+// Nil if the type set of the type parameter has no core type (e.g. [T cmp.Ordered])
+// or the constraint does not restrict the type set (e.g. [T comparable]).
+func (c *Config) typeparamOperand(x gotypes.DataType) (gotypes.DataType, error) {
+	typeParam, ok := x.(*gotypes.Typeparam)
+	if !ok {
+		return x, nil
+	}
+	core, err := c.symbolsAccessor.TypeparamCoreType(typeParam)
+	if err != nil {
+		if errors.Is(err, accessors.ErrNoCoreType) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	isInterface, err := c.symbolsAccessor.IsDataTypeInterface(core)
+	if err != nil {
+		return nil, err
+	}
+	if isInterface {
+		return nil, nil
+	}
+	return core, nil
+}
+
+// binaryExprTypeparams resolves operands of a type parameter type through
+// the core type of its constraint, e.g. x + y over [T ~int] is an integer operation.
+// Without the core type, the operation is valid for all types of the type set.
+// Comparison yields a boolean, any other operation the type parameter.
+func (c *Config) binaryExprTypeparams(exprOp token.Token, xDataType, yDataType gotypes.DataType) (gotypes.DataType, error) {
+	zDataType := xDataType
+	if _, ok := xDataType.(*gotypes.Typeparam); !ok && exprOp != token.SHL && exprOp != token.SHR {
+		zDataType = yDataType
+	}
+
+	xCore, err := c.typeparamOperand(xDataType)
+	if err != nil {
+		return nil, err
+	}
+	yCore, err := c.typeparamOperand(yDataType)
+	if err != nil {
+		return nil, err
+	}
+
+	if xCore != nil && yCore != nil {
+		dt, err := c.BinaryExpr(exprOp, xCore, yCore)
+		if err != nil {
+			return nil, err
+		}
+		switch exprOp {
+		case token.EQL, token.NEQ, token.LEQ, token.LSS, token.GEQ, token.GTR:
+			return dt, nil
+		}
+		return zDataType, nil
+	}
+
+	switch exprOp {
+	case token.EQL, token.NEQ, token.LEQ, token.LSS, token.GEQ, token.GTR:
+		return &gotypes.Builtin{Def: "bool", Untyped: true}, nil
+	}
+	return zDataType, nil
+}
+
 func (c *Config) BinaryExpr(exprOp token.Token, xDataType, yDataType gotypes.DataType) (gotypes.DataType, error) {
+	if xDataType.GetType() == gotypes.TypeparamType || yDataType.GetType() == gotypes.TypeparamType {
+		return c.binaryExprTypeparams(exprOp, xDataType, yDataType)
+	}
 
 	xType, xE := c.getOperandType(xDataType)
 	if xE != nil {
@@ -1405,7 +1476,6 @@ func (c *Config) UnaryExpr(exprOp token.Token, xDataType gotypes.DataType) (goty
 				o := ^uintptr(p)
 				yDataType = &gotypes.Constant{Package: constant.Package, Def: constant.Def, Literal: fmt.Sprintf("%v", o), Untyped: constant.Untyped}
 			default:
-				panic(fmt.Errorf("%v underlying type not recognized", constant.Def))
 				return nil, fmt.Errorf("%v underlying type not recognized", constant.Def)
 			}
 
@@ -1483,6 +1553,8 @@ func (c *Config) SelectorExpr(xDataType gotypes.DataType, item string) (*accesso
 			return c.symbolsAccessor.RetrieveDataTypeField(
 				accessors.NewFieldAccessor(currentSt, &symbols.SymbolDef{Def: def}, &ast.Ident{Name: item}),
 			)
+		case *gotypes.Instance:
+			return c.instanceSelectorExpr(def, item)
 		default:
 			return nil, fmt.Errorf("Trying to retrieve a %q field from a pointer to non-struct data type: %#v", item, xType.Def)
 		}
@@ -1563,11 +1635,45 @@ func (c *Config) SelectorExpr(xDataType gotypes.DataType, item string) (*accesso
 		return c.symbolsAccessor.RetrieveDataTypeField(
 			accessors.NewFieldAccessor(table, def, &ast.Ident{Name: item}).SetMethodsOnly(),
 		)
+	case *gotypes.Instance:
+		return c.instanceSelectorExpr(xType, item)
+	case *gotypes.Typeparam:
+		// Methods are declared by the constraint, fields are common to all types of the type set
+		if xType.Constraint != nil {
+			if attr, err := c.SelectorExpr(xType.Constraint, item); err == nil {
+				return attr, nil
+			}
+		}
+		core, err := c.symbolsAccessor.TypeparamCoreType(xType)
+		if err != nil {
+			return nil, err
+		}
+		return c.SelectorExpr(core, item)
 	default:
 		return nil, fmt.Errorf("Trying to retrieve a %q field from a non-struct data type when parsing selector expression %#v", item, xDataType)
 	}
 }
 
+// instanceSelectorExpr retrieves a field/method of an instance of a generic data type.
+// Type parameters of the field/method are substituted by the instance's type arguments.
+func (c *Config) instanceSelectorExpr(inst *gotypes.Instance, item string) (*accessors.FieldAttribute, error) {
+	attr, err := c.SelectorExpr(inst.Def, item)
+	if err != nil {
+		return nil, err
+	}
+	// A method receiver can rename type parameters of its data type
+	if method, ok := attr.DataType.(*gotypes.Method); ok {
+		attr.DataType = gotypes.Substitute(method, method.Typeparams, inst.Args)
+		return attr, nil
+	}
+	symbolDef, err := c.symbolsAccessor.LookupGenericDataType(inst.Def)
+	if err != nil {
+		return nil, err
+	}
+	attr.DataType = gotypes.Substitute(attr.DataType, symbolDef.Typeparams, inst.Args)
+	return attr, nil
+}
+
 func (c *Config) MakKeyExpr(xDataType gotypes.DataType) (gotypes.DataType, error) {
 	// One can not do &(&(a))
 	var indexExpr gotypes.DataType
@@ -1643,6 +1749,15 @@ func (c *Config) IndexExpr(xDataType, idxDataType gotypes.DataType) (gotypes.Dat
 			break
 		}
 	}
+	// Instance of a generic data type, resp. a type parameter
+	switch indexExpr.(type) {
+	case *gotypes.Instance, *gotypes.Typeparam:
+		def, err := c.symbolsAccessor.FindFirstNonidDataType(indexExpr)
+		if err != nil {
+			return nil, "", err
+		}
+		indexExpr = def
+	}
 	// TODO(jchaloup): check idxDataType as well, .e.g. it is an integer type when accessing slice, array or string
 
 	// Get definition of the X from the symbol Table (it must be a variable of a data type)
@@ -1676,7 +1791,7 @@ func (c *Config) IndexExpr(xDataType, idxDataType gotypes.DataType) (gotypes.Dat
 	case *gotypes.Ellipsis:
 		return xType.Def, indexExpr.GetType(), nil
 	default:
-		panic(fmt.Errorf("Unrecognized indexExpr type: %#v", xDataType))
+		return nil, "", fmt.Errorf("Unrecognized indexExpr type: %#v", xDataType)
 	}
 }
 
@@ -1740,6 +1855,11 @@ func (c *Config) RangeExpr(xDataType gotypes.DataType) (gotypes.DataType, gotype
 func (c *Config) TypecastExpr(xDataType, tDataType gotypes.DataType) (gotypes.DataType, error) {
 	klog.V(2).Infof("TypecastExpr, xDataType: %#v, tDataType: %#v", xDataType, tDataType)
 
+	// valid for all types of the type set, no more a constant
+	if tDataType.GetType() == gotypes.TypeparamType {
+		return tDataType, nil
+	}
+
 	if constant, ok := xDataType.(*gotypes.Constant); ok {
 		// if the tDataType is interface => no constant
 		klog.V(2).Infof("Checking if IsDataTypeInterface(%#v)", tDataType)
@@ -1844,8 +1964,7 @@ func (c *Config) BuiltinFunctionInvocation(name string, arguments []gotypes.Data
 			return nil, fmt.Errorf("imag argument is not a single expression")
 		}
 		if constant, ok := arguments[0].(*gotypes.Constant); ok {
-			fmt.Printf("Constant: %#v\n", constant)
-			panic("CCC")
+			return nil, fmt.Errorf("%v of constant %v not supported", name, constant.Literal)
 		}
 		// variable => accepting only complex64 or complex128
 		xType, xE := c.getOperandType(arguments[0])
@@ -1950,7 +2069,13 @@ func (c *Config) BuiltinFunctionInvocation(name string, arguments []gotypes.Data
 				return nil, err
 			}
 			return []gotypes.DataType{
-				&gotypes.Constant{Package: "builtin", Def: targetType, Literal: lit, Untyped: true},
+				&gotypes.Constant{
+					Package: "builtin",
+					Def:     targetType,
+					Literal: lit,
+					Untyped: true,
+					Value:   complexValue(arguments[0].(*gotypes.Constant), arguments[1].(*gotypes.Constant)),
+				},
 			}, nil
 		}
 		// at least one operand is a variable
@@ -2019,5 +2144,5 @@ func (c *Config) BuiltinFunctionInvocation(name string, arguments []gotypes.Data
 			&gotypes.Identifier{Package: "builtin", Def: targetType},
 		}, nil
 	}
-	panic("PPPP")
+	return nil, fmt.Errorf("Unrecognized builtin function %v", name)
 }
@@ -0,0 +1,292 @@
+package propagation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+// InferTypeArguments infers type arguments of a generic function from data types
+// of arguments the function is invoked with (function argument type inference),
+// resp. from core types of constraints of already inferred type parameters
+// (constraint type inference). The function with inferred type parameters substituted is returned.
+// E.g. given 'func Map[S ~[]E, E, R any](s S, f func(E) R) []R' invoked
+// as Map(names, strings.ToUpper) with names of type []string, E = string and R = string.
+// Untyped constants are considered last, converted to their default type.
+// Type parameters that can not be inferred are kept.
+func (c *Config) InferTypeArguments(function *gotypes.Function, args []gotypes.DataType, spread bool) (*gotypes.Function, error) {
+	if len(function.Typeparams) == 0 {
+		return function, nil
+	}
+	typeParams := make(map[string]bool)
+	for _, item := range function.Typeparams {
+		if typeParam, ok := item.(*gotypes.Typeparam); ok {
+			typeParams[typeParam.Name] = true
+		}
+	}
+	mapping := make(map[string]gotypes.DataType)
+
+	// param of each argument
+	paramOf := func(i int) gotypes.DataType {
+		if i < len(function.Params) {
+			if ellipsis, ok := function.Params[i].(*gotypes.Ellipsis); ok && i == len(function.Params)-1 {
+				if spread {
+					return &gotypes.Slice{Elmtype: ellipsis.Def}
+				}
+				return ellipsis.Def
+			}
+			return function.Params[i]
+		}
+		if len(function.Params) > 0 {
+			if ellipsis, ok := function.Params[len(function.Params)-1].(*gotypes.Ellipsis); ok {
+				return ellipsis.Def
+			}
+		}
+		return nil
+	}
+
+	// typed arguments first
+	for i, arg := range args {
+		param := paramOf(i)
+		if param == nil || isUntyped(arg) {
+			continue
+		}
+		if err := c.unify(param, typedDataType(arg), typeParams, mapping); err != nil {
+			return nil, err
+		}
+	}
+	// untyped constants of the remaining type parameters
+	for i, arg := range args {
+		typeParam, ok := paramOf(i).(*gotypes.Typeparam)
+		if !ok || !typeParams[typeParam.Name] || !isUntyped(arg) {
+			continue
+		}
+		if _, ok := mapping[typeParam.Name]; ok {
+			continue
+		}
+		if _, ok := arg.(*gotypes.Nil); ok {
+			continue
+		}
+		mapping[typeParam.Name] = typedDataType(arg)
+	}
+
+	if len(mapping) < len(typeParams) {
+		if err := c.inferFromConstraints(function, typeParams, mapping); err != nil {
+			return nil, err
+		}
+	}
+
+	var params, inferred []gotypes.DataType
+	for _, item := range function.Typeparams {
+		typeParam, ok := item.(*gotypes.Typeparam)
+		if !ok {
+			continue
+		}
+		if arg, ok := mapping[typeParam.Name]; ok {
+			params = append(params, typeParam)
+			inferred = append(inferred, arg)
+		}
+	}
+	return gotypes.Substitute(function, params, inferred).(*gotypes.Function), nil
+}
+
+// inferFromConstraints unifies core types of constraints with type arguments, e.g. S = []int, S ~[]E => E = int.
+// Unless inferred, a type parameter gets its core type with known type arguments substituted.
+func (c *Config) inferFromConstraints(function *gotypes.Function, typeParams map[string]bool, mapping map[string]gotypes.DataType) error {
+	for {
+		inferred := len(mapping)
+		for _, item := range function.Typeparams {
+			typeParam, ok := item.(*gotypes.Typeparam)
+			if !ok || typeParam.Constraint == nil {
+				continue
+			}
+			core, err := c.symbolsAccessor.TypeparamCoreType(typeParam)
+			if err != nil {
+				if errors.Is(err, accessors.ErrNoCoreType) {
+					continue
+				}
+				return err
+			}
+			if core == typeParam.Constraint {
+				// the constraint does not restrict the type set by a union
+				continue
+			}
+			if arg, ok := mapping[typeParam.Name]; ok {
+				if err := c.unify(core, arg, typeParams, mapping); err != nil {
+					return err
+				}
+				continue
+			}
+			if !hasTypeparams(core, typeParams, mapping) {
+				mapping[typeParam.Name] = gotypes.Substitute(core, function.Typeparams, typeArgs(function.Typeparams, mapping))
+			}
+		}
+		if len(mapping) == inferred {
+			return nil
+		}
+	}
+}
+
+// unify matches a parameter with an argument and collects type arguments of the parameter's type parameters
+func (c *Config) unify(param, arg gotypes.DataType, typeParams map[string]bool, mapping map[string]gotypes.DataType) error {
+	switch p := param.(type) {
+	case *gotypes.Typeparam:
+		if !typeParams[p.Name] {
+			return nil
+		}
+		if _, ok := mapping[p.Name]; !ok {
+			mapping[p.Name] = arg
+		}
+		return nil
+	case *gotypes.Instance:
+		a, ok := arg.(*gotypes.Instance)
+		if !ok || !reflect.DeepEqual(a.Def, p.Def) || len(a.Args) != len(p.Args) {
+			return nil
+		}
+		for i := range p.Args {
+			if err := c.unify(p.Args[i], a.Args[i], typeParams, mapping); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *gotypes.Pointer, *gotypes.Slice, *gotypes.Array, *gotypes.Map, *gotypes.Channel, *gotypes.Ellipsis, *gotypes.Function:
+	default:
+		// no type parameters
+		return nil
+	}
+
+	// a named type (e.g. type Names []string) is matched by its underlying type
+	if param.GetType() != arg.GetType() {
+		switch arg.(type) {
+		case *gotypes.Identifier, *gotypes.Selector, *gotypes.Instance:
+			def, err := c.symbolsAccessor.FindFirstNonidDataType(arg)
+			if err != nil {
+				return err
+			}
+			arg = def
+		}
+	}
+
+	var pairs [][2]gotypes.DataType
+	switch p := param.(type) {
+	case *gotypes.Pointer:
+		if a, ok := arg.(*gotypes.Pointer); ok {
+			pairs = append(pairs, [2]gotypes.DataType{p.Def, a.Def})
+		}
+	case *gotypes.Slice:
+		switch a := arg.(type) {
+		case *gotypes.Slice:
+			pairs = append(pairs, [2]gotypes.DataType{p.Elmtype, a.Elmtype})
+		case *gotypes.Ellipsis:
+			pairs = append(pairs, [2]gotypes.DataType{p.Elmtype, a.Def})
+		}
+	case *gotypes.Ellipsis:
+		switch a := arg.(type) {
+		case *gotypes.Slice:
+			pairs = append(pairs, [2]gotypes.DataType{p.Def, a.Elmtype})
+		case *gotypes.Ellipsis:
+			pairs = append(pairs, [2]gotypes.DataType{p.Def, a.Def})
+		}
+	case *gotypes.Array:
+		if a, ok := arg.(*gotypes.Array); ok {
+			pairs = append(pairs, [2]gotypes.DataType{p.Elmtype, a.Elmtype})
+		}
+	case *gotypes.Map:
+		if a, ok := arg.(*gotypes.Map); ok {
+			pairs = append(pairs, [2]gotypes.DataType{p.Keytype, a.Keytype}, [2]gotypes.DataType{p.Valuetype, a.Valuetype})
+		}
+	case *gotypes.Channel:
+		if a, ok := arg.(*gotypes.Channel); ok {
+			pairs = append(pairs, [2]gotypes.DataType{p.Value, a.Value})
+		}
+	case *gotypes.Function:
+		a, ok := arg.(*gotypes.Function)
+		if !ok {
+			if method, isMethod := arg.(*gotypes.Method); isMethod {
+				a, ok = method.Def.(*gotypes.Function)
+			}
+		}
+		if !ok {
+			break
+		}
+		if len(a.Params) != len(p.Params) || len(a.Results) != len(p.Results) {
+			return fmt.Errorf("Function argument %#v does not match signature of parameter %#v", a, p)
+		}
+		for i := range p.Params {
+			pairs = append(pairs, [2]gotypes.DataType{p.Params[i], a.Params[i]})
+		}
+		for i := range p.Results {
+			pairs = append(pairs, [2]gotypes.DataType{p.Results[i], a.Results[i]})
+		}
+	}
+
+	for _, pair := range pairs {
+		if err := c.unify(pair[0], pair[1], typeParams, mapping); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasTypeparams checks if a data type refers to any type parameter not yet inferred
+func hasTypeparams(def gotypes.DataType, typeParams map[string]bool, mapping map[string]gotypes.DataType) bool {
+	var params []gotypes.DataType
+	for name := range typeParams {
+		if _, ok := mapping[name]; !ok {
+			params = append(params, &gotypes.Typeparam{Name: name})
+		}
+	}
+	if len(params) == 0 {
+		return false
+	}
+	// substitution of the type parameters not inferred changes the data type
+	marker := &gotypes.Nil{}
+	args := make([]gotypes.DataType, len(params))
+	for i := range args {
+		args[i] = marker
+	}
+	return !reflect.DeepEqual(gotypes.Substitute(def, params, args), def)
+}
+
+func typeArgs(typeParams []gotypes.DataType, mapping map[string]gotypes.DataType) []gotypes.DataType {
+	args := make([]gotypes.DataType, 0, len(typeParams))
+	for _, item := range typeParams {
+		typeParam, ok := item.(*gotypes.Typeparam)
+		if !ok {
+			args = append(args, item)
+			continue
+		}
+		if arg, ok := mapping[typeParam.Name]; ok {
+			args = append(args, arg)
+			continue
+		}
+		args = append(args, typeParam)
+	}
+	return args
+}
+
+func isUntyped(def gotypes.DataType) bool {
+	switch d := def.(type) {
+	case *gotypes.Constant:
+		return d.Untyped
+	case *gotypes.Builtin:
+		return d.Untyped
+	case *gotypes.Nil:
+		return true
+	}
+	return false
+}
+
+// typedDataType turns a (typed or untyped) constant into a data type of its type
+func typedDataType(def gotypes.DataType) gotypes.DataType {
+	switch d := def.(type) {
+	case *gotypes.Constant:
+		return &gotypes.Identifier{Package: d.Package, Def: d.Def}
+	case *gotypes.Builtin:
+		return &gotypes.Identifier{Package: "builtin", Def: d.Def}
+	}
+	return def
+}
@@ -135,7 +135,8 @@ func (m *MultiArith) PerformUnary(op token.Token) *MultiArith {
 		}
 		return m
 	default:
-		panic(fmt.Sprintf("MultiArith.Perform NYI for %v op", op))
+		m.err = fmt.Errorf("MultiArith.PerformUnary NYI for %v op", op)
+		return m
 	}
 }
 
@@ -263,8 +264,11 @@ func (m *MultiArith) Perform(op token.Token) *MultiArith {
 			m.err = fmt.Errorf("%v is not uint", m.Y.String())
 			return m
 		}
-		// x >> y = x/2^y
-		z := (*m.X).Div(decimal.NewFromFloat(2).Pow(*m.Y)).Floor()
+		// x >> y = floor(x/2^y), Div rounds to DivisionPrecision digits
+		z, r := (*m.X).QuoRem(decimal.NewFromFloat(2).Pow(*m.Y), 0)
+		if r.Sign() < 0 {
+			z = z.Sub(decimal.NewFromFloat(1))
+		}
 		m.Z = &z
 	case token.EQL:
 		m.zBool = (*m.X).Equal(*m.Y)
@@ -312,7 +316,7 @@ func (m *MultiArith) Perform(op token.Token) *MultiArith {
 		z, _ := decimal.NewFromString(zI.String())
 		m.Z = &z
 	default:
-		panic(fmt.Sprintf("MultiArith.Perform NYI for %v op", op))
+		m.err = fmt.Errorf("MultiArith.Perform NYI for %v op", op)
 	}
 	return m
 }
@@ -366,7 +370,6 @@ func checkIntegerRanges(x *decimal.Decimal, targetType string) error {
 		}
 		return nil
 	default:
-		panic(fmt.Errorf("%v type not recognized", targetType))
 		return fmt.Errorf("%v type not recognized", targetType)
 	}
 	if (*x).Cmp(top) > 0 {
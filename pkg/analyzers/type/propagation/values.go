@@ -0,0 +1,252 @@
+package propagation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"math/big"
+	"strings"
+
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+// Constant values are evaluated with arbitrary precision (following the go/constant semantics)
+// and stored in their exact form (e.g. 1/3 instead of 0.333...).
+// An empty value means the value is not known (e.g. unsafe.Sizeof, or a constant
+// of older artefacts), every expression with such an operand is not evaluated either.
+
+// LiteralValue evaluates a basic literal (all literal forms including
+// octal, binary and hexadecimal numbers, hexadecimal floats, digit separators and escaped runes)
+func LiteralValue(lit *ast.BasicLit) constant.Value {
+	return constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+}
+
+// ValueString turns a value into its exact (stored) form.
+// Integral floats are stored as integers (e.g. 2.0 as 2) so they are parsed back as Int values.
+// Their kind is given by the constant type once the value is used (see represent).
+func ValueString(v constant.Value) string {
+	if v.Kind() == constant.Unknown {
+		return ""
+	}
+	return v.ExactString()
+}
+
+// ParseValue parses a value stored in its exact form.
+// If the value is empty or not recognized, unknown value is returned.
+func ParseValue(value string) constant.Value {
+	switch {
+	case value == "":
+		return constant.MakeUnknown()
+	case value == "true" || value == "false":
+		return constant.MakeBool(value == "true")
+	case value[0] == '"':
+		return constant.MakeFromLiteral(value, token.STRING, 0)
+	case value[0] == '(' && strings.HasSuffix(value, "i)"):
+		// complex number in (<real> + <imag>i) form
+		parts := strings.SplitN(value[1:len(value)-2], " + ", 2)
+		if len(parts) != 2 {
+			return constant.MakeUnknown()
+		}
+		re, im := ParseValue(parts[0]), ParseValue(parts[1])
+		if re.Kind() == constant.Unknown || im.Kind() == constant.Unknown {
+			return constant.MakeUnknown()
+		}
+		return constant.BinaryOp(re, token.ADD, constant.MakeImag(im))
+	case strings.Contains(value, "/"):
+		// rational number in <numerator>/<denominator> form
+		parts := strings.SplitN(value, "/", 2)
+		num, denom := ParseValue(parts[0]), ParseValue(parts[1])
+		if num.Kind() != constant.Int || denom.Kind() != constant.Int || constant.Sign(denom) == 0 {
+			return constant.MakeUnknown()
+		}
+		return constant.BinaryOp(num, token.QUO, denom)
+	}
+	lit := value
+	sign := ""
+	if lit[0] == '-' {
+		sign, lit = "-", lit[1:]
+	}
+	v := constant.MakeFromLiteral(lit, token.INT, 0)
+	if v.Kind() == constant.Unknown {
+		v = constant.MakeFromLiteral(lit, token.FLOAT, 0)
+	}
+	if sign != "" {
+		return constant.UnaryOp(token.SUB, v, 0)
+	}
+	return v
+}
+
+// ValueToLiteral turns a numeric value into a literal understood by the MultiArith
+// (a decimal number, or <real>,<imag>i for complex numbers)
+func ValueToLiteral(v constant.Value) string {
+	switch v.Kind() {
+	case constant.Int:
+		return v.ExactString()
+	case constant.Float:
+		var f *big.Float
+		switch x := constant.Val(v).(type) {
+		case *big.Rat:
+			f = new(big.Float).SetPrec(512).SetRat(x)
+		case *big.Float:
+			f = x
+		default:
+			// int64 or *big.Int of an integral value
+			return constant.ToInt(v).ExactString()
+		}
+		return f.Text('g', -1)
+	case constant.Complex:
+		re, im := ValueToLiteral(constant.Real(v)), ValueToLiteral(constant.Imag(v))
+		if constant.Sign(constant.Real(v)) == 0 {
+			return im + "i"
+		}
+		return fmt.Sprintf("%v,%vi", re, im)
+	}
+	return v.ExactString()
+}
+
+// evaluate catches panics of the go/constant operations over invalid operands
+// (e.g. division by zero) so the value is reported as unknown instead
+func evaluate(f func() constant.Value) (v constant.Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			v = constant.MakeUnknown()
+		}
+	}()
+	return f()
+}
+
+// uintSize returns a number of bits of unsigned integral types (0 for other types)
+func uintSize(id string) uint {
+	switch id {
+	case "uint8":
+		return 8
+	case "uint16":
+		return 16
+	case "uint32":
+		return 32
+	case "uint", "uint64", "uintptr":
+		// TODO(jchaloup): the architecture must be input of the processing
+		return 64
+	}
+	return 0
+}
+
+// represent converts a value to the kind of a constant's (underlying) type
+func (c *Config) represent(v constant.Value, z *gotypes.Constant) constant.Value {
+	if v.Kind() == constant.Unknown {
+		return v
+	}
+	zType, err := c.getOperandType(z)
+	if err != nil {
+		return constant.MakeUnknown()
+	}
+	switch {
+	case c.isIntegral(zType):
+		v = constant.ToInt(v)
+	case c.isFloating(zType):
+		v = constant.ToFloat(v)
+	case c.isComplex(zType):
+		v = constant.ToComplex(v)
+	case zType.pkg == "builtin" && zType.id == "string":
+		if v.Kind() != constant.String {
+			return constant.MakeUnknown()
+		}
+	case zType.pkg == "builtin" && zType.id == "bool":
+		if v.Kind() != constant.Bool {
+			return constant.MakeUnknown()
+		}
+	}
+	return v
+}
+
+// UnaryValue evaluates a value of the z constant given as `op x`
+func (c *Config) UnaryValue(op token.Token, x, z *gotypes.Constant) string {
+	xv := ParseValue(x.Value)
+	if xv.Kind() == constant.Unknown {
+		return ""
+	}
+	zType, err := c.getOperandType(z)
+	if err != nil {
+		return ""
+	}
+	var prec uint
+	if c.isIntegral(zType) {
+		xv = constant.ToInt(xv)
+		if !zType.untyped {
+			// ^x of unsigned integers flips only the type size bits
+			prec = uintSize(zType.id)
+		}
+	}
+	return ValueString(c.represent(evaluate(func() constant.Value {
+		return constant.UnaryOp(op, xv, prec)
+	}), z))
+}
+
+// BinaryValue evaluates a value of the z constant given as `x op y`
+func (c *Config) BinaryValue(op token.Token, x, y, z *gotypes.Constant) string {
+	xv, yv := ParseValue(x.Value), ParseValue(y.Value)
+	if xv.Kind() == constant.Unknown || yv.Kind() == constant.Unknown {
+		return ""
+	}
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return ValueString(evaluate(func() constant.Value {
+			return constant.MakeBool(constant.Compare(xv, op, yv))
+		}))
+	case token.SHL, token.SHR:
+		s, ok := constant.Uint64Val(constant.ToInt(yv))
+		if !ok {
+			return ""
+		}
+		return ValueString(c.represent(evaluate(func() constant.Value {
+			return constant.Shift(constant.ToInt(xv), op, uint(s))
+		}), z))
+	}
+	zType, err := c.getOperandType(z)
+	if err != nil {
+		return ""
+	}
+	if c.isIntegral(zType) {
+		xv, yv = constant.ToInt(xv), constant.ToInt(yv)
+		// integer division truncates
+		if op == token.QUO {
+			op = token.QUO_ASSIGN
+		}
+	}
+	return ValueString(c.represent(evaluate(func() constant.Value {
+		return constant.BinaryOp(xv, op, yv)
+	}), z))
+}
+
+// ConvertValue evaluates a value of the z constant given as a conversion of the x constant
+func (c *Config) ConvertValue(x, z *gotypes.Constant) string {
+	xv := ParseValue(x.Value)
+	if xv.Kind() == constant.Unknown {
+		return ""
+	}
+	zType, err := c.getOperandType(z)
+	if err != nil {
+		return ""
+	}
+	// string(rune)
+	if zType.pkg == "builtin" && zType.id == "string" && xv.Kind() == constant.Int {
+		r, ok := constant.Int64Val(xv)
+		if !ok {
+			r = -1
+		}
+		return ValueString(constant.MakeString(string(rune(r))))
+	}
+	return ValueString(c.represent(xv, z))
+}
+
+// complexValue evaluates a value of complex(re, im) built-in function invocation
+func complexValue(re, im *gotypes.Constant) string {
+	rv, iv := ParseValue(re.Value), ParseValue(im.Value)
+	if rv.Kind() == constant.Unknown || iv.Kind() == constant.Unknown {
+		return ""
+	}
+	return ValueString(evaluate(func() constant.Value {
+		return constant.BinaryOp(constant.ToFloat(rv), token.ADD, constant.MakeImag(constant.ToFloat(iv)))
+	}))
+}
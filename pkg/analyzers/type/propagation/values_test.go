@@ -0,0 +1,257 @@
+package propagation
+
+import (
+	"go/constant"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/symbols"
+	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+// newValuesConfig creates a config with example.com/time.Duration (int64) and example.com/time.Mode (uint8) data types
+func newValuesConfig(t *testing.T) *Config {
+	table := tables.NewTable()
+	for name, underlying := range map[string]string{"Duration": "int64", "Mode": "uint8"} {
+		if err := table.AddDataType(&symbols.SymbolDef{
+			Name:    name,
+			Package: "example.com/time",
+			Def:     &gotypes.Identifier{Package: "builtin", Def: underlying},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gtable := global.New("", "", nil)
+	if err := gtable.Add("example.com/time", table, false); err != nil {
+		t.Fatal(err)
+	}
+	return New(accessors.NewAccessor(gtable))
+}
+
+// untyped, resp. typed constants of the given value
+func untyped(def, value string) *gotypes.Constant {
+	return &gotypes.Constant{Package: "builtin", Def: def, Untyped: true, Value: value}
+}
+
+func typed(pkg, def, value string) *gotypes.Constant {
+	return &gotypes.Constant{Package: pkg, Def: def, Value: value}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		value string
+		kind  constant.Kind
+		exact string
+	}{
+		{value: "", kind: constant.Unknown, exact: "unknown"},
+		{value: "true", kind: constant.Bool, exact: "true"},
+		{value: `"a\n\"b"`, kind: constant.String, exact: `"a\n\"b"`},
+		{value: "42", kind: constant.Int, exact: "42"},
+		{value: "-42", kind: constant.Int, exact: "-42"},
+		{value: "18446744073709551616", kind: constant.Int, exact: "18446744073709551616"},
+		{value: "1/3", kind: constant.Float, exact: "1/3"},
+		{value: "-1/4", kind: constant.Float, exact: "-1/4"},
+		{value: "1e+500", kind: constant.Float, exact: "1" + strings.Repeat("0", 500)},
+		{value: "(1 + 2i)", kind: constant.Complex, exact: "(1 + 2i)"},
+		{value: "(-1/2 + -2i)", kind: constant.Complex, exact: "(-1/2 + -2i)"},
+		{value: "1/0", kind: constant.Unknown, exact: "unknown"},
+		{value: "(1 + x)", kind: constant.Unknown, exact: "unknown"},
+		{value: "nil", kind: constant.Unknown, exact: "unknown"},
+	}
+
+	for _, test := range tests {
+		v := ParseValue(test.value)
+		if v.Kind() != test.kind || v.ExactString() != test.exact {
+			t.Errorf("Expected %q to be parsed into %v %v, got %v %v", test.value, test.kind, test.exact, v.Kind(), v.ExactString())
+		}
+	}
+}
+
+func TestValueStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		literal string
+		tok     token.Token
+		value   string
+		// kind of the parsed value
+		kind constant.Kind
+	}{
+		{literal: "0b1010_1010", tok: token.INT, value: "170", kind: constant.Int},
+		{literal: "0o777", tok: token.INT, value: "511", kind: constant.Int},
+		{literal: "'a'", tok: token.CHAR, value: "97", kind: constant.Int},
+		{literal: `'é'`, tok: token.CHAR, value: "233", kind: constant.Int},
+		{literal: "0.1", tok: token.FLOAT, value: "1/10", kind: constant.Float},
+		{literal: "0x1p-2", tok: token.FLOAT, value: "1/4", kind: constant.Float},
+		// integral floats are stored as integers and parsed back as Int,
+		// the kind is given by the constant type once the value is used (see represent)
+		{literal: "2.0", tok: token.FLOAT, value: "2", kind: constant.Int},
+		{literal: "0x10p0", tok: token.FLOAT, value: "16", kind: constant.Int},
+		{literal: "2i", tok: token.IMAG, value: "(0 + 2i)", kind: constant.Complex},
+		{literal: "`raw\\n`", tok: token.STRING, value: `"raw\\n"`, kind: constant.String},
+	}
+
+	for _, test := range tests {
+		v := constant.MakeFromLiteral(test.literal, test.tok, 0)
+		value := ValueString(v)
+		if value != test.value {
+			t.Errorf("Expected %v to be stored as %q, got %q", test.literal, test.value, value)
+			continue
+		}
+		parsed := ParseValue(value)
+		if parsed.Kind() != test.kind {
+			t.Errorf("Expected %q to be parsed as %v, got %v", value, test.kind, parsed.Kind())
+		}
+		if !constant.Compare(parsed, token.EQL, v) {
+			t.Errorf("Expected %q to be parsed back into %v, got %v", value, v, parsed)
+		}
+		if ValueString(parsed) != value {
+			t.Errorf("Expected %q to be stored the same way once parsed, got %q", value, ValueString(parsed))
+		}
+	}
+
+	if value := ValueString(constant.MakeUnknown()); value != "" {
+		t.Errorf("Expected unknown value to be stored as empty, got %q", value)
+	}
+}
+
+func TestValueToLiteral(t *testing.T) {
+	tests := []struct {
+		value   string
+		literal string
+	}{
+		{value: "42", literal: "42"},
+		{value: "-42", literal: "-42"},
+		{value: "1/4", literal: "0.25"},
+		{value: "-3/2", literal: "-1.5"},
+		{value: "(0 + 2i)", literal: "2i"},
+		{value: "(1/2 + -2i)", literal: "0.5,-2i"},
+	}
+
+	for _, test := range tests {
+		if literal := ValueToLiteral(ParseValue(test.value)); literal != test.literal {
+			t.Errorf("Expected %q to be turned into %q literal, got %q", test.value, test.literal, literal)
+		}
+	}
+
+	// integral float
+	if literal := ValueToLiteral(constant.ToFloat(constant.MakeInt64(2))); literal != "2" {
+		t.Errorf("Expected 2.0 to be turned into 2 literal, got %q", literal)
+	}
+}
+
+func TestUnaryValue(t *testing.T) {
+	c := newValuesConfig(t)
+	tests := []struct {
+		name  string
+		op    token.Token
+		x, z  *gotypes.Constant
+		value string
+	}{
+		{name: "-x", op: token.SUB, x: untyped("int", "5"), z: untyped("int", ""), value: "-5"},
+		{name: "-x float", op: token.SUB, x: untyped("float64", "1/3"), z: untyped("float64", ""), value: "-1/3"},
+		{name: "^x untyped", op: token.XOR, x: untyped("int", "1"), z: untyped("int", ""), value: "-2"},
+		{name: "^x int", op: token.XOR, x: typed("builtin", "int", "1"), z: typed("builtin", "int", ""), value: "-2"},
+		{name: "^x uint8", op: token.XOR, x: typed("builtin", "uint8", "1"), z: typed("builtin", "uint8", ""), value: "254"},
+		{name: "^x uint", op: token.XOR, x: typed("builtin", "uint", "0"), z: typed("builtin", "uint", ""), value: "18446744073709551615"},
+		{name: "^x uintptr", op: token.XOR, x: typed("builtin", "uintptr", "0"), z: typed("builtin", "uintptr", ""), value: "18446744073709551615"},
+		{name: "^x named uint8", op: token.XOR, x: typed("example.com/time", "Mode", "15"), z: typed("example.com/time", "Mode", ""), value: "240"},
+		{name: "!x", op: token.NOT, x: untyped("bool", "true"), z: untyped("bool", ""), value: "false"},
+		{name: "unknown operand", op: token.SUB, x: untyped("int", ""), z: untyped("int", ""), value: ""},
+		{name: "invalid operation", op: token.NOT, x: untyped("int", "1"), z: untyped("int", ""), value: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value := c.UnaryValue(test.op, test.x, test.z); value != test.value {
+				t.Errorf("Expected %q, got %q", test.value, value)
+			}
+		})
+	}
+}
+
+func TestBinaryValue(t *testing.T) {
+	c := newValuesConfig(t)
+	tests := []struct {
+		name    string
+		op      token.Token
+		x, y, z *gotypes.Constant
+		value   string
+	}{
+		{name: "integer division", op: token.QUO, x: untyped("int", "7"), y: untyped("int", "2"), z: untyped("int", ""), value: "3"},
+		{name: "negative integer division", op: token.QUO, x: untyped("int", "-7"), y: untyped("int", "2"), z: untyped("int", ""), value: "-3"},
+		{name: "float division", op: token.QUO, x: untyped("float64", "7"), y: untyped("int", "2"), z: untyped("float64", ""), value: "7/2"},
+		{name: "typed integer division", op: token.QUO, x: typed("example.com/time", "Duration", "7"), y: untyped("int", "2"), z: typed("example.com/time", "Duration", ""), value: "3"},
+		{name: "remainder", op: token.REM, x: untyped("int", "7"), y: untyped("int", "3"), z: untyped("int", ""), value: "1"},
+		{name: "integral float", op: token.MUL, x: untyped("float64", "5/2"), y: untyped("int", "2"), z: untyped("float64", ""), value: "5"},
+		{name: "shift", op: token.SHL, x: untyped("int", "1"), y: untyped("int", "10"), z: untyped("int", ""), value: "1024"},
+		{name: "shift beyond 64 bits", op: token.SHL, x: untyped("int", "1"), y: untyped("int", "64"), z: untyped("int", ""), value: "18446744073709551616"},
+		{name: "right shift", op: token.SHR, x: typed("builtin", "uintptr", "18446744073709551615"), y: untyped("int", "63"), z: typed("builtin", "uintptr", ""), value: "1"},
+		{name: "shift of an integral float", op: token.SHL, x: untyped("float64", "2"), y: untyped("int", "2"), z: untyped("int", ""), value: "8"},
+		{name: "negative shift count", op: token.SHL, x: untyped("int", "1"), y: untyped("int", "-1"), z: untyped("int", ""), value: ""},
+		{name: "iota based", op: token.MUL, x: untyped("int", "3"), y: typed("example.com/time", "Duration", "1000"), z: typed("example.com/time", "Duration", ""), value: "3000"},
+		{name: "comparison", op: token.LSS, x: untyped("int", "1"), y: untyped("float64", "3/2"), z: untyped("bool", ""), value: "true"},
+		{name: "string concatenation", op: token.ADD, x: untyped("string", `"a"`), y: untyped("string", `"b"`), z: untyped("string", ""), value: `"ab"`},
+		{name: "rune arithmetic", op: token.ADD, x: untyped("rune", "97"), y: untyped("int", "1"), z: untyped("rune", ""), value: "98"},
+		{name: "complex", op: token.MUL, x: untyped("complex128", "(0 + 1i)"), y: untyped("complex128", "(0 + 1i)"), z: untyped("complex128", ""), value: "(-1 + 0i)"},
+		{name: "division by zero", op: token.QUO, x: untyped("int", "1"), y: untyped("int", "0"), z: untyped("int", ""), value: ""},
+		{name: "unknown operand", op: token.ADD, x: untyped("int", "1"), y: untyped("int", ""), z: untyped("int", ""), value: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value := c.BinaryValue(test.op, test.x, test.y, test.z); value != test.value {
+				t.Errorf("Expected %q, got %q", test.value, value)
+			}
+		})
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	c := newValuesConfig(t)
+	tests := []struct {
+		name  string
+		x, z  *gotypes.Constant
+		value string
+	}{
+		{name: "string(rune)", x: untyped("rune", "233"), z: typed("builtin", "string", ""), value: `"é"`},
+		{name: "string(invalid rune)", x: untyped("int", "-1"), z: typed("builtin", "string", ""), value: `"�"`},
+		{name: "float64(int)", x: untyped("int", "1"), z: typed("builtin", "float64", ""), value: "1"},
+		{name: "int(integral float)", x: untyped("float64", "2"), z: typed("builtin", "int", ""), value: "2"},
+		{name: "named type", x: untyped("int", "5"), z: typed("example.com/time", "Duration", ""), value: "5"},
+		{name: "complex128(float)", x: untyped("float64", "1/2"), z: typed("builtin", "complex128", ""), value: "(1/2 + 0i)"},
+		{name: "bool to string", x: untyped("bool", "true"), z: typed("builtin", "string", ""), value: ""},
+		{name: "unknown operand", x: untyped("int", ""), z: typed("builtin", "int", ""), value: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value := c.ConvertValue(test.x, test.z); value != test.value {
+				t.Errorf("Expected %q, got %q", test.value, value)
+			}
+		})
+	}
+}
+
+func TestComplexValue(t *testing.T) {
+	tests := []struct {
+		re, im *gotypes.Constant
+		value  string
+	}{
+		{re: untyped("int", "1"), im: untyped("int", "2"), value: "(1 + 2i)"},
+		{re: untyped("float64", "1/2"), im: untyped("float64", "-1/4"), value: "(1/2 + -1/4i)"},
+		{re: untyped("int", "0"), im: untyped("int", "0"), value: "(0 + 0i)"},
+		{re: untyped("int", ""), im: untyped("int", "1"), value: ""},
+	}
+
+	for _, test := range tests {
+		if value := complexValue(test.re, test.im); value != test.value {
+			t.Errorf("Expected complex(%q, %q) to be %q, got %q", test.re.Value, test.im.Value, test.value, value)
+		}
+		if test.value != "" && ValueString(ParseValue(test.value)) != test.value {
+			t.Errorf("Expected %q to be parsed back, got %q", test.value, ValueString(ParseValue(test.value)))
+		}
+	}
+}
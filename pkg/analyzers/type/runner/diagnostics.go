@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
+	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
+)
+
+// Unresolved describes a contract that could not be evaluated,
+// either blocked on a typevar or failed with an error
+type Unresolved struct {
+	// function the contract is generated in
+	Function string `json:"function"`
+	// contract type (e.g. propagatesto)
+	Contract string              `json:"contract"`
+	Position *positions.Position `json:"position,omitempty"`
+	// the first typevar of the contract whose data type is not known
+	BlockedOn string `json:"blocked_on,omitempty"`
+	// error of the contract evaluation
+	Error string `json:"error,omitempty"`
+}
+
+// Diagnostics lists contracts of a package that could not be evaluated.
+// Symbols allocated by the evaluated contracts are kept.
+type Diagnostics struct {
+	Package string `json:"package"`
+	// contracts of test files
+	Tests      bool         `json:"tests,omitempty"`
+	Unresolved []Unresolved `json:"unresolved"`
+}
+
+func (d *Diagnostics) Error() string {
+	return fmt.Sprintf("There are still some unprocessed contract: %v", len(d.Unresolved))
+}
+
+// describeTypevar describes the typevar in the notation of the VarTable keys
+// (e.g. virtual.var.1#Field(name))
+func describeTypevar(i typevars.Interface) string {
+	switch d := i.(type) {
+	case *typevars.Variable:
+		return d.String()
+	case *typevars.Field:
+		if d.Name == "" {
+			return fmt.Sprintf("%v#Field(%v)", d.X.String(), d.Index)
+		}
+		return fmt.Sprintf("%v#Field(%v)", d.X.String(), d.Name)
+	case *typevars.ReturnType:
+		return fmt.Sprintf("%v#ReturnType(%v)", d.Function.String(), d.Index)
+	case *typevars.Argument:
+		return fmt.Sprintf("%v#Argument(%v)", d.Function.String(), d.Index)
+	case *typevars.ListValue:
+		return fmt.Sprintf("%v#ListValue", d.X.String())
+	case *typevars.MapKey:
+		return fmt.Sprintf("%v#MapKey", d.X.String())
+	case *typevars.MapValue:
+		return fmt.Sprintf("%v#MapValue", d.X.String())
+	case *typevars.RangeKey:
+		return fmt.Sprintf("%v#RangeKey", d.X.String())
+	case *typevars.RangeValue:
+		return fmt.Sprintf("%v#RangeValue", d.X.String())
+	}
+	return typevars.TypeVar2String(i)
+}
+
+// contractPos returns a position of the contract in the "<file>:<line>:<column>" form
+func contractPos(c contracts.Contract) string {
+	switch d := c.(type) {
+	case *contracts.UnaryOp:
+		return d.Pos
+	case *contracts.BinaryOp:
+		return d.Pos
+	case *contracts.HasField:
+		return d.Pos
+	case *contracts.IsCompatibleWith:
+		return d.Pos
+	case *contracts.PropagatesTo:
+		return d.Pos
+	case *contracts.IsInvocable:
+		return d.Pos
+	case *contracts.IsIndexable:
+		return d.Pos
+	case *contracts.IsDereferenceable:
+		return d.Pos
+	case *contracts.DereferenceOf:
+		return d.Pos
+	case *contracts.IsReferenceable:
+		return d.Pos
+	case *contracts.ReferenceOf:
+		return d.Pos
+	case *contracts.IsReceiveableFrom:
+		return d.Pos
+	case *contracts.IsSendableTo:
+		return d.Pos
+	case *contracts.IsIncDecable:
+		return d.Pos
+	case *contracts.IsRangeable:
+		return d.Pos
+	case *contracts.MethodValue:
+		return d.Pos
+	case *contracts.TypecastsTo:
+		return d.Pos
+	}
+	return ""
+}
+
+// Report collects diagnostics of packages (the latest diagnostics of a package are kept)
+type Report struct {
+	diagnostics map[string]*Diagnostics
+	// guards the diagnostics (packages are processed concurrently)
+	mutex sync.Mutex
+}
+
+func NewReport() *Report {
+	return &Report{
+		diagnostics: make(map[string]*Diagnostics),
+	}
+}
+
+func (r *Report) Add(d *Diagnostics) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.diagnostics[fmt.Sprintf("%v#%v", d.Package, d.Tests)] = d
+}
+
+// Diagnostics lists diagnostics sorted by the package
+func (r *Report) Diagnostics() []*Diagnostics {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	list := make([]*Diagnostics, 0, len(r.diagnostics))
+	for _, d := range r.diagnostics {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Package != list[j].Package {
+			return list[i].Package < list[j].Package
+		}
+		return !list[i].Tests && list[j].Tests
+	})
+	return list
+}
+
+// Save saves the report into the file in JSON
+func (r *Report) Save(file string) error {
+	byteSlice, err := json.MarshalIndent(r.Diagnostics(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Unable to marshal diagnostics: %v", err)
+	}
+	return ioutil.WriteFile(file, byteSlice, 0644)
+}
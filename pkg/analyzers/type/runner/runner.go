@@ -2,14 +2,17 @@ package runner
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/gofed/symbols-extractor/pkg/analyzers/type/compatibility"
 	"github.com/gofed/symbols-extractor/pkg/analyzers/type/propagation"
 	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
 	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	contracttable "github.com/gofed/symbols-extractor/pkg/parser/contracts/table"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	"github.com/gofed/symbols-extractor/pkg/symbols"
 	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
@@ -57,13 +60,14 @@ func New(packageName string, globalSymbolTable *symbolsglobal.Table, globalAlloc
 	r.symbolAccessor.SetCurrentTable(r.packageName, st)
 
 	storeVar := func(v *typevars.Variable, c contracts.Contract) {
+		name := v.String()
 		// Allocate table of variables for data type propagation
-		r.varTable.SetVariable(v.String(), nil)
-		r.v2c.addVar(v.String(), c)
+		r.varTable.SetVariable(name, nil)
+		r.v2c.addVar(name, c)
 		if v.Package != "" {
 			// Entry points are all global scope variables.
 			// Except for var a = ... cases where the type is not known (handled later).
-			r.entryTypevars[v.String()] = v
+			r.entryTypevars[name] = v
 		}
 	}
 
@@ -75,6 +79,16 @@ func New(packageName string, globalSymbolTable *symbolsglobal.Table, globalAlloc
 	for funcName, cs := range r.contractTable.List() {
 		for _, c := range cs {
 			r.waitingContracts.addContract(funcName, c)
+			// wake the contract up once any of its typevars gets evaluated
+			// (variables are stored below)
+			for _, i := range waitingTypevars(c) {
+				if isVariable(i) {
+					continue
+				}
+				if root := typevarRoot(i); root != "" {
+					r.v2c.addVar(root, c)
+				}
+			}
 			switch d := c.(type) {
 			case *contracts.UnaryOp:
 				if isVariable(d.X) {
@@ -151,6 +165,13 @@ func New(packageName string, globalSymbolTable *symbolsglobal.Table, globalAlloc
 				if isVariable(d.X) {
 					storeVar(d.X.(*typevars.Variable), c)
 				}
+			case *contracts.MethodValue:
+				if isVariable(d.X) {
+					storeVar(d.X.(*typevars.Variable), c)
+				}
+				if isVariable(d.Y) {
+					storeVar(d.Y.(*typevars.Variable), c)
+				}
 			case *contracts.TypecastsTo:
 				if isVariable(d.X) {
 					storeVar(d.X.(*typevars.Variable), c)
@@ -240,97 +261,166 @@ func (r *Runner) isTypevarEvaluated(i typevars.Interface) bool {
 	}
 }
 
-func (r *Runner) splitContracts(ctrs *contractPayload) (*contractPayload, *contractPayload) {
-	// create two groups:
-	// - contracts ready for evaluation
-	// - contracts not ready for evaluation
-	readyPayload := newContractPayload(nil)
-	unreadyPayload := newContractPayload(nil)
+// waitingTypevars lists typevars the contract waits on (the contract is ready
+// for evaluation once all of them are evaluated)
+func waitingTypevars(c contracts.Contract) []typevars.Interface {
+	switch d := c.(type) {
+	case *contracts.UnaryOp:
+		return []typevars.Interface{d.X}
+	case *contracts.BinaryOp:
+		return []typevars.Interface{d.X, d.Y}
+	case *contracts.HasField:
+		return []typevars.Interface{d.X}
+	case *contracts.IsCompatibleWith:
+		return []typevars.Interface{d.X, d.Y}
+	case *contracts.PropagatesTo:
+		return []typevars.Interface{d.X}
+	case *contracts.IsInvocable:
+		return []typevars.Interface{d.F}
+	case *contracts.IsIndexable:
+		if d.Key != nil {
+			return []typevars.Interface{d.X, d.Key}
+		}
+		return []typevars.Interface{d.X}
+	case *contracts.IsDereferenceable:
+		return []typevars.Interface{d.X}
+	case *contracts.DereferenceOf:
+		return []typevars.Interface{d.X}
+	case *contracts.IsReferenceable:
+		return []typevars.Interface{d.X}
+	case *contracts.ReferenceOf:
+		return []typevars.Interface{d.X}
+	case *contracts.IsReceiveableFrom:
+		return []typevars.Interface{d.X}
+	case *contracts.IsSendableTo:
+		return []typevars.Interface{d.X, d.Y}
+	case *contracts.IsIncDecable:
+		return []typevars.Interface{d.X}
+	case *contracts.IsRangeable:
+		return []typevars.Interface{d.X}
+	case *contracts.MethodValue:
+		return []typevars.Interface{d.X}
+	case *contracts.TypecastsTo:
+		return []typevars.Interface{d.X}
+	default:
+		panic(fmt.Sprintf("Unrecognized contract: %#v", c))
+	}
+}
 
-	for key, cs := range ctrs.contracts() {
-		for _, c := range cs {
-			ready := false
-			switch d := c.(type) {
-			case *contracts.UnaryOp:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.BinaryOp:
-				if r.isTypevarEvaluated(d.X) && r.isTypevarEvaluated(d.Y) {
-					ready = true
-				}
-			case *contracts.HasField:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.IsCompatibleWith:
-				if r.isTypevarEvaluated(d.X) && r.isTypevarEvaluated(d.Y) {
-					ready = true
-				}
-			case *contracts.PropagatesTo:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.IsInvocable:
-				if r.isTypevarEvaluated(d.F) {
-					ready = true
-				}
-			case *contracts.IsIndexable:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-				if d.Key != nil && !r.isTypevarEvaluated(d.Key) {
-					ready = false
-				}
-			case *contracts.IsDereferenceable:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.DereferenceOf:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.IsReferenceable:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.ReferenceOf:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.IsReceiveableFrom:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.IsSendableTo:
-				if r.isTypevarEvaluated(d.X) && r.isTypevarEvaluated(d.Y) {
-					ready = true
-				}
-			case *contracts.IsIncDecable:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.IsRangeable:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			case *contracts.TypecastsTo:
-				if r.isTypevarEvaluated(d.X) {
-					ready = true
-				}
-			default:
-				panic(fmt.Sprintf("Unrecognized contract: %#v", c))
-			}
+// typevarRoot returns a name of the variable whose data type (or field) evaluates the typevar.
+// Typevars evaluated from the start (e.g. constants) have no root variable.
+func typevarRoot(i typevars.Interface) string {
+	switch d := i.(type) {
+	case *typevars.Variable:
+		return d.String()
+	case *typevars.Field:
+		return d.X.String()
+	case *typevars.ReturnType:
+		return d.Function.String()
+	case *typevars.Argument:
+		return d.Function.String()
+	case *typevars.ListValue:
+		return d.X.String()
+	case *typevars.MapKey:
+		return d.X.String()
+	case *typevars.MapValue:
+		return d.X.String()
+	case *typevars.RangeKey:
+		return d.X.String()
+	case *typevars.RangeValue:
+		return d.X.String()
+	}
+	return ""
+}
 
-			if ready {
-				readyPayload.addContract(key, c)
-			} else {
-				unreadyPayload.addContract(key, c)
+// allocateMethod records the method as allocated (through the method's receiver data type)
+func (r *Runner) allocateMethod(method *gotypes.Method, name, pos string) error {
+	// TODO(jchaloup): pick the entry data type instead of the data type that actually defines the method
+	// var fieldCache struct {
+	//         sync.RWMutex
+	//         m map[reflect.Type][]field
+	// }
+	//
+	// fieldCache.RLock() is actually a call of the RLock through the anonymous struct
+	var ident *gotypes.Identifier
+	switch recvr := method.Receiver.(type) {
+	case *gotypes.Pointer:
+		i, ok := recvr.Def.(*gotypes.Identifier)
+		if !ok {
+			return fmt.Errorf("Receiver expected to be a pointer to identifier or an identifier, got pointer to %#v instead", recvr.Def)
+		}
+		ident = i
+	case *gotypes.Identifier:
+		ident = recvr
+	default:
+		return fmt.Errorf("Receiver expected to be a pointer to identifier or an identifier, got %#v instead", method.Receiver)
+	}
+
+	allocTable, err := r.globalAllocSymbolTable.Lookup(r.packageName, strings.Split(pos, ":")[0])
+	if err != nil {
+		return nil
+	}
+	allocTable.AddMethod(ident.Package, ident.Def, name, positions.Parse(pos))
+	return nil
+}
+
+// allocateInterfaceMethods records methods of the data type implementing the interface
+// the data type is assigned to (e.g. passed as an argument of an interface data type) as allocated.
+// Data types not implementing the interface allocate nothing.
+func (r *Runner) allocateInterfaceMethods(xDataType, yDataType gotypes.DataType, pos string) error {
+	yDef, err := r.symbolAccessor.FindFirstNonidDataType(yDataType)
+	if err != nil {
+		return err
+	}
+	iface, ok := yDef.(*gotypes.Interface)
+	if !ok || len(iface.Methods) == 0 {
+		return nil
+	}
+
+	methods, err := compatibility.New(r.symbolAccessor).ImplementingMethods(xDataType, iface)
+	if err != nil {
+		return err
+	}
+	if methods == nil {
+		klog.V(2).Infof("%#v does not implement %#v", xDataType, yDataType)
+		return nil
+	}
+	for _, key := range methods.Names() {
+		// methods of interfaces are not allocated
+		if method := methods[key]; method.Def != nil {
+			if err := r.allocateMethod(method.Def, method.Name, pos); err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
 
-	return readyPayload, unreadyPayload
+// isTypeparam checks the data type is a type parameter (or a pointer to it)
+func isTypeparam(dataType gotypes.DataType) bool {
+	if pointer, ok := dataType.(*gotypes.Pointer); ok {
+		return isTypeparam(pointer.Def)
+	}
+	_, ok := dataType.(*gotypes.Typeparam)
+	return ok
+}
+
+// typevarPos returns a position of the expression the typevar is evaluated from (if known)
+func typevarPos(i typevars.Interface) string {
+	switch d := i.(type) {
+	case *typevars.Variable:
+		return d.Pos
+	case *typevars.Field:
+		if d.Pos != "" {
+			return d.Pos
+		}
+		return d.X.Pos
+	case *typevars.ReturnType:
+		return d.Function.Pos
+	case *typevars.Argument:
+		return d.Function.Pos
+	}
+	return ""
 }
 
 func (r *Runner) evaluateContract(c contracts.Contract) error {
@@ -345,7 +435,10 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		r.varTable.SetVariable(i.(*typevars.Variable).String(), item)
 	}
 
-	klog.V(2).Infof("Checking %v...", contracts.Contract2String(c))
+	// formatting of the contract is costly, do it only when logged
+	if klog.V(2).Enabled() {
+		klog.Infof("Checking %v...", contracts.Contract2String(c))
+	}
 
 	typevar2varTableItem := func(i typevars.Interface) (*varTableItem, error) {
 		//klog.V(2).Infof("typevar2varTableItem, i=%#v", i)
@@ -353,7 +446,7 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		case *typevars.Constant:
 			st, err := r.globalSymbolTable.Lookup(td.Package)
 			if err != nil {
-				panic(err)
+				return nil, err
 			}
 			return &varTableItem{
 				dataType:    td.DataType,
@@ -379,26 +472,72 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 				return nil, err
 			}
 
+			var results []gotypes.DataType
 			switch fDef := dt.(type) {
 			case *gotypes.Method:
-				return &varTableItem{
-					dataType:    fDef.Def.(*gotypes.Function).Results[td.Index],
-					packageName: item.packageName,
-					symbolTable: item.symbolTable,
-				}, nil
+				f, ok := fDef.Def.(*gotypes.Function)
+				if !ok {
+					return nil, fmt.Errorf("typevars.ReturnType expected to be a method of a funtion, got %#v instead", fDef.Def)
+				}
+				results = f.Results
 			case *gotypes.Function:
-				return &varTableItem{
-					dataType:    fDef.Results[td.Index],
-					packageName: item.packageName,
-					symbolTable: item.symbolTable,
-				}, nil
+				results = fDef.Results
 			default:
 				return nil, fmt.Errorf("typevars.ReturnType expected to be a funtion/method, got %#v instead", dt)
 			}
+			if td.Index >= len(results) {
+				return nil, fmt.Errorf("typevars.ReturnType index %v out of range of %v results", td.Index, len(results))
+			}
+			return &varTableItem{
+				dataType:    results[td.Index],
+				packageName: item.packageName,
+				symbolTable: item.symbolTable,
+			}, nil
+		case *typevars.Argument:
+			item, ok := getVar(td.Function)
+			if !ok {
+				return nil, fmt.Errorf("Variable %v does not exist", td.Function.String())
+			}
+			dt, err := r.symbolAccessor.FindFirstNonIdSymbol(item.dataType)
+			if err != nil {
+				return nil, err
+			}
+
+			var params []gotypes.DataType
+			switch fDef := dt.(type) {
+			case *gotypes.Method:
+				f, ok := fDef.Def.(*gotypes.Function)
+				if !ok {
+					return nil, fmt.Errorf("typevars.Argument expected to be a method of a funtion, got %#v instead", fDef.Def)
+				}
+				params = f.Params
+			case *gotypes.Function:
+				params = fDef.Params
+			default:
+				return nil, fmt.Errorf("typevars.Argument expected to be a funtion/method, got %#v instead", dt)
+			}
+			// all trailing arguments are passed through the variadic parameter
+			if n := len(params); n > 0 && td.Index >= n-1 {
+				if ellipsis, ok := params[n-1].(*gotypes.Ellipsis); ok {
+					return &varTableItem{
+						dataType:    ellipsis.Def,
+						packageName: item.packageName,
+						symbolTable: item.symbolTable,
+					}, nil
+				}
+			}
+			if td.Index >= len(params) {
+				return nil, fmt.Errorf("typevars.Argument index %v out of range of %v parameters", td.Index, len(params))
+			}
+			return &varTableItem{
+				dataType:    params[td.Index],
+				packageName: item.packageName,
+				symbolTable: item.symbolTable,
+			}, nil
 		case *typevars.ListKey:
 			st, err := r.globalSymbolTable.Lookup("builtin")
 			if err != nil {
-				panic(err)
+				return nil, err
 			}
 			return &varTableItem{
 				dataType:    &gotypes.Constant{Package: "builtin", Def: "int", Untyped: true},
@@ -484,7 +623,7 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		case *typevars.CGO:
 			st, err := r.globalSymbolTable.Lookup(td.Package)
 			if err != nil {
-				panic(err)
+				return nil, err
 			}
 			return &varTableItem{
 				dataType:    td.DataType,
@@ -492,7 +631,7 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 				symbolTable: st,
 			}, nil
 		default:
-			panic(fmt.Sprintf("Unrecognized typevar %#v", i))
+			return nil, fmt.Errorf("Unrecognized typevar %#v", i)
 		}
 	}
 
@@ -574,7 +713,6 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 				d.Field,
 			)
 			if err != nil {
-				panic(err)
 				return err
 			}
 			yDataType := fieldAttribute.DataType
@@ -588,40 +726,8 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 
 			// method of a struct or a data type?
 			if method, ok := yDataType.(*gotypes.Method); ok {
-				switch recvr := method.Receiver.(type) {
-				case *gotypes.Pointer:
-					ident, ok := recvr.Def.(*gotypes.Identifier)
-					if ok {
-						allocTable, err := r.globalAllocSymbolTable.Lookup(r.packageName, strings.Split(d.Pos, ":")[0])
-						if err != nil {
-							return nil
-						}
-						// TODO(jchaloup): pick the entry data type instead of the data type that actually defines the method
-						// var fieldCache struct {
-						//         sync.RWMutex
-						//         m map[reflect.Type][]field
-						// }
-						//
-						// fieldCache.RLock() is actually a call of the RLock through the anonymous struct
-						allocTable.AddMethod(ident.Package, ident.Def, d.Field, d.Pos)
-					} else {
-						return fmt.Errorf("Receiver expected to be a pointer to identifier or an identifier, got pointer to %#v instead", recvr.Def)
-					}
-				case *gotypes.Identifier:
-					allocTable, err := r.globalAllocSymbolTable.Lookup(r.packageName, strings.Split(d.Pos, ":")[0])
-					if err != nil {
-						return nil
-					}
-					// TODO(jchaloup): pick the entry data type instead of the data type that actually defines the method
-					// var fieldCache struct {
-					//         sync.RWMutex
-					//         m map[reflect.Type][]field
-					// }
-					//
-					// fieldCache.RLock() is actually a call of the RLock through the anonymous struct
-					allocTable.AddMethod(recvr.Package, recvr.Def, d.Field, d.Pos)
-				default:
-					return fmt.Errorf("Receiver expected to be a pointer to identifier or an identifier, got %#v instead", method.Receiver)
+				if err := r.allocateMethod(method, d.Field, d.Pos); err != nil {
+					return err
 				}
 			} else {
 				// struct field?
@@ -634,20 +740,19 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 							if err != nil {
 								return nil
 							}
-							allocTable.AddStructField(item.Package, item.Name, d.Field, d.Pos)
+							allocTable.AddStructField(item.Package, item.Name, d.Field, positions.Parse(d.Pos))
 							found = true
 							break
 						}
 					}
 					if !found {
-						panic("Field origin not found")
+						return fmt.Errorf("Origin of %q field not found", d.Field)
 					}
 				}
 			}
 		} else {
 			dt, err := r.symbolAccessor.FindFirstNonidDataType(xVarItem.dataType)
 			if err != nil {
-				panic(err)
 				return err
 			}
 			// pointer? Maybe
@@ -663,7 +768,6 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 
 			dt, err = r.symbolAccessor.FindFirstNonidDataType(dt)
 			if err != nil {
-				panic(err)
 				return err
 			}
 
@@ -674,7 +778,6 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 			// retrieve positional field
 			field, err := r.symbolAccessor.RetrieveStructFieldAtIndex(structDef, d.Index)
 			if err != nil {
-				panic(err)
 				return err
 			}
 			r.varTable.SetFieldAt(d.X.(*typevars.Variable).String(), d.Index, &varTableItem{
@@ -684,6 +787,37 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 			})
 		}
 	case *contracts.IsCompatibleWith:
+		// cases of type switches only check the data type can be asserted
+		if d.Weak {
+			return nil
+		}
+		xItem, xErr := typevar2varTableItem(d.X)
+		if xErr != nil {
+			return xErr
+		}
+		yItem, yErr := typevar2varTableItem(d.Y)
+		if yErr != nil {
+			return yErr
+		}
+		// The compatibility is not checked for typevars with no data type resolved (e.g. fields
+		// not set) and for type parameters (checked against their constraints by the compiler)
+		if xItem == nil || yItem == nil || xItem.dataType == nil || yItem.dataType == nil {
+			klog.V(2).Infof("Unable to check compatibility of %v and %v: data type not resolved", typevars.TypeVar2String(d.X), typevars.TypeVar2String(d.Y))
+			return nil
+		}
+		if isTypeparam(xItem.dataType) || isTypeparam(yItem.dataType) {
+			return nil
+		}
+		pos := d.Pos
+		if pos == "" {
+			pos = typevarPos(d.X)
+		}
+		if pos == "" {
+			pos = typevarPos(d.Y)
+		}
+		if err := r.allocateInterfaceMethods(xItem.dataType, yItem.dataType, pos); err != nil {
+			return fmt.Errorf("Unable to check %v implements %v: %v", typevars.TypeVar2String(d.X), typevars.TypeVar2String(d.Y), err)
+		}
 	case *contracts.PropagatesTo:
 		item, err := typevar2varTableItem(d.X)
 		if err != nil {
@@ -712,13 +846,13 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		if item.entry {
 			variable, ok := d.F.(*typevars.Variable)
 			if !ok {
-				panic("Expected variable")
+				return fmt.Errorf("Expected variable, got %#v instead", d.F)
 			}
 			allocTable, err := r.globalAllocSymbolTable.Lookup(r.packageName, strings.Split(variable.Pos, ":")[0])
 			if err != nil {
 				return nil
 			}
-			allocTable.AddFunction(variable.Package, variable.Name, variable.Pos)
+			allocTable.AddFunction(variable.Package, variable.Name, variable.Position.Or(variable.Pos))
 		}
 		dt, err := r.symbolAccessor.FindFirstNonIdSymbol(item.dataType)
 		if err != nil {
@@ -806,18 +940,13 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 					return nil
 				}
 			}
-			fmt.Printf("constantType not ID: %#v\n", constantType)
 		case *gotypes.Identifier:
 			if d.Package == "builtin" && d.Def == "string" {
 				// TODO(jchaloup): check the index is compatible with Integer type
 				return nil
 			}
-		default:
-			panic(fmt.Errorf("Unsupported indexable typevar %#v", dt))
 		}
-		// error
-		fmt.Printf("Unsupported indexable typevar %#v\n", dt)
-		panic("|||")
+		return fmt.Errorf("Unsupported indexable typevar %#v", dt)
 	case *contracts.IsDereferenceable:
 		item, xErr := typevar2varTableItem(d.X)
 		if xErr != nil {
@@ -842,8 +971,12 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		if err != nil {
 			return err
 		}
+		pointer, ok := nonIdent.(*gotypes.Pointer)
+		if !ok {
+			return fmt.Errorf("Expected pointer, got %#v instead", nonIdent)
+		}
 		setVar(d.Y, &varTableItem{
-			dataType:    nonIdent.(*gotypes.Pointer).Def,
+			dataType:    pointer.Def,
 			packageName: item.packageName,
 			symbolTable: item.symbolTable,
 		})
@@ -944,6 +1077,52 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		default:
 			return fmt.Errorf("Expression %#v not rangeable", dt)
 		}
+	case *contracts.MethodValue:
+		xVarItem, xErr := typevar2varTableItem(d.X)
+		if xErr != nil {
+			return xErr
+		}
+		fieldAttribute, err := propagation.New(r.symbolAccessor).SelectorExpr(
+			xVarItem.dataType,
+			d.Method,
+		)
+		if err != nil {
+			return err
+		}
+
+		var function *gotypes.Function
+		switch fDef := fieldAttribute.DataType.(type) {
+		case *gotypes.Method:
+			if err := r.allocateMethod(fDef, d.Method, d.Pos); err != nil {
+				return err
+			}
+			f, ok := fDef.Def.(*gotypes.Function)
+			if !ok {
+				return fmt.Errorf("Expected %q to be a method of a function, got %#v instead", d.Method, fDef.Def)
+			}
+			function = f
+		case *gotypes.Function:
+			// method of an interface
+			function = fDef
+		default:
+			return fmt.Errorf("Expected %q to be a method, got %#v instead", d.Method, fieldAttribute.DataType)
+		}
+
+		if d.Expression {
+			// T.Method(recv, args...) <=> recv.Method(args...)
+			function = &gotypes.Function{
+				Package:    function.Package,
+				Params:     append([]gotypes.DataType{xVarItem.dataType}, function.Params...),
+				Results:    function.Results,
+				Typeparams: function.Typeparams,
+			}
+		}
+
+		setVar(d.Y, &varTableItem{
+			dataType:    function,
+			packageName: xVarItem.packageName,
+			symbolTable: xVarItem.symbolTable,
+		})
 	case *contracts.TypecastsTo:
 		item, xErr := typevar2varTableItem(d.X)
 		if xErr != nil {
@@ -976,8 +1155,7 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		case *gotypes.Selector:
 			qid, ok := d.Prefix.(*gotypes.Packagequalifier)
 			if !ok {
-				fmt.Printf("slector not qid: %#v\n", d.Prefix)
-				panic("SELECTOR not QID!!!")
+				return fmt.Errorf("Expected selector prefix to be a package qualifier, got %#v instead", d.Prefix)
 			}
 			dtOrigin = qid.Path
 		default:
@@ -990,6 +1168,9 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		}
 
 		castedDef, err := propagation.New(r.symbolAccessor).TypecastExpr(item.dataType, tc.DataType)
+		if err != nil {
+			return err
+		}
 		yItem := &varTableItem{
 			dataType:    castedDef,
 			packageName: dtOrigin,
@@ -998,7 +1179,7 @@ func (r *Runner) evaluateContract(c contracts.Contract) error {
 		//klog.V(2).Infof("yItem: %#v, yItem.dataType: %#v\n", yItem, yItem.dataType)
 		setVar(d.Y, yItem)
 	default:
-		panic(fmt.Sprintf("Unrecognized contract: %#v", c))
+		return fmt.Errorf("Unrecognized contract: %#v", c)
 	}
 
 	return nil
@@ -1010,6 +1191,8 @@ func (r *Runner) dumpTypeVars() {
 	fmt.Printf("\n\n")
 }
 
+// Run evaluates the contracts to collect dynamically allocated symbols.
+// If some contracts can not be evaluated, *Diagnostics describing them is returned.
 func (r *Runner) Run() error {
 	// propagate data type definitions of all entry typevars
 	for _, ev := range r.entryTypevars {
@@ -1039,31 +1222,95 @@ func (r *Runner) Run() error {
 		}
 	}
 
-	ready, unready := r.splitContracts(newContractPayload(r.waitingContracts.contracts()))
-	for !ready.isEmpty() {
-		// fmt.Printf("Ready:\n")
-		// ready.dump()
-		// fmt.Printf("Unready:\n")
-		// unready.dump()
-		for _, cs := range ready.contracts() {
-			for _, c := range cs {
-				//for _, c := range ready.sortedContracts() {
-				if err := r.evaluateContract(c); err != nil {
-					return err
+	// Contracts are evaluated in rounds. Each round evaluates all contracts ready
+	// at its start. The first round checks all contracts, each next round checks
+	// only contracts whose first unevaluated typevar depends on a variable set
+	// in the previous round (an evaluated typevar stays evaluated).
+	ctrs := make([]contracts.Contract, 0, r.waitingContracts.len())
+	funcNames := make([]string, 0, r.waitingContracts.len())
+	for _, fnc := range r.waitingContracts.functions() {
+		for _, c := range r.waitingContracts.contracts()[fnc] {
+			ctrs = append(ctrs, c)
+			funcNames = append(funcNames, fnc)
+		}
+	}
+	index := make(map[contracts.Contract]int, len(ctrs))
+	waiting := make([][]typevars.Interface, len(ctrs))
+	// index of the first unevaluated typevar of each contract and its root variable
+	pending := make([]int, len(ctrs))
+	pendingRoot := make([]string, len(ctrs))
+	candidates := make([]int, 0, len(ctrs))
+	for i, c := range ctrs {
+		index[c] = i
+		waiting[i] = waitingTypevars(c)
+		candidates = append(candidates, i)
+	}
+	evaluated := make([]bool, len(ctrs))
+	// errors of contracts that failed to evaluate (considered evaluated)
+	failed := make([]error, len(ctrs))
+	woken := make([]bool, len(ctrs))
+	var ready []int
+	r.varTable.takeTouched()
+
+	for len(candidates) > 0 {
+		ready = ready[:0]
+		for _, i := range candidates {
+			for pending[i] < len(waiting[i]) && r.isTypevarEvaluated(waiting[i][pending[i]]) {
+				pending[i]++
+			}
+			if pending[i] == len(waiting[i]) {
+				ready = append(ready, i)
+				continue
+			}
+			pendingRoot[i] = typevarRoot(waiting[i][pending[i]])
+		}
+		for _, i := range ready {
+			if err := r.evaluateContract(ctrs[i]); err != nil {
+				klog.V(2).Infof("Unable to evaluate %v: %v", contracts.Contract2String(ctrs[i]), err)
+				failed[i] = err
+			}
+			evaluated[i] = true
+		}
+
+		candidates = candidates[:0]
+		for _, name := range r.varTable.takeTouched() {
+			for _, c := range r.v2c.contracts(name) {
+				if i := index[c]; !evaluated[i] && !woken[i] && pendingRoot[i] == name {
+					woken[i] = true
+					candidates = append(candidates, i)
 				}
 			}
 		}
+		for _, i := range candidates {
+			woken[i] = false
+		}
+		sort.Ints(candidates)
+	}
 
-		ready, unready = r.splitContracts(unready)
+	// Symbols allocated by the evaluated contracts are kept
+	diagnostics := &Diagnostics{Package: r.packageName}
+	for i, c := range ctrs {
+		if failed[i] != nil {
+			diagnostics.Unresolved = append(diagnostics.Unresolved, Unresolved{
+				Function: funcNames[i],
+				Contract: string(c.GetType()),
+				Position: positions.Parse(contractPos(c)),
+				Error:    failed[i].Error(),
+			})
+			continue
+		}
+		if evaluated[i] {
+			continue
+		}
+		diagnostics.Unresolved = append(diagnostics.Unresolved, Unresolved{
+			Function:  funcNames[i],
+			Contract:  string(c.GetType()),
+			Position:  positions.Parse(contractPos(c)),
+			BlockedOn: describeTypevar(waiting[i][pending[i]]),
+		})
 	}
-	// fmt.Printf("Ready:\n")
-	// ready.dump()
-	// fmt.Printf("Unready:\n")
-	// unready.dump()
-	if !unready.isEmpty() {
-		fmt.Printf("\n\n")
-		unready.dump()
-		return fmt.Errorf("There are still some unprocessed contract: %v", unready.len())
+	if len(diagnostics.Unresolved) > 0 {
+		return diagnostics
 	}
 
 	return nil
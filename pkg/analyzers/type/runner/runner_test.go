@@ -0,0 +1,358 @@
+package runner_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/analyzers/type/runner"
+	"github.com/gofed/symbols-extractor/pkg/parser"
+	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
+	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
+	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+// benchmarkPackage is a standard library package with thousands of contracts
+// whose data types propagate through tens of rounds
+const benchmarkPackage = "math"
+
+func BenchmarkRun(b *testing.B) {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(b.TempDir(), "", goVersion, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := p.Parse(benchmarkPackage, false); err != nil {
+		b.Skipf("Unable to parse %q: %v", benchmarkPackage, err)
+	}
+	contractTable, err := p.GlobalContractsTable().Lookup(benchmarkPackage)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := runner.New(benchmarkPackage, p.GlobalSymbolTable(), p.GlobalAllocTable(), contractTable)
+		if err := r.Run(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestRunDiagnostics(t *testing.T) {
+	pkg := "container/list"
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(t.TempDir(), "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse(pkg, false); err != nil {
+		t.Skipf("Unable to parse %q: %v", pkg, err)
+	}
+	contractTable, err := p.GlobalContractsTable().Lookup(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// data type of the virtual variable is never known
+	blocking := typevars.MakeVirtualVar(1000000)
+	contractTable.SetPrefix("Unresolved")
+	contractTable.AddContract(&contracts.PropagatesTo{
+		X:   blocking,
+		Y:   typevars.MakeVirtualVar(1000001),
+		Pos: "list.go:10:2",
+	})
+	// the evaluation fails, other contracts are evaluated anyway
+	contractTable.AddContract(&contracts.IsDereferenceable{
+		X:   typevars.MakeConstant("builtin", &gotypes.Identifier{Package: "builtin", Def: "int"}),
+		Pos: "list.go:11:2",
+	})
+	contractTable.UnsetPrefix()
+
+	err = runner.New(pkg, p.GlobalSymbolTable(), p.GlobalAllocTable(), contractTable).Run()
+	diagnostics, ok := err.(*runner.Diagnostics)
+	if !ok {
+		t.Fatalf("Expected diagnostics, got %v", err)
+	}
+	expected := &runner.Diagnostics{
+		Package: pkg,
+		Unresolved: []runner.Unresolved{
+			{
+				Function:  "Unresolved",
+				Contract:  string(contracts.PropagatesToType),
+				Position:  &positions.Position{File: "list.go", Line: 10, Column: 2},
+				BlockedOn: blocking.String(),
+			},
+			{
+				Function: "Unresolved",
+				Contract: string(contracts.IsDereferenceableType),
+				Position: &positions.Position{File: "list.go", Line: 11, Column: 2},
+				Error:    `Expected pointer, got &types.Identifier{Def:"int", Package:"builtin"} instead`,
+			},
+		},
+	}
+	if !reflect.DeepEqual(diagnostics, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, diagnostics)
+	}
+}
+
+// testdataResolver locates packages in the testdata directory
+type testdataResolver map[string]string
+
+func (r testdataResolver) PackageDir(pkg string) (string, error) {
+	if dir, ok := r[pkg]; ok {
+		return filepath.Abs(dir)
+	}
+	return "", fmt.Errorf("Package %q not in testdata", pkg)
+}
+
+// newParser creates a parser locating testdata packages
+func newParser(t *testing.T) *parser.ProjectParser {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(t.TempDir(), "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.SetResolver(testdataResolver{
+		"example.com/generics":  "testdata/generics",
+		"example.com/methods":   "testdata/methods",
+		"example.com/worklist":  "testdata/worklist",
+		"example.com/receivers": "testdata/receivers",
+	})
+}
+
+// parse parses the package and evaluates its contracts,
+// no contract is expected to remain unresolved
+func parse(t *testing.T, p *parser.ProjectParser, pkg string) *parser.ProjectParser {
+	if err := p.Parse(pkg, true); err != nil {
+		t.Fatalf("Unable to parse %q: %v", pkg, err)
+	}
+	for _, d := range p.Diagnostics().Diagnostics() {
+		t.Errorf("Unresolved contracts of %v: %#v", d.Package, d.Unresolved)
+	}
+	return p
+}
+
+func TestRunGenerics(t *testing.T) {
+	// standard library packages with generic functions
+	for _, pkg := range []string{"cmp", "unicode/utf8"} {
+		t.Run(pkg, func(t *testing.T) {
+			parse(t, newParser(t), pkg)
+		})
+	}
+
+	pkg := "example.com/generics"
+	table, err := parse(t, newParser(t), pkg).GlobalSymbolTable().Lookup(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin := func(name string) gotypes.DataType {
+		return &gotypes.Identifier{Package: "builtin", Def: name}
+	}
+	expected := map[string]gotypes.DataType{
+		"total":   builtin("int"),
+		"ftotal":  builtin("float64"),
+		"less":    builtin("bool"),
+		"first":   builtin("byte"),
+		"doubled": &gotypes.Slice{Elmtype: builtin("int")},
+		"sizes":   &gotypes.Slice{Elmtype: builtin("int")},
+	}
+	for name, dataType := range expected {
+		sym, err := table.LookupVariable(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sym.Def, dataType) {
+			t.Errorf("Expected %v to be %#v, got %#v", name, dataType, sym.Def)
+		}
+	}
+}
+
+func TestRunMethodValues(t *testing.T) {
+	pkg := "example.com/methods"
+	p := parse(t, newParser(t), pkg)
+	table, err := p.GlobalSymbolTable().Lookup(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin := func(name string) gotypes.DataType {
+		return &gotypes.Identifier{Package: "builtin", Def: name}
+	}
+	handler := &gotypes.Identifier{Package: pkg, Def: "Handler"}
+	expected := map[string]gotypes.DataType{
+		"handled": builtin("int"),
+		// the receiver is bound
+		"serve": &gotypes.Function{
+			Package: pkg,
+			Params:  []gotypes.DataType{builtin("string")},
+			Results: []gotypes.DataType{builtin("int")},
+		},
+		"served": builtin("int"),
+		// the receiver is the first parameter
+		"prefix": &gotypes.Function{
+			Package: pkg,
+			Params:  []gotypes.DataType{handler},
+			Results: []gotypes.DataType{builtin("string")},
+		},
+		"prefixed": builtin("string"),
+		"serveExpr": &gotypes.Function{
+			Package: pkg,
+			Params:  []gotypes.DataType{&gotypes.Pointer{Def: handler}, builtin("string")},
+			Results: []gotypes.DataType{builtin("int")},
+		},
+		"pPrefix": &gotypes.Function{
+			Package: pkg,
+			Results: []gotypes.DataType{builtin("string")},
+		},
+	}
+	for name, dataType := range expected {
+		sym, err := table.LookupVariable(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sym.Def, dataType) {
+			t.Errorf("Expected %v to be %#v, got %#v", name, dataType, sym.Def)
+		}
+	}
+
+	// methods used as values are allocated as methods invoked
+	files, err := p.GlobalAllocTable().LookupPackage(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocated := map[string]int{}
+	for _, file := range files {
+		for _, item := range file.Symbols[pkg].Methods {
+			allocated[item.Parent+"."+item.Name]++
+		}
+	}
+	// h.ServeHTTP (twice), (*Handler).ServeHTTP, Handler.Prefix
+	// and Handler.Prefix implementing Prefixer
+	expectedAllocated := map[string]int{
+		"Handler.ServeHTTP": 3,
+		"Handler.Prefix":    2,
+		"Server.Handle":     1,
+	}
+	if !reflect.DeepEqual(allocated, expectedAllocated) {
+		t.Errorf("Expected %v allocated methods, got %v", expectedAllocated, allocated)
+	}
+}
+
+// dumpAllocated lists symbols allocated in the package, one per line
+func dumpAllocated(table *alloctable.Table) []string {
+	var lines []string
+	for pkg, symbols := range table.Symbols {
+		for _, item := range symbols.Datatypes {
+			lines = append(lines, fmt.Sprintf("datatype %v.%v %v", pkg, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Functions {
+			lines = append(lines, fmt.Sprintf("function %v.%v %v", pkg, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Variables {
+			lines = append(lines, fmt.Sprintf("variable %v.%v %v", pkg, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Methods {
+			lines = append(lines, fmt.Sprintf("method %v.%v.%v %v", pkg, item.Parent, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Structfields {
+			lines = append(lines, fmt.Sprintf("structfield %v.%v.%v %v", pkg, item.Parent, item.Field, item.Pos))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// dumpVarTable lists data types of variables in the VarTable, one per line
+func dumpVarTable(t *testing.T, varTable *runner.VarTable) []string {
+	var lines []string
+	for _, name := range varTable.Names() {
+		item, _ := varTable.GetVariable(name)
+		if item == nil {
+			continue
+		}
+		def, err := json.Marshal(item.DataType())
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, fmt.Sprintf("%v: %s", name, def))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// TestRunReceivers checks methods of data types passed as interfaces are allocated,
+// incl. methods with value and pointer receivers and methods promoted from embedded fields
+func TestRunReceivers(t *testing.T) {
+	pkg := "example.com/receivers"
+	allocated, err := parse(t, newParser(t), pkg).GlobalAllocTable().MergeFiles(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var methods []string
+	for _, line := range dumpAllocated(allocated[alloctable.ProductionScope]) {
+		if strings.HasPrefix(line, "method ") {
+			methods = append(methods, line)
+		}
+	}
+	expected := []string{
+		// size(dir) with pointer receivers of *Dir,
+		// size(mount) with methods promoted from the embedded *Dir
+		"method example.com/receivers.Dir.Name receivers.go:51:19",
+		"method example.com/receivers.Dir.Name receivers.go:53:19",
+		"method example.com/receivers.Dir.Size receivers.go:51:19",
+		"method example.com/receivers.Dir.Size receivers.go:53:19",
+		// namer Namer = link, promoted from the embedded File (at the link declaration)
+		"method example.com/receivers.File.Name receivers.go:46:2",
+		// name(file), value receiver of File
+		"method example.com/receivers.File.Name receivers.go:49:19",
+		// name(&file), value receiver of *File
+		"method example.com/receivers.File.Name receivers.go:50:19",
+		// name(link), promoted from the embedded File
+		"method example.com/receivers.File.Name receivers.go:52:19",
+	}
+	if !reflect.DeepEqual(methods, expected) {
+		t.Errorf("Expected:\n%v\ngot:\n%v", strings.Join(expected, "\n"), strings.Join(methods, "\n"))
+	}
+}
+
+// TestRunWorklist checks symbols allocated by the contracts and data types of the variables
+// do not depend on the order the contracts are evaluated in (the same on each run)
+func TestRunWorklist(t *testing.T) {
+	pkg := "example.com/worklist"
+	expected, err := ioutil.ReadFile("testdata/worklist.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		p := parse(t, newParser(t), pkg)
+		allocated, err := p.GlobalAllocTable().MergeFiles(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contractTable, err := p.GlobalContractsTable().Lookup(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := runner.New(pkg, p.GlobalSymbolTable(), p.GlobalAllocTable(), contractTable)
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+
+		var lines []string
+		lines = append(lines, dumpAllocated(allocated[alloctable.ProductionScope])...)
+		lines = append(lines, dumpVarTable(t, r.VarTable())...)
+		if actual := strings.Join(lines, "\n") + "\n"; actual != string(expected) {
+			t.Fatalf("Run %v: expected:\n%s\ngot:\n%s", i, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package generics
+
+// Number is a union constraint
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Bytes has string and []byte in its type set, both indexed by bytes
+type Bytes interface {
+	string | []byte
+}
+
+// Sum applies binary operations on type parameter operands
+func Sum[T Number](values ...T) T {
+	var s T
+	for _, v := range values {
+		s = s + v
+	}
+	return s
+}
+
+func Less[T Number](x, y T) bool {
+	return x < y || (x != x && y == y)
+}
+
+// First indexes an operand of a union of string and []byte
+func First[T Bytes](word T) byte {
+	return word[0]
+}
+
+func Map[S ~[]E, E, R any](s S, f func(E) R) []R {
+	r := make([]R, 0, len(s))
+	for _, item := range s {
+		r = append(r, f(item))
+	}
+	return r
+}
+
+type List[T any] struct {
+	items []T
+}
+
+// Push is a method on a generic receiver
+func (l *List[T]) Push(item T) {
+	l.items = append(l.items, item)
+}
+
+func (l List[T]) Len() int {
+	return len(l.items)
+}
+
+func (l List[T]) At(i int) T {
+	return l.items[i]
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Named embeds an instance of a generic type
+type Named struct {
+	List[string]
+	Pair[string, int]
+	Name string
+}
+
+type Ints []int
+
+func double(i int) int {
+	return i * 2
+}
+
+func size(s string) int {
+	return len(s)
+}
+
+var (
+	total   = Sum(1, 2, 3)
+	ftotal  = Sum[float64](1.5, 2)
+	less    = Less(total, 4)
+	first   = First("word")
+	doubled = Map(Ints{1, 2}, double)
+	sizes   = Map([]string{"a"}, size)
+)
+
+func Use() (int, string, int) {
+	var n Named
+	n.Push("a")
+	n.Key = "k"
+	return n.Len(), n.At(0) + n.Key, n.Value + int(first)
+}
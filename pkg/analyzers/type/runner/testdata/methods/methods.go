@@ -0,0 +1,40 @@
+package methods
+
+type Handler struct {
+	prefix string
+}
+
+func (h *Handler) ServeHTTP(path string) int {
+	return len(h.prefix + path)
+}
+
+func (h Handler) Prefix() string {
+	return h.prefix
+}
+
+type Server struct{}
+
+func (s *Server) Handle(path string, f func(string) int) int {
+	return f(path)
+}
+
+type Prefixer interface {
+	Prefix() string
+}
+
+var (
+	h   = &Handler{}
+	srv = &Server{}
+	// method value passed as an argument
+	handled = srv.Handle("/", h.ServeHTTP)
+	// method value stored in a variable
+	serve  = h.ServeHTTP
+	served = serve("/")
+	// method expressions
+	prefix    = Handler.Prefix
+	prefixed  = prefix(Handler{})
+	serveExpr = (*Handler).ServeHTTP
+	// method value of an interface
+	p       Prefixer = Handler{}
+	pPrefix          = p.Prefix
+)
@@ -0,0 +1,56 @@
+package receivers
+
+type Namer interface {
+	Name() string
+}
+
+type Sizer interface {
+	Namer
+	Size() int
+}
+
+type File struct {
+	name string
+}
+
+func (f File) Name() string { return f.name }
+
+type Dir struct {
+	name  string
+	files []File
+}
+
+func (d *Dir) Name() string { return d.name }
+
+func (d *Dir) Size() int { return len(d.files) }
+
+// Link promotes Name of the embedded File
+type Link struct {
+	File
+	target string
+}
+
+// Mount promotes Name and Size of the embedded *Dir
+type Mount struct {
+	*Dir
+	device string
+}
+
+func name(n Namer) string { return n.Name() }
+
+func size(s Sizer) int { return s.Size() }
+
+var (
+	file  = File{name: "a"}
+	dir   = &Dir{name: "b"}
+	link  = Link{File: file}
+	mount = Mount{Dir: dir}
+
+	fileName  = name(file)
+	filePtr   = name(&file)
+	dirSize   = size(dir)
+	linkName  = name(link)
+	mountSize = size(mount)
+
+	namer Namer = link
+)
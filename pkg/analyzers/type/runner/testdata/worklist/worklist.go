@@ -0,0 +1,53 @@
+package worklist
+
+import "unicode/utf8"
+
+type Item struct {
+	Name  string
+	Count int
+	next  *Item
+}
+
+func (i *Item) Next() *Item {
+	return i.next
+}
+
+func (i Item) Len() int {
+	return utf8.RuneCountInString(i.Name)
+}
+
+type Items []*Item
+
+type Index map[string]*Item
+
+func NewIndex(items Items) Index {
+	index := make(Index)
+	for _, item := range items {
+		index[item.Name] = item
+	}
+	return index
+}
+
+// Total walks a chain of items, each data type depends on the previous one
+func Total(index Index, name string) (int, []int) {
+	var lens []int
+	total := 0
+	for item := index[name]; item != nil; item = item.Next() {
+		total += item.Count
+		lens = append(lens, item.Len())
+	}
+	valid := func(s string) bool {
+		return utf8.ValidString(s)
+	}
+	first := (*index[name]).Name
+	if !valid(first) {
+		return 0, nil
+	}
+	return total, lens
+}
+
+var (
+	head  = &Item{Name: "head", next: &Item{Name: "tail"}}
+	index = NewIndex(Items{head, head.next})
+	count = int64(len(index))
+)
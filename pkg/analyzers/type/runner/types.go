@@ -25,9 +25,18 @@ func (v *var2Contract) addVar(name string, c contracts.Contract) {
 	if _, ok := v.vars[name]; !ok {
 		v.vars[name] = make([]contracts.Contract, 0)
 	}
+	// a contract can refer to the same variable more than once
+	if l := len(v.vars[name]); l > 0 && v.vars[name][l-1] == c {
+		return
+	}
 	v.vars[name] = append(v.vars[name], c)
 }
 
+// contracts lists contracts referring to the variable
+func (v *var2Contract) contracts(name string) []contracts.Contract {
+	return v.vars[name]
+}
+
 type contractPayload struct {
 	items map[string][]contracts.Contract
 }
@@ -55,13 +64,18 @@ func (cp *contractPayload) contracts() map[string][]contracts.Contract {
 	return cp.items
 }
 
-func (cp *contractPayload) sortedContracts() (ctrs []contracts.Contract) {
+// functions lists sorted names of functions with contracts
+func (cp *contractPayload) functions() []string {
 	var keys []string
 	for fnc := range cp.items {
 		keys = append(keys, fnc)
 	}
 	sort.Strings(keys)
-	for _, key := range keys {
+	return keys
+}
+
+func (cp *contractPayload) sortedContracts() (ctrs []contracts.Contract) {
+	for _, key := range cp.functions() {
 		for _, c := range cp.items[key] {
 			ctrs = append(ctrs, c)
 		}
@@ -116,6 +130,8 @@ type VarTable struct {
 	// variable name, field
 	fields   map[string]map[string]*varTableItem
 	fieldsAt map[string]map[int]*varTableItem
+	// variables set (or whose fields were set) since the last takeTouched call
+	touched map[string]struct{}
 }
 
 func newVarTable() *VarTable {
@@ -123,6 +139,7 @@ func newVarTable() *VarTable {
 		variables: make(map[string]*varTableItem),
 		fields:    make(map[string]map[string]*varTableItem),
 		fieldsAt:  make(map[string]map[int]*varTableItem),
+		touched:   make(map[string]struct{}),
 	}
 }
 
@@ -136,6 +153,7 @@ func (v *VarTable) Names() []string {
 
 func (v *VarTable) SetVariable(name string, item *varTableItem) {
 	v.variables[name] = item
+	v.touched[name] = struct{}{}
 }
 
 func (v *VarTable) GetVariable(name string) (*varTableItem, bool) {
@@ -150,6 +168,7 @@ func (v *VarTable) SetField(name, field string, item *varTableItem) {
 		v.fields[name] = make(map[string]*varTableItem)
 	}
 	v.fields[name][field] = item
+	v.touched[name] = struct{}{}
 }
 
 func (v *VarTable) SetFieldAt(name string, idx int, item *varTableItem) {
@@ -157,6 +176,17 @@ func (v *VarTable) SetFieldAt(name string, idx int, item *varTableItem) {
 		v.fieldsAt[name] = make(map[int]*varTableItem)
 	}
 	v.fieldsAt[name][idx] = item
+	v.touched[name] = struct{}{}
+}
+
+// takeTouched lists variables set (or whose fields were set) since the last call
+func (v *VarTable) takeTouched() []string {
+	var names []string
+	for name := range v.touched {
+		names = append(names, name)
+	}
+	v.touched = make(map[string]struct{})
+	return names
 }
 
 func (v *VarTable) GetField(name, field string) (*varTableItem, bool) {
@@ -0,0 +1,290 @@
+package incremental
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+)
+
+// Symbol identifies a package-level symbol (a method is identified by its receiver data type)
+type Symbol struct {
+	Package string
+	Name    string
+}
+
+func (s Symbol) String() string {
+	return fmt.Sprintf("%v.%v", s.Package, s.Name)
+}
+
+// Changes captures changed symbols of each package
+type Changes map[string]map[string]struct{}
+
+// Record records the package (with no changed symbols unless added)
+func (c Changes) Record(pkg string) {
+	if _, ok := c[pkg]; !ok {
+		c[pkg] = make(map[string]struct{})
+	}
+}
+
+// Recorded checks the package is recorded (its changed symbols are known)
+func (c Changes) Recorded(pkg string) bool {
+	_, ok := c[pkg]
+	return ok
+}
+
+func (c Changes) Add(pkg, name string) {
+	c.Record(pkg)
+	c[pkg][name] = struct{}{}
+}
+
+func (c Changes) Contains(s Symbol) bool {
+	_, ok := c[s.Package][s.Name]
+	return ok
+}
+
+// Symbols lists sorted changed symbols of the package
+func (c Changes) Symbols(pkg string) []string {
+	var names []string
+	for name := range c[pkg] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Used lists sorted changed symbols among the referenced ones
+func (c Changes) Used(refs map[Symbol]struct{}) []string {
+	var used []string
+	for s := range refs {
+		if c.Contains(s) {
+			used = append(used, s.String())
+		}
+	}
+	sort.Strings(used)
+	return used
+}
+
+// apiSymbols decodes definitions of symbols of the table (positions stripped) keyed by the symbol name.
+// Methods are keyed by their receiver data type (a changed method changes the method set of the data type).
+func apiSymbols(table *tables.Table) (map[string][]interface{}, error) {
+	data, err := json.Marshal(table)
+	if err != nil {
+		return nil, err
+	}
+	var api struct {
+		Symbols map[string][]map[string]interface{} `json:"symbols"`
+	}
+	if err := json.Unmarshal(data, &api); err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[string][]interface{})
+	// canonical JSON of each definition (a loaded table may list a definition more than once)
+	seen := make(map[string]struct{})
+	for _, list := range api.Symbols {
+		for _, sym := range list {
+			name, _ := sym["name"].(string)
+			if def, ok := sym["def"].(map[string]interface{}); ok && def["type"] == "method" {
+				if receiver := receiverName(def["receiver"]); receiver != "" {
+					name = receiver
+				}
+			}
+			stripped := stripPositions(sym)
+			canonical, err := json.Marshal(stripped)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := seen[name+"#"+string(canonical)]; ok {
+				continue
+			}
+			seen[name+"#"+string(canonical)] = struct{}{}
+			symbols[name] = append(symbols[name], stripped)
+		}
+	}
+	// the order of methods of a data type does not matter
+	for name, defs := range symbols {
+		sort.Slice(defs, func(i, j int) bool {
+			a, _ := json.Marshal(defs[i])
+			b, _ := json.Marshal(defs[j])
+			return string(a) < string(b)
+		})
+		symbols[name] = defs
+	}
+	return symbols, nil
+}
+
+// receiverName returns a name of the data type of the method receiver (e.g. T of *T)
+func receiverName(v interface{}) string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if m["type"] == "identifier" {
+		name, _ := m["def"].(string)
+		return name
+	}
+	return receiverName(m["def"])
+}
+
+func stripPositions(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{})
+		for key, value := range t {
+			switch strings.ToLower(key) {
+			case "pos", "position":
+				continue
+			}
+			stripped[key] = stripPositions(value)
+		}
+		return stripped
+	case []interface{}:
+		var stripped []interface{}
+		for _, value := range t {
+			stripped = append(stripped, stripPositions(value))
+		}
+		return stripped
+	}
+	return v
+}
+
+// Diff lists symbols of the package defined differently in the previous and the current symbol table
+// (incl. added and removed symbols). Positions of the symbols are not compared.
+func Diff(previous, current *tables.Table) ([]string, error) {
+	prev, err := apiSymbols(previous)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := apiSymbols(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for name, defs := range cur {
+		if !reflect.DeepEqual(defs, prev[name]) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Propagate marks symbols of the package whose definitions refer to changed symbols
+// (of the package itself or of already propagated imported packages) as changed as well.
+// E.g. a function returning a data type with a changed method is changed.
+// The package is recorded afterwards.
+func Propagate(pkg string, table *tables.Table, changes Changes) error {
+	changes.Record(pkg)
+	symbols, err := apiSymbols(table)
+	if err != nil {
+		return err
+	}
+	refs := make(map[string]map[Symbol]struct{})
+	for name, defs := range symbols {
+		refs[name] = make(map[Symbol]struct{})
+		collect(defs, refs[name])
+	}
+
+	for {
+		propagated := false
+		for name := range symbols {
+			if changes.Contains(Symbol{Package: pkg, Name: name}) {
+				continue
+			}
+			if len(changes.Used(refs[name])) > 0 {
+				changes.Add(pkg, name)
+				propagated = true
+			}
+		}
+		if !propagated {
+			return nil
+		}
+	}
+}
+
+// ContractsReferences collects symbols referenced in the contracts (marshaled into JSON):
+// variables of other packages and data types used in the contracts.
+func ContractsReferences(contracts interface{}) (map[Symbol]struct{}, error) {
+	data, err := json.Marshal(contracts)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	refs := make(map[Symbol]struct{})
+	collect(v, refs)
+	return refs, nil
+}
+
+// AllocatedReferences collects symbols allocated in the files (methods and struct fields by their data types)
+func AllocatedReferences(files map[string]*alloctable.Table) map[Symbol]struct{} {
+	refs := make(map[Symbol]struct{})
+	for _, table := range files {
+		for pkg, p := range table.Symbols {
+			for _, item := range p.Datatypes {
+				refs[Symbol{Package: pkg, Name: item.Name}] = struct{}{}
+			}
+			for _, item := range p.Functions {
+				refs[Symbol{Package: pkg, Name: item.Name}] = struct{}{}
+			}
+			for _, item := range p.Variables {
+				refs[Symbol{Package: pkg, Name: item.Name}] = struct{}{}
+			}
+			for _, item := range p.Methods {
+				refs[Symbol{Package: pkg, Name: item.Parent}] = struct{}{}
+			}
+			for _, item := range p.Structfields {
+				refs[Symbol{Package: pkg, Name: item.Parent}] = struct{}{}
+			}
+		}
+	}
+	return refs
+}
+
+// collect collects symbols referenced in a decoded JSON document:
+// identifiers (of data types), qualified selectors (pkg.Name) and variables of contracts
+func collect(v interface{}, refs map[Symbol]struct{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		switch t["type"] {
+		case "identifier":
+			name, _ := t["def"].(string)
+			pkg, _ := t["package"].(string)
+			if pkg != "" {
+				refs[Symbol{Package: pkg, Name: name}] = struct{}{}
+			}
+		case "selector":
+			if prefix, ok := t["prefix"].(map[string]interface{}); ok && prefix["type"] == "packagequalifier" {
+				name, _ := t["item"].(string)
+				pkg, _ := prefix["path"].(string)
+				refs[Symbol{Package: pkg, Name: name}] = struct{}{}
+			}
+		case "Variable":
+			name, _ := t["Name"].(string)
+			pkg, _ := t["Package"].(string)
+			if pkg != "" {
+				refs[Symbol{Package: pkg, Name: name}] = struct{}{}
+			}
+		}
+		for _, value := range t {
+			collect(value, refs)
+		}
+	case []interface{}:
+		for _, value := range t {
+			collect(value, refs)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package incremental_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofed/symbols-extractor/pkg/incremental"
+	"github.com/gofed/symbols-extractor/pkg/parser"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+)
+
+// testdataResolver locates packages in the testdata directory
+type testdataResolver map[string]string
+
+func (r testdataResolver) PackageDir(pkg string) (string, error) {
+	if dir, ok := r[pkg]; ok {
+		return filepath.Abs(dir)
+	}
+	return "", fmt.Errorf("Package %q not in testdata", pkg)
+}
+
+// extract extracts the packages into the directory (example.com/a of the version)
+func extract(t *testing.T, symbolTableDir, version string, incremental bool, pkgs ...string) *parser.ProjectParser {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(symbolTableDir, "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetResolver(testdataResolver{
+		"example.com/a": filepath.Join("testdata", version, "a"),
+		"example.com/b": "testdata/b",
+		"example.com/c": "testdata/c",
+		"example.com/d": "testdata/d",
+	}).SetIncremental(incremental)
+	for _, pkg := range pkgs {
+		if err := p.Parse(pkg, true); err != nil {
+			t.Fatalf("Unable to parse %q: %v", pkg, err)
+		}
+	}
+	return p
+}
+
+// symbolTable looks up the symbol table of the package
+func symbolTable(t *testing.T, p *parser.ProjectParser, pkg string) *tables.Table {
+	table, err := p.GlobalSymbolTable().Lookup(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table.(*tables.Table)
+}
+
+func TestDiff(t *testing.T) {
+	v1 := symbolTable(t, extract(t, t.TempDir(), "v1", false, "example.com/a"), "example.com/a")
+	v2 := symbolTable(t, extract(t, t.TempDir(), "v2", false, "example.com/a"), "example.com/a")
+
+	// Counter moved to another line is not changed, the changed Size method changes Item
+	changed, err := incremental.Diff(v1, v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Count", "Item"}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected %v changed, got %v", expected, changed)
+	}
+	if changed, err := incremental.Diff(v1, v1); err != nil || changed != nil {
+		t.Errorf("Expected no changes, got %v (%v)", changed, err)
+	}
+
+	// NewItem refers to the changed Item
+	changes := make(incremental.Changes)
+	for _, name := range changed {
+		changes.Add("example.com/a", name)
+	}
+	if err := incremental.Propagate("example.com/a", v2, changes); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Count", "Item", "NewItem"}; !reflect.DeepEqual(changes.Symbols("example.com/a"), expected) {
+		t.Errorf("Expected %v changed, got %v", expected, changes.Symbols("example.com/a"))
+	}
+
+	// a package referring to no changed symbol is recorded with no changes
+	b := symbolTable(t, extract(t, t.TempDir(), "v1", false, "example.com/b"), "example.com/b")
+	if err := incremental.Propagate("example.com/b", b, changes); err != nil {
+		t.Fatal(err)
+	}
+	if !changes.Recorded("example.com/b") || changes.Symbols("example.com/b") != nil {
+		t.Errorf("Expected example.com/b to be recorded with no changes, got %v", changes["example.com/b"])
+	}
+}
+
+func TestReferences(t *testing.T) {
+	p := extract(t, t.TempDir(), "v1", false, "example.com/b", "example.com/c", "example.com/d")
+	changes := incremental.Changes{
+		"example.com/a": {"Count": {}, "Item": {}, "NewItem": {}},
+	}
+
+	tests := []struct {
+		pkg  string
+		used []string
+	}{
+		{"example.com/b", []string{"example.com/a.Count"}},
+		{"example.com/c", nil},
+		{"example.com/d", []string{"example.com/a.Item", "example.com/a.NewItem"}},
+	}
+	for _, test := range tests {
+		contracts, err := p.GlobalContractsTable().Lookup(test.pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs, err := incremental.ContractsReferences(contracts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := p.GlobalAllocTable().LookupPackage(test.pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for ref := range incremental.AllocatedReferences(files) {
+			refs[ref] = struct{}{}
+		}
+		if used := changes.Used(refs); !reflect.DeepEqual(used, test.used) {
+			t.Errorf("Expected %v to use %v, got %v", test.pkg, test.used, used)
+		}
+	}
+}
+
+// modified lists packages whose contracts were stored since the time
+func modified(t *testing.T, symbolTableDir string, since time.Time, pkgs ...string) map[string]bool {
+	stored := make(map[string]bool)
+	for _, pkg := range pkgs {
+		info, err := os.Stat(filepath.Join(symbolTableDir, pkg, "contracts.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored[pkg] = info.ModTime().After(since)
+	}
+	return stored
+}
+
+func TestIncrementalExtraction(t *testing.T) {
+	symbolTableDir := t.TempDir()
+	pkgs := []string{"example.com/a", "example.com/b", "example.com/c", "example.com/d"}
+	extract(t, symbolTableDir, "v1", false, pkgs[1:]...)
+
+	// artefacts of the first extraction are dated back so the extracted ones are recognized
+	since := time.Now().Add(-time.Hour)
+	for _, pkg := range pkgs {
+		file := filepath.Join(symbolTableDir, pkg, "contracts.json")
+		if err := os.Chtimes(file, since, since); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// only packages using changed symbols of example.com/a are extracted again
+	extract(t, symbolTableDir, "v2", true, pkgs[1:]...)
+	expected := map[string]bool{
+		"example.com/a": true,
+		"example.com/b": true,
+		"example.com/c": false,
+		"example.com/d": true,
+	}
+	if extracted := modified(t, symbolTableDir, since, pkgs...); !reflect.DeepEqual(extracted, expected) {
+		t.Errorf("Expected %v extracted again, got %v", expected, extracted)
+	}
+}
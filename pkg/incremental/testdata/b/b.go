@@ -0,0 +1,8 @@
+package b
+
+import "example.com/a"
+
+// Total uses the changed a.Count
+func Total() int {
+	return int(a.Count()) + 1
+}
@@ -0,0 +1,8 @@
+package c
+
+import "example.com/a"
+
+// Add uses the unchanged a.Counter
+func Add(n int) a.Counter {
+	return a.Zero.Add(n)
+}
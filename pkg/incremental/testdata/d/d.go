@@ -0,0 +1,8 @@
+package d
+
+import "example.com/a"
+
+// Name uses a.NewItem referring to the changed a.Item
+func Name() string {
+	return a.NewItem("d").Name
+}
@@ -0,0 +1,29 @@
+package a
+
+// Item has a method changed in v2
+type Item struct {
+	Name string
+}
+
+func (i Item) Size() int {
+	return len(i.Name)
+}
+
+// NewItem refers to the changed Item
+func NewItem(name string) Item {
+	return Item{Name: name}
+}
+
+// Counter is not changed in v2
+type Counter int
+
+func (c Counter) Add(n int) Counter {
+	return c + Counter(n)
+}
+
+var Zero Counter
+
+// Count is changed in v2
+func Count() int {
+	return 1
+}
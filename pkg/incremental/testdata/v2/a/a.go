@@ -0,0 +1,30 @@
+package a
+
+// Item has a method changed in v2
+type Item struct {
+	Name string
+}
+
+func (i Item) Size() int64 {
+	return int64(len(i.Name))
+}
+
+// NewItem refers to the changed Item
+func NewItem(name string) Item {
+	return Item{Name: name}
+}
+
+// Counter is not changed in v2
+// (its definition is moved to another line)
+type Counter int
+
+func (c Counter) Add(n int) Counter {
+	return c + Counter(n)
+}
+
+var Zero Counter
+
+// Count is changed in v2
+func Count() int64 {
+	return 1
+}
@@ -0,0 +1,150 @@
+package loader
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofed/symbols-extractor/pkg/platforms"
+	"k8s.io/klog/v2"
+)
+
+// Loader lists packages in-process (through go/build) instead of running `go list`.
+// Every listing (including a failed one) is cached so each package directory is read at most once.
+type Loader struct {
+	ctx *build.Context
+
+	mutex  sync.Mutex
+	byPath map[string]*listing
+	byDir  map[string]*listing
+}
+
+type listing struct {
+	pkg *build.Package
+	err error
+}
+
+var (
+	loadersMutex sync.Mutex
+	loaders      = make(map[string]*Loader)
+)
+
+// New creates a loader listing packages in a build context
+func New(ctx *build.Context) *Loader {
+	return &Loader{
+		ctx:    ctx,
+		byPath: make(map[string]*listing),
+		byDir:  make(map[string]*listing),
+	}
+}
+
+// For returns a loader shared by everyone listing packages for the platform.
+// If platform is nil, the host build context is used.
+func For(platform *platforms.Platform) *Loader {
+	loadersMutex.Lock()
+	defer loadersMutex.Unlock()
+
+	key := ""
+	ctx := &build.Default
+	if platform != nil {
+		key = platform.String()
+		ctx = platform.Context()
+	}
+
+	if l, ok := loaders[key]; ok {
+		return l
+	}
+	l := New(ctx)
+	loaders[key] = l
+	return l
+}
+
+// Import lists a package given by its import path (located under GOROOT or GOPATH).
+// If the package directory has no buildable Go files, the package is returned with *build.NoGoError.
+func (l *Loader) Import(path string) (*build.Package, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if c, ok := l.byPath[path]; ok {
+		return c.pkg, c.err
+	}
+
+	klog.V(2).Infof("Listing %v package", path)
+	pkg, err := l.ctx.Import(path, "", 0)
+	c := &listing{pkg: pkg, err: err}
+	l.byPath[path] = c
+	if pkg != nil && pkg.Dir != "" {
+		if _, ok := l.byDir[pkg.Dir]; !ok {
+			l.byDir[pkg.Dir] = c
+		}
+	}
+	return pkg, err
+}
+
+// ImportDir lists a package in a directory.
+// If the directory has no buildable Go files, the package is returned with *build.NoGoError.
+func (l *Loader) ImportDir(dir string) (*build.Package, error) {
+	dir = filepath.Clean(dir)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if c, ok := l.byDir[dir]; ok {
+		return c.pkg, c.err
+	}
+
+	klog.V(2).Infof("Listing %v directory", dir)
+	pkg, err := l.ctx.ImportDir(dir, 0)
+	l.byDir[dir] = &listing{pkg: pkg, err: err}
+	return pkg, err
+}
+
+// Std lists all packages of the standard library (including the GOROOT/src/vendor packages).
+// Packages with no buildable Go files (for the build context) are skipped.
+func (l *Loader) Std() ([]string, error) {
+	if l.ctx.GOROOT == "" {
+		return nil, fmt.Errorf("GOROOT not set")
+	}
+	root := filepath.Join(l.ctx.GOROOT, "src")
+
+	var packages []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root {
+			// Cited from https://golang.org/cmd/go/#hdr-Package_lists:
+			//   Directory and file names that begin with "." or "_" are ignored
+			//   by the go tool, as are directories named "testdata".
+			if base := info.Name(); base[0] == '.' || base[0] == '_' || base == "testdata" {
+				return filepath.SkipDir
+			}
+			// nested modules (e.g. cmd) are not part of the standard library
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+
+		if _, err := l.ImportDir(path); err != nil {
+			if _, ok := err.(*build.NoGoError); ok {
+				return nil
+			}
+			return err
+		}
+		packages = append(packages, filepath.ToSlash(strings.TrimPrefix(path, root+string(filepath.Separator))))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list packages under %v: %v", root, err)
+	}
+
+	sort.Strings(packages)
+	return packages, nil
+}
@@ -0,0 +1,139 @@
+package loader
+
+import (
+	"go/build"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/platforms"
+)
+
+func writeFile(t *testing.T, file, content string) {
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportDirCache(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.go"), "package a\n")
+
+	ctx := build.Default
+	l := New(&ctx)
+
+	pkg, err := l.ImportDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the directory is not read again
+	writeFile(t, filepath.Join(dir, "b.go"), "package a\n")
+	cached, err := l.ImportDir(dir + string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != pkg {
+		t.Errorf("Expected the cached listing of %v", dir)
+	}
+	if !reflect.DeepEqual(cached.GoFiles, []string{"a.go"}) {
+		t.Errorf("Expected [a.go] files, got %v", cached.GoFiles)
+	}
+	if pkg, _ := New(&ctx).ImportDir(dir); !reflect.DeepEqual(pkg.GoFiles, []string{"a.go", "b.go"}) {
+		t.Errorf("Expected [a.go b.go] files listed by a new loader, got %v", pkg.GoFiles)
+	}
+
+	// failed listings are cached as well
+	empty := t.TempDir()
+	if _, err := l.ImportDir(empty); err == nil {
+		t.Fatalf("Expected %v with no Go files to fail", empty)
+	}
+	writeFile(t, filepath.Join(empty, "c.go"), "package c\n")
+	if _, err := l.ImportDir(empty); err == nil {
+		t.Errorf("Expected the cached failure of %v", empty)
+	} else if _, ok := err.(*build.NoGoError); !ok {
+		t.Errorf("Expected *build.NoGoError, got %#v", err)
+	}
+}
+
+func TestImportCache(t *testing.T) {
+	ctx := build.Default
+	l := New(&ctx)
+
+	pkg, err := l.Import("errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := l.Import("errors"); cached != pkg {
+		t.Errorf("Expected the cached listing of errors")
+	}
+	// a package listed by its import path is cached under its directory as well
+	if cached, _ := l.ImportDir(pkg.Dir); cached != pkg {
+		t.Errorf("Expected the cached listing of %v", pkg.Dir)
+	}
+}
+
+func TestFor(t *testing.T) {
+	parse := func(spec string) *platforms.Platform {
+		p, err := platforms.Parse(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	if For(nil) != For(nil) {
+		t.Errorf("Expected the host loader to be shared")
+	}
+	linux := For(parse("linux/amd64"))
+	if linux != For(parse("linux/amd64")) {
+		t.Errorf("Expected the linux/amd64 loader to be shared")
+	}
+	if linux == For(parse("linux/amd64/nocgo")) || linux == For(parse("windows/amd64")) {
+		t.Errorf("Expected a loader per platform")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":         "package a\n",
+		"a_linux.go":   "package a\n",
+		"a_windows.go": "package a\n",
+		"arm64.go":     "//go:build arm64\n\npackage a\n",
+		"cgo.go":       "package a\n\nimport \"C\"\n",
+		"nocgo.go":     "//go:build !cgo\n\npackage a\n",
+	}
+	for file, content := range files {
+		writeFile(t, filepath.Join(dir, file), content)
+	}
+
+	tests := []struct {
+		platform string
+		goFiles  []string
+		cgoFiles []string
+	}{
+		{
+			platform: "linux/amd64",
+			goFiles:  []string{"a.go", "a_linux.go"},
+			cgoFiles: []string{"cgo.go"},
+		},
+		{
+			platform: "linux/arm64/nocgo",
+			goFiles:  []string{"a.go", "a_linux.go", "arm64.go", "nocgo.go"},
+		},
+		{
+			platform: "windows/amd64/nocgo",
+			goFiles:  []string{"a.go", "a_windows.go", "nocgo.go"},
+		},
+	}
+
+	for _, test := range tests {
+		pkg, err := For(parse(test.platform)).ImportDir(dir)
+		if err != nil {
+			t.Errorf("Unable to list %v for %v: %v", dir, test.platform, err)
+			continue
+		}
+		if !reflect.DeepEqual(pkg.GoFiles, test.goFiles) || !reflect.DeepEqual(pkg.CgoFiles, test.cgoFiles) {
+			t.Errorf("Expected %v and %v cgo files for %v, got %v and %v", test.goFiles, test.cgoFiles, test.platform, pkg.GoFiles, pkg.CgoFiles)
+		}
+	}
+}
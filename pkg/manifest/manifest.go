@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/store"
+)
+
+// ExtractorVersion is a version of the extraction recorded in every manifest.
+// It must be increased whenever the extracted artefacts change (so they get re-extracted).
+const ExtractorVersion = "1"
+
+// Manifest records inputs of artefacts of an extracted package so stale artefacts
+// (e.g. of locally edited sources under the same commit) are detected
+type Manifest struct {
+	Package string `json:"package"`
+	// Extractor version the artefacts were extracted by
+	Extractor string `json:"extractor"`
+	// Build context the package files were selected for (go version and platform)
+	Context string `json:"context"`
+	// sha256 of each package file
+	Files map[string]string `json:"files"`
+	// sha256 of all the above
+	Hash string `json:"hash"`
+}
+
+// Compute computes a manifest of package files located in the dir
+func Compute(pkg, dir string, files []string, context string) (*Manifest, error) {
+	m := &Manifest{
+		Package:   pkg,
+		Extractor: ExtractorVersion,
+		Context:   context,
+		Files:     make(map[string]string),
+	}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("Unable to hash %v: %v", file, err)
+		}
+		sum := sha256.Sum256(content)
+		m.Files[file] = hex.EncodeToString(sum[:])
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "extractor:%v\ncontext:%v\n", m.Extractor, m.Context)
+	sorted := make([]string, 0, len(m.Files))
+	for file := range m.Files {
+		sorted = append(sorted, file)
+	}
+	sort.Strings(sorted)
+	for _, file := range sorted {
+		fmt.Fprintf(h, "%v:%v\n", file, m.Files[file])
+	}
+	m.Hash = hex.EncodeToString(h.Sum(nil))
+	return m, nil
+}
+
+// Diff lists differences of the manifest (of stored artefacts) from the other manifest (of current sources)
+func (m *Manifest) Diff(other *Manifest) []string {
+	if m.Hash == other.Hash {
+		return nil
+	}
+	var diffs []string
+	if m.Extractor != other.Extractor {
+		diffs = append(diffs, fmt.Sprintf("extracted by version %v, current version is %v", m.Extractor, other.Extractor))
+	}
+	if m.Context != other.Context {
+		diffs = append(diffs, fmt.Sprintf("extracted for %v, current context is %v", m.Context, other.Context))
+	}
+	var files []string
+	for file := range m.Files {
+		files = append(files, file)
+	}
+	for file := range other.Files {
+		if _, ok := m.Files[file]; !ok {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		hash, ok := m.Files[file]
+		otherHash, otherOk := other.Files[file]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("%v added", file))
+		case !otherOk:
+			diffs = append(diffs, fmt.Sprintf("%v removed", file))
+		case hash != otherHash:
+			diffs = append(diffs, fmt.Sprintf("%v changed", file))
+		}
+	}
+	return diffs
+}
+
+// Table keeps manifests of extracted packages next to their artefacts
+type Table struct {
+	symbolTableDir string
+	artefacts      store.Store
+	goVersion      string
+	glide          snapshots.Snapshot
+}
+
+func New(symbolTableDir, goVersion string, snapshot snapshots.Snapshot) *Table {
+	return &Table{
+		symbolTableDir: symbolTableDir,
+		artefacts:      store.For(symbolTableDir),
+		goVersion:      goVersion,
+		glide:          snapshot,
+	}
+}
+
+func (t *Table) key(pkg string) store.Key {
+	return store.PackageKey(t.artefacts, pkg, t.goVersion, t.glide, store.ManifestKind)
+}
+
+func (t *Table) Exists(pkg string) bool {
+	return t.artefacts.Exists(t.key(pkg))
+}
+
+func (t *Table) Load(pkg string) (*Manifest, error) {
+	return t.load(t.key(pkg))
+}
+
+func (t *Table) load(key store.Key) (*Manifest, error) {
+	raw, err := t.artefacts.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("Unable to load %v manifest: %v", key.Path(), err)
+	}
+	return &m, nil
+}
+
+// Save stores the manifest (replacing the stored one)
+func (t *Table) Save(m *Manifest) error {
+	if t.symbolTableDir == "" {
+		return nil
+	}
+	byteSlice, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("Unable to save %q manifest: %v", m.Package, err)
+	}
+	return t.artefacts.Save(t.key(m.Package), byteSlice)
+}
+
+// DropArtefacts removes all stored artefacts (incl. the manifest) of the package
+func (t *Table) DropArtefacts(pkg string) error {
+	key := t.key(pkg)
+	key.Kind = store.AnyKind
+	return t.artefacts.Drop(key)
+}
+
+// Walk calls the fn for every stored manifest (with a location of the manifest)
+func (t *Table) Walk(fn func(location string, m *Manifest) error) error {
+	return t.artefacts.Walk(func(key store.Key) error {
+		if key.Kind != store.ManifestKind {
+			return nil
+		}
+		m, err := t.load(key)
+		if err != nil {
+			return err
+		}
+		return fn(key.Path(), m)
+	})
+}
@@ -0,0 +1,157 @@
+package manifest
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates package files in a temporary directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+var files = map[string]string{
+	"a.go": "package a\n\nfunc A() {}\n",
+	"b.go": "package a\n\nfunc B() {}\n",
+}
+
+func TestComputeHashStability(t *testing.T) {
+	dir := writeFiles(t, files)
+	m, err := Compute("example.com/a", dir, []string{"a.go", "b.go"}, "go1.20 linux/amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Hash) != 64 || len(m.Files) != 2 {
+		t.Errorf("Expected sha256 of 2 files, got %#v", m)
+	}
+
+	// the same sources in another directory, listed in another order
+	other, err := Compute("example.com/a", writeFiles(t, files), []string{"b.go", "a.go"}, "go1.20 linux/amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, other) {
+		t.Errorf("Expected %#v, got %#v", m, other)
+	}
+	if diffs := m.Diff(other); diffs != nil {
+		t.Errorf("Expected no differences, got %v", diffs)
+	}
+
+	if _, err := Compute("example.com/a", dir, []string{"missing.go"}, ""); err == nil {
+		t.Errorf("Expected a missing file to be reported")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	stored, err := Compute("example.com/a", writeFiles(t, files), []string{"a.go", "b.go"}, "go1.20 linux/amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		context string
+		diffs   []string
+	}{
+		{
+			name:    "file changed",
+			files:   map[string]string{"a.go": files["a.go"] + "\nfunc C() {}\n", "b.go": files["b.go"]},
+			context: "go1.20 linux/amd64",
+			diffs:   []string{"a.go changed"},
+		},
+		{
+			name:    "file added and removed",
+			files:   map[string]string{"a.go": files["a.go"], "c.go": files["b.go"]},
+			context: "go1.20 linux/amd64",
+			diffs:   []string{"b.go removed", "c.go added"},
+		},
+		{
+			name:    "context changed",
+			files:   files,
+			context: "go1.21 linux/arm64",
+			diffs:   []string{"extracted for go1.20 linux/amd64, current context is go1.21 linux/arm64"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var names []string
+			for name := range test.files {
+				names = append(names, name)
+			}
+			current, err := Compute("example.com/a", writeFiles(t, test.files), names, test.context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if current.Hash == stored.Hash {
+				t.Errorf("Expected the hash to change")
+			}
+			if diffs := stored.Diff(current); !reflect.DeepEqual(diffs, test.diffs) {
+				t.Errorf("Expected %v, got %v", test.diffs, diffs)
+			}
+		})
+	}
+
+	// artefacts of another extractor version
+	previous := *stored
+	previous.Extractor = "0"
+	previous.Hash = "0"
+	expected := []string{"extracted by version 0, current version is " + ExtractorVersion}
+	if diffs := previous.Diff(stored); !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("Expected %v, got %v", expected, diffs)
+	}
+}
+
+func TestTable(t *testing.T) {
+	symbolTableDir := t.TempDir()
+	table := New(symbolTableDir, "", nil)
+	m, err := Compute("example.com/a", writeFiles(t, files), []string{"a.go", "b.go"}, "go1.20 linux/amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Exists(m.Package) {
+		t.Errorf("Expected no manifest of %v", m.Package)
+	}
+	if err := table.Save(m); err != nil {
+		t.Fatal(err)
+	}
+
+	// a manifest is loaded by another table of the directory
+	loaded, err := New(symbolTableDir, "", nil).Load(m.Package)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, m) {
+		t.Errorf("Expected %#v, got %#v", m, loaded)
+	}
+
+	var locations []string
+	err = table.Walk(func(location string, walked *Manifest) error {
+		locations = append(locations, location)
+		if !reflect.DeepEqual(walked, m) {
+			t.Errorf("Expected %#v, got %#v", m, walked)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"example.com/a/manifest.json"}; !reflect.DeepEqual(locations, expected) {
+		t.Errorf("Expected %v, got %v", expected, locations)
+	}
+
+	if err := table.DropArtefacts(m.Package); err != nil {
+		t.Fatal(err)
+	}
+	if table.Exists(m.Package) {
+		t.Errorf("Expected the manifest of %v to be dropped", m.Package)
+	}
+}
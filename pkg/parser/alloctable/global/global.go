@@ -3,13 +3,12 @@ package global
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path"
 	"sort"
+	"sync"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
 	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/store"
 	"k8s.io/klog/v2"
 )
 
@@ -17,36 +16,48 @@ import (
 type Table struct {
 	tables         map[string]PackageTable
 	symbolTableDir string
+	artefacts      store.Store
 	goVersion      string
 	glide          snapshots.Snapshot
+	// scope of the allocated symbols (see alloctable.TestScope)
+	scope string
+	// guards the tables (used by concurrent package extractions)
+	mutex sync.Mutex
 }
 
 func New(symbolTableDir, goVersion string, snapshot snapshots.Snapshot) *Table {
 	return &Table{
 		tables:         make(map[string]PackageTable, 0),
 		symbolTableDir: symbolTableDir,
+		artefacts:      store.For(symbolTableDir),
 		goVersion:      goVersion,
 		glide:          snapshot,
 	}
 }
 
-func (t *Table) getPackagePath(pkg string) string {
-	packagePath := path.Join(t.symbolTableDir, "golang", t.goVersion, pkg)
-	if _, err := os.Stat(packagePath); err == nil {
-		return packagePath
-	}
+// NewTest creates a table of symbols allocated in test files.
+// The table is stored in allocated_test.json next to allocated.json.
+func NewTest(symbolTableDir, goVersion string, snapshot snapshots.Snapshot) *Table {
+	t := New(symbolTableDir, goVersion, snapshot)
+	t.scope = alloctable.TestScope
+	return t
+}
 
-	packagePath = path.Join(t.symbolTableDir, pkg)
-	if t.glide != nil {
-		if commit, err := t.glide.Commit(pkg); err == nil {
-			return path.Join(packagePath, commit)
-		}
+func (t *Table) kind() store.Kind {
+	if t.scope == alloctable.ProductionScope {
+		return store.AllocatedKind
 	}
+	return store.Kind(fmt.Sprintf("allocated_%v", t.scope))
+}
 
-	return packagePath
+func (t *Table) key(pkg string) store.Key {
+	return store.PackageKey(t.artefacts, pkg, t.goVersion, t.glide, t.kind())
 }
 
 func (t *Table) Add(packagePath, file string, table *alloctable.Table) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	if _, ok := t.tables[packagePath]; !ok {
 		t.tables[packagePath] = *NewPackageTable()
 	}
@@ -57,6 +68,9 @@ func (t *Table) Add(packagePath, file string, table *alloctable.Table) {
 }
 
 func (t *Table) Packages() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	var packages []string
 	for key, _ := range t.tables {
 		packages = append(packages, key)
@@ -78,6 +92,9 @@ func (t *Table) Print(packagePath string, all bool) {
 }
 
 func (t *Table) Files(packagePath string) []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	var files []string
 	fileTable, ok := t.tables[packagePath]
 	if !ok {
@@ -89,9 +106,14 @@ func (t *Table) Files(packagePath string) []string {
 	return files
 }
 
-// MergeFiles merges all per-file allocated tables into one
+// MergeFiles merges all per-file allocated tables into one.
+// The merged table is keyed by the table scope.
 func (t *Table) MergeFiles(packagePath string) (PackageTable, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	maTable := alloctable.New(packagePath, "")
+	maTable.Scope = t.scope
 	table, ok := t.tables[packagePath]
 	if !ok {
 		return nil, fmt.Errorf("Unable to find %q package", packagePath)
@@ -99,31 +121,34 @@ func (t *Table) MergeFiles(packagePath string) (PackageTable, error) {
 	for _, atable := range table {
 		for pkg, symbolSets := range atable.Symbols {
 			for _, item := range symbolSets.Datatypes {
-				maTable.AddDataType(pkg, item.Name, item.Pos)
+				maTable.AddDataType(pkg, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSets.Functions {
-				maTable.AddFunction(pkg, item.Name, item.Pos)
+				maTable.AddFunction(pkg, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSets.Variables {
-				maTable.AddVariable(pkg, item.Name, item.Pos)
+				maTable.AddVariable(pkg, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSets.Methods {
-				maTable.AddMethod(pkg, item.Parent, item.Name, item.Pos)
+				maTable.AddMethod(pkg, item.Parent, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSets.Structfields {
-				maTable.AddStructField(pkg, item.Parent, item.Field, item.Pos)
+				maTable.AddStructField(pkg, item.Parent, item.Field, item.Position.Or(item.Pos))
 			}
 		}
 	}
 	return PackageTable{
-		"": maTable,
+		t.scope: maTable,
 	}, nil
 }
 
 func (t *Table) Lookup(packagePath, file string) (*alloctable.Table, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	files, ok := t.tables[packagePath]
 	if !ok {
-		table, err := t.LookupPackage(packagePath)
+		table, err := t.lookupPackage(packagePath)
 		if err != nil {
 			return nil, fmt.Errorf("Unable to find package-level allocated symbol table for package %q", packagePath)
 		}
@@ -137,18 +162,25 @@ func (t *Table) Lookup(packagePath, file string) (*alloctable.Table, error) {
 }
 
 func (t *Table) Exists(pkg string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	_, ok := t.tables[pkg]
 	if ok {
 		return true
 	}
 
-	if _, err := os.Stat(path.Join(t.getPackagePath(pkg), "allocated.json")); err == nil {
-		return true
-	}
-
-	return false
+	return t.artefacts.Exists(t.key(pkg))
 }
+
 func (t *Table) LookupPackage(pkg string) (PackageTable, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.lookupPackage(pkg)
+}
+
+func (t *Table) lookupPackage(pkg string) (PackageTable, error) {
 	// the table must have at least one file processed
 	if table, ok := t.tables[pkg]; ok && len(table) > 0 {
 		klog.V(2).Infof("Package-level allocated symbol table %q found: %#v", pkg, table)
@@ -169,19 +201,16 @@ func (t *Table) LookupPackage(pkg string) (PackageTable, error) {
 
 // Store allocation for a given package
 func (t *Table) Save(pkg string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	table, ok := t.tables[pkg]
 	if !ok {
 		return fmt.Errorf("Allocated table for %q does not exist", pkg)
 	}
 
-	packagePath := t.getPackagePath(pkg)
-	pErr := os.MkdirAll(packagePath, 0777)
-	if pErr != nil {
-		return fmt.Errorf("Unable to create package path %v: %v", packagePath, pErr)
-	}
-
-	file := path.Join(packagePath, "allocated.json")
-	if _, err := os.Stat(file); err == nil {
+	key := t.key(pkg)
+	if t.artefacts.Exists(key) {
 		return nil
 	}
 
@@ -190,11 +219,7 @@ func (t *Table) Save(pkg string) error {
 		return fmt.Errorf("Unable to save %q symbol table: %v", pkg, err)
 	}
 
-	if err := ioutil.WriteFile(file, byteSlice, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return t.artefacts.Save(key, byteSlice)
 }
 
 func (t *Table) loadFromFile(pkg string) (PackageTable, error) {
@@ -202,13 +227,11 @@ func (t *Table) loadFromFile(pkg string) (PackageTable, error) {
 		return nil, fmt.Errorf("Unable to load %q, symbol table dir not set", pkg)
 	}
 
-	// check if the symbol table is available locally
-	packagePath := t.getPackagePath(pkg)
-
-	file := path.Join(packagePath, "allocated.json")
+	key := t.key(pkg)
+	file := key.Path()
 	klog.V(2).Infof("Global symbol table %q loading", file)
 
-	raw, err := ioutil.ReadFile(file)
+	raw, err := t.artefacts.Load(key)
 	if err != nil {
 		klog.V(2).Infof("Global symbol table %q loading failed: %v", file, err)
 		return nil, fmt.Errorf("Unable to load %q symbol table from %q: %v", pkg, file, err)
@@ -222,6 +245,18 @@ func (t *Table) loadFromFile(pkg string) (PackageTable, error) {
 	return table, nil
 }
 
+// DropStored drops the package table along with its stored artefact (so the table can be stored again)
+func (t *Table) DropStored(pkg string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.tables, pkg)
+	return t.artefacts.Drop(t.key(pkg))
+}
+
 func (t *Table) Drop(pkg string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	delete(t.tables, pkg)
 }
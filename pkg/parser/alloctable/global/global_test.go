@@ -0,0 +1,132 @@
+package global
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
+	"github.com/gofed/symbols-extractor/pkg/positions"
+)
+
+// findFiles lists files of the given name under the dir
+func findFiles(t *testing.T, dir, name string) []string {
+	var files []string
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && info.Name() == name {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestNewTest(t *testing.T) {
+	dir := t.TempDir()
+	pkg := "example.com/pkg"
+
+	prod := alloctable.New(pkg, "pkg.go")
+	prod.AddFunction("example.com/dep", "Run", positions.Parse("pkg.go:6:9"))
+
+	test := alloctable.New(pkg, "pkg_test.go")
+	test.Scope = alloctable.TestScope
+	test.AddFunction("example.com/dep", "Mock", positions.Parse("pkg_test.go:6:9"))
+	xtest := alloctable.New(pkg, "ext_test.go")
+	xtest.Scope = alloctable.TestScope
+	xtest.AddDataType(pkg, "Config", positions.Parse("ext_test.go:8:8"))
+
+	prodTable := New(dir, "1.21", nil)
+	prodTable.Add(pkg, "pkg.go", prod)
+	if err := prodTable.Save(pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	testTable := NewTest(dir, "1.21", nil)
+	testTable.Add(pkg, "pkg_test.go", test)
+	testTable.Add(pkg, "ext_test.go", xtest)
+	if err := testTable.Save(pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"allocated.json", "allocated_test.json"} {
+		if files := findFiles(t, dir, name); len(files) != 1 {
+			t.Errorf("Expected one %v, got %v", name, files)
+		}
+	}
+
+	// test files are not part of allocated.json
+	loaded, err := New(dir, "1.21", nil).LookupPackage(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (PackageTable{"pkg.go": prod}); !reflect.DeepEqual(loaded, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, loaded)
+	}
+
+	loadedTests, err := NewTest(dir, "1.21", nil).LookupPackage(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (PackageTable{"pkg_test.go": test, "ext_test.go": xtest}); !reflect.DeepEqual(loadedTests, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, loadedTests)
+	}
+
+	if NewTest(dir, "1.21", nil).Exists("example.com/other") {
+		t.Errorf("Expected no test allocated symbols of example.com/other")
+	}
+
+	merged, err := testTable.MergeFiles(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merged) != 1 || merged[alloctable.TestScope] == nil {
+		t.Fatalf("Expected the merged table keyed by the %q scope, got %#v", alloctable.TestScope, merged)
+	}
+	if scope := merged[alloctable.TestScope].Scope; scope != alloctable.TestScope {
+		t.Errorf("Expected the merged table of the %q scope, got %q", alloctable.TestScope, scope)
+	}
+	for _, p := range []string{"example.com/dep", pkg} {
+		if _, ok := merged[alloctable.TestScope].Symbols[p]; !ok {
+			t.Errorf("Expected symbols of %q in the merged table", p)
+		}
+	}
+}
+
+func TestPackageTableConsolidate(t *testing.T) {
+	pkg := "example.com/pkg"
+	prod := alloctable.New(pkg, "pkg.go")
+	prod.AddFunction("example.com/dep", "Run", positions.Parse("pkg.go:6:9"))
+	test := alloctable.New(pkg, "pkg_test.go")
+	test.Scope = alloctable.TestScope
+	test.AddFunction("example.com/dep", "Mock", positions.Parse("pkg_test.go:6:9"))
+
+	table := PackageTable{"pkg.go": prod, "pkg_test.go": test}
+	table.Consolidate()
+
+	if len(table) != 2 {
+		t.Fatalf("Expected production and test tables, got %#v", table)
+	}
+	functions := func(scope string) []string {
+		var names []string
+		for _, item := range table[scope].Symbols["example.com/dep"].Functions {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+	if names := functions(alloctable.ProductionScope); !reflect.DeepEqual(names, []string{"Run"}) {
+		t.Errorf("Expected Run allocated in the production scope, got %v", names)
+	}
+	if names := functions(alloctable.TestScope); !reflect.DeepEqual(names, []string{"Mock"}) {
+		t.Errorf("Expected Mock allocated in the test scope, got %v", names)
+	}
+	if scope := table[alloctable.TestScope].Scope; scope != alloctable.TestScope {
+		t.Errorf("Expected the %q scope, got %q", alloctable.TestScope, scope)
+	}
+}
@@ -51,15 +51,24 @@ func (t *PackageTable) FilterOut(prefix string) bool {
 	return emptyTable
 }
 
-// Consolidate merges all per-file allocated tables into one
+// Consolidate merges all per-file allocated tables into one.
+// Tables of the test scope are merged separately (keyed by the scope).
 func (t *PackageTable) Consolidate() {
-	cTable := alloctable.New("", "")
+	cTables := map[string]*alloctable.Table{
+		alloctable.ProductionScope: alloctable.New("", ""),
+	}
 
 	for file, table := range *t {
-		cTable.MergeWith(table)
+		if _, ok := cTables[table.Scope]; !ok {
+			cTables[table.Scope] = alloctable.New("", "")
+			cTables[table.Scope].Scope = table.Scope
+		}
+		cTables[table.Scope].MergeWith(table)
 		delete(*t, file)
 	}
-	(*t)[""] = cTable
+	for scope, table := range cTables {
+		(*t)[scope] = table
+	}
 }
 
 // Merge current table with passed one
@@ -67,6 +76,10 @@ func (t *PackageTable) MergeWith(pt *PackageTable) {
 	t.Consolidate()
 
 	for _, table := range *pt {
-		(*t)[""].MergeWith(table)
+		if _, ok := (*t)[table.Scope]; !ok {
+			(*t)[table.Scope] = alloctable.New("", "")
+			(*t)[table.Scope].Scope = table.Scope
+		}
+		(*t)[table.Scope].MergeWith(table)
 	}
 }
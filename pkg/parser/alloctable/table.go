@@ -5,37 +5,44 @@ import (
 	"go/ast"
 	"sort"
 	"strings"
+
+	"github.com/gofed/symbols-extractor/pkg/positions"
 )
 
 // - count a number of each symbol used (to watch how intensively is a given symbol used)
 // - each AS table is per file, AS package is a union of file AS tables
 //
 type Datatype struct {
-	Name string `json:"name"`
-	Pos  string `json:"pos"`
+	Name     string              `json:"name"`
+	Pos      string              `json:"pos"`
+	Position *positions.Position `json:"position,omitempty"`
 }
 
 type Function struct {
-	Name string `json:"name"`
-	Pos  string `json:"pos"`
+	Name     string              `json:"name"`
+	Pos      string              `json:"pos"`
+	Position *positions.Position `json:"position,omitempty"`
 }
 
 type Variable struct {
-	Name string `json:"name"`
-	Pos  string `json:"pos"`
+	Name     string              `json:"name"`
+	Pos      string              `json:"pos"`
+	Position *positions.Position `json:"position,omitempty"`
 }
 
 type Method struct {
-	Name   string `json:"name"`
-	Parent string `json:"parent"`
-	Pos    string `json:"pos"`
+	Name     string              `json:"name"`
+	Parent   string              `json:"parent"`
+	Pos      string              `json:"pos"`
+	Position *positions.Position `json:"position,omitempty"`
 }
 
 type StructField struct {
 	Parent string `json:"parent"`
 	Field  string `json:"field"`
 	//Chain  []*symbols.SymbolDef `json:"chain"`
-	Pos string `json:"pos"`
+	Pos      string              `json:"pos"`
+	Position *positions.Position `json:"position,omitempty"`
 }
 
 type Package struct {
@@ -46,10 +53,19 @@ type Package struct {
 	Structfields map[string]StructField `json:"structfields"`
 }
 
+// Scopes of allocated symbols
+const (
+	// symbols allocated in package files
+	ProductionScope = ""
+	// symbols allocated in _test.go files (including external _test packages)
+	TestScope = "test"
+)
+
 type Table struct {
 	File    string              `json:"file"`
 	Package string              `json:"package"`
 	Symbols map[string]*Package `json:"symbols"`
+	Scope   string              `json:"scope,omitempty"`
 	locked  bool
 }
 
@@ -74,19 +90,19 @@ func (allSt *Table) MergeWith(pt *Table) {
 	for pkg, symbolSet := range pt.Symbols {
 		if _, ok := allSt.Symbols[pkg]; ok {
 			for _, item := range symbolSet.Datatypes {
-				allSt.AddDataType(pkg, item.Name, item.Pos)
+				allSt.AddDataType(pkg, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSet.Functions {
-				allSt.AddFunction(pkg, item.Name, item.Pos)
+				allSt.AddFunction(pkg, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSet.Variables {
-				allSt.AddVariable(pkg, item.Name, item.Pos)
+				allSt.AddVariable(pkg, item.Name, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSet.Structfields {
-				allSt.AddStructField(pkg, item.Parent, item.Field, item.Pos)
+				allSt.AddStructField(pkg, item.Parent, item.Field, item.Position.Or(item.Pos))
 			}
 			for _, item := range symbolSet.Methods {
-				allSt.AddStructField(pkg, item.Parent, item.Name, item.Pos)
+				allSt.AddStructField(pkg, item.Parent, item.Name, item.Position.Or(item.Pos))
 			}
 		} else {
 			allSt.Symbols[pkg] = symbolSet
@@ -102,39 +118,41 @@ func (allSt *Table) Unlock() {
 	allSt.locked = false
 }
 
-func (allSt *Table) AddDataType(pkg, name, pos string) {
+func (allSt *Table) AddDataType(pkg, name string, pos *positions.Position) {
 	items, exists := allSt.Symbols[pkg]
 	if !exists {
 		allSt.Symbols[pkg] = newPackage()
 		items = allSt.Symbols[pkg]
 	}
 
-	k := toKey(name, pos)
+	k := toKey(name, pos.String())
 	if _, ok := items.Datatypes[k]; ok {
 		return
 	}
 
 	items.Datatypes[k] = Datatype{
-		Name: name,
-		Pos:  pos,
+		Name:     name,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
 
-func (allSt *Table) AddVariable(pkg, name, pos string) {
+func (allSt *Table) AddVariable(pkg, name string, pos *positions.Position) {
 	items, exists := allSt.Symbols[pkg]
 	if !exists {
 		allSt.Symbols[pkg] = newPackage()
 		items = allSt.Symbols[pkg]
 	}
 
-	k := toKey(name, pos)
+	k := toKey(name, pos.String())
 	if _, ok := items.Variables[k]; ok {
 		return
 	}
 
 	items.Variables[k] = Variable{
-		Name: name,
-		Pos:  pos,
+		Name:     name,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
 
@@ -142,53 +160,56 @@ func toKey(name, pos string) string {
 	return fmt.Sprintf("%v:%v", name, pos)
 }
 
-func (allSt *Table) AddFunction(pkg, name, pos string) {
+func (allSt *Table) AddFunction(pkg, name string, pos *positions.Position) {
 	items, exists := allSt.Symbols[pkg]
 	if !exists {
 		allSt.Symbols[pkg] = newPackage()
 		items = allSt.Symbols[pkg]
 	}
 
-	k := toKey(name, pos)
+	k := toKey(name, pos.String())
 	if _, ok := items.Functions[k]; ok {
 		return
 	}
 
 	items.Functions[k] = Function{
-		Name: name,
-		Pos:  pos,
+		Name:     name,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
 
-func (allSt *Table) AddStructField(pkg, parent, field string, pos string) {
+func (allSt *Table) AddStructField(pkg, parent, field string, pos *positions.Position) {
 	items, exists := allSt.Symbols[pkg]
 	if !exists {
 		allSt.Symbols[pkg] = newPackage()
 		items = allSt.Symbols[pkg]
 	}
 
-	k := toKey(field, pos)
+	k := toKey(field, pos.String())
 	if _, ok := items.Structfields[k]; ok {
 		return
 	}
 
 	items.Structfields[k] = StructField{
-		Parent: parent,
-		Field:  field,
-		Pos:    pos,
+		Parent:   parent,
+		Field:    field,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
 
-func (allSt *Table) AddMethod(pkg, parent, name, pos string) {
+func (allSt *Table) AddMethod(pkg, parent, name string, pos *positions.Position) {
 	items, exists := allSt.Symbols[pkg]
 	if !exists {
 		allSt.Symbols[pkg] = newPackage()
 		items = allSt.Symbols[pkg]
 	}
 	items.Methods = append(items.Methods, Method{
-		Parent: parent,
-		Name:   name,
-		Pos:    pos,
+		Parent:   parent,
+		Name:     name,
+		Pos:      pos.String(),
+		Position: pos,
 	})
 }
 
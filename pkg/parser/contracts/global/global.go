@@ -3,12 +3,11 @@ package global
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path"
+	"sync"
 
 	contracttable "github.com/gofed/symbols-extractor/pkg/parser/contracts/table"
 	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/store"
 	"k8s.io/klog/v2"
 )
 
@@ -17,40 +16,55 @@ type PackageTable map[string]*contracttable.Table
 type Table struct {
 	tables         PackageTable
 	symbolTableDir string
+	artefacts      store.Store
 	goVersion      string
 	glide          snapshots.Snapshot
+	// contracts of test files are stored separately
+	tests bool
+	// guards the tables (used by concurrent package extractions)
+	mutex sync.Mutex
 }
 
 func New(symbolTableDir, goVersion string, snapshot snapshots.Snapshot) *Table {
 	return &Table{
 		tables:         make(PackageTable),
 		symbolTableDir: symbolTableDir,
+		artefacts:      store.For(symbolTableDir),
 		goVersion:      goVersion,
 		glide:          snapshot,
 	}
 }
 
-func (t *Table) getPackagePath(pkg string) string {
-	packagePath := path.Join(t.symbolTableDir, "golang", t.goVersion, pkg)
-	if _, err := os.Stat(packagePath); err == nil {
-		return packagePath
-	}
+// NewTest creates a table of contracts collected in test files.
+// The table is stored in contracts_test.json next to contracts.json.
+func NewTest(symbolTableDir, goVersion string, snapshot snapshots.Snapshot) *Table {
+	t := New(symbolTableDir, goVersion, snapshot)
+	t.tests = true
+	return t
+}
 
-	packagePath = path.Join(t.symbolTableDir, pkg)
-	if t.glide != nil {
-		if commit, err := t.glide.Commit(pkg); err == nil {
-			return path.Join(packagePath, commit)
-		}
+func (t *Table) kind() store.Kind {
+	if t.tests {
+		return store.ContractsTestKind
 	}
+	return store.ContractsKind
+}
 
-	return packagePath
+func (t *Table) key(pkg string) store.Key {
+	return store.PackageKey(t.artefacts, pkg, t.goVersion, t.glide, t.kind())
 }
 
 func (t *Table) Add(packagePath string, table *contracttable.Table) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	t.tables[packagePath] = table
 }
 
 func (t *Table) Packages() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	var packages []string
 	for key := range t.tables {
 		packages = append(packages, key)
@@ -59,20 +73,16 @@ func (t *Table) Packages() []string {
 }
 
 func (t *Table) Save(pkg string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	table, ok := t.tables[pkg]
 	if !ok {
 		return fmt.Errorf("Allocated table for %q does not exist", pkg)
 	}
 
-	packagePath := t.getPackagePath(pkg)
-
-	pErr := os.MkdirAll(packagePath, 0777)
-	if pErr != nil {
-		return fmt.Errorf("Unable to create package path %v: %v", packagePath, pErr)
-	}
-
-	file := path.Join(packagePath, "contracts.json")
-	if _, err := os.Stat(file); err == nil {
+	key := t.key(pkg)
+	if t.artefacts.Exists(key) {
 		return nil
 	}
 
@@ -81,11 +91,7 @@ func (t *Table) Save(pkg string) error {
 		return fmt.Errorf("Unable to save %q symbol table: %v", pkg, err)
 	}
 
-	if err := ioutil.WriteFile(file, byteSlice, 0644); err != nil {
-		return err
-	}
-
-	return nil
+	return t.artefacts.Save(key, byteSlice)
 }
 
 func (t *Table) Load(pkg string) (*contracttable.Table, error) {
@@ -93,10 +99,11 @@ func (t *Table) Load(pkg string) (*contracttable.Table, error) {
 		return nil, fmt.Errorf("Unable to load %q, symbol table dir not set", pkg)
 	}
 
-	file := path.Join(t.getPackagePath(pkg), "contracts.json")
+	key := t.key(pkg)
+	file := key.Path()
 	klog.V(2).Infof("Global contract table %q loading", file)
 
-	raw, err := ioutil.ReadFile(file)
+	raw, err := t.artefacts.Load(key)
 	if err != nil {
 		klog.V(2).Infof("Global contracts table %q loading failed: %v", file, err)
 		return nil, fmt.Errorf("Unable to load %q contracts table from %q: %v", pkg, file, err)
@@ -111,19 +118,21 @@ func (t *Table) Load(pkg string) (*contracttable.Table, error) {
 }
 
 func (t *Table) Exists(pkg string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	_, ok := t.tables[pkg]
 	if ok {
 		return true
 	}
 
-	if _, err := os.Stat(path.Join(t.getPackagePath(pkg), "contracts.json")); err == nil {
-		return true
-	}
-
-	return false
+	return t.artefacts.Exists(t.key(pkg))
 }
 
 func (t *Table) Lookup(pkg string) (*contracttable.Table, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	// the table must have at least one file processed
 	if table, ok := t.tables[pkg]; ok {
 		klog.V(2).Infof("Package-level contracts table %q found", pkg)
@@ -142,6 +151,18 @@ func (t *Table) Lookup(pkg string) (*contracttable.Table, error) {
 	return table, nil
 }
 
+// DropStored drops the package table along with its stored artefact (so the table can be stored again)
+func (t *Table) DropStored(pkg string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.tables, pkg)
+	return t.artefacts.Drop(t.key(pkg))
+}
+
 func (t *Table) Drop(pkg string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	delete(t.tables, pkg)
 }
@@ -0,0 +1,73 @@
+package global
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
+	contracttable "github.com/gofed/symbols-extractor/pkg/parser/contracts/table"
+	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
+	"github.com/gofed/symbols-extractor/pkg/store"
+)
+
+func TestNewTest(t *testing.T) {
+	dir := t.TempDir()
+	pkg := "example.com/pkg"
+
+	newTable := func(name string) *contracttable.Table {
+		table := contracttable.New(pkg, dir, "1.21")
+		table.SetPrefix(name)
+		table.AddContract(&contracts.PropagatesTo{
+			X:   typevars.MakeVar(pkg, name, positions.Parse("pkg.go:3:6")),
+			Y:   table.NewVirtualVar(),
+			Pos: "pkg.go:4:2",
+		})
+		table.UnsetPrefix()
+		return table
+	}
+
+	prodTable := New(dir, "1.21", nil)
+	prodTable.Add(pkg, newTable("Run"))
+	if err := prodTable.Save(pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	testTable := NewTest(dir, "1.21", nil)
+	testTable.Add(pkg, newTable("TestRun"))
+	if err := testTable.Save(pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		table  *Table
+		prefix string
+	}{
+		{table: New(dir, "1.21", nil), prefix: "Run"},
+		{table: NewTest(dir, "1.21", nil), prefix: "TestRun"},
+	}
+	for _, test := range tests {
+		if !test.table.Exists(pkg) {
+			t.Errorf("Expected %v of %q to exist", test.table.kind().Filename(store.JSONFormat), pkg)
+			continue
+		}
+		loaded, err := test.table.Load(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var prefixes []string
+		for prefix := range loaded.Contracts {
+			prefixes = append(prefixes, prefix)
+		}
+		if !reflect.DeepEqual(prefixes, []string{test.prefix}) {
+			t.Errorf("Expected contracts of %v in %v, got %v", test.prefix, test.table.kind().Filename(store.JSONFormat), prefixes)
+		}
+	}
+
+	if filename := NewTest(dir, "1.21", nil).kind().Filename(store.JSONFormat); filename != "contracts_test.json" {
+		t.Errorf("Expected contracts_test.json, got %v", filename)
+	}
+	if NewTest(dir, "1.21", nil).Exists("example.com/other") {
+		t.Errorf("Expected no test contracts of example.com/other")
+	}
+}
@@ -182,6 +182,13 @@ func (pc *PackageContracts) UnmarshalJSON(b []byte) error {
 				}
 				pContracts[fncName] = append(pContracts[fncName], r)
 
+			case contracts.MethodValueType:
+				r := &contracts.MethodValue{}
+				if err := json.Unmarshal(*a[i], &r); err != nil {
+					return err
+				}
+				pContracts[fncName] = append(pContracts[fncName], r)
+
 			default:
 				panic(fmt.Errorf("Unrecognized contract %v", cItem["type"].(string)))
 			}
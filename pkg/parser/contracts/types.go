@@ -31,6 +31,7 @@ var IsSendableToType Type = "issendableto"
 var IsReceiveableFromType Type = "isreceiveablefrom"
 var IsIncDecableType Type = "isincdecable"
 var IsRangeableType Type = "israngeable"
+var MethodValueType Type = "methodvalue"
 
 func Contract2String(c Contract) string {
 	switch d := c.(type) {
@@ -66,6 +67,8 @@ func Contract2String(c Contract) string {
 		return fmt.Sprintf("IsIncDecable:\n\tX=%v", typevars.TypeVar2String(d.X))
 	case *IsRangeable:
 		return fmt.Sprintf("IsRangeable:\n\tX=%v,\n\tPos=%v", typevars.TypeVar2String(d.X), d.Pos)
+	case *MethodValue:
+		return fmt.Sprintf("MethodValue:\n\tX=%v,\n\tMethod=%v,\n\tY=%v,\n\tExpression=%v,\n\tPos=%v", typevars.TypeVar2String(d.X), d.Method, typevars.TypeVar2String(d.Y), d.Expression, d.Pos)
 	default:
 		panic(fmt.Sprintf("Contract %#v not recognized", c))
 	}
@@ -972,6 +975,66 @@ func (o *IsRangeable) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MethodValue represents a method used as a value (not invoked):
+// - method value x.Method: Y is the method bound to the X receiver variable
+// - method expression T.Method, (*T).Method: Y is the method with the X receiver prepended
+type MethodValue struct {
+	X          typevars.Interface `json:"x"`
+	Method     string             `json:"method"`
+	Y          typevars.Interface `json:"y"`
+	Expression bool               `json:"expression"`
+	Pos        string             `json:"pos"`
+}
+
+func (o *MethodValue) MarshalJSON() (b []byte, e error) {
+	type Copy MethodValue
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*Copy
+	}{
+		Type: string(o.GetType()),
+		Copy: (*Copy)(o),
+	})
+}
+
+func (o *MethodValue) UnmarshalJSON(b []byte) error {
+	var objMap map[string]*json.RawMessage
+
+	if err := json.Unmarshal(b, &objMap); err != nil {
+		return err
+	}
+
+	{
+		ti, err := UnmarshalTypevar(objMap["x"])
+		if err != nil {
+			return err
+		}
+		o.X = ti
+	}
+
+	{
+		ti, err := UnmarshalTypevar(objMap["y"])
+		if err != nil {
+			return err
+		}
+		o.Y = ti
+	}
+
+	if err := json.Unmarshal(*objMap["method"], &(o.Method)); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(*objMap["expression"], &(o.Expression)); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(*objMap["pos"], &(o.Pos)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (b *BinaryOp) GetType() Type {
 	return BinaryOpType
 }
@@ -1035,3 +1098,7 @@ func (i *IsIncDecable) GetType() Type {
 func (i *IsRangeable) GetType() Type {
 	return IsRangeableType
 }
+
+func (i *MethodValue) GetType() Type {
+	return MethodValueType
+}
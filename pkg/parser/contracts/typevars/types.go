@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	"github.com/gofed/symbols-extractor/pkg/symbols"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 )
@@ -180,6 +181,24 @@ func UnmarshalDataType(rawMessage *json.RawMessage) (gotypes.DataType, error) {
 			return nil, err
 		}
 		return r, nil
+	case gotypes.TypeparamType:
+		r := &gotypes.Typeparam{}
+		if err := json.Unmarshal(*rawMessage, &r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	case gotypes.InstanceType:
+		r := &gotypes.Instance{}
+		if err := json.Unmarshal(*rawMessage, &r); err != nil {
+			return nil, err
+		}
+		return r, nil
+	case gotypes.UnionType:
+		r := &gotypes.Union{}
+		if err := json.Unmarshal(*rawMessage, &r); err != nil {
+			return nil, err
+		}
+		return r, nil
 	case gotypes.NilType:
 		r := &gotypes.Nil{}
 		if err := json.Unmarshal(*rawMessage, &r); err != nil {
@@ -196,9 +215,10 @@ func UnmarshalDataType(rawMessage *json.RawMessage) (gotypes.DataType, error) {
 // - global variable
 // - qid-ed variable
 type Variable struct {
-	Name    string
-	Pos     string
-	Package string
+	Name     string
+	Pos      string
+	Package  string
+	Position *positions.Position `json:"Position,omitempty"`
 }
 
 func (v *Variable) GetType() Type {
@@ -222,17 +242,19 @@ func (o *Variable) MarshalJSON() (b []byte, e error) {
 
 func VariableFromSymbolDef(def *symbols.SymbolDef) *Variable {
 	return &Variable{
-		Name:    def.Name,
-		Pos:     def.Pos,
-		Package: def.Package,
+		Name:     def.Name,
+		Pos:      def.Pos,
+		Package:  def.Package,
+		Position: def.Position,
 	}
 }
 
 type Field struct {
-	X     *Variable
-	Name  string
-	Index int
-	Pos   string
+	X        *Variable
+	Name     string
+	Index    int
+	Pos      string
+	Position *positions.Position `json:"Position,omitempty"`
 }
 
 func (f *Field) GetType() Type {
@@ -492,18 +514,20 @@ func (o *ReturnType) MarshalJSON() (b []byte, e error) {
 	})
 }
 
-func MakeVar(packageName, name, pos string) *Variable {
+func MakeVar(packageName, name string, pos *positions.Position) *Variable {
 	return &Variable{
-		Package: packageName,
-		Name:    name,
-		Pos:     pos,
+		Package:  packageName,
+		Name:     name,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
 
-func MakeLocalVar(name, pos string) *Variable {
+func MakeLocalVar(name string, pos *positions.Position) *Variable {
 	return &Variable{
-		Name: name,
-		Pos:  pos,
+		Name:     name,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
 
@@ -568,11 +592,12 @@ func MakeRangeValue(v *Variable) *RangeValue {
 	}
 }
 
-func MakeField(v *Variable, field string, index int, pos string) *Field {
+func MakeField(v *Variable, field string, index int, pos *positions.Position) *Field {
 	return &Field{
-		X:     v,
-		Name:  field,
-		Index: index,
-		Pos:   pos,
+		X:        v,
+		Name:     field,
+		Index:    index,
+		Pos:      pos.String(),
+		Position: pos,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"strings"
 
@@ -38,7 +39,6 @@ type Parser struct {
 // parseBasicLit consumes *ast.BasicLit and produces Builtin
 func (ep *Parser) parseBasicLit(lit *ast.BasicLit) (*types.ExprAttribute, error) {
 	klog.V(2).Infof("Processing BasicLit: %#v\n", lit)
-	// TODO(jchaloup): store the literal as well so one can argue about []int{1, 2.0} and []int{1, 2.2}
 	var builtin *gotypes.Constant
 	switch lit.Kind {
 	case token.INT:
@@ -56,11 +56,16 @@ func (ep *Parser) parseBasicLit(lit *ast.BasicLit) (*types.ExprAttribute, error)
 	}
 
 	builtin.Untyped = true
-	if lit.Kind == token.CHAR {
-		// convert rune to uint8 number
-		builtin.Literal = fmt.Sprintf("%v", lit.Value[1])
-	} else {
+	value := propagation.LiteralValue(lit)
+	if value.Kind() == constant.Unknown {
+		return nil, fmt.Errorf("Unable to evaluate BasicLit %v", lit.Value)
+	}
+	builtin.Value = propagation.ValueString(value)
+	if lit.Kind == token.STRING {
 		builtin.Literal = lit.Value
+	} else {
+		// numbers (including runes) in a form the multi-arithmetic understands
+		builtin.Literal = propagation.ValueToLiteral(value)
 	}
 	builtin.Package = "builtin"
 
@@ -98,7 +103,7 @@ func (ep *Parser) parseKeyValueLikeExpr(litDataType gotypes.DataType, lit *ast.C
 	ep.Config.ContractTable.AddContract(&contracts.IsIndexable{
 		X:   outputTypeVar,
 		Key: keyTypeVar,
-		Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, lit.Pos()),
+		Pos: ep.Config.SymbolPos(lit.Pos()),
 	})
 
 	for _, litElement := range lit.Elts {
@@ -228,7 +233,7 @@ func (ep *Parser) parseKeyValueLikeExpr(litDataType gotypes.DataType, lit *ast.C
 				outputOriginalTypeVar = typevars.MakeVar(
 					qid.Path,
 					sel.Item,
-					"", // no position for a global symbol
+					nil, // no position for a global symbol
 				)
 			}
 		}
@@ -262,9 +267,9 @@ func (ep *Parser) parseCompositeLitStructElements(litDataType gotypes.DataType,
 			ep.Config.ContractTable.AddContract(&contracts.HasField{
 				X:     structOutputTypeVar,
 				Field: keyDefIdentifier.Name,
-				Pos:   fmt.Sprintf("%v:%v", ep.Config.FileName, keyDefIdentifier.Pos()),
+				Pos:   ep.Config.SymbolPos(keyDefIdentifier.Pos()),
 			})
-			fieldTypeVar = typevars.MakeField(structOutputTypeVar, keyDefIdentifier.Name, 0, fmt.Sprintf("%v:%v", ep.Config.FileName, keyDefIdentifier.Pos()))
+			fieldTypeVar = typevars.MakeField(structOutputTypeVar, keyDefIdentifier.Name, 0, ep.Config.SymbolPosition(keyDefIdentifier))
 			valueExpr = kvExpr.Value
 		} else {
 			if fieldCounter >= fieldLen {
@@ -273,9 +278,9 @@ func (ep *Parser) parseCompositeLitStructElements(litDataType gotypes.DataType,
 			ep.Config.ContractTable.AddContract(&contracts.HasField{
 				X:     structOutputTypeVar,
 				Index: fieldCounter,
-				Pos:   fmt.Sprintf("%v:%v", ep.Config.FileName, litElement.Pos()),
+				Pos:   ep.Config.SymbolPos(litElement.Pos()),
 			})
-			fieldTypeVar = typevars.MakeField(structOutputTypeVar, "", fieldCounter, fmt.Sprintf("%v:%v", ep.Config.FileName, litElement.Pos()))
+			fieldTypeVar = typevars.MakeField(structOutputTypeVar, "", fieldCounter, ep.Config.SymbolPosition(litElement))
 			valueExpr = litElement
 		}
 
@@ -301,7 +306,7 @@ func (ep *Parser) parseCompositeLitStructElements(litDataType gotypes.DataType,
 				structOriginalTypeVar = typevars.MakeVar(
 					qid.Path,
 					sel.Item,
-					"", // no position for a global symbol
+					nil, // no position for a global symbol
 				)
 			}
 		}
@@ -439,7 +444,7 @@ func (ep *Parser) parseCompositeLit(lit *ast.CompositeLit, typeDef gotypes.DataT
 			X:            typevars.MakeConstant(ep.Config.PackageName, def),
 			Y:            typeVar,
 			ExpectedType: def,
-			Pos:          fmt.Sprintf("%v:%v", ep.Config.FileName, lit.Pos()),
+			Pos:          ep.Config.SymbolPos(lit.Pos()),
 		})
 	}
 
@@ -473,7 +478,7 @@ func (ep *Parser) parseIdentifier(ident *ast.Ident) (*types.ExprAttribute, error
 			klog.V(2).Infof("Variable by identifier found: %v\n", string(byteSlice))
 
 			if def.Block == 0 && st == symbols.VariableSymbol && def.Def.GetType() != gotypes.PackagequalifierType {
-				ep.AllocatedSymbolsTable.AddVariable(def.Package, def.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, ident.Pos()))
+				ep.AllocatedSymbolsTable.AddVariable(def.Package, def.Name, ep.Config.SymbolPosition(ident))
 			}
 			// The data type of the variable is not accounted as it is not implicitely used
 			// The variable itself carries the data type and as long as the variable does not
@@ -488,6 +493,8 @@ func (ep *Parser) parseIdentifier(ident *ast.Ident) (*types.ExprAttribute, error
 				typevars.VariableFromSymbolDef(def),
 			), nil
 		}
+		// E.g. a type argument F of f[F](...) with f not yet processed
+		postponedErr = fmt.Errorf("parseIdentifier: Symbol %q is not a variable", ident.Name)
 	}
 
 	// Maybe it is a builtin variable
@@ -502,7 +509,7 @@ func (ep *Parser) parseIdentifier(ident *ast.Ident) (*types.ExprAttribute, error
 		case symbols.VariableSymbol:
 			switch ident.Name {
 			case "true", "false":
-				c := &gotypes.Constant{Package: "builtin", Def: "bool", Literal: ident.Name, Untyped: true}
+				c := &gotypes.Constant{Package: "builtin", Def: "bool", Literal: ident.Name, Untyped: true, Value: ident.Name}
 				return types.ExprAttributeFromDataType(c).AddTypeVar(
 					typevars.MakeConstant("builtin", c),
 				), nil
@@ -511,7 +518,7 @@ func (ep *Parser) parseIdentifier(ident *ast.Ident) (*types.ExprAttribute, error
 					typevars.MakeConstant("builtin", &gotypes.Nil{}),
 				), nil
 			case "iota":
-				c := &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: fmt.Sprintf("%v", ep.Config.Iota)}
+				c := &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: fmt.Sprintf("%v", ep.Config.Iota), Value: fmt.Sprintf("%v", ep.Config.Iota)}
 				return types.ExprAttributeFromDataType(c).AddTypeVar(
 					typevars.MakeConstant("builtin", c),
 				), nil
@@ -555,6 +562,14 @@ func (ep *Parser) parseUnaryExpr(expr *ast.UnaryExpr) (*types.ExprAttribute, err
 	if err != nil {
 		return nil, err
 	}
+	if x, ok := attr.DataTypeList[0].(*gotypes.Constant); ok {
+		if y, ok := yDataType.(*gotypes.Constant); ok {
+			// the constant can be shared with the operand
+			yc := *y
+			yc.Value = propagation.New(ep.Config.SymbolsAccessor).UnaryValue(expr.Op, x, y)
+			yDataType = &yc
+		}
+	}
 
 	y := ep.Config.ContractTable.NewVirtualVar()
 	if expr.Op == token.ARROW {
@@ -641,6 +656,16 @@ func (ep *Parser) parseBinaryExpr(expr *ast.BinaryExpr) (*types.ExprAttribute, e
 	if err != nil {
 		return nil, fmt.Errorf("parseBinaryExpr: %v, at pos %v", err, expr.Pos())
 	}
+	if x, ok := xAttr.DataTypeList[0].(*gotypes.Constant); ok {
+		if y, ok := yAttr.DataTypeList[0].(*gotypes.Constant); ok {
+			if z, ok := zDataType.(*gotypes.Constant); ok {
+				// the constant can be shared with any of the operands
+				zc := *z
+				zc.Value = propagation.New(ep.Config.SymbolsAccessor).BinaryValue(expr.Op, x, y, z)
+				zDataType = &zc
+			}
+		}
+	}
 
 	z := ep.Config.ContractTable.NewVirtualVar()
 	ep.Config.ContractTable.AddContract(&contracts.BinaryOp{
@@ -649,7 +674,7 @@ func (ep *Parser) parseBinaryExpr(expr *ast.BinaryExpr) (*types.ExprAttribute, e
 		Y:            yAttr.TypeVarList[0],
 		Z:            z,
 		ExpectedType: zDataType,
-		Pos:          fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+		Pos:          ep.Config.SymbolPos(expr.Pos()),
 	})
 	return types.ExprAttributeFromDataType(zDataType).AddTypeVar(z), nil
 }
@@ -797,7 +822,11 @@ func (ep *Parser) isDataType(expr ast.Expr) (bool, error) {
 	case *ast.StructType:
 		return true, nil
 	case *ast.IndexExpr:
-		return false, nil
+		// Instance of a generic data type, e.g. List[int]
+		return ep.isGenericDataType(exprType.X)
+	case *ast.IndexListExpr:
+		// Instance of a generic data type, e.g. Map[string, int]
+		return ep.isGenericDataType(exprType.X)
 	default:
 		// TODO(jchaloup): yes? As now it is anonymous data type. Or should we check for each such type?
 		panic(fmt.Errorf("Unrecognized isDataType expr: %#v at %v", expr, expr.Pos()))
@@ -805,6 +834,16 @@ func (ep *Parser) isDataType(expr ast.Expr) (bool, error) {
 	return false, nil
 }
 
+// isGenericDataType checks if X of X[Index] is a data type.
+// Only identifiers and qualified identifiers can be generic data types.
+func (ep *Parser) isGenericDataType(expr ast.Expr) (bool, error) {
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return ep.isDataType(expr)
+	}
+	return false, nil
+}
+
 func (ep *Parser) getFunctionDef(def gotypes.DataType) (*types.ExprAttribute, []string, error) {
 	klog.V(2).Infof("getFunctionDef of %#v: typeDef.Package", def)
 	switch typeDef := def.(type) {
@@ -876,14 +915,15 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 	expr.Fun = ep.parseParenExpr(expr.Fun)
 
 	// params = list of function/method parameters definition (from its signature)
-	processArgs := func(functionTypeVar *typevars.Variable, args []ast.Expr, params []gotypes.DataType) error {
+	// Returns data types of the arguments
+	processArgs := func(functionTypeVar *typevars.Variable, args []ast.Expr, params []gotypes.DataType) ([]gotypes.DataType, error) {
 		// TODO(jchaloup): check the arguments can be assigned to the parameters
 		// TODO(jchaloup): generate type contract for each argument
 		if params != nil {
 			if len(args) == 1 {
 				attr, err := ep.Parse(args[0])
 				if err != nil {
-					return err
+					return nil, err
 				}
 				// e.g. f(...) (a, b, c); g(a,b,c) ...; g(f(...))
 				// or in case the function has ellipiss argument, len(attr.DataTypeList) does not matter
@@ -894,14 +934,15 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 								X:            attr.TypeVarList[i],
 								Y:            typevars.MakeArgument(functionTypeVar, i),
 								ExpectedType: attr.DataTypeList[i],
+								Pos:          ep.Config.SymbolPos(args[0].Pos()),
 							})
 						}
 					}
-					return nil
+					return attr.DataTypeList, nil
 				}
 
 				if len(attr.DataTypeList) != 1 {
-					return fmt.Errorf("(1)Argument %#v of a call expression does not have one return value", args[0])
+					return nil, fmt.Errorf("(1)Argument %#v of a call expression does not have one return value", args[0])
 				}
 
 				// E.g. func f(a string, b ...int) called as f("aaa"), the 'b' is nil then
@@ -910,20 +951,22 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 						X:            attr.TypeVarList[0],
 						Y:            typevars.MakeArgument(functionTypeVar, 0),
 						ExpectedType: attr.DataTypeList[0],
+						Pos:          ep.Config.SymbolPos(args[0].Pos()),
 					})
 				}
-				return nil
+				return attr.DataTypeList, nil
 			}
 		}
+		var argTypes []gotypes.DataType
 		for i, arg := range args {
 			// an argument is passed to the function so its data type does not affect the result data type of the call
 			attr, err := ep.Parse(arg)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			if attr == nil || len(attr.DataTypeList) != 1 {
-				return fmt.Errorf("(2)Argument %#v of a call expression does not have one return value", arg)
+				return nil, fmt.Errorf("(2)Argument %#v of a call expression does not have one return value", arg)
 			}
 
 			if functionTypeVar != nil {
@@ -931,10 +974,12 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 					X:            attr.TypeVarList[0],
 					Y:            typevars.MakeArgument(functionTypeVar, i),
 					ExpectedType: attr.DataTypeList[0],
+					Pos:          ep.Config.SymbolPos(arg.Pos()),
 				})
 			}
+			argTypes = append(argTypes, attr.DataTypeList[0])
 		}
-		return nil
+		return argTypes, nil
 	}
 
 	// data type => explicit type casting
@@ -966,6 +1011,12 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 			return nil, fmt.Errorf("Unable to type-cast: %v", err)
 		}
 
+		if x, ok := attr.DataTypeList[0].(*gotypes.Constant); ok {
+			if z, ok := castedDef.(*gotypes.Constant); ok {
+				z.Value = propagation.New(ep.Config.SymbolsAccessor).ConvertValue(x, z)
+			}
+		}
+
 		klog.V(2).Infof("Casted to %#v\n", castedDef)
 
 		newVar := ep.Config.ContractTable.NewVirtualVar()
@@ -988,8 +1039,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 		case *gotypes.Selector:
 			qid, ok := d.Prefix.(*gotypes.Packagequalifier)
 			if !ok {
-				fmt.Printf("slector not qid: %#v\n", d.Prefix)
-				panic("SELECTOR not QID!!!")
+				return nil, fmt.Errorf("Expected selector prefix to be a package qualifier, got %#v instead", d.Prefix)
 			}
 			dtOrigin = qid.Path
 		default:
@@ -1008,7 +1058,13 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 	klog.V(2).Infof("isDataType of %#v is false", expr.Fun)
 
 	// function
-	attr, err := ep.ExprParser.Parse(expr.Fun)
+	var attr *types.ExprAttribute
+	if sel, ok := expr.Fun.(*ast.SelectorExpr); ok {
+		// invoked method is not a method value
+		attr, err = ep.parseSelectorExpr(sel, true)
+	} else {
+		attr, err = ep.ExprParser.Parse(expr.Fun)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1032,19 +1088,19 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 				// arglen == 1: make(type) type
 				// arglen == 2: make(type, size) type
 				// arglen == 3: make(type, size, cap) type
-				f := typevars.MakeVar("builtin", ident.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+				f := typevars.MakeVar("builtin", ident.Name, ep.Config.SymbolPosition(expr))
 				arglen := len(expr.Args)
 				switch arglen {
 				case 1:
 					klog.V(2).Infof("Processing make arguments for make(type) type: %#v", expr.Args)
 				case 2:
 					klog.V(2).Infof("Processing make arguments for make(type, size) type: %#v", expr.Args)
-					if err := processArgs(f, []ast.Expr{expr.Args[1]}, nil); err != nil {
+					if _, err := processArgs(f, []ast.Expr{expr.Args[1]}, nil); err != nil {
 						return nil, err
 					}
 				case 3:
 					klog.V(2).Infof("Processing make arguments for make(type, size, size) type: %#v", expr.Args)
-					if err := processArgs(f, []ast.Expr{expr.Args[1], expr.Args[2]}, nil); err != nil {
+					if _, err := processArgs(f, []ast.Expr{expr.Args[1], expr.Args[2]}, nil); err != nil {
 						return nil, err
 					}
 				default:
@@ -1061,7 +1117,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 					typevars.MakeConstant(ep.Config.PackageName, typeDef),
 				), err
 			case "append":
-				f := typevars.MakeVar("builtin", ident.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+				f := typevars.MakeVar("builtin", ident.Name, ep.Config.SymbolPosition(expr))
 				arglen := len(expr.Args)
 
 				if arglen == 0 {
@@ -1069,7 +1125,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 				}
 
 				klog.V(2).Infof("Processing append arguments: %#v", expr.Args)
-				if err := processArgs(f, expr.Args[1:], nil); err != nil {
+				if _, err := processArgs(f, expr.Args[1:], nil); err != nil {
 					return nil, err
 				}
 
@@ -1115,7 +1171,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 						return nil, err
 					}
 					if isDT {
-						f := typevars.MakeVar("builtin", ident.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+						f := typevars.MakeVar("builtin", ident.Name, ep.Config.SymbolPosition(expr))
 						ep.Config.ContractTable.AddContract(&contracts.IsInvocable{
 							F:         f,
 							ArgsCount: 1,
@@ -1135,8 +1191,8 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 				// check if the len can be determined (in case then len is a constant).
 				// See https://golang.org/ref/spec#Length_and_capacity
 				// TODO(jchaloup): return the correct value/type of the len built-in function
-				f := typevars.MakeVar("builtin", ident.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
-				if err := processArgs(f, []ast.Expr{expr.Args[0]}, nil); err != nil {
+				f := typevars.MakeVar("builtin", ident.Name, ep.Config.SymbolPosition(expr))
+				if _, err := processArgs(f, []ast.Expr{expr.Args[0]}, nil); err != nil {
 					return nil, err
 				}
 				ep.Config.ContractTable.AddContract(&contracts.IsInvocable{
@@ -1167,7 +1223,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 				if err != nil {
 					return nil, err
 				}
-				f := typevars.MakeVar("builtin", ident.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+				f := typevars.MakeVar("builtin", ident.Name, ep.Config.SymbolPosition(expr))
 				ep.Config.ContractTable.AddContract(&contracts.IsInvocable{
 					F:         f,
 					ArgsCount: 1,
@@ -1194,7 +1250,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 				if err != nil {
 					return nil, err
 				}
-				f := typevars.MakeVar("builtin", ident.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+				f := typevars.MakeVar("builtin", ident.Name, ep.Config.SymbolPosition(expr))
 				ep.Config.ContractTable.AddContract(&contracts.IsInvocable{
 					F:         f,
 					ArgsCount: 2,
@@ -1211,7 +1267,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 				if ident.Name == "unsafe" {
 					switch sel.Sel.Name {
 					case "Sizeof", "Alignof", "Offsetof":
-						if err := processArgs(typevars.MakeVar("unsafe", sel.Sel.Name, ""), []ast.Expr{expr.Args[0]}, nil); err != nil {
+						if _, err := processArgs(typevars.MakeVar("unsafe", sel.Sel.Name, nil), []ast.Expr{expr.Args[0]}, nil); err != nil {
 							return nil, err
 						}
 						// always produces a constant, taking a data type and providing its size (during compilation)
@@ -1223,6 +1279,32 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 						return types.ExprAttributeFromDataType(c).AddTypeVar(
 							typevars.MakeConstant("builtin", c),
 						), nil
+					case "Slice", "SliceData":
+						// Slice(ptr *ArbitraryType, len IntegerType) []ArbitraryType
+						// SliceData(slice []ArbitraryType) *ArbitraryType
+						argTypes, err := processArgs(typevars.MakeVar("unsafe", sel.Sel.Name, nil), expr.Args, nil)
+						if err != nil {
+							return nil, err
+						}
+						if len(argTypes) == 0 {
+							return nil, fmt.Errorf("unsafe.%v expects at least one argument", sel.Sel.Name)
+						}
+						nonIdentDef, err := ep.SymbolsAccessor.FindFirstNonidDataType(argTypes[0])
+						if err != nil {
+							return nil, err
+						}
+						var def gotypes.DataType
+						switch d := nonIdentDef.(type) {
+						case *gotypes.Pointer:
+							def = &gotypes.Slice{Elmtype: d.Def}
+						case *gotypes.Slice:
+							def = &gotypes.Pointer{Def: d.Elmtype}
+						default:
+							return nil, fmt.Errorf("Unexpected argument %#v of unsafe.%v", argTypes[0], sel.Sel.Name)
+						}
+						return types.ExprAttributeFromDataType(def).AddTypeVar(
+							typevars.MakeConstant(ep.Config.PackageName, def),
+						), nil
 					}
 				}
 			}
@@ -1241,7 +1323,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 	switch d := attr.TypeVarList[0].(type) {
 	case *typevars.Variable:
 		if !strings.HasPrefix(d.Name, "virtual.var") {
-			f = &typevars.Variable{Package: d.Package, Name: d.Name, Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos())}
+			f = &typevars.Variable{Package: d.Package, Name: d.Name, Pos: ep.Config.SymbolPos(expr.Pos()), Position: ep.Config.SymbolPosition(expr)}
 		} else {
 			f = &typevars.Variable{Package: d.Package, Name: d.Name}
 		}
@@ -1251,7 +1333,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 			ep.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 				X:   d,
 				Y:   f,
-				Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+				Pos: ep.Config.SymbolPos(expr.Pos()),
 			})
 		}
 	case *typevars.Constant, *typevars.Field, *typevars.ReturnType, *typevars.ListValue, *typevars.MapValue:
@@ -1259,7 +1341,7 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 		ep.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 			X:   attr.TypeVarList[0],
 			Y:   newVar,
-			Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+			Pos: ep.Config.SymbolPos(expr.Pos()),
 		})
 		f = newVar
 	default:
@@ -1271,10 +1353,20 @@ func (ep *Parser) parseCallExpr(expr *ast.CallExpr) (*types.ExprAttribute, error
 		ArgsCount: len(expr.Args),
 	})
 
-	if err := processArgs(f, expr.Args, params); err != nil {
+	argTypes, err := processArgs(f, expr.Args, params)
+	if err != nil {
 		return nil, err
 	}
 
+	// Implicit instantiation, e.g. Map(list, fnc)
+	if function, ok := funcDefAttr.DataTypeList[0].(*gotypes.Function); ok && len(function.Typeparams) > 0 {
+		function, err = propagation.New(ep.Config.SymbolsAccessor).InferTypeArguments(function, argTypes, expr.Ellipsis.IsValid())
+		if err != nil {
+			return nil, err
+		}
+		results = function.Results
+	}
+
 	outputAttr := types.ExprAttributeFromDataType(results...)
 	for i := range results {
 		z := typevars.MakeReturn(f, i)
@@ -1350,7 +1442,7 @@ func (ep *Parser) parseSliceExpr(expr *ast.SliceExpr) (*types.ExprAttribute, err
 			X: exprDefAttr.TypeVarList[0],
 			// TODO(jchaloup): this can be removed
 			IsSlice: true,
-			Pos:     fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+			Pos:     ep.Config.SymbolPos(expr.Pos()),
 		})
 
 		return types.ExprAttributeFromDataType(exprDefAttr.DataTypeList[0]).AddTypeVar(
@@ -1392,7 +1484,7 @@ func (ep *Parser) parseSliceExpr(expr *ast.SliceExpr) (*types.ExprAttribute, err
 		X: exprDefAttr.TypeVarList[0],
 		// TODO(jchaloup): this can be removed
 		IsSlice: true,
-		Pos:     fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+		Pos:     ep.Config.SymbolPos(expr.Pos()),
 	})
 
 	return types.ExprAttributeFromDataType(sliceExpr).AddTypeVar(
@@ -1444,7 +1536,7 @@ func (ep *Parser) parseFuncLit(expr *ast.FuncLit) (*types.ExprAttribute, error)
 	ep.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 		X:   typevars.MakeConstant(ep.Config.PackageName, def),
 		Y:   newVar,
-		Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+		Pos: ep.Config.SymbolPos(expr.Pos()),
 	})
 
 	return types.ExprAttributeFromDataType(def).AddTypeVar(
@@ -1461,13 +1553,30 @@ func (ep *Parser) parseStructType(expr *ast.StructType) (*types.ExprAttribute, e
 	return types.ExprAttributeFromDataType(def), err
 }
 
+// checkAngGetDataTypeMethod checks if the selector is a method expression (T.Method, pkg.T.Method,
+// (T).Method, (*T).Method) and returns the method as a function with the receiver
+// prepended to its parameters, i.e. T.Method(recv, args...) is recv.Method(args...).
 func (ep *Parser) checkAngGetDataTypeMethod(expr *ast.SelectorExpr) (bool, *types.ExprAttribute, error) {
-	parExpr, ok := expr.X.(*ast.ParenExpr)
-	if !ok {
-		return false, nil, nil
+	receiverExpr := expr.X
+	if parExpr, ok := expr.X.(*ast.ParenExpr); ok {
+		receiverExpr = parExpr.X
+	} else {
+		// T.Method or pkg.T.Method (x.Method and pkg.Symbol are not method expressions)
+		switch xExpr := expr.X.(type) {
+		case *ast.Ident:
+			if _, st, err := ep.SymbolTable.Lookup(xExpr.Name); err != nil || st != symbols.DataTypeSymbol {
+				return false, nil, nil
+			}
+		case *ast.SelectorExpr:
+			if isType, err := ep.isDataType(xExpr); err != nil || !isType {
+				return false, nil, nil
+			}
+		default:
+			return false, nil, nil
+		}
 	}
-	dataTypeIdent := parExpr.X
-	pointer, ok := parExpr.X.(*ast.StarExpr)
+	dataTypeIdent := receiverExpr
+	pointer, ok := receiverExpr.(*ast.StarExpr)
 	if ok {
 		dataTypeIdent = pointer.X
 	}
@@ -1519,33 +1628,37 @@ func (ep *Parser) checkAngGetDataTypeMethod(expr *ast.SelectorExpr) (bool, *type
 	if err != nil {
 		return false, nil, fmt.Errorf("Unable to find method %q of data type %v", expr.Sel.Name, typeSymbolDef.Name)
 	}
-	method, ok := methodDefAttr.DataType.(*gotypes.Method)
-	if !ok {
+	var function *gotypes.Function
+	switch fDef := methodDefAttr.DataType.(type) {
+	case *gotypes.Method:
+		function = fDef.Def.(*gotypes.Function)
+	case *gotypes.Function:
+		// method of an interface
+		function = fDef
+	default:
 		return false, nil, fmt.Errorf("Expected a method %q of data type %v, got %#v instead", expr.Sel.Name, typeSymbolDef.Name, methodDefAttr)
 	}
 
-	y := ep.Config.ContractTable.NewVirtualVar()
-
-	receiverDef, err := ep.TypeParser.Parse(dataTypeIdent)
+	receiverDef, err := ep.TypeParser.Parse(receiverExpr)
 	if err != nil {
 		return true, nil, err
 	}
 
-	ep.Config.ContractTable.AddContract(&contracts.PropagatesTo{
-		X:   typevars.MakeConstant(ep.Config.PackageName, receiverDef),
-		Y:   y,
-		Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
-	})
-
-	ep.Config.ContractTable.AddContract(&contracts.HasField{
-		X:     y,
-		Field: expr.Sel.Name,
-		Pos:   fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Sel.Pos()),
+	y := ep.Config.ContractTable.NewVirtualVar()
+	ep.Config.ContractTable.AddContract(&contracts.MethodValue{
+		X:          typevars.MakeConstant(ep.Config.PackageName, receiverDef),
+		Method:     expr.Sel.Name,
+		Y:          y,
+		Expression: true,
+		Pos:        ep.Config.SymbolPos(expr.Sel.Pos()),
 	})
 
-	return true, types.ExprAttributeFromDataType(method.Def.(*gotypes.Function)).AddTypeVar(
-		typevars.MakeField(y, expr.Sel.Name, 0, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Sel.Pos())),
-	), nil
+	return true, types.ExprAttributeFromDataType(&gotypes.Function{
+		Package:    function.Package,
+		Params:     append([]gotypes.DataType{receiverDef}, function.Params...),
+		Results:    function.Results,
+		Typeparams: function.Typeparams,
+	}).AddTypeVar(y), nil
 }
 
 // parseSelectorExpr consumes ast.SelectorExpr and produces:
@@ -1558,7 +1671,8 @@ func (ep *Parser) checkAngGetDataTypeMethod(expr *ast.SelectorExpr) (bool, *type
 // - if the prefix is an identifier of a data type, data type of a method pointed by the selector item is returned
 // - if the prefix is pointer to identifier of a data type, data type of a method pointed by the selector item is returned
 // - if the prefix is an interface data type, data type of a method pointed by the selector item is returned
-func (ep *Parser) parseSelectorExpr(expr *ast.SelectorExpr) (*types.ExprAttribute, error) {
+// - if the method is not invoked (method value), the method bound to the prefix is returned as a function
+func (ep *Parser) parseSelectorExpr(expr *ast.SelectorExpr, invoked bool) (*types.ExprAttribute, error) {
 	klog.V(2).Infof("Processing SelectorExpr: %#v at %v\n", expr, expr.Pos())
 	// Check for data type method cases
 	// (*Receiver).method: use method of a data type as a value to store to a variable
@@ -1599,19 +1713,19 @@ func (ep *Parser) parseSelectorExpr(expr *ast.SelectorExpr) (*types.ExprAttribut
 		}
 		switch symbolType {
 		case symbols.FunctionSymbol:
-			ep.AllocatedSymbolsTable.AddFunction(qid.Path, expr.Sel.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+			ep.AllocatedSymbolsTable.AddFunction(qid.Path, expr.Sel.Name, ep.Config.SymbolPosition(expr))
 		case symbols.VariableSymbol:
-			ep.AllocatedSymbolsTable.AddVariable(qid.Path, expr.Sel.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+			ep.AllocatedSymbolsTable.AddVariable(qid.Path, expr.Sel.Name, ep.Config.SymbolPosition(expr))
 		case symbols.DataTypeSymbol:
 			// in case of container.Isolation.IsDefault, the Isolation is a data type
-			ep.AllocatedSymbolsTable.AddDataType(qid.Path, expr.Sel.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()))
+			ep.AllocatedSymbolsTable.AddDataType(qid.Path, expr.Sel.Name, ep.Config.SymbolPosition(expr))
 		default:
 			fmt.Printf("symbolType: %v\n", symbolType)
 			panic("/O/")
 		}
 
 		return types.ExprAttributeFromDataType(fieldAttribute.DataType).AddTypeVar(
-			typevars.MakeVar(qid.Path, expr.Sel.Name, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos())),
+			typevars.MakeVar(qid.Path, expr.Sel.Name, ep.Config.SymbolPosition(expr)),
 		), nil
 	}
 
@@ -1619,14 +1733,26 @@ func (ep *Parser) parseSelectorExpr(expr *ast.SelectorExpr) (*types.ExprAttribut
 	// It is easier to evaluate during the data type propagation analysis.
 	yVar := ep.typevar2variable(xDefAttr.TypeVarList[0])
 
+	// x.Method used as a value, e.g. srv.Handle(path, h.ServeHTTP)
+	if method, ok := fieldAttribute.DataType.(*gotypes.Method); ok && !invoked {
+		y := ep.Config.ContractTable.NewVirtualVar()
+		ep.Config.ContractTable.AddContract(&contracts.MethodValue{
+			X:      yVar,
+			Method: expr.Sel.Name,
+			Y:      y,
+			Pos:    ep.Config.SymbolPos(expr.Sel.Pos()),
+		})
+		return types.ExprAttributeFromDataType(method.Def).AddTypeVar(y), nil
+	}
+
 	ep.Config.ContractTable.AddContract(&contracts.HasField{
 		X:     yVar,
 		Field: expr.Sel.Name,
-		Pos:   fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Sel.Pos()),
+		Pos:   ep.Config.SymbolPos(expr.Sel.Pos()),
 	})
 
 	return types.ExprAttributeFromDataType(fieldAttribute.DataType).AddTypeVar(
-		typevars.MakeField(yVar, expr.Sel.Name, 0, fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Sel.Pos())),
+		typevars.MakeField(yVar, expr.Sel.Name, 0, ep.Config.SymbolPosition(expr.Sel)),
 	), nil
 }
 
@@ -1650,6 +1776,10 @@ func (ep *Parser) typevar2variable(xTypeVar typevars.Interface) *typevars.Variab
 // - if the expression as an ellipsis, data type of the ellipsis value is returned
 func (ep *Parser) parseIndexExpr(expr *ast.IndexExpr) (*types.ExprAttribute, error) {
 	klog.V(2).Infof("Processing IndexExpr: %#v\n", expr)
+	// X[Index] can be an instantiation of a generic data type or function
+	if attr, ok, err := ep.parseGenericInstance(expr, expr.X, []ast.Expr{expr.Index}); ok || err != nil {
+		return attr, err
+	}
 	// X[Index]
 	// The Index can be a simple literal or another compound expression
 	indexAttr, indexErr := ep.Parse(expr.Index)
@@ -1675,7 +1805,7 @@ func (ep *Parser) parseIndexExpr(expr *ast.IndexExpr) (*types.ExprAttribute, err
 	ep.Config.ContractTable.AddContract(&contracts.IsIndexable{
 		X:   yVarType,
 		Key: indexAttr.TypeVarList[0],
-		Pos: fmt.Sprintf("%v:%v", ep.Config.FileName, expr.Pos()),
+		Pos: ep.Config.SymbolPos(expr.Pos()),
 	})
 
 	// Get definition of the X from the symbol Table (it must be a variable of a data type)
@@ -1684,7 +1814,7 @@ func (ep *Parser) parseIndexExpr(expr *ast.IndexExpr) (*types.ExprAttribute, err
 	case gotypes.MapType:
 		ep.Config.ContractTable.AddContract(&contracts.IsCompatibleWith{
 			X: indexAttr.TypeVarList[0],
-			Y: typevars.MakeMapKey(ep.typevar2variable(indexAttr.TypeVarList[0])),
+			Y: typevars.MakeMapKey(yVarType),
 		})
 		return types.ExprAttributeFromDataType(indexExpr).AddTypeVar(
 			typevars.MakeMapValue(yVarType),
@@ -1702,6 +1832,117 @@ func (ep *Parser) parseIndexExpr(expr *ast.IndexExpr) (*types.ExprAttribute, err
 	}
 }
 
+// parseIndexListExpr consumes ast.IndexListExpr and produces:
+// - if the expression is a generic data type, instance of the data type is returned
+// - if the expression is a generic function, instance of the function is returned
+func (ep *Parser) parseIndexListExpr(expr *ast.IndexListExpr) (*types.ExprAttribute, error) {
+	klog.V(2).Infof("Processing IndexListExpr: %#v\n", expr)
+	attr, ok, err := ep.parseGenericInstance(expr, expr.X, expr.Indices)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("IndexListExpr %#v is neither a generic data type nor a generic function", expr)
+	}
+	return attr, nil
+}
+
+// parseGenericInstance consumes X[Index1, ..., IndexN] and produces (if X is generic):
+// - if the X is a data type, instance of the data type is returned
+// - if the X is a function, the function with type parameters substituted by the indices is returned
+// The second return value is false if the X is not generic.
+func (ep *Parser) parseGenericInstance(expr ast.Expr, x ast.Expr, indices []ast.Expr) (*types.ExprAttribute, bool, error) {
+	isDT, err := ep.isGenericDataType(x)
+	if err != nil {
+		return nil, false, err
+	}
+	if isDT {
+		def, err := ep.TypeParser.Parse(expr)
+		if err != nil {
+			return nil, false, err
+		}
+		return types.ExprAttributeFromDataType(def).AddTypeVar(
+			typevars.MakeConstant(ep.Config.PackageName, def),
+		), true, nil
+	}
+
+	if !ep.isGenericFunction(x) {
+		return nil, false, nil
+	}
+
+	xDefAttr, err := ep.Parse(x)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(xDefAttr.DataTypeList) != 1 {
+		return nil, false, fmt.Errorf("X of %#v does not return one value", expr)
+	}
+	function, ok := xDefAttr.DataTypeList[0].(*gotypes.Function)
+	if !ok {
+		return nil, false, fmt.Errorf("X of %#v is expected to be a generic function, got %#v instead", expr, xDefAttr.DataTypeList[0])
+	}
+
+	var args []gotypes.DataType
+	for _, index := range indices {
+		arg, err := ep.TypeParser.Parse(index)
+		if err != nil {
+			return nil, false, err
+		}
+		args = append(args, arg)
+	}
+
+	// Make sure the generic function gets accounted as used
+	ep.Config.ContractTable.AddContract(&contracts.PropagatesTo{
+		X: xDefAttr.TypeVarList[0],
+		Y: ep.Config.ContractTable.NewVirtualVar(),
+	})
+
+	// Type arguments not listed are inferred from arguments of the call (see parseCallExpr)
+	def := gotypes.Substitute(function, function.Typeparams, args)
+	return types.ExprAttributeFromDataType(def).AddTypeVar(
+		typevars.MakeConstant(ep.Config.PackageName, def),
+	), true, nil
+}
+
+// isGenericFunction checks if X of X[Index] is a function (or a qualified function) with type parameters
+func (ep *Parser) isGenericFunction(expr ast.Expr) bool {
+	var def *symbols.SymbolDef
+	switch exprType := expr.(type) {
+	case *ast.Ident:
+		sDef, st, err := ep.SymbolTable.LookupVariableLikeSymbol(exprType.Name)
+		if err != nil || st != symbols.FunctionSymbol {
+			return false
+		}
+		def = sDef
+	case *ast.SelectorExpr:
+		ident, ok := exprType.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		qiddef, err := ep.SymbolTable.LookupVariable(ident.Name)
+		if err != nil {
+			return false
+		}
+		qid, ok := qiddef.Def.(*gotypes.Packagequalifier)
+		if !ok {
+			return false
+		}
+		table, err := ep.GlobalSymbolTable.Lookup(qid.Path)
+		if err != nil {
+			return false
+		}
+		sDef, err := table.LookupFunction(exprType.Sel.Name)
+		if err != nil {
+			return false
+		}
+		def = sDef
+	default:
+		return false
+	}
+	function, ok := def.Def.(*gotypes.Function)
+	return ok && len(function.Typeparams) > 0
+}
+
 // parseTypeAssertExpr consumes ast.TypeAssertExpr and produces asserted data type
 func (ep *Parser) parseTypeAssertExpr(expr *ast.TypeAssertExpr) (*types.ExprAttribute, error) {
 	klog.V(2).Infof("Processing TypeAssertExpr: %#v\n", expr)
@@ -1783,8 +2024,10 @@ func (ep *Parser) Parse(expr ast.Expr) (*types.ExprAttribute, error) {
 		return ep.parseStructType(exprType)
 	case *ast.IndexExpr:
 		return ep.parseIndexExpr(exprType)
+	case *ast.IndexListExpr:
+		return ep.parseIndexListExpr(exprType)
 	case *ast.SelectorExpr:
-		return ep.parseSelectorExpr(exprType)
+		return ep.parseSelectorExpr(exprType, false)
 	case *ast.TypeAssertExpr:
 		return ep.parseTypeAssertExpr(exprType)
 	case *ast.FuncLit:
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"path"
 	"strings"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/types"
@@ -43,6 +44,11 @@ func (fp *FileParser) MakePackagequalifier(spec *ast.ImportSpec) *gotypes.Packag
 		// TODO(jchaloup): get the q.Name from the spec.Path's package symbol table
 		st, err := fp.Config.GlobalSymbolTable.Lookup(q.Path)
 		if err != nil {
+			// packages imported by builtin are processed after builtin
+			if fp.PackageName == "builtin" {
+				q.Name = path.Base(q.Path)
+				return q
+			}
 			panic(err)
 		}
 		switch d := st.(type) {
@@ -192,23 +198,24 @@ func (fp *FileParser) parseTypeSpecs(specs []*ast.TypeSpec) ([]*ast.TypeSpec, er
 	defer fp.Config.ContractTable.UnsetPrefix()
 
 	for _, spec := range specs {
-		fp.Config.ContractTable.SetPrefix(fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Pos()))
+		fp.Config.ContractTable.SetPrefix(fp.Config.SymbolPos(spec.Pos()))
 		// Store a symbol just with a name and origin.
 		// Setting the symbol's definition to nil means the symbol is being parsed (somewhere in the chain)
 		if err := fp.SymbolTable.AddDataType(&symbols.SymbolDef{
-			Name:    spec.Name.Name,
-			Package: fp.PackageName,
-			Pos:     fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Pos()),
-			Def:     nil,
+			Name:     spec.Name.Name,
+			Package:  fp.PackageName,
+			Pos:      fp.Config.SymbolPos(spec.Pos()),
+			Position: fp.Config.SymbolPosition(spec),
+			Def:      nil,
 		}); err != nil {
-			fp.Config.ContractTable.DropPrefixContracts(fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Pos()))
+			fp.Config.ContractTable.DropPrefixContracts(fp.Config.SymbolPos(spec.Pos()))
 			return nil, err
 		}
 
 		// TODO(jchaloup): capture the current state of the allocated symbol table
 		// JIC the parsing ends with end error. Which can result into re-parsing later on.
 		// Which can result in re-allocation. It should be enough two-level allocated symbol table.
-		typeDef, err := fp.TypeParser.Parse(spec.Type)
+		typeParams, typeDef, err := fp.parseTypeSpecDef(spec)
 		if err != nil {
 			klog.V(2).Infof("File parse TypeParser error: %v\n", err)
 			postponed = append(postponed, spec)
@@ -216,10 +223,13 @@ func (fp *FileParser) parseTypeSpecs(specs []*ast.TypeSpec) ([]*ast.TypeSpec, er
 		}
 
 		if err := fp.SymbolTable.AddDataType(&symbols.SymbolDef{
-			Name:    spec.Name.Name,
-			Package: fp.PackageName,
-			Pos:     fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Pos()),
-			Def:     typeDef,
+			Name:       spec.Name.Name,
+			Package:    fp.PackageName,
+			Pos:        fp.Config.SymbolPos(spec.Pos()),
+			Position:   fp.Config.SymbolPosition(spec),
+			Def:        typeDef,
+			Typeparams: typeParams,
+			Alias:      spec.Assign.IsValid(),
 		}); err != nil {
 			return nil, err
 		}
@@ -227,6 +237,28 @@ func (fp *FileParser) parseTypeSpecs(specs []*ast.TypeSpec) ([]*ast.TypeSpec, er
 	return postponed, nil
 }
 
+// parseTypeSpecDef parses a data type definition together with its type parameters (if any).
+// The type parameters are visible in the definition only.
+func (fp *FileParser) parseTypeSpecDef(spec *ast.TypeSpec) ([]gotypes.DataType, gotypes.DataType, error) {
+	if spec.TypeParams == nil {
+		typeDef, err := fp.TypeParser.Parse(spec.Type)
+		return nil, typeDef, err
+	}
+
+	fp.SymbolTable.Push()
+	defer fp.SymbolTable.Pop()
+
+	typeParams, err := fp.TypeParser.ParseTypeParams(spec.TypeParams)
+	if err != nil {
+		return nil, nil, err
+	}
+	typeDef, err := fp.TypeParser.Parse(spec.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	return typeParams, typeDef, nil
+}
+
 func (fp *FileParser) parseConstValueSpecs(specs []types.ConstSpec, reprocessing bool) ([]types.ConstSpec, error) {
 	var postponed []types.ConstSpec
 	fp.Config.ContractTable.UnsetPrefix()
@@ -234,12 +266,12 @@ func (fp *FileParser) parseConstValueSpecs(specs []types.ConstSpec, reprocessing
 
 	for {
 		for _, spec := range specs {
-			fp.Config.ContractTable.SetPrefix(fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Spec.Names[0].Pos()))
+			fp.Config.ContractTable.SetPrefix(fp.Config.SymbolPos(spec.Spec.Names[0].Pos()))
 			defs, err := fp.StmtParser.ParseConstValueSpec(spec)
 			if err != nil {
 				klog.V(2).Infof("File parse ValueSpec %#v error: %v\n", spec, err)
 				postponed = append(postponed, spec)
-				fp.Config.ContractTable.DropPrefixContracts(fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Spec.Names[0].Pos()))
+				fp.Config.ContractTable.DropPrefixContracts(fp.Config.SymbolPos(spec.Spec.Names[0].Pos()))
 				continue
 			}
 			for _, def := range defs {
@@ -272,12 +304,12 @@ func (fp *FileParser) parseValueSpecs(specs []*ast.ValueSpec, reprocessing bool)
 
 	for {
 		for _, spec := range specs {
-			fp.Config.ContractTable.SetPrefix(fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Names[0].Pos()))
+			fp.Config.ContractTable.SetPrefix(fp.Config.SymbolPos(spec.Names[0].Pos()))
 			defs, err := fp.StmtParser.ParseValueSpec(spec)
 			if err != nil {
 				klog.V(2).Infof("File parse ValueSpec %q error: %v\n", spec.Names[0].Name, err)
 				postponed = append(postponed, spec)
-				fp.Config.ContractTable.DropPrefixContracts(fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Names[0].Pos()))
+				fp.Config.ContractTable.DropPrefixContracts(fp.Config.SymbolPos(spec.Names[0].Pos()))
 				continue
 			}
 
@@ -346,10 +378,11 @@ func (fp *FileParser) parseFuncDeclaration(spec *ast.FuncDecl) (bool, error) {
 	}
 
 	if err := fp.SymbolTable.AddFunction(&symbols.SymbolDef{
-		Name:    spec.Name.Name,
-		Package: fp.PackageName,
-		Pos:     fmt.Sprintf("%v:%v", fp.Config.FileName, spec.Pos()),
-		Def:     funcDef,
+		Name:     spec.Name.Name,
+		Package:  fp.PackageName,
+		Pos:      fp.Config.SymbolPos(spec.Pos()),
+		Position: fp.Config.SymbolPosition(spec),
+		Def:      funcDef,
 	}); err != nil {
 		klog.V(2).Infof("Error during parsing of function %q declaration: %v", spec.Name.Name, err)
 		return false, err
@@ -6,6 +6,7 @@ import (
 	"go/token"
 	"os"
 	"path"
+	"reflect"
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
@@ -18,6 +19,7 @@ import (
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables/stack"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 )
 
 func parseBuiltin(config *parsertypes.Config) error {
@@ -50,6 +52,23 @@ func parseBuiltin(config *parsertypes.Config) error {
 	return nil
 }
 
+// newConfig creates a config of a package parsed in a single file
+func newConfig(gtable *global.Table, pkg string) *parsertypes.Config {
+	config := &parsertypes.Config{
+		PackageName:           pkg,
+		SymbolTable:           stack.New(),
+		AllocatedSymbolsTable: alloctable.New("", ""),
+		GlobalSymbolTable:     gtable,
+		ContractTable:         contracttable.New(pkg, "", ""),
+	}
+	config.SymbolsAccessor = accessors.NewAccessor(config.GlobalSymbolTable).SetCurrentTable(config.PackageName, config.SymbolTable)
+	config.SymbolTable.Push()
+	config.TypeParser = typeparser.New(config)
+	config.ExprParser = exprparser.New(config)
+	config.StmtParser = stmtparser.New(config)
+	return config
+}
+
 // TODO(jchaloup): replace this test with generated tests
 func TestDataTypes(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/pkg/parser/testdata"
@@ -111,3 +130,348 @@ func TestDataTypes(t *testing.T) {
 		t.Errorf("Unable to parse file %v: %v", gofile, err)
 	}
 }
+
+func TestGenerics(t *testing.T) {
+	gopkg := "example.com/generics"
+	gocode := `package generics
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type List[T any] struct {
+	items []T
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type Named struct {
+	List[string]
+	*Pair[string, int]
+	Name string
+}
+
+func Sum[T Number](values ...T) T {
+	var s T
+	for _, v := range values {
+		s += v
+	}
+	return s
+}
+
+func (l *List[T]) Push(item T) {
+	l.items = append(l.items, item)
+}
+
+func (l List[T]) At(i int) T {
+	return l.items[i]
+}
+
+var total = Sum(1, 2)
+`
+	f, err := parser.ParseFile(token.NewFileSet(), "generics.go", gocode, 0)
+	if err != nil {
+		t.Fatalf("AST Parse error: %v", err)
+	}
+
+	gtable := global.New("", "", nil)
+	if err := parseBuiltin(newConfig(gtable, "builtin")); err != nil {
+		t.Skipf("Unable to parse builtin: %v", err)
+	}
+
+	config := newConfig(gtable, gopkg)
+	payload, err := MakePayload(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewParser(config).Parse(payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.DataTypes)+len(payload.Functions)+len(payload.Variables) > 0 {
+		t.Fatalf("Expected no symbol postponed, got %#v", payload)
+	}
+	table, err := config.SymbolTable.Table(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ident := func(pkg, name string) *gotypes.Identifier {
+		return &gotypes.Identifier{Package: pkg, Def: name}
+	}
+	typeParamT := &gotypes.Typeparam{Name: "T", Constraint: ident("builtin", "any")}
+	typeParamK := &gotypes.Typeparam{Name: "K", Constraint: ident("builtin", "comparable")}
+	typeParamV := &gotypes.Typeparam{Name: "V", Constraint: ident("builtin", "any")}
+	number := &gotypes.Typeparam{Name: "T", Constraint: ident(gopkg, "Number")}
+
+	dataTypes := []struct {
+		name       string
+		def        gotypes.DataType
+		typeParams []gotypes.DataType
+	}{
+		{
+			name: "Number",
+			def: &gotypes.Interface{Methods: []gotypes.InterfaceMethodsItem{
+				{Def: &gotypes.Union{Terms: []gotypes.UnionTermsItem{
+					{Tilde: true, Def: ident("builtin", "int")},
+					{Tilde: true, Def: ident("builtin", "int64")},
+					{Tilde: true, Def: ident("builtin", "float64")},
+				}}},
+			}},
+		},
+		{
+			name: "List",
+			def: &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+				{Name: "items", Def: &gotypes.Slice{Elmtype: typeParamT}},
+			}},
+			typeParams: []gotypes.DataType{typeParamT},
+		},
+		{
+			name: "Pair",
+			def: &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+				{Name: "Key", Def: typeParamK},
+				{Name: "Value", Order: 1, Def: typeParamV},
+			}},
+			typeParams: []gotypes.DataType{typeParamK, typeParamV},
+		},
+		{
+			name: "Named",
+			def: &gotypes.Struct{Fields: []gotypes.StructFieldsItem{
+				{Name: "List", Embedded: true, Def: &gotypes.Instance{
+					Def:  ident(gopkg, "List"),
+					Args: []gotypes.DataType{ident("builtin", "string")},
+				}},
+				{Name: "Pair", Embedded: true, Order: 1, Def: &gotypes.Pointer{Def: &gotypes.Instance{
+					Def:  ident(gopkg, "Pair"),
+					Args: []gotypes.DataType{ident("builtin", "string"), ident("builtin", "int")},
+				}}},
+				{Name: "Name", Order: 2, Def: ident("builtin", "string")},
+			}},
+		},
+	}
+	for _, dataType := range dataTypes {
+		sym, err := table.LookupDataType(dataType.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sym.Def, dataType.def) {
+			t.Errorf("Expected %v to be %#v, got %#v", dataType.name, dataType.def, sym.Def)
+		}
+		if !reflect.DeepEqual(sym.Typeparams, dataType.typeParams) {
+			t.Errorf("Expected %v type parameters %#v, got %#v", dataType.name, dataType.typeParams, sym.Typeparams)
+		}
+	}
+
+	functions := []struct {
+		receiver string
+		name     string
+		def      gotypes.DataType
+	}{
+		{
+			name: "Sum",
+			def: &gotypes.Function{
+				Package:    gopkg,
+				Params:     []gotypes.DataType{&gotypes.Ellipsis{Def: number}},
+				Results:    []gotypes.DataType{number},
+				Typeparams: []gotypes.DataType{number},
+			},
+		},
+		{
+			receiver: "List",
+			name:     "Push",
+			def: &gotypes.Method{
+				Def: &gotypes.Function{
+					Package: gopkg,
+					Params:  []gotypes.DataType{typeParamT},
+				},
+				Receiver:   &gotypes.Pointer{Def: ident(gopkg, "List")},
+				Typeparams: []gotypes.DataType{typeParamT},
+			},
+		},
+		{
+			receiver: "List",
+			name:     "At",
+			def: &gotypes.Method{
+				Def: &gotypes.Function{
+					Package: gopkg,
+					Params:  []gotypes.DataType{ident("builtin", "int")},
+					Results: []gotypes.DataType{typeParamT},
+				},
+				Receiver:   ident(gopkg, "List"),
+				Typeparams: []gotypes.DataType{typeParamT},
+			},
+		},
+	}
+	for _, function := range functions {
+		sym, err := table.LookupFunction(function.name)
+		if function.receiver != "" {
+			sym, err = table.LookupMethod(function.receiver, function.name)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sym.Def, function.def) {
+			t.Errorf("Expected %v to be %#v, got %#v", function.name, function.def, sym.Def)
+		}
+	}
+
+	// type argument inferred from untyped constants
+	sym, err := table.LookupVariable("total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := ident("builtin", "int"); !reflect.DeepEqual(sym.Def, expected) {
+		t.Errorf("Expected total to be %#v, got %#v", expected, sym.Def)
+	}
+}
+
+func TestConstantValues(t *testing.T) {
+	gopkg := "example.com/constants"
+	gocode := `package constants
+
+type Duration int64
+
+type Mode uint8
+
+const (
+	Nanosecond  Duration = 1
+	Microsecond          = 1000 * Nanosecond
+	Millisecond          = 1000 * Microsecond
+)
+
+const (
+	A = iota * 10
+	B
+	C
+	_
+	E
+)
+
+const (
+	KB = 1 << (10 * (iota + 1))
+	MB
+	GB
+	EB = 1 << 60
+	ZB = EB << 10
+)
+
+const (
+	MaxUint   = ^uint(0)
+	MaxUint8  = ^uint8(0)
+	Perm      = ^Mode(0x0f)
+	MinusTwo  = ^1
+	Half      = 1 / 2
+	HalfFloat = 1 / 2.0
+	Trunc     = -7 / 2
+	Rem       = -7 % 2
+)
+
+const (
+	Letter   = 'a'
+	Next     = Letter + 1
+	Accented = '\u00e9'
+	Word     = "go" + "pher"
+	Char     = string(Next)
+)
+
+const (
+	Quarter = 0x1p-2
+	Big     = 0x1p100
+	Two     = 2.0
+	Third   = 1.0 / 3
+	Typed   float32 = 1.5
+)
+
+const (
+	I        = 2i
+	Z        = 1 + 2i
+	Square   = Z * Z
+	Built    = complex(1, -0.5)
+)
+
+const (
+	Yes  = 1 < 2
+	No   = !Yes
+	Mask = 0b1010_1010 &^ 0o17
+)
+`
+	f, err := parser.ParseFile(token.NewFileSet(), "constants.go", gocode, 0)
+	if err != nil {
+		t.Fatalf("AST Parse error: %v", err)
+	}
+
+	gtable := global.New("", "", nil)
+	if err := parseBuiltin(newConfig(gtable, "builtin")); err != nil {
+		t.Skipf("Unable to parse builtin: %v", err)
+	}
+
+	config := newConfig(gtable, gopkg)
+	payload, err := MakePayload(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := NewParser(config).Parse(payload); err != nil {
+		t.Fatal(err)
+	}
+	table, err := config.SymbolTable.Table(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{
+		"Nanosecond":  "1",
+		"Microsecond": "1000",
+		"Millisecond": "1000000",
+		"A":           "0",
+		"B":           "10",
+		"C":           "20",
+		"E":           "40",
+		"KB":          "1024",
+		"MB":          "1048576",
+		"GB":          "1073741824",
+		"EB":          "1152921504606846976",
+		"ZB":          "1180591620717411303424",
+		"MaxUint":     "18446744073709551615",
+		"MaxUint8":    "255",
+		"Perm":        "240",
+		"MinusTwo":    "-2",
+		"Half":        "0",
+		"HalfFloat":   "1/2",
+		"Trunc":       "-3",
+		"Rem":         "-1",
+		"Letter":      "97",
+		"Next":        "98",
+		"Accented":    "233",
+		"Word":        `"gopher"`,
+		"Char":        `"b"`,
+		"Quarter":     "1/4",
+		"Big":         "1267650600228229401496703205376",
+		"Two":         "2",
+		"Third":       "1/3",
+		"Typed":       "3/2",
+		"I":           "(0 + 2i)",
+		"Z":           "(1 + 2i)",
+		"Square":      "(-3 + 4i)",
+		"Built":       "(1 + -1/2i)",
+		"Yes":         "true",
+		"No":          "false",
+		"Mask":        "160",
+	}
+	for name, value := range values {
+		sym, err := table.LookupVariable(name)
+		if err != nil {
+			t.Errorf("Constant %v not found: %v", name, err)
+			continue
+		}
+		c, ok := sym.Def.(*gotypes.Constant)
+		if !ok {
+			t.Errorf("Expected %v to be a constant, got %#v", name, sym.Def)
+			continue
+		}
+		if c.Value != value {
+			t.Errorf("Expected %v = %v, got %q", name, value, c.Value)
+		}
+	}
+}
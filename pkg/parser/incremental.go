@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gofed/symbols-extractor/pkg/incremental"
+	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
+	contractglobal "github.com/gofed/symbols-extractor/pkg/parser/contracts/global"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+	"k8s.io/klog/v2"
+)
+
+// keepPreviousAPI keeps the stored symbol table of the changed package
+// to compare it with the extracted one (pp.mutex is expected to be held)
+func (pp *ProjectParser) keepPreviousAPI(pkg string) {
+	table, err := pp.globalSymbolTable.Lookup(pkg)
+	if err != nil {
+		klog.V(1).Infof("Previous API of %q not available: %v", pkg, err)
+		return
+	}
+	if previous, ok := table.(*tables.Table); ok {
+		pp.previousAPIs[pkg] = previous
+	}
+}
+
+// recorded checks changed symbols of the package are known
+func (pp *ProjectParser) recorded(pkg string) bool {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+	return pp.changes.Recorded(pkg)
+}
+
+// changedSymbols lists changed symbols of the package
+func (pp *ProjectParser) changedSymbols(pkg string) []string {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+	return pp.changes.Symbols(pkg)
+}
+
+// recordChanges records symbols of the processed package changed since the package was extracted previously
+// and symbols referring to changed symbols (of the package or of its imported packages)
+func (pp *ProjectParser) recordChanges(pkg string, table *tables.Table) error {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if previous, ok := pp.previousAPIs[pkg]; ok {
+		delete(pp.previousAPIs, pkg)
+		changed, err := incremental.Diff(previous, table)
+		if err != nil {
+			return fmt.Errorf("Unable to compare %q symbols: %v", pkg, err)
+		}
+		for _, name := range changed {
+			pp.changes.Add(pkg, name)
+		}
+	}
+	if err := incremental.Propagate(pkg, table, pp.changes); err != nil {
+		return fmt.Errorf("Unable to propagate changed symbols of %q: %v", pkg, err)
+	}
+
+	if changed := pp.changes.Symbols(pkg); len(changed) > 0 {
+		fmt.Fprintf(os.Stderr, "Package %q changed symbols: %v\n", pkg, strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// usedChanges lists changed symbols referred to by the stored contracts and allocated symbols of the package
+func (pp *ProjectParser) usedChanges(pkg string, contractsTable *contractglobal.Table, allocTable *allocglobal.Table) ([]string, error) {
+	refs := make(map[incremental.Symbol]struct{})
+	if contractsTable.Exists(pkg) {
+		table, err := contractsTable.Lookup(pkg)
+		if err != nil {
+			return nil, err
+		}
+		contractsRefs, err := incremental.ContractsReferences(table)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read %q contracts: %v", pkg, err)
+		}
+		for ref := range contractsRefs {
+			refs[ref] = struct{}{}
+		}
+	}
+	if allocTable.Exists(pkg) {
+		files, err := allocTable.LookupPackage(pkg)
+		if err != nil {
+			return nil, err
+		}
+		for ref := range incremental.AllocatedReferences(files) {
+			refs[ref] = struct{}{}
+		}
+	}
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+	return pp.changes.Used(refs), nil
+}
+
+// processDependent processes the already processed package again
+// only if it uses changed symbols of its imported packages
+func (pp *ProjectParser) processDependent(c *PackageContext) error {
+	pkg := c.PackagePath
+	used, err := pp.usedChanges(pkg, pp.globalContractsTable, pp.globalAllocSymbolTable)
+	if err != nil {
+		return err
+	}
+
+	if len(used) == 0 {
+		klog.V(1).Infof("Package %q does not use any changed symbol", pkg)
+		table, err := pp.globalSymbolTable.Lookup(pkg)
+		if err != nil {
+			return err
+		}
+		stored, ok := table.(*tables.Table)
+		if !ok {
+			return fmt.Errorf("Unexpected symbol table of %q", pkg)
+		}
+		return pp.recordChanges(pkg, stored)
+	}
+
+	fmt.Fprintf(os.Stderr, "Package %q uses changed symbols (%v), extracting again\n", pkg, strings.Join(used, ", "))
+	pp.mutex.Lock()
+	pp.keepPreviousAPI(pkg)
+	pp.mutex.Unlock()
+	pp.dropArtefacts(pkg)
+	return pp.processPackageContext(c)
+}
+
+// testsAffected checks the stored test artefacts of the package use changed symbols (in the incremental mode).
+// The affected test artefacts are dropped.
+func (pp *ProjectParser) testsAffected(pkg string) (bool, error) {
+	if !pp.incremental {
+		return false, nil
+	}
+	used, err := pp.usedChanges(pkg, pp.globalTestContractsTable, pp.globalTestAllocSymbolTable)
+	if err != nil {
+		return false, err
+	}
+	if len(used) == 0 {
+		return false, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Tests of package %q use changed symbols (%v), extracting again\n", pkg, strings.Join(used, ", "))
+	if err := pp.globalTestAllocSymbolTable.DropStored(pkg); err != nil {
+		return false, err
+	}
+	if err := pp.globalTestContractsTable.DropStored(pkg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,38 @@
+package parser
+
+import "fmt"
+
+// Level of extraction, i.e. which artefacts are stored for each processed package
+type Level int
+
+const (
+	// APILevel stores api.json only
+	APILevel Level = iota
+	// AllocatedLevel stores api.json and allocated.json
+	AllocatedLevel
+	// FullLevel stores api.json, allocated.json and contracts.json
+	FullLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case APILevel:
+		return "api"
+	case AllocatedLevel:
+		return "api+allocated"
+	}
+	return "full"
+}
+
+// ParseLevel parses an extraction level (api, api+allocated or full)
+func ParseLevel(level string) (Level, error) {
+	switch level {
+	case "api":
+		return APILevel, nil
+	case "api+allocated":
+		return AllocatedLevel, nil
+	case "", "full":
+		return FullLevel, nil
+	}
+	return FullLevel, fmt.Errorf("Unknown extraction level %q, expected api, api+allocated or full", level)
+}
@@ -7,13 +7,15 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
-	"os/exec"
 	"path"
 	"sort"
 	"strings"
+	"sync"
 
 	util "github.com/gofed/symbols-extractor/cmd/go"
 	"github.com/gofed/symbols-extractor/pkg/analyzers/type/runner"
+	"github.com/gofed/symbols-extractor/pkg/incremental"
+	"github.com/gofed/symbols-extractor/pkg/manifest"
 	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
 	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
 	contractglobal "github.com/gofed/symbols-extractor/pkg/parser/contracts/global"
@@ -23,6 +25,8 @@ import (
 	stmtparser "github.com/gofed/symbols-extractor/pkg/parser/statement"
 	typeparser "github.com/gofed/symbols-extractor/pkg/parser/type"
 	"github.com/gofed/symbols-extractor/pkg/parser/types"
+	"github.com/gofed/symbols-extractor/pkg/platforms"
+	"github.com/gofed/symbols-extractor/pkg/resolvers"
 	"github.com/gofed/symbols-extractor/pkg/snapshots"
 	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
@@ -32,33 +36,6 @@ import (
 	"k8s.io/klog/v2"
 )
 
-var GOOS = map[string]struct{}{
-	"android":   {},
-	"darwin":    {},
-	"dragonfly": {},
-	"freebsd":   {},
-	"linux":     {},
-	"nacl":      {},
-	"netbsd":    {},
-	"openbsd":   {},
-	"plan9":     {},
-	"solaris":   {},
-	"windows":   {},
-}
-
-var GOARCH = map[string]struct{}{
-	"386":      {},
-	"amd64":    {},
-	"amd64p32": {},
-	"arm64":    {},
-	"arm":      {},
-	"mips64":   {},
-	"mips64le": {},
-	"ppc64":    {},
-	"ppc64le":  {},
-	"s390x":    {},
-}
-
 // Context participants:
 // - package (fully qualified package name, e.g. github.com/coreos/etcd/pkg/wait)
 // - package file (package + its underlying filename)
@@ -79,6 +56,8 @@ type FileContext struct {
 	Functions []*ast.FuncDecl
 
 	AllocatedSymbolsTable *alloctable.Table
+	// Contracts collected in the file (the table is shared by all files of a package)
+	ContractTable *contracttable.Table
 }
 
 // PackageContext storing context for a package
@@ -103,9 +82,17 @@ type PackageContext struct {
 	DataTypes []*ast.TypeSpec
 	Variables []*ast.ValueSpec
 	Functions []*ast.FuncDecl
+
+	// Package the test files belong to (set for the test scope only, i.e. for
+	// a package extended with its _test.go files and for the external _test package)
+	TestedPackage string
+	// _test.go files of the package (only their symbols are allocated in the test scope)
+	TestFiles map[string]struct{}
 }
 
 // Idea:
+// - collect the graph of imported packages not yet processed (see scheduler.go)
+// - process packages of the graph concurrently, each package once its imported packages are processed
 // - process the input package
 // - retrieve all input package files
 // - process each file of the input package
@@ -128,22 +115,58 @@ type ProjectParser struct {
 	// For each package and its file store its alloc symbol table
 	globalAllocSymbolTable *allocglobal.Table
 	globalContractsTable   *contractglobal.Table
-	// package stack
-	packageStack []*PackageContext
+	// Allocated symbols and contracts of _test.go files
+	globalTestAllocSymbolTable *allocglobal.Table
+	globalTestContractsTable   *contractglobal.Table
+	// inputs of the stored artefacts of each package
+	manifests *manifest.Table
+	// packages with artefacts checked to be up to date
+	upToDatePackages map[string]struct{}
+	// C symbol table loaded
+	cgoLoaded bool
+	// guards upToDatePackages and cgoLoaded (packages are processed concurrently)
+	mutex sync.Mutex
+	// number of packages processed concurrently
+	workers int
 
 	goVersion string
 	allocated bool
+	// extract _test.go files of the parsed package as well
+	tests bool
+	// locates package files before vendor directories and GOPATH are searched
+	resolver resolvers.Resolver
+	// platform the package files are selected for (host if not set)
+	platform *platforms.Platform
+	// artefacts stored for each processed package
+	level Level
+	// re-extract packages using changed symbols of re-extracted packages (see SetIncremental)
+	incremental bool
+	// API of the changed packages as extracted previously
+	previousAPIs map[string]*tables.Table
+	// changed symbols of processed (and checked) packages
+	changes incremental.Changes
+	// contracts that could not be evaluated (with allocated set)
+	diagnostics *runner.Report
 }
 
 func New(symbolTableDir, cgoSymbolsPath, goVersion string, snapshot snapshots.Snapshot) (*ProjectParser, error) {
 	pp := &ProjectParser{
 		symbolTableDirectory:   symbolTableDir,
 		cgoSymbolsPath:         cgoSymbolsPath,
-		packageStack:           make([]*PackageContext, 0),
 		globalSymbolTable:      global.New(symbolTableDir, goVersion, snapshot),
 		globalAllocSymbolTable: allocglobal.New(symbolTableDir, goVersion, snapshot),
 		globalContractsTable:   contractglobal.New(symbolTableDir, goVersion, snapshot),
 		goVersion:              goVersion,
+		level:                  FullLevel,
+		workers:                1,
+
+		globalTestAllocSymbolTable: allocglobal.NewTest(symbolTableDir, goVersion, snapshot),
+		globalTestContractsTable:   contractglobal.NewTest(symbolTableDir, goVersion, snapshot),
+		manifests:                  manifest.New(symbolTableDir, goVersion, snapshot),
+		upToDatePackages:           make(map[string]struct{}),
+		previousAPIs:               make(map[string]*tables.Table),
+		changes:                    make(incremental.Changes),
+		diagnostics:                runner.NewReport(),
 	}
 
 	// set C pseudo-package
@@ -156,6 +179,67 @@ func New(symbolTableDir, cgoSymbolsPath, goVersion string, snapshot snapshots.Sn
 	return pp, nil
 }
 
+// SetResolver sets a resolver used to locate package files (e.g. in the module cache)
+// before vendor directories and GOPATH are searched.
+func (pp *ProjectParser) SetResolver(resolver resolvers.Resolver) *ProjectParser {
+	pp.resolver = resolver
+	return pp
+}
+
+// SetPlatform sets a platform (GOOS, GOARCH, CGO_ENABLED) the package files are selected for.
+// If not set, the files are selected for the host.
+func (pp *ProjectParser) SetPlatform(platform *platforms.Platform) *ProjectParser {
+	pp.platform = platform
+	return pp
+}
+
+// SetTests enables extraction of _test.go files (including the external _test package)
+// of each parsed package. Symbols allocated in the test files are stored separately
+// (in allocated_test.json and contracts_test.json) and marked as the test scope.
+func (pp *ProjectParser) SetTests(tests bool) *ProjectParser {
+	pp.tests = tests
+	return pp
+}
+
+// SetLevel sets artefacts stored for each processed package (all of them by default).
+// An already processed package is reused if it provides the artefacts of the level.
+func (pp *ProjectParser) SetLevel(level Level) *ProjectParser {
+	pp.level = level
+	return pp
+}
+
+// SetWorkers sets a number of packages processed concurrently (one by default).
+// A package is processed once all its imported packages are processed.
+func (pp *ProjectParser) SetWorkers(workers int) *ProjectParser {
+	pp.workers = workers
+	return pp
+}
+
+// loadCGOSymbols loads the C pseudo-package symbol table (once, the table does not change)
+func (pp *ProjectParser) loadCGOSymbols() error {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if pp.cgoLoaded {
+		return nil
+	}
+	pp.cgoSymbolTable.Flush()
+	if err := pp.cgoSymbolTable.LoadFromFile(pp.cgoSymbolsPath); err != nil {
+		return err
+	}
+	pp.cgoLoaded = true
+	return nil
+}
+
+// SetIncremental enables the incremental mode. Packages changed since extracted are extracted again
+// as always. In addition, symbols of each changed package are compared with the previously extracted ones
+// and every package whose contracts or allocated symbols refer to a changed symbol is extracted again as well.
+// Packages not affected by the changes keep their artefacts.
+func (pp *ProjectParser) SetIncremental(incremental bool) *ProjectParser {
+	pp.incremental = incremental
+	return pp
+}
+
 func (pp *ProjectParser) processImports(file string, imports []*ast.ImportSpec) (missingImports []*gotypes.Packagequalifier) {
 	for _, spec := range imports {
 		qPath := strings.Replace(spec.Path.Value, "\"", "", -1)
@@ -165,128 +249,57 @@ func (pp *ProjectParser) processImports(file string, imports []*ast.ImportSpec)
 			if pp.cgoSymbolsPath == "" {
 				klog.Fatalf("Unable to load C symbol table. cgoSymbolsPath not set.")
 			}
-			pp.cgoSymbolTable.Flush()
-			if err := pp.cgoSymbolTable.LoadFromFile(pp.cgoSymbolsPath); err != nil {
+			if err := pp.loadCGOSymbols(); err != nil {
 				panic(err)
 			}
 			continue
 		}
-		// Check if the imported package is already processed
-		_, err := pp.globalSymbolTable.Lookup(qPath)
-		if err != nil {
-			missingImports = append(missingImports, &gotypes.Packagequalifier{Path: qPath})
-			klog.V(2).Infof("Package %q not yet processed\n", qPath)
+		// Check if the imported package is already processed (from the current package files)
+		if pp.upToDate(qPath) {
+			if _, err := pp.globalSymbolTable.Lookup(qPath); err == nil {
+				continue
+			}
 		}
+		missingImports = append(missingImports, &gotypes.Packagequalifier{Path: qPath})
+		klog.V(2).Infof("Package %q not yet processed\n", qPath)
 		// TODO(jchaloup): Check if the package is already in the package queue
 		//                 If it is it is an error (import cycles are not permitted)
 	}
 	return
 }
 
-func (pp *ProjectParser) getPackageFiles(packagePath string) (files []string, packageLocation string, err error) {
-
-	listGoFiles := func(packagePath string, cgo bool) ([]string, error) {
-
-		collectFiles := func(output string) []string {
-			line := strings.Split(string(output), "\n")[0]
-			line = line[1 : len(line)-1]
-			if line == "" {
-				return nil
-			}
-			return strings.Split(line, " ")
-		}
-		// check GOPATH/packagePath
-		filter := "{{.GoFiles}}"
-		if cgo {
-			filter = "{{.CgoFiles}}"
-		}
-		cmd := exec.Command("go", "list", "-f", filter, packagePath)
-		output, e := cmd.CombinedOutput()
-		if e == nil {
-			return collectFiles(string(output)), nil
-		}
-
-		if strings.Contains(string(output), "no buildable Go source files in") {
-			return nil, nil
-		}
-
-		return nil, e
-	}
-
-	files, ppath, e := func() ([]string, string, error) {
-		var searched []string
-		// First searched the vendor directories
-		pathParts := strings.Split(pp.packagePath, string(os.PathSeparator))
-		for i := len(pathParts); i >= 0; i-- {
-			vendorpath := path.Join(path.Join(pathParts[:i]...), "vendor", packagePath)
-			klog.V(1).Infof("Checking %v directory", vendorpath)
-			if l, e := listGoFiles(vendorpath, false); e == nil {
-				return l, vendorpath, e
-			}
-			searched = append(searched, vendorpath)
-		}
-
-		klog.V(1).Infof("Checking %v directory", packagePath)
-		if l, e := listGoFiles(packagePath, false); e == nil {
-			return l, packagePath, e
-		}
-		searched = append(searched, packagePath)
-
-		return nil, "", fmt.Errorf("Unable to find %q in any of:\n\t\t%v\n", packagePath, strings.Join(searched, "\n\t\t"))
-	}()
-
-	if e != nil {
-		return nil, "", e
-	}
-
-	// cgo files enabled?
-	cgoFiles, e := listGoFiles(ppath, true)
-	if e != nil {
-		return nil, "", e
-	}
-
-	if len(cgoFiles) > 0 {
-		files = append(files, cgoFiles...)
-	}
-
-	{
-		cmd := exec.Command("go", "list", "-f", "{{.Dir}}", ppath)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return nil, "", fmt.Errorf("go list -f {{.Dir}} %v failed: %v", ppath, err)
-		}
-		lines := strings.Split(string(output), "\n")
-		packageLocation = string(lines[0])
+func (pp *ProjectParser) createPackageContext(packagePath string) (*PackageContext, error) {
+	files, path, err := util.GetPackageFiles(pp.packagePath, packagePath, pp.resolver, pp.platform)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get %q package files: %v", packagePath, err)
 	}
-
-	return files, packageLocation, nil
+	return pp.newPackageContext(packagePath, path, files), nil
 }
 
-func (pp *ProjectParser) createPackageContext(packagePath string) (*PackageContext, error) {
+func (pp *ProjectParser) newPackageContext(packagePath, packageDir string, files []string) *PackageContext {
 	c := &PackageContext{
 		PackagePath: packagePath,
+		PackageDir:  packageDir,
 		FileIndex:   0,
 		SymbolTable: stack.New(),
 	}
 
-	files, path, err := util.GetPackageFiles(pp.packagePath, packagePath)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to get %q package files: %v", packagePath, err)
-	}
-	c.PackageDir = path
+	contractTable := contracttable.New(packagePath, pp.symbolTableDirectory, pp.goVersion)
 	for _, file := range files {
 		fc := &FileContext{
 			Filename:              file,
 			AllocatedSymbolsTable: alloctable.New(packagePath, file),
+			ContractTable:         contractTable,
 		}
 		c.Files = append(c.Files, fc)
 	}
 
 	config := &types.Config{
 		PackageName:       packagePath,
+		FileSet:           token.NewFileSet(),
 		SymbolTable:       c.SymbolTable,
 		GlobalSymbolTable: pp.globalSymbolTable,
-		ContractTable:     contracttable.New(packagePath, pp.symbolTableDirectory, pp.goVersion),
+		ContractTable:     contractTable,
 		SymbolsAccessor:   accessors.NewAccessor(pp.globalSymbolTable).SetCurrentTable(packagePath, c.SymbolTable),
 	}
 
@@ -297,7 +310,65 @@ func (pp *ProjectParser) createPackageContext(packagePath string) (*PackageConte
 	c.Config = config
 
 	klog.V(2).Infof("PackageContextCreated: %#v\n\n", c)
-	return c, nil
+	return c
+}
+
+// createTestPackageContexts creates a context of a package extended with its _test.go files
+// and a context of the external _test package (if there are any test files).
+// Contracts of all test files are collected in one contract table.
+func (pp *ProjectParser) createTestPackageContexts(packagePath string) ([]*PackageContext, error) {
+	c, err := pp.createPackageContext(packagePath)
+	if err != nil {
+		return nil, err
+	}
+	if c.PackageDir == "" {
+		return nil, nil
+	}
+
+	testFiles, xtestFiles, err := resolvers.PackageTestFiles(c.PackageDir, pp.platform)
+	if err != nil {
+		return nil, err
+	}
+
+	var contexts []*PackageContext
+	if len(testFiles) > 0 {
+		var files []string
+		for _, fc := range c.Files {
+			files = append(files, fc.Filename)
+		}
+		contexts = append(contexts, pp.newPackageContext(packagePath, c.PackageDir, append(files, testFiles...)))
+	}
+	if len(xtestFiles) > 0 {
+		contexts = append(contexts, pp.newPackageContext(packagePath+"_test", c.PackageDir, xtestFiles))
+	}
+	if len(contexts) == 0 {
+		return nil, nil
+	}
+
+	testFilesSet := make(map[string]struct{})
+	for _, file := range append(testFiles, xtestFiles...) {
+		testFilesSet[file] = struct{}{}
+	}
+
+	// contracts of the package files are already stored in contracts.json
+	discarded := contracttable.New(packagePath, pp.symbolTableDirectory, pp.goVersion)
+	contractTable := contexts[0].Config.ContractTable
+	for _, tc := range contexts {
+		tc.TestedPackage = packagePath
+		tc.TestFiles = testFilesSet
+		for _, fc := range tc.Files {
+			if _, ok := testFilesSet[fc.Filename]; !ok {
+				fc.ContractTable = discarded
+				continue
+			}
+			fc.ContractTable = contractTable
+			// positions are relative to the tested package directory
+			fc.AllocatedSymbolsTable.Package = packagePath
+			fc.AllocatedSymbolsTable.Scope = alloctable.TestScope
+		}
+	}
+
+	return contexts, nil
 }
 
 func (pp *ProjectParser) reprocessDataTypes(p *PackageContext) error {
@@ -314,6 +385,7 @@ func (pp *ProjectParser) reprocessDataTypes(p *PackageContext) error {
 				payload.Imports = append(payload.Imports, spec)
 			}
 			p.Config.AllocatedSymbolsTable = fileContext.AllocatedSymbolsTable
+			p.Config.ContractTable = fileContext.ContractTable
 			p.Config.FileName = fileContext.Filename
 			if err := fileparser.NewParser(p.Config).Parse(payload); err != nil {
 				return err
@@ -353,6 +425,7 @@ func (pp *ProjectParser) reprocessVariables(p *PackageContext) error {
 					payload.Imports = append(payload.Imports, spec)
 				}
 				p.Config.AllocatedSymbolsTable = fileContext.AllocatedSymbolsTable
+				p.Config.ContractTable = fileContext.ContractTable
 				p.Config.FileName = fileContext.Filename
 				if err := fileparser.NewParser(p.Config).Parse(payload); err != nil {
 					return err
@@ -395,6 +468,7 @@ func (pp *ProjectParser) reprocessConstants(p *PackageContext) error {
 					payload.Imports = append(payload.Imports, spec)
 				}
 				p.Config.AllocatedSymbolsTable = fileContext.AllocatedSymbolsTable
+				p.Config.ContractTable = fileContext.ContractTable
 				p.Config.FileName = fileContext.Filename
 				if err := fileparser.NewParser(p.Config).Parse(payload); err != nil {
 					return err
@@ -437,6 +511,7 @@ func (pp *ProjectParser) reprocessFunctionDeclarations(p *PackageContext) error
 				payload.Imports = append(payload.Imports, spec)
 			}
 			p.Config.AllocatedSymbolsTable = fileContext.AllocatedSymbolsTable
+			p.Config.ContractTable = fileContext.ContractTable
 			p.Config.FileName = fileContext.Filename
 			if err := fileparser.NewParser(p.Config).Parse(payload); err != nil {
 				return err
@@ -474,6 +549,7 @@ func (pp *ProjectParser) reprocessFunctions(p *PackageContext) error {
 				payload.Imports = append(payload.Imports, spec)
 			}
 			p.Config.AllocatedSymbolsTable = fileContext.AllocatedSymbolsTable
+			p.Config.ContractTable = fileContext.ContractTable
 			p.Config.FileName = fileContext.Filename
 			if err := fileparser.NewParser(p.Config).Parse(payload); err != nil {
 				return err
@@ -490,12 +566,120 @@ func (pp *ProjectParser) reprocessFunctions(p *PackageContext) error {
 	return nil
 }
 
+// buildContext identifies the go version and the platform the package files are selected for
+func (pp *ProjectParser) buildContext() string {
+	platform := pp.platform
+	if platform == nil {
+		platform = platforms.Host()
+	}
+	return fmt.Sprintf("go%v %v", pp.goVersion, platform)
+}
+
+// currentManifest computes a manifest of the current package files
+func (pp *ProjectParser) currentManifest(pkg string) (*manifest.Manifest, error) {
+	files, dir, err := util.GetPackageFiles(pp.packagePath, pkg, pp.resolver, pp.platform)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Compute(pkg, dir, files, pp.buildContext())
+}
+
+// upToDate checks the stored artefacts of a package were extracted from the current package files
+// (by the current extractor version for the same build context). Stale artefacts are dropped
+// so the package is processed again. Artefacts with no manifest (e.g. of older extractor versions)
+// and packages with no files available are not checked.
+func (pp *ProjectParser) upToDate(pkg string) bool {
+	pp.mutex.Lock()
+	_, checked := pp.upToDatePackages[pkg]
+	pp.mutex.Unlock()
+	if checked {
+		return true
+	}
+
+	// the package files are listed and hashed without the lock held
+	diffs := pp.manifestDiffs(pkg)
+
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	// checked by another worker in the meantime
+	if _, ok := pp.upToDatePackages[pkg]; ok {
+		return true
+	}
+	pp.upToDatePackages[pkg] = struct{}{}
+	if len(diffs) == 0 {
+		return true
+	}
+
+	fmt.Fprintf(os.Stderr, "Package %q changed since extracted (%v), extracting again\n", pkg, strings.Join(diffs, ", "))
+	if pp.incremental {
+		pp.keepPreviousAPI(pkg)
+	}
+	pp.dropArtefacts(pkg)
+	return false
+}
+
+// manifestDiffs compares the stored manifest of a package with the current package files.
+// No differences are reported if the artefacts can not be checked.
+func (pp *ProjectParser) manifestDiffs(pkg string) []string {
+	if !pp.manifests.Exists(pkg) {
+		return nil
+	}
+	stored, err := pp.manifests.Load(pkg)
+	if err != nil {
+		klog.Warningf("Unable to check %q artefacts are up to date: %v", pkg, err)
+		return nil
+	}
+	current, err := pp.currentManifest(pkg)
+	if err != nil {
+		klog.V(1).Infof("Unable to check %q artefacts are up to date: %v", pkg, err)
+		return nil
+	}
+	return stored.Diff(current)
+}
+
+// dropArtefacts drops the stored artefacts of the package so the package is processed again
+func (pp *ProjectParser) dropArtefacts(pkg string) {
+	if err := pp.manifests.DropArtefacts(pkg); err != nil {
+		klog.Warningf("Unable to drop stale %q artefacts: %v", pkg, err)
+	}
+	pp.globalSymbolTable.Drop(pkg)
+	pp.globalAllocSymbolTable.Drop(pkg)
+	pp.globalContractsTable.Drop(pkg)
+	pp.globalTestAllocSymbolTable.Drop(pkg)
+	pp.globalTestContractsTable.Drop(pkg)
+}
+
+// Verify checks stored artefacts of all packages (with a manifest) were extracted from the current package files.
+// The fn is called for each manifest with its location and differences (none if the artefacts are up to date).
+func (pp *ProjectParser) Verify(fn func(location string, diffs []string)) error {
+	return pp.manifests.Walk(func(location string, stored *manifest.Manifest) error {
+		current, err := pp.currentManifest(stored.Package)
+		if err != nil {
+			fn(location, []string{fmt.Sprintf("package files not found: %v", err)})
+			return nil
+		}
+		fn(location, stored.Diff(current))
+		return nil
+	})
+}
+
+// levelOf returns the extraction level of a package.
+// Only the API of the extracted standard library is needed, the allocated symbols
+// and the contracts (which can grow up to tens of MB) are not important.
+func (pp *ProjectParser) levelOf(pkg string) Level {
+	if pp.globalSymbolTable.Stdlib(pkg) {
+		return APILevel
+	}
+	return pp.level
+}
+
 func (pp *ProjectParser) packageProcessed(pkg string) bool {
-	// TODO(jchaloup):
-	// We should process only the API by default for the stdlib.
-	// The allocated and the contracts are not important.
-	// Plus, the contracts can grow up to tens of MB so they should be
-	// optional as well and rather extracted only when needed.
+	if !pp.upToDate(pkg) {
+		return false
+	}
+
+	level := pp.levelOf(pkg)
 
 	// API available?
 	if !pp.globalSymbolTable.Exists(pkg) {
@@ -503,12 +687,26 @@ func (pp *ProjectParser) packageProcessed(pkg string) bool {
 	}
 
 	// Static allocations available?
-	if !pp.globalAllocSymbolTable.Exists(pkg) {
+	if level >= AllocatedLevel && !pp.globalAllocSymbolTable.Exists(pkg) {
 		return false
 	}
 
 	// Contracts available
-	if !pp.globalContractsTable.Exists(pkg) {
+	if level >= FullLevel && !pp.globalContractsTable.Exists(pkg) {
+		return false
+	}
+
+	return true
+}
+
+func (pp *ProjectParser) testsProcessed(pkg string) bool {
+	level := pp.levelOf(pkg)
+
+	if level >= AllocatedLevel && !pp.globalTestAllocSymbolTable.Exists(pkg) {
+		return false
+	}
+
+	if level >= FullLevel && !pp.globalTestContractsTable.Exists(pkg) {
 		return false
 	}
 
@@ -525,15 +723,39 @@ func (pp *ProjectParser) Parse(packagePath string, allocated bool) error {
 			return err
 		}
 	}
+	// then packages imported by builtin (e.g. cmp for constraints of min and max)
+	if err := pp.processBuiltinImports(); err != nil {
+		return err
+	}
 
 	// check if the requested package is already provided
-	if pp.packageProcessed(pp.packagePath) {
+	// (in the incremental mode, the package is checked for changed symbols of its imported packages)
+	if !pp.incremental && pp.packageProcessed(pp.packagePath) {
 		klog.V(1).Infof("Package %q already processed\n", pp.packagePath)
+	} else {
+		// process the requested package
+		if err := pp.processPackage(pp.packagePath); err != nil {
+			return err
+		}
+	}
+
+	if !pp.tests {
 		return nil
 	}
 
-	// process the requested package
-	return pp.processPackage(pp.packagePath)
+	// dynamically allocated symbols are not stored so the tests need to be processed again
+	if !pp.allocated && pp.testsProcessed(pp.packagePath) {
+		affected, err := pp.testsAffected(pp.packagePath)
+		if err != nil {
+			return err
+		}
+		if !affected {
+			klog.V(1).Infof("Tests of package %q already processed\n", pp.packagePath)
+			return nil
+		}
+	}
+
+	return pp.processTests(pp.packagePath)
 }
 
 func (pp *ProjectParser) processPackage(packagePath string) error {
@@ -542,31 +764,107 @@ func (pp *ProjectParser) processPackage(packagePath string) error {
 	if err != nil {
 		return err
 	}
+	return pp.process(c)
+}
+
+// processTests processes the package extended with its _test.go files
+// and the external _test package. Symbol tables of both are available
+// only while the test files are processed (they are never stored).
+func (pp *ProjectParser) processTests(packagePath string) error {
+	contexts, err := pp.createTestPackageContexts(packagePath)
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		klog.V(1).Infof("Package %q has no test files\n", packagePath)
+		return nil
+	}
+
+	// the package symbol table gets replaced with the one extended with the test files
+	table, err := pp.globalSymbolTable.Lookup(packagePath)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range contexts {
+		if err := pp.process(c); err != nil {
+			return err
+		}
+	}
+
+	// Store allocated symbols and contracts of all test files under the tested package
+	for _, c := range contexts {
+		for _, fc := range c.Files {
+			if _, ok := c.TestFiles[fc.Filename]; ok {
+				pp.globalTestAllocSymbolTable.Add(packagePath, fc.Filename, fc.AllocatedSymbolsTable)
+			}
+		}
+	}
+	contractTable := contexts[0].Files[0].ContractTable
+	for _, fc := range contexts[0].Files {
+		if _, ok := contexts[0].TestFiles[fc.Filename]; ok {
+			contractTable = fc.ContractTable
+			break
+		}
+	}
+	pp.globalTestContractsTable.Add(packagePath, contractTable)
+
+	if pp.levelOf(packagePath) >= AllocatedLevel {
+		if err := pp.globalTestAllocSymbolTable.Save(packagePath); err != nil {
+			return err
+		}
+	}
+
+	if pp.allocated {
+		// Evaluate contracts to collect remaining allocated symbols of the test files (not stored)
+		r := runner.New(packagePath, pp.globalSymbolTable, pp.globalTestAllocSymbolTable, contractTable)
+		if err := pp.evaluateContracts(r, true); err != nil {
+			return fmt.Errorf("Unable to evaluate contracts of %v test files: %v", packagePath, err)
+		}
+	}
+
+	for _, c := range contexts {
+		pp.globalSymbolTable.Drop(c.PackagePath)
+	}
+	// the package symbol table is already stored
+	pp.globalSymbolTable.AddTransient(packagePath, table)
+
+	if pp.levelOf(packagePath) < FullLevel {
+		return nil
+	}
+	return pp.globalTestContractsTable.Save(packagePath)
+}
+
+// processPackageContext processes the package. Imported packages are expected to be processed already
+// (see process), any imported package not processed yet is processed first.
+func (pp *ProjectParser) processPackageContext(c *PackageContext) error {
 	// Push the input package into the package stack
-	pp.packageStack = append(pp.packageStack, c)
+	packageStack := []*PackageContext{c}
 
 PACKAGE_STACK:
-	for len(pp.packageStack) > 0 {
+	for len(packageStack) > 0 {
 		// Process the package stack
-		p := pp.packageStack[0]
+		p := packageStack[0]
 		if p.PackageDir == "" {
 			// Pop the package from the package stack
-			pp.packageStack = pp.packageStack[1:]
+			packageStack = packageStack[1:]
 			continue
 		}
 
 		klog.V(2).Infof("\n\n\nPS processing %#v\n", p.PackageDir)
-		if pp.packageProcessed(p.PackagePath) {
+		if p.TestedPackage == "" && pp.packageProcessed(p.PackagePath) {
 			klog.V(2).Infof("\n\n\nPS %#v already processed\n", p.PackageDir)
 			// Pop the package from the package stack
-			pp.packageStack = pp.packageStack[1:]
+			packageStack = packageStack[1:]
 			continue
 		}
 		// a package may be processed again in case at least one of
-		// api.json, allocated.json or contracts.json is missing
-		pp.globalSymbolTable.Drop(p.PackagePath)
-		pp.globalAllocSymbolTable.Drop(p.PackagePath)
-		pp.globalContractsTable.Drop(p.PackagePath)
+		// api.json, allocated.json or contracts.json (required by the level) is missing
+		if p.TestedPackage == "" {
+			pp.globalSymbolTable.Drop(p.PackagePath)
+			pp.globalAllocSymbolTable.Drop(p.PackagePath)
+			pp.globalContractsTable.Drop(p.PackagePath)
+		}
 
 		// Process the files
 		fLen := len(p.Files)
@@ -574,7 +872,7 @@ PACKAGE_STACK:
 			fileContext := p.Files[i]
 			klog.V(2).Infof("File %q processing...", path.Join(p.PackageDir, fileContext.Filename))
 			if fileContext.FileAST == nil {
-				f, err := parser.ParseFile(token.NewFileSet(), path.Join(p.PackageDir, fileContext.Filename), nil, 0)
+				f, err := parser.ParseFile(p.Config.FileSet, path.Join(p.PackageDir, fileContext.Filename), nil, 0)
 				if err != nil {
 					return err
 				}
@@ -589,8 +887,8 @@ PACKAGE_STACK:
 					}
 				}
 			}
-			// processed imported packages
-			if !fileContext.ImportsProcessed {
+			// processed imported packages (see buildImportGraph for imports of builtin)
+			if !fileContext.ImportsProcessed && p.PackagePath != "builtin" {
 				missingImports := pp.processImports(path.Join(p.PackagePath, fileContext.Filename), fileContext.FileAST.Imports)
 				klog.V(2).Infof("Unknown imports:\t\t%#v\n\n", missingImports)
 				if len(missingImports) > 0 {
@@ -600,7 +898,7 @@ PACKAGE_STACK:
 							return err
 						}
 
-						pp.packageStack = append([]*PackageContext{c}, pp.packageStack...)
+						packageStack = append([]*PackageContext{c}, packageStack...)
 					}
 					// At least one imported package is not yet processed
 					klog.V(2).Infof("----Postponing %v\n\n", p.PackageDir)
@@ -619,6 +917,7 @@ PACKAGE_STACK:
 				return fmt.Errorf("Unable to create a payload: %v", err)
 			}
 			p.Config.AllocatedSymbolsTable = fileContext.AllocatedSymbolsTable
+			p.Config.ContractTable = fileContext.ContractTable
 			p.Config.FileName = fileContext.Filename
 			if err := fileparser.NewParser(p.Config).Parse(payload); err != nil {
 				return err
@@ -689,10 +988,26 @@ PACKAGE_STACK:
 		}
 		table.PackageQID = p.PackageQID
 
+		if p.TestedPackage != "" {
+			// Make the package symbol table available to the remaining test files
+			// (e.g. the external _test package importing symbols of export_test.go)
+			// until all test files are processed
+			pp.globalSymbolTable.AddTransient(p.PackagePath, table)
+			// Pop the package from the package stack
+			packageStack = packageStack[1:]
+			continue
+		}
+
 		if err := pp.globalSymbolTable.Add(p.PackagePath, table, true); err != nil {
 			panic(err)
 		}
 
+		if pp.incremental {
+			if err := pp.recordChanges(p.PackagePath, table); err != nil {
+				return err
+			}
+		}
+
 		// Store the allocated symbols
 		for i := 0; i < fLen; i++ {
 			fc := p.Files[i]
@@ -700,32 +1015,92 @@ PACKAGE_STACK:
 			pp.globalContractsTable.Add(p.PackagePath, p.Config.ContractTable)
 		}
 
-		if err := pp.globalAllocSymbolTable.Save(p.PackagePath); err != nil {
-			panic(err)
+		level := pp.levelOf(p.PackagePath)
+		if level >= AllocatedLevel {
+			if err := pp.globalAllocSymbolTable.Save(p.PackagePath); err != nil {
+				panic(err)
+			}
 		}
 
 		if pp.allocated {
 			// Evaluate contracts to collect remaining allocated symbols (so called dynamicly allocated symbols).
 			// The symbols are not stored as they depend on a specific dependency package commit
 			r := runner.New(p.Config.PackageName, pp.globalSymbolTable, pp.globalAllocSymbolTable, p.Config.ContractTable)
-			if err := r.Run(); err != nil {
+			if err := pp.evaluateContracts(r, false); err != nil {
+				return fmt.Errorf("Unable to evaluate contracts of %v: %v", p.PackagePath, err)
+			}
+		}
+
+		if level >= FullLevel {
+			if err := pp.globalContractsTable.Save(p.PackagePath); err != nil {
 				panic(err)
 			}
 		}
 
-		if err := pp.globalContractsTable.Save(p.PackagePath); err != nil {
-			panic(err)
+		// Record the package files the artefacts are extracted from.
+		// The manifest is saved last so an interrupted extraction leaves no manifest
+		// of incomplete artefacts behind (the package is processed again).
+		var files []string
+		for _, fc := range p.Files {
+			files = append(files, fc.Filename)
 		}
+		m, err := manifest.Compute(p.PackagePath, p.PackageDir, files, pp.buildContext())
+		if err != nil {
+			return err
+		}
+		if err := pp.manifests.Save(m); err != nil {
+			return err
+		}
+		pp.mutex.Lock()
+		pp.upToDatePackages[p.PackagePath] = struct{}{}
+		pp.mutex.Unlock()
 
 		// Pop the package from the package stack
-		pp.packageStack = pp.packageStack[1:]
+		packageStack = packageStack[1:]
 	}
 
-	if pp.symbolTableDirectory != "" {
-		return pp.globalSymbolTable.Save(pp.symbolTableDirectory)
+	return nil
+}
+
+// processBuiltinImports processes packages imported by builtin. Every package depends on builtin
+// so the packages can not be processed before builtin as other imported packages are.
+func (pp *ProjectParser) processBuiltinImports() error {
+	st, err := pp.globalSymbolTable.Lookup("builtin")
+	if err != nil {
+		return err
+	}
+	table, ok := st.(*tables.Table)
+	if !ok {
+		return nil
+	}
+	for _, pkg := range table.Imports {
+		if pp.packageProcessed(pkg) {
+			continue
+		}
+		if err := pp.processPackage(pkg); err != nil {
+			return err
+		}
 	}
 	return nil
+}
 
+// evaluateContracts evaluates contracts of the package (or of its test files).
+// Contracts that can not be evaluated are reported in the diagnostics, the package is processed anyway.
+func (pp *ProjectParser) evaluateContracts(r *runner.Runner, tests bool) error {
+	err := r.Run()
+	diagnostics, ok := err.(*runner.Diagnostics)
+	if !ok {
+		return err
+	}
+	diagnostics.Tests = tests
+	pp.diagnostics.Add(diagnostics)
+	klog.Warningf("Package %q has %v unresolved contracts", diagnostics.Package, len(diagnostics.Unresolved))
+	return nil
+}
+
+// Diagnostics reports contracts that could not be evaluated (with allocated set)
+func (pp *ProjectParser) Diagnostics() *runner.Report {
+	return pp.diagnostics
 }
 
 func (pp *ProjectParser) GlobalSymbolTable() *global.Table {
@@ -740,6 +1115,14 @@ func (pp *ProjectParser) GlobalContractsTable() *contractglobal.Table {
 	return pp.globalContractsTable
 }
 
+func (pp *ProjectParser) GlobalTestAllocTable() *allocglobal.Table {
+	return pp.globalTestAllocSymbolTable
+}
+
+func (pp *ProjectParser) GlobalTestContractsTable() *contractglobal.Table {
+	return pp.globalTestContractsTable
+}
+
 func printDataType(dataType gotypes.DataType) {
 	byteSlice, _ := json.Marshal(dataType)
 	fmt.Printf("\n%v\n", string(byteSlice))
@@ -2,17 +2,39 @@ package parser
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
-	symboltable "github.com/gofed/symbols-extractor/pkg/symbols/tables"
+	"github.com/gofed/symbols-extractor/pkg/symbols"
 	"github.com/gofed/symbols-extractor/pkg/types"
 )
 
+// newParser creates a parser locating the dag testdata packages
+func newParser(t *testing.T) *ProjectParser {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	pp, err := New(t.TempDir(), "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pp.SetResolver(testdataResolver{
+		"example.com/dag/a": "testdata/dag/a",
+		"example.com/dag/b": "testdata/dag/b",
+		"example.com/dag/c": "testdata/dag/c",
+		"example.com/dag/d": "testdata/dag/d",
+	})
+}
+
 func TestProjectParser(t *testing.T) {
-	pp := New("github.com/gofed/symbols-extractor/pkg/parser/testdata/unordered", "")
-	if err := pp.Parse(); err != nil {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	pp, err := New(t.TempDir(), "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pp.Parse("github.com/gofed/symbols-extractor/pkg/parser/testdata/unordered", true); err != nil {
 		t.Errorf("Parse error: %v", err)
 	}
 	gtable := pp.GlobalSymbolTable()
@@ -36,13 +58,48 @@ func TestProjectParser(t *testing.T) {
 		}
 	}
 
+	checkSymbols := func(pkg string, datatypes, functions map[string]types.DataType) {
+		st, err := gtable.Lookup(pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for kind, expected := range map[string]map[string]types.DataType{"data type": datatypes, "function": functions} {
+			for name, def := range expected {
+				var sym *symbols.SymbolDef
+				var err error
+				if kind == "data type" {
+					sym, err = st.LookupDataType(name)
+				} else {
+					sym, err = st.LookupFunction(name)
+				}
+				if err != nil {
+					t.Errorf("Symbol table %q: %v", pkg, err)
+					continue
+				}
+				if !reflect.DeepEqual(sym.Def, def) {
+					x, _ := json.Marshal(sym.Def)
+					y, _ := json.Marshal(def)
+					t.Errorf("Symbol table %q: %v %v mismatch.\nGot:\n%v\nExpected:\n%v", pkg, kind, name, string(x), string(y))
+				}
+			}
+		}
+	}
+
+	checkAllocated := func(pkg, file string, expected []string) {
+		at, err := atable.Lookup(pkg, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := dumpAllocated(at); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Alloc symbol table %q mismatch.\nGot:\n%v\nExpected:\n%v", pkg, strings.Join(actual, "\n"), strings.Join(expected, "\n"))
+		}
+	}
+
 	// Check unordered ST
-	{
-		st := symboltable.NewTable()
-		st.AddDataType(&types.SymbolDef{
-			Name:    "Struct",
-			Package: expectedPackages["unordered"],
-			Def: &types.Struct{
+	checkSymbols(
+		expectedPackages["unordered"],
+		map[string]types.DataType{
+			"Struct": &types.Struct{
 				Fields: []types.StructFieldsItem{
 					{
 						Name: "i",
@@ -62,6 +119,7 @@ func TestProjectParser(t *testing.T) {
 								},
 							},
 						},
+						Order: 1,
 					},
 					{
 						Name: "impb",
@@ -74,23 +132,19 @@ func TestProjectParser(t *testing.T) {
 								},
 							},
 						},
+						Order: 2,
 					},
 				},
 			},
-		})
-		st.AddDataType(&types.SymbolDef{
-			Name:    "MyInt",
-			Package: expectedPackages["unordered"],
-			Def: &types.Identifier{
+			"MyInt": &types.Identifier{
 				Def:     "int",
 				Package: "builtin",
 			},
-		})
-		st.AddFunction(&types.SymbolDef{
-			Name:    "Nic",
-			Package: expectedPackages["unordered"],
-			Def: &types.Function{
-				Params: nil,
+		},
+		map[string]types.DataType{
+			"Nic": &types.Function{
+				Package: expectedPackages["unordered"],
+				Params:  nil,
 				// *pkg.Imp
 				Results: []types.DataType{
 					&types.Pointer{
@@ -104,63 +158,43 @@ func TestProjectParser(t *testing.T) {
 					},
 				},
 			},
-		})
-
-		pst, _ := gtable.Lookup(expectedPackages["unordered"])
-		if !reflect.DeepEqual(st, pst) {
-			x, _ := json.Marshal(pst)
-			y, _ := json.Marshal(st)
-			t.Errorf("Symbol table %q mismatch. \nGot:\n%v\nExpected:\n%v", expectedPackages["unordered"], string(x), string(y))
-		}
-
-		at := alloctable.New()
-		// type Struct struct {
-		// 	i MyInt
-		// 	impa *pkg.Imp
-		// 	impb *pkgb.Imp
-		// }
-		at.AddSymbol(expectedPackages["unordered"], "MyInt")
-		at.AddSymbol(expectedPackages["pkg"], "Imp")
-		at.AddSymbol(expectedPackages["pkgb"], "Imp")
-		// type MyInt int
-		at.AddSymbol("builtin", "int")
-		// func Nic() *pkg.Imp {
-		// 	pkg.Nic().Imp.Size
-		// 	return &pkg.Imp{
-		// 		Name: "haluz",
-		// 		Size: 2,
-		// 	}
-		// }
-		at.AddSymbol(expectedPackages["pkg"], "Imp")
-		at.AddSymbol(expectedPackages["pkg"], "Nic")
-		// accounted as it is returned by pkg.Nic() and its field Imp is accessed
-		at.AddSymbol(expectedPackages["pkg"], "Imp")
-		// temporary bump by one once the allocated symbol is extended with file.go:linenumber
-		at.AddSymbol(expectedPackages["pkg"], "Imp")
-		// Accessing field Imp of type Imp does not imply allocation of the type Imp itself,
-		// Return type of the Nic() can change but it the field Imp is still available,
-		// The change is backward compatible. Thus, the type Imp must not be allocated.
-		at.AddDataTypeField(expectedPackages["pkg"], "Imp", "Imp")
-		at.AddDataTypeField(expectedPackages["pkgb"], "Imp", "Size")
-		at.AddSymbol(expectedPackages["pkg"], "Imp")
-		at.AddDataTypeField(expectedPackages["pkg"], "Imp", "Name")
-		at.AddDataTypeField(expectedPackages["pkg"], "Imp", "Size")
-
-		pat, _ := atable.Lookup(expectedPackages["unordered"], "unordered.go")
-		if !reflect.DeepEqual(at, pat) {
-			x, _ := json.Marshal(pat)
-			y, _ := json.Marshal(at)
-			t.Errorf("Alloc symbol table %q mismatch.\nGot:\n%v\nExpected:\n%v", expectedPackages["pkg"], string(x), string(y))
-		}
-	}
+		},
+	)
+	// type Struct struct {
+	// 	i MyInt
+	// 	impa *pkg.Imp
+	// 	impb *pkgb.Imp
+	// }
+	// type MyInt int
+	// func Nic() *pkg.Imp {
+	// 	pkg.Nic().Imp.Size
+	// 	return &pkg.Imp{
+	// 		Name: "haluz",
+	// 		Size: 2,
+	// 	}
+	// }
+	// Accessing field Imp of type Imp does not imply allocation of the type Imp itself,
+	// Return type of the Nic() can change but it the field Imp is still available,
+	// The change is backward compatible. Thus, the type Imp must not be allocated.
+	checkAllocated(expectedPackages["unordered"], "unordered.go", []string{
+		"datatype builtin.int unordered.go:15:12",
+		"datatype " + expectedPackages["unordered"] + ".MyInt unordered.go:9:4",
+		"datatype " + expectedPackages["pkg"] + ".Imp unordered.go:11:8",
+		"datatype " + expectedPackages["pkg"] + ".Imp unordered.go:17:13",
+		"datatype " + expectedPackages["pkg"] + ".Imp unordered.go:19:10",
+		"datatype " + expectedPackages["pkgb"] + ".Imp unordered.go:12:8",
+		"function " + expectedPackages["pkg"] + ".Nic unordered.go:18:2",
+		"structfield " + expectedPackages["pkg"] + ".Imp.Imp unordered.go:18:12",
+		"structfield " + expectedPackages["pkg"] + ".Imp.Name unordered.go:20:3",
+		"structfield " + expectedPackages["pkg"] + ".Imp.Size unordered.go:21:3",
+		"structfield " + expectedPackages["pkgb"] + ".Imp.Size unordered.go:18:16",
+	})
 
 	// Check unordered/pkg ST
-	{
-		st := symboltable.NewTable()
-		st.AddDataType(&types.SymbolDef{
-			Name:    "Imp",
-			Package: expectedPackages["pkg"],
-			Def: &types.Struct{
+	checkSymbols(
+		expectedPackages["pkg"],
+		map[string]types.DataType{
+			"Imp": &types.Struct{
 				Fields: []types.StructFieldsItem{
 					{
 						Name: "Name",
@@ -175,6 +209,7 @@ func TestProjectParser(t *testing.T) {
 							Def:     "int",
 							Package: "builtin",
 						},
+						Order: 1,
 					},
 					{
 						Name: "Imp",
@@ -187,15 +222,15 @@ func TestProjectParser(t *testing.T) {
 								},
 							},
 						},
+						Order: 2,
 					},
 				},
 			},
-		})
-		st.AddFunction(&types.SymbolDef{
-			Name:    "Nic",
-			Package: expectedPackages["pkg"],
-			Def: &types.Function{
-				Params: nil,
+		},
+		map[string]types.DataType{
+			"Nic": &types.Function{
+				Package: expectedPackages["pkg"],
+				Params:  nil,
 				Results: []types.DataType{
 					&types.Pointer{
 						Def: &types.Identifier{
@@ -205,37 +240,21 @@ func TestProjectParser(t *testing.T) {
 					},
 				},
 			},
-		})
-		pst, _ := gtable.Lookup(expectedPackages["pkg"])
-		if !reflect.DeepEqual(st, pst) {
-			x, _ := json.Marshal(pst)
-			y, _ := json.Marshal(st)
-			t.Errorf("Symbol table %q mismatch. \nGot:\n%v\nExpected:\n%v", expectedPackages["pkg"], string(x), string(y))
-		}
-		at := alloctable.New()
-		at.AddSymbol("builtin", "string")
-		at.AddSymbol("builtin", "int")
-		at.AddSymbol("github.com/gofed/symbols-extractor/pkg/parser/testdata/unordered/pkgb", "Imp")
-		at.AddSymbol("github.com/gofed/symbols-extractor/pkg/parser/testdata/unordered/pkg", "Imp")
-		at.AddSymbol("github.com/gofed/symbols-extractor/pkg/parser/testdata/unordered/pkg", "Imp")
-		// temporary bump by one once the allocated symbol is extended with file.go:linenumber
-		at.AddSymbol("github.com/gofed/symbols-extractor/pkg/parser/testdata/unordered/pkg", "Imp")
-
-		pat, _ := atable.Lookup(expectedPackages["pkg"], "pkg.go")
-		if !reflect.DeepEqual(at, pat) {
-			x, _ := json.Marshal(pat)
-			y, _ := json.Marshal(at)
-			t.Errorf("Alloc symbol table %q mismatch.\nGot:\n%v\nExpected:\n%v", expectedPackages["pkg"], string(x), string(y))
-		}
-	}
+		},
+	)
+	checkAllocated(expectedPackages["pkg"], "pkg.go", []string{
+		"datatype builtin.int pkg.go:7:7",
+		"datatype builtin.string pkg.go:6:7",
+		"datatype " + expectedPackages["pkg"] + ".Imp pkg.go:11:13",
+		"datatype " + expectedPackages["pkg"] + ".Imp pkg.go:12:10",
+		"datatype " + expectedPackages["pkgb"] + ".Imp pkg.go:8:8",
+	})
 
 	// Check unordered/pkgb ST
-	{
-		st := symboltable.NewTable()
-		st.AddDataType(&types.SymbolDef{
-			Name:    "Imp",
-			Package: expectedPackages["pkgb"],
-			Def: &types.Struct{
+	checkSymbols(
+		expectedPackages["pkgb"],
+		map[string]types.DataType{
+			"Imp": &types.Struct{
 				Fields: []types.StructFieldsItem{
 					{
 						Name: "Name",
@@ -250,25 +269,56 @@ func TestProjectParser(t *testing.T) {
 							Def:     "int",
 							Package: "builtin",
 						},
+						Order: 1,
 					},
 				},
 			},
-		})
-		pst, _ := gtable.Lookup(expectedPackages["pkgb"])
-		if !reflect.DeepEqual(st, pst) {
-			x, _ := json.Marshal(pst)
-			y, _ := json.Marshal(st)
-			t.Errorf("Symbol table %q mismatch.\nGot:\n%v\nExpected:\n%v", expectedPackages["pkgb"], string(x), string(y))
+		},
+		nil,
+	)
+	checkAllocated(expectedPackages["pkgb"], "pkg.go", []string{
+		"datatype builtin.int pkg.go:5:7",
+		"datatype builtin.string pkg.go:4:7",
+	})
+}
+
+// TestParseConcurrently processes packages of a DAG (d imports b and c, both import a)
+// concurrently (run with -race). The global tables are expected to be the same as when
+// the packages are processed one by one.
+func TestParseConcurrently(t *testing.T) {
+	pkgs := []string{"example.com/dag/a", "example.com/dag/b", "example.com/dag/c", "example.com/dag/d"}
+	dump := func(pp *ProjectParser) []string {
+		var lines []string
+		for _, pkg := range pkgs {
+			table, err := pp.GlobalSymbolTable().Lookup(pkg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			def, err := json.Marshal(table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines = append(lines, fmt.Sprintf("%v: %s", pkg, def))
+			allocated, err := pp.GlobalAllocTable().MergeFiles(pkg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines = append(lines, dumpAllocated(allocated[alloctable.ProductionScope])...)
 		}
-		at := alloctable.New()
-		at.AddSymbol("builtin", "string")
-		at.AddSymbol("builtin", "int")
+		return lines
+	}
+	parse := func(pp *ProjectParser) *ProjectParser {
+		if err := pp.Parse("example.com/dag/d", true); err != nil {
+			t.Fatalf("Unable to parse: %v", err)
+		}
+		return pp
+	}
 
-		pat, _ := atable.Lookup(expectedPackages["pkgb"], "pkg.go")
-		if !reflect.DeepEqual(at, pat) {
-			x, _ := json.Marshal(pat)
-			y, _ := json.Marshal(at)
-			t.Errorf("Alloc symbol table %q mismatch.\nGot:\n%v\nExpected:\n%v", expectedPackages["pkgb"], string(x), string(y))
+	expected := dump(parse(newParser(t)))
+	for i := 0; i < 5; i++ {
+		actual := dump(parse(newParser(t).SetWorkers(4)))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Run %v: expected:\n%v\ngot:\n%v", i, strings.Join(expected, "\n"), strings.Join(actual, "\n"))
 		}
 	}
 }
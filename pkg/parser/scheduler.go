@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofed/symbols-extractor/pkg/symbols"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+	"k8s.io/klog/v2"
+)
+
+// importGraph captures packages to be processed and their imported packages
+// (only those to be processed as well)
+type importGraph struct {
+	// packages in the order they were discovered (the root package goes first)
+	packages []string
+	contexts map[string]*PackageContext
+	imports  map[string][]string
+	// processed packages to be checked for changed symbols they use (see SetIncremental)
+	checked map[string]struct{}
+}
+
+// packageImports lists unique packages imported by the package files
+func packageImports(c *PackageContext) ([]string, error) {
+	paths := make(map[string]struct{})
+	fset := token.NewFileSet()
+	for _, fc := range c.Files {
+		f, err := parser.ParseFile(fset, path.Join(c.PackageDir, fc.Filename), nil, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range f.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid import %v in %v: %v", spec.Path.Value, path.Join(c.PackageDir, fc.Filename), err)
+			}
+			paths[importPath] = struct{}{}
+		}
+	}
+
+	var imports []string
+	for importPath := range paths {
+		imports = append(imports, importPath)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// buildImportGraph collects the root package and all packages it (transitively) imports
+// that are not processed yet. Only imports of each package file are parsed.
+// In the incremental mode, processed packages importing changed packages are collected as well
+// to be checked for the changed symbols they use.
+func (pp *ProjectParser) buildImportGraph(root *PackageContext) (*importGraph, error) {
+	g := &importGraph{
+		contexts: make(map[string]*PackageContext),
+		imports:  make(map[string][]string),
+		checked:  make(map[string]struct{}),
+	}
+	// packages not to be processed
+	done := make(map[string]struct{})
+
+	var include func(importPath string, c *PackageContext) (bool, error)
+
+	// add adds the package into the graph along with its imported packages to be processed
+	add := func(c *PackageContext) error {
+		g.contexts[c.PackagePath] = c
+		g.packages = append(g.packages, c.PackagePath)
+
+		// every package depends on builtin, packages imported by builtin (cmp) are processed afterwards
+		if c.PackagePath == "builtin" {
+			return nil
+		}
+		imports, err := packageImports(c)
+		if err != nil {
+			return err
+		}
+		for _, importPath := range imports {
+			// 'C' is a pseudo-package
+			if importPath == "C" {
+				continue
+			}
+			ok, err := include(importPath, nil)
+			if err != nil {
+				return err
+			}
+			if ok {
+				g.imports[c.PackagePath] = append(g.imports[c.PackagePath], importPath)
+			}
+		}
+		return nil
+	}
+
+	// include adds the package into the graph unless it is processed already.
+	// Returns true if the package is to be processed.
+	include = func(importPath string, c *PackageContext) (bool, error) {
+		if _, ok := g.contexts[importPath]; ok {
+			return true, nil
+		}
+		if _, ok := done[importPath]; ok {
+			return false, nil
+		}
+		if pp.upToDate(importPath) {
+			if table, err := pp.globalSymbolTable.Lookup(importPath); err == nil {
+				done[importPath] = struct{}{}
+				if !pp.incremental || pp.globalSymbolTable.Stdlib(importPath) {
+					return false, nil
+				}
+				return pp.includeDependent(g, importPath, c, table, include)
+			}
+		}
+		if c == nil {
+			var err error
+			if c, err = pp.createPackageContext(importPath); err != nil {
+				return false, err
+			}
+		}
+		return true, add(c)
+	}
+
+	var err error
+	if pp.incremental && root.TestedPackage == "" && pp.packageProcessed(root.PackagePath) {
+		_, err = include(root.PackagePath, root)
+	} else {
+		err = add(root)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(1).Infof("Import graph of %q: %v packages to process", root.PackagePath, len(g.packages))
+	return g, nil
+}
+
+// includeDependent adds the processed package into the graph (to be checked) if any of its imported packages
+// is to be processed or has changed symbols. Imported packages are listed in the stored symbol table.
+func (pp *ProjectParser) includeDependent(g *importGraph, pkg string, c *PackageContext, table symbols.SymbolTable, include func(string, *PackageContext) (bool, error)) (bool, error) {
+	if pp.recorded(pkg) {
+		return false, nil
+	}
+	stored, ok := table.(*tables.Table)
+	if !ok {
+		return false, nil
+	}
+
+	var imports []string
+	changed := false
+	for _, importPath := range stored.Imports {
+		if importPath == "C" {
+			continue
+		}
+		ok, err := include(importPath, nil)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			imports = append(imports, importPath)
+			continue
+		}
+		changed = changed || len(pp.changedSymbols(importPath)) > 0
+	}
+	if len(imports) == 0 && !changed {
+		// no imported package changed so neither the package did
+		pp.mutex.Lock()
+		pp.changes.Record(pkg)
+		pp.mutex.Unlock()
+		return false, nil
+	}
+
+	if c == nil {
+		var err error
+		if c, err = pp.createPackageContext(pkg); err != nil {
+			return false, err
+		}
+	}
+	g.contexts[pkg] = c
+	g.packages = append(g.packages, pkg)
+	g.imports[pkg] = imports
+	g.checked[pkg] = struct{}{}
+	return true, nil
+}
+
+// processGraph processes all packages of the import graph. Each package is processed
+// once all its imported packages are processed, up to pp.workers packages at once.
+// No new package is processed after the first error.
+func (pp *ProjectParser) processGraph(g *importGraph) error {
+	pending := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, pkg := range g.packages {
+		pending[pkg] = len(g.imports[pkg])
+		for _, importPath := range g.imports[pkg] {
+			dependents[importPath] = append(dependents[importPath], pkg)
+		}
+	}
+
+	workers := pp.workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	type result struct {
+		pkg string
+		err error
+	}
+	// each package is scheduled at most once so no send blocks
+	jobs := make(chan *PackageContext, len(g.packages))
+	results := make(chan result, len(g.packages))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				if _, ok := g.checked[c.PackagePath]; ok {
+					results <- result{pkg: c.PackagePath, err: pp.processDependent(c)}
+					continue
+				}
+				results <- result{pkg: c.PackagePath, err: pp.processPackageContext(c)}
+			}
+		}()
+	}
+
+	running := 0
+	// the most distant imported packages go first
+	for i := len(g.packages) - 1; i >= 0; i-- {
+		if pending[g.packages[i]] == 0 {
+			jobs <- g.contexts[g.packages[i]]
+			running++
+		}
+	}
+
+	processed := make(map[string]struct{})
+	var err error
+	for running > 0 {
+		r := <-results
+		running--
+		if r.err != nil {
+			if err == nil {
+				err = r.err
+			}
+			continue
+		}
+		processed[r.pkg] = struct{}{}
+		if err != nil {
+			continue
+		}
+		for _, pkg := range dependents[r.pkg] {
+			pending[pkg]--
+			if pending[pkg] == 0 {
+				jobs <- g.contexts[pkg]
+				running++
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	if len(processed) < len(g.packages) {
+		var cycle []string
+		for _, pkg := range g.packages {
+			if _, ok := processed[pkg]; !ok {
+				cycle = append(cycle, pkg)
+			}
+		}
+		return fmt.Errorf("Import cycle not allowed, unable to process %v", strings.Join(cycle, ", "))
+	}
+	return nil
+}
+
+// process processes the package and all its imported packages not processed yet.
+// The global symbol table is stored afterwards.
+func (pp *ProjectParser) process(c *PackageContext) error {
+	g, err := pp.buildImportGraph(c)
+	if err != nil {
+		return err
+	}
+	if err := pp.processGraph(g); err != nil {
+		return err
+	}
+	if pp.symbolTableDirectory != "" {
+		return pp.globalSymbolTable.Save(pp.symbolTableDirectory)
+	}
+	return nil
+}
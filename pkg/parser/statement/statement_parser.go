@@ -29,8 +29,91 @@ func New(config *types.Config) types.StatementParser {
 	}
 }
 
+// splitGenericReceiver splits a receiver of a generic data type (e.g. List[T] or *Map[K, V])
+// into the receiver without type parameters (e.g. List or *Map) and the type parameters.
+func splitGenericReceiver(receiver ast.Expr) (ast.Expr, []ast.Expr) {
+	if starExpr, ok := receiver.(*ast.StarExpr); ok {
+		x, indices := splitGenericReceiver(starExpr.X)
+		if indices == nil {
+			return receiver, nil
+		}
+		return &ast.StarExpr{Star: starExpr.Star, X: x}, indices
+	}
+	switch typedExpr := receiver.(type) {
+	case *ast.IndexExpr:
+		return typedExpr.X, []ast.Expr{typedExpr.Index}
+	case *ast.IndexListExpr:
+		return typedExpr.X, typedExpr.Indices
+	}
+	return receiver, nil
+}
+
+// parseReceiverTypeParams stores type parameters of a generic receiver
+// into the top most block of the symbol table. The receiver can rename
+// the type parameters of its data type, e.g. type List[T any] and func (l *List[E]) Len() int.
+// So the constraints are taken from the data type definition.
+func (ep *Parser) parseReceiverTypeParams(receiver ast.Expr) ([]gotypes.DataType, error) {
+	x, indices := splitGenericReceiver(receiver)
+	if indices == nil {
+		return nil, nil
+	}
+	if starExpr, ok := x.(*ast.StarExpr); ok {
+		x = starExpr.X
+	}
+	ident, ok := x.(*ast.Ident)
+	if !ok {
+		return nil, fmt.Errorf("Method receiver %#v is not a generic identifier", receiver)
+	}
+	def, err := ep.SymbolTable.LookupDataType(ident.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(def.Typeparams) != len(indices) {
+		return nil, fmt.Errorf("Method receiver %q expects %v type parameters, got %v", ident.Name, len(def.Typeparams), len(indices))
+	}
+
+	var typeParams []gotypes.DataType
+	for _, index := range indices {
+		name, ok := index.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("Method receiver type parameter %#v is not an identifier", index)
+		}
+		typeParam := &gotypes.Typeparam{Name: name.Name}
+		if name.Name != "_" {
+			if err := ep.SymbolTable.AddDataType(&symbols.SymbolDef{
+				Name:     name.Name,
+				Pos:      ep.Config.SymbolPos(name.Pos()),
+				Position: ep.Config.SymbolPosition(name),
+				Def:      typeParam,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		typeParams = append(typeParams, typeParam)
+	}
+
+	for i, item := range def.Typeparams {
+		if typeParam, ok := item.(*gotypes.Typeparam); ok && typeParam.Constraint != nil {
+			typeParams[i].(*gotypes.Typeparam).Constraint = gotypes.Substitute(typeParam.Constraint, def.Typeparams, typeParams)
+		}
+	}
+
+	return typeParams, nil
+}
+
+// parseFuncTypeParams stores type parameters of a function, resp. of a method's receiver
+// into the top most block of the symbol table.
+func (sp *Parser) parseFuncTypeParams(funcDecl *ast.FuncDecl) ([]gotypes.DataType, error) {
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) == 1 {
+		return sp.parseReceiverTypeParams(funcDecl.Recv.List[0].Type)
+	}
+	return sp.TypeParser.ParseTypeParams(funcDecl.Type.TypeParams)
+}
+
 func (ep *Parser) parseReceiver(receiver ast.Expr, skip_allocated bool) (gotypes.DataType, error) {
 	klog.V(2).Infof("Processing Receiver: %#v\n", receiver)
+	// Type parameters of a generic receiver are processed separately
+	receiver, _ = splitGenericReceiver(receiver)
 	// Receiver's type must be of the form T or *T (possibly using parentheses) where T is a type name.
 	switch typedExpr := receiver.(type) {
 	case *ast.Ident:
@@ -44,7 +127,7 @@ func (ep *Parser) parseReceiver(receiver ast.Expr, skip_allocated bool) (gotypes
 		}
 
 		if !skip_allocated {
-			ep.AllocatedSymbolsTable.AddDataType(def.Package, typedExpr.Name, ep.Config.SymbolPos(typedExpr.Pos()))
+			ep.AllocatedSymbolsTable.AddDataType(def.Package, typedExpr.Name, ep.Config.SymbolPosition(typedExpr))
 		}
 
 		return &gotypes.Identifier{
@@ -64,7 +147,7 @@ func (ep *Parser) parseReceiver(receiver ast.Expr, skip_allocated bool) (gotypes
 			}
 
 			if !skip_allocated {
-				ep.AllocatedSymbolsTable.AddDataType(def.Package, idExpr.Name, ep.Config.SymbolPos(idExpr.Pos()))
+				ep.AllocatedSymbolsTable.AddDataType(def.Package, idExpr.Name, ep.Config.SymbolPosition(idExpr))
 			}
 
 			return &gotypes.Pointer{
@@ -90,7 +173,20 @@ func (sp *Parser) ParseFuncDecl(d *ast.FuncDecl) (gotypes.DataType, error) {
 	// parseFunction does not store name of params, resp. results
 	// as the names are not important. Just params, resp. results ordering is.
 	// Thus, this method is used to parse function's signature only.
+	var recvTypeParams []gotypes.DataType
+	if d.Recv != nil && len(d.Recv.List) == 1 {
+		sp.SymbolTable.Push()
+		params, err := sp.parseReceiverTypeParams(d.Recv.List[0].Type)
+		if err != nil {
+			sp.SymbolTable.Pop()
+			return nil, err
+		}
+		recvTypeParams = params
+	}
 	funcDef, err := sp.TypeParser.Parse(d.Type)
+	if d.Recv != nil && len(d.Recv.List) == 1 {
+		sp.SymbolTable.Pop()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -124,8 +220,9 @@ func (sp *Parser) ParseFuncDecl(d *ast.FuncDecl) (gotypes.DataType, error) {
 	}
 
 	methodDef := &gotypes.Method{
-		Def:      funcDef,
-		Receiver: recDef,
+		Def:        funcDef,
+		Receiver:   recDef,
+		Typeparams: recvTypeParams,
 	}
 
 	return methodDef, nil
@@ -142,7 +239,12 @@ func (sp *Parser) ParseFuncBody(funcDecl *ast.FuncDecl) error {
 	// construct a first level of a multi-level symbol table stack..
 	// For each new block (including the body) push another level into the stack.
 	sp.SymbolTable.Push()
-	if err := sp.parseFuncHeadVariables(funcDecl); err != nil {
+	typeParams, err := sp.parseFuncTypeParams(funcDecl)
+	if err != nil {
+		sp.SymbolTable.Pop()
+		return fmt.Errorf("sp.ParseFuncBody: %v", err)
+	}
+	if err := sp.parseFuncHeadVariables(funcDecl, typeParams); err != nil {
 		sp.SymbolTable.Pop()
 		return fmt.Errorf("sp.ParseFuncBody: %v", err)
 	}
@@ -211,10 +313,11 @@ func (sp *Parser) ParseConstValueSpec(constSpec types.ConstSpec) ([]*symbols.Sym
 			continue
 		}
 		sDef := &symbols.SymbolDef{
-			Name:    spec.Names[i].Name,
-			Package: sp.PackageName,
-			Def:     nil,
-			Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, spec.Names[i].Pos()),
+			Name:     spec.Names[i].Name,
+			Package:  sp.PackageName,
+			Def:      nil,
+			Pos:      sp.Config.SymbolPos(spec.Names[i].Pos()),
+			Position: sp.Config.SymbolPosition(spec.Names[i]),
 		}
 		if sp.SymbolTable.CurrentLevel() > 0 {
 			sDef.Package = ""
@@ -226,7 +329,7 @@ func (sp *Parser) ParseConstValueSpec(constSpec types.ConstSpec) ([]*symbols.Sym
 				X:            valueExprAttr.TypeVarList[0],
 				Y:            typevars.VariableFromSymbolDef(sDef),
 				ExpectedType: sDef.Def,
-				Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, spec.Names[i].Pos()),
+				Pos:          sp.Config.SymbolPos(spec.Names[i].Pos()),
 			})
 		} else {
 			// typeDef must be identifier
@@ -272,11 +375,16 @@ func (sp *Parser) ParseConstValueSpec(constSpec types.ConstSpec) ([]*symbols.Sym
 				}
 			}
 
-			sDef.Def = &gotypes.Constant{
+			typedConstant := &gotypes.Constant{
 				Def:     typeDefIdent.Def,
 				Package: typeDefIdent.Package,
 				Literal: valueExprAttr.DataTypeList[0].(*gotypes.Constant).Literal,
 			}
+			typedConstant.Value = propagation.New(sp.Config.SymbolsAccessor).ConvertValue(
+				valueExprAttr.DataTypeList[0].(*gotypes.Constant),
+				typedConstant,
+			)
+			sDef.Def = typedConstant
 
 			// TODO(jchaloup): most likely one should generate contracts.IsTypecastableTo as well
 			sp.Config.ContractTable.AddContract(&contracts.TypecastsTo{
@@ -321,10 +429,11 @@ func (sp *Parser) ParseValueSpec(spec *ast.ValueSpec) ([]*symbols.SymbolDef, err
 				continue
 			}
 			sDef := &symbols.SymbolDef{
-				Name:    name.String(),
-				Package: sp.PackageName,
-				Def:     typeDef,
-				Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, name.Pos()),
+				Name:     name.String(),
+				Package:  sp.PackageName,
+				Def:      typeDef,
+				Pos:      sp.Config.SymbolPos(name.Pos()),
+				Position: sp.Config.SymbolPosition(name),
 			}
 			symbolsDef = append(symbolsDef, sDef)
 			if sp.SymbolTable.CurrentLevel() > 0 {
@@ -334,7 +443,7 @@ func (sp *Parser) ParseValueSpec(spec *ast.ValueSpec) ([]*symbols.SymbolDef, err
 				X:            typevars.MakeConstant(sp.PackageName, typeDef),
 				Y:            typevars.VariableFromSymbolDef(sDef),
 				ExpectedType: typeDef,
-				Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, name.Pos()),
+				Pos:          sp.Config.SymbolPos(name.Pos()),
 			})
 		}
 		return symbolsDef, nil
@@ -364,9 +473,10 @@ func (sp *Parser) parseDeclStmt(statement *ast.DeclStmt) error {
 				if err := sp.SymbolTable.AddDataType(&symbols.SymbolDef{
 					Name: genDeclSpec.Name.Name,
 					// local variable has package origin as well (though the symbol gets dropped later on)
-					Package: sp.PackageName,
-					Def:     nil,
-					Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, spec.Pos()),
+					Package:  sp.PackageName,
+					Def:      nil,
+					Pos:      sp.Config.SymbolPos(spec.Pos()),
+					Position: sp.Config.SymbolPosition(spec),
 				}); err != nil {
 					return err
 				}
@@ -380,10 +490,12 @@ func (sp *Parser) parseDeclStmt(statement *ast.DeclStmt) error {
 				}
 
 				if err := sp.SymbolTable.AddDataType(&symbols.SymbolDef{
-					Name:    genDeclSpec.Name.Name,
-					Package: sp.PackageName,
-					Def:     typeDef,
-					Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, spec.Pos()),
+					Name:     genDeclSpec.Name.Name,
+					Package:  sp.PackageName,
+					Def:      typeDef,
+					Pos:      sp.Config.SymbolPos(spec.Pos()),
+					Position: sp.Config.SymbolPosition(spec),
+					Alias:    genDeclSpec.Assign.IsValid(),
 				}); err != nil {
 					return err
 				}
@@ -393,10 +505,12 @@ func (sp *Parser) parseDeclStmt(statement *ast.DeclStmt) error {
 				// so the contract runner can still access it
 				if sp.SymbolTable.CurrentLevel() > 0 {
 					if err := sp.SymbolTable.AddVirtualDataType(&symbols.SymbolDef{
-						Name:    genDeclSpec.Name.Name,
-						Package: sp.PackageName,
-						Def:     typeDef,
-						Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, spec.Pos()),
+						Name:     genDeclSpec.Name.Name,
+						Package:  sp.PackageName,
+						Def:      typeDef,
+						Pos:      sp.Config.SymbolPos(spec.Pos()),
+						Position: sp.Config.SymbolPosition(spec),
+						Alias:    genDeclSpec.Assign.IsValid(),
 					}); err != nil {
 						return err
 					}
@@ -701,10 +815,11 @@ func (sp *Parser) parseAssignments(statement *ast.AssignStmt, typeDef gotypes.Da
 			switch statement.Tok {
 			case token.DEFINE:
 				sDef := &symbols.SymbolDef{
-					Name:    lhsExpr.Name,
-					Package: sp.PackageName,
-					Def:     rhsExpr,
-					Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+					Name:     lhsExpr.Name,
+					Package:  sp.PackageName,
+					Def:      rhsExpr,
+					Pos:      sp.Config.SymbolPos(statement.Lhs[i].Pos()),
+					Position: sp.Config.SymbolPosition(statement.Lhs[i]),
 				}
 				if sp.SymbolTable.CurrentLevel() > 0 {
 					sDef.Package = ""
@@ -715,7 +830,7 @@ func (sp *Parser) parseAssignments(statement *ast.AssignStmt, typeDef gotypes.Da
 					Y:            typevars.VariableFromSymbolDef(sDef),
 					ExpectedType: sDef.Def,
 					ToVariable:   true,
-					Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+					Pos:          sp.Config.SymbolPos(statement.Lhs[i].Pos()),
 				})
 			case token.VAR:
 
@@ -736,10 +851,11 @@ func (sp *Parser) parseAssignments(statement *ast.AssignStmt, typeDef gotypes.Da
 						}
 					}
 					sDef = &symbols.SymbolDef{
-						Name:    lhsExpr.Name,
-						Package: sp.PackageName,
-						Def:     rhsExpr,
-						Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+						Name:     lhsExpr.Name,
+						Package:  sp.PackageName,
+						Def:      rhsExpr,
+						Pos:      sp.Config.SymbolPos(statement.Lhs[i].Pos()),
+						Position: sp.Config.SymbolPosition(statement.Lhs[i]),
 					}
 					if sp.SymbolTable.CurrentLevel() > 0 {
 						sDef.Package = ""
@@ -749,14 +865,15 @@ func (sp *Parser) parseAssignments(statement *ast.AssignStmt, typeDef gotypes.Da
 						Y:            typevars.VariableFromSymbolDef(sDef),
 						ExpectedType: sDef.Def,
 						ToVariable:   true,
-						Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+						Pos:          sp.Config.SymbolPos(statement.Lhs[i].Pos()),
 					})
 				} else {
 					sDef = &symbols.SymbolDef{
-						Name:    lhsExpr.Name,
-						Package: sp.PackageName,
-						Def:     typeDef,
-						Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+						Name:     lhsExpr.Name,
+						Package:  sp.PackageName,
+						Def:      typeDef,
+						Pos:      sp.Config.SymbolPos(statement.Lhs[i].Pos()),
+						Position: sp.Config.SymbolPosition(statement.Lhs[i]),
 					}
 					if sp.SymbolTable.CurrentLevel() > 0 {
 						sDef.Package = ""
@@ -772,7 +889,7 @@ func (sp *Parser) parseAssignments(statement *ast.AssignStmt, typeDef gotypes.Da
 						Y:            typevars.VariableFromSymbolDef(sDef),
 						ExpectedType: sDef.Def,
 						ToVariable:   true,
-						Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+						Pos:          sp.Config.SymbolPos(statement.Lhs[i].Pos()),
 					})
 				}
 				symbolsDef = append(symbolsDef, sDef)
@@ -1044,10 +1161,11 @@ func (sp *Parser) parseAssignStmt(statement *ast.AssignStmt) error {
 					}
 				}
 				sDef := &symbols.SymbolDef{
-					Name:    lhsExpr.Name,
-					Package: sp.PackageName,
-					Def:     rhsExpr,
-					Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+					Name:     lhsExpr.Name,
+					Package:  sp.PackageName,
+					Def:      rhsExpr,
+					Pos:      sp.Config.SymbolPos(statement.Lhs[i].Pos()),
+					Position: sp.Config.SymbolPosition(statement.Lhs[i]),
 				}
 				if sp.SymbolTable.CurrentLevel() > 0 {
 					sDef.Package = ""
@@ -1058,7 +1176,7 @@ func (sp *Parser) parseAssignStmt(statement *ast.AssignStmt) error {
 					Y:            typevars.VariableFromSymbolDef(sDef),
 					ExpectedType: sDef.Def,
 					ToVariable:   true,
-					Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Lhs[i].Pos()),
+					Pos:          sp.Config.SymbolPos(statement.Lhs[i].Pos()),
 				})
 			} else {
 				sDef, err := sp.SymbolTable.LookupVariable(lhsExpr.Name)
@@ -1280,13 +1398,14 @@ func (sp *Parser) parseTypeSwitchStmt(statement *ast.TypeSwitchStmt) error {
 						// The Pos in this context is not applicable to the typevars in each
 						// case block the rhsIdentifier.Def has different scope
 						// TODO(jchaloup): find a different way to state the position of the rhsIdentifier
-						Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, caseStmt.Pos()),
+						Pos:      sp.Config.SymbolPos(caseStmt.Pos()),
+						Position: sp.Config.SymbolPosition(caseStmt),
 					}
 					sp.SymbolTable.AddVariable(sDef)
 					sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 						X:   typevars.MakeConstant(sp.Config.PackageName, typeDef),
 						Y:   typevars.VariableFromSymbolDef(sDef),
-						Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, caseStmt.Pos()),
+						Pos: sp.Config.SymbolPos(caseStmt.Pos()),
 					})
 				}
 			} else {
@@ -1314,13 +1433,14 @@ func (sp *Parser) parseTypeSwitchStmt(statement *ast.TypeSwitchStmt) error {
 							// The Pos in this context is not applicable to the typevars in each
 							// case block the rhsIdentifier.Def has different scope
 							// TODO(jchaloup): find a different way to state the position of the rhsIdentifier
-							Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, caseStmt.Pos()),
+							Pos:      sp.Config.SymbolPos(caseStmt.Pos()),
+							Position: sp.Config.SymbolPosition(caseStmt),
 						}
 						sp.SymbolTable.AddVariable(sDef)
 						sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 							X:   typevars.MakeConstant(sp.Config.PackageName, rhsType),
 							Y:   typevars.VariableFromSymbolDef(sDef),
-							Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, caseStmt.Pos()),
+							Pos: sp.Config.SymbolPos(caseStmt.Pos()),
 						})
 					}
 				}
@@ -1443,17 +1563,18 @@ func (sp *Parser) parseSelectStmt(statement *ast.SelectStmt) error {
 								return fmt.Errorf("Expecting an identifier in select clause due to := assignment")
 							}
 							sDef := &symbols.SymbolDef{
-								Name:    ident.Name,
-								Package: sp.PackageName,
-								Def:     rhsChannel.Value,
-								Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, ident.Pos()),
+								Name:     ident.Name,
+								Package:  sp.PackageName,
+								Def:      rhsChannel.Value,
+								Pos:      sp.Config.SymbolPos(ident.Pos()),
+								Position: sp.Config.SymbolPosition(ident),
 							}
 							sp.SymbolTable.AddVariable(sDef)
 
 							sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 								X:   y,
 								Y:   typevars.VariableFromSymbolDef(sDef),
-								Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, ident.Pos()),
+								Pos: sp.Config.SymbolPos(ident.Pos()),
 							})
 						} else {
 							if err := func() error {
@@ -1497,29 +1618,31 @@ func (sp *Parser) parseSelectStmt(statement *ast.SelectStmt) error {
 							}
 
 							sDef1 := &symbols.SymbolDef{
-								Name:    ident1.Name,
-								Package: sp.PackageName,
-								Def:     rhsChannel.Value,
-								Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, ident1.Pos()),
+								Name:     ident1.Name,
+								Package:  sp.PackageName,
+								Def:      rhsChannel.Value,
+								Pos:      sp.Config.SymbolPos(ident1.Pos()),
+								Position: sp.Config.SymbolPosition(ident1),
 							}
 							sp.SymbolTable.AddVariable(sDef1)
 							sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 								X:   y,
 								Y:   typevars.VariableFromSymbolDef(sDef1),
-								Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, ident1.Pos()),
+								Pos: sp.Config.SymbolPos(ident1.Pos()),
 							})
 
 							sDef2 := &symbols.SymbolDef{
-								Name:    ident2.Name,
-								Package: sp.PackageName,
-								Def:     &gotypes.Identifier{Package: "builtin", Def: "bool"},
-								Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, ident2.Pos()),
+								Name:     ident2.Name,
+								Package:  sp.PackageName,
+								Def:      &gotypes.Identifier{Package: "builtin", Def: "bool"},
+								Pos:      sp.Config.SymbolPos(ident2.Pos()),
+								Position: sp.Config.SymbolPosition(ident2),
 							}
 							sp.SymbolTable.AddVariable(sDef2)
 							sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 								X:   typevars.MakeConstant("builtin", &gotypes.Identifier{Package: "builtin", Def: "bool"}),
 								Y:   typevars.VariableFromSymbolDef(sDef2),
-								Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, ident2.Pos()),
+								Pos: sp.Config.SymbolPos(ident2.Pos()),
 							})
 						} else {
 							// Both vars can be just _
@@ -1633,13 +1756,13 @@ func (sp *Parser) parseRangeStmt(statement *ast.RangeStmt) error {
 		sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 			X:   xExprAttr.TypeVarList[0],
 			Y:   xVarType,
-			Pos: fmt.Sprintf("%v:%v", sp.Config.FileName, statement.Pos()),
+			Pos: sp.Config.SymbolPos(statement.Pos()),
 		})
 	}
 
 	sp.Config.ContractTable.AddContract(&contracts.IsRangeable{
 		X:   xVarType,
-		Pos: fmt.Sprintf("%v/%v:%v", sp.Config.PackageName, sp.Config.FileName, statement.Pos()),
+		Pos: fmt.Sprintf("%v/%v", sp.Config.PackageName, sp.Config.SymbolPos(statement.Pos())),
 	})
 
 	key, value, rErr := propagation.New(sp.Config.SymbolsAccessor).RangeExpr(xExprAttr.DataTypeList[0])
@@ -1657,10 +1780,11 @@ func (sp *Parser) parseRangeStmt(statement *ast.RangeStmt) error {
 
 			if keyIdent.Name != "_" {
 				sDef := &symbols.SymbolDef{
-					Name:    keyIdent.Name,
-					Package: sp.PackageName,
-					Def:     key,
-					Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, keyIdent.Pos()),
+					Name:     keyIdent.Name,
+					Package:  sp.PackageName,
+					Def:      key,
+					Pos:      sp.Config.SymbolPos(keyIdent.Pos()),
+					Position: sp.Config.SymbolPosition(keyIdent),
 				}
 				if err := sp.SymbolTable.AddVariable(sDef); err != nil {
 					return err
@@ -1669,7 +1793,7 @@ func (sp *Parser) parseRangeStmt(statement *ast.RangeStmt) error {
 					X:            typevars.MakeRangeKey(xVarType),
 					Y:            typevars.VariableFromSymbolDef(sDef),
 					ExpectedType: key,
-					Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, keyIdent.Pos()),
+					Pos:          sp.Config.SymbolPos(keyIdent.Pos()),
 				})
 			}
 		} else {
@@ -1711,10 +1835,11 @@ func (sp *Parser) parseRangeStmt(statement *ast.RangeStmt) error {
 			klog.V(2).Infof("Processing range.Value variable %v", valueIdent.Name)
 			if valueIdent.Name != "_" && value != nil {
 				sDef := &symbols.SymbolDef{
-					Name:    valueIdent.Name,
-					Package: sp.PackageName,
-					Def:     value,
-					Pos:     fmt.Sprintf("%v:%v", sp.Config.FileName, valueIdent.Pos()),
+					Name:     valueIdent.Name,
+					Package:  sp.PackageName,
+					Def:      value,
+					Pos:      sp.Config.SymbolPos(valueIdent.Pos()),
+					Position: sp.Config.SymbolPosition(valueIdent),
 				}
 				if err := sp.SymbolTable.AddVariable(sDef); err != nil {
 					return err
@@ -1723,7 +1848,7 @@ func (sp *Parser) parseRangeStmt(statement *ast.RangeStmt) error {
 					X:            typevars.MakeRangeValue(xVarType),
 					Y:            typevars.VariableFromSymbolDef(sDef),
 					ExpectedType: value,
-					Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, valueIdent.Pos()),
+					Pos:          sp.Config.SymbolPos(valueIdent.Pos()),
 				})
 			}
 		} else {
@@ -1868,7 +1993,7 @@ func (sp *Parser) Parse(statement ast.Stmt) error {
 	return nil
 }
 
-func (sp *Parser) parseFuncHeadVariables(funcDecl *ast.FuncDecl) error {
+func (sp *Parser) parseFuncHeadVariables(funcDecl *ast.FuncDecl, typeParams []gotypes.DataType) error {
 	sp.AllocatedSymbolsTable.Lock()
 	defer sp.AllocatedSymbolsTable.Unlock()
 	// TOOD(jchaloup): check the id of the receiver is not the same
@@ -1894,15 +2019,16 @@ func (sp *Parser) parseFuncHeadVariables(funcDecl *ast.FuncDecl) error {
 			// field.Names is always non-empty if param's datatype is defined
 			for _, name := range field.Names {
 				sDef := &symbols.SymbolDef{
-					Name: name.Name,
-					Def:  def,
-					Pos:  fmt.Sprintf("%v:%v", sp.Config.FileName, name.Pos()),
+					Name:     name.Name,
+					Def:      def,
+					Pos:      sp.Config.SymbolPos(name.Pos()),
+					Position: sp.Config.SymbolPosition(name),
 				}
 				sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 					X:            typevars.MakeConstant(sp.Config.PackageName, def),
 					Y:            typevars.VariableFromSymbolDef(sDef),
 					ExpectedType: sDef.Def,
-					Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, name.Pos()),
+					Pos:          sp.Config.SymbolPos(name.Pos()),
 				})
 				sDefs = append(sDefs, sDef)
 			}
@@ -1918,15 +2044,16 @@ func (sp *Parser) parseFuncHeadVariables(funcDecl *ast.FuncDecl) error {
 
 			for _, name := range field.Names {
 				sDef := &symbols.SymbolDef{
-					Name: name.Name,
-					Def:  def,
-					Pos:  fmt.Sprintf("%v:%v", sp.Config.FileName, name.Pos()),
+					Name:     name.Name,
+					Def:      def,
+					Pos:      sp.Config.SymbolPos(name.Pos()),
+					Position: sp.Config.SymbolPosition(name),
 				}
 				sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 					X:            typevars.MakeConstant(sp.Config.PackageName, def),
 					Y:            typevars.VariableFromSymbolDef(sDef),
 					ExpectedType: sDef.Def,
-					Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, name.Pos()),
+					Pos:          sp.Config.SymbolPos(name.Pos()),
 				})
 				sDefs = append(sDefs, sDef)
 			}
@@ -1947,18 +2074,27 @@ func (sp *Parser) parseFuncHeadVariables(funcDecl *ast.FuncDecl) error {
 		if err != nil {
 			return fmt.Errorf("sp.parseReceiver: %v", err)
 		}
+		// A receiver of a generic data type is an instance of the data type
+		if typeParams != nil {
+			if pointer, ok := def.(*gotypes.Pointer); ok {
+				def = &gotypes.Pointer{Def: &gotypes.Instance{Def: pointer.Def, Args: typeParams}}
+			} else {
+				def = &gotypes.Instance{Def: def, Args: typeParams}
+			}
+		}
 		// the receiver can be typed only
 		if funcDecl.Recv.List[0].Names != nil {
 			sDef := &symbols.SymbolDef{
-				Name: (*funcDecl.Recv).List[0].Names[0].Name,
-				Def:  def,
-				Pos:  fmt.Sprintf("%v:%v", sp.Config.FileName, (*funcDecl.Recv).List[0].Names[0].Pos()),
+				Name:     (*funcDecl.Recv).List[0].Names[0].Name,
+				Def:      def,
+				Pos:      sp.Config.SymbolPos((*funcDecl.Recv).List[0].Names[0].Pos()),
+				Position: sp.Config.SymbolPosition((*funcDecl.Recv).List[0].Names[0]),
 			}
 			sp.Config.ContractTable.AddContract(&contracts.PropagatesTo{
 				X:            typevars.MakeConstant(sp.Config.PackageName, def),
 				Y:            typevars.VariableFromSymbolDef(sDef),
 				ExpectedType: sDef.Def,
-				Pos:          fmt.Sprintf("%v:%v", sp.Config.FileName, (*funcDecl.Recv).List[0].Names[0].Pos()),
+				Pos:          sp.Config.SymbolPos((*funcDecl.Recv).List[0].Names[0].Pos()),
 			})
 			sp.SymbolTable.AddVariable(sDef)
 		}
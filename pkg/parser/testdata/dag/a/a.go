@@ -0,0 +1,14 @@
+package a
+
+type Node struct {
+	Name  string
+	Edges []*Node
+}
+
+func (n *Node) Add(m *Node) {
+	n.Edges = append(n.Edges, m)
+}
+
+func New(name string) *Node {
+	return &Node{Name: name}
+}
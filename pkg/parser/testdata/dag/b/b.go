@@ -0,0 +1,11 @@
+package b
+
+import "example.com/dag/a"
+
+type Tree struct {
+	Root *a.Node
+}
+
+func Leaf(name string) *a.Node {
+	return a.New(name)
+}
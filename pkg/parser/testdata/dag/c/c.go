@@ -0,0 +1,10 @@
+package c
+
+import "example.com/dag/a"
+
+var Nodes = map[string]*a.Node{}
+
+func Register(n *a.Node) int {
+	Nodes[n.Name] = n
+	return len(n.Edges)
+}
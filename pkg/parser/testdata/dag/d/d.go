@@ -0,0 +1,13 @@
+package d
+
+import (
+	"example.com/dag/a"
+	"example.com/dag/b"
+	"example.com/dag/c"
+)
+
+func Build() (*b.Tree, int) {
+	t := &b.Tree{Root: a.New("root")}
+	t.Root.Add(b.Leaf("leaf"))
+	return t, c.Register(t.Root)
+}
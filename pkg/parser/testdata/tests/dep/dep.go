@@ -0,0 +1,13 @@
+package dep
+
+type Mock struct {
+	Name string
+}
+
+func Run() int {
+	return 0
+}
+
+func NewMock(name string) *Mock {
+	return &Mock{Name: name}
+}
@@ -0,0 +1,7 @@
+package lib_test
+
+import "example.com/tests/lib"
+
+func ExampleRun() {
+	lib.Run(lib.Config{Port: 8080})
+}
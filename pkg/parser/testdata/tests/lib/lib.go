@@ -0,0 +1,15 @@
+package lib
+
+import "example.com/tests/dep"
+
+type Config struct {
+	Port int
+}
+
+func Run(c Config) int {
+	return dep.Run() + c.Port
+}
+
+func helper() string {
+	return "helper"
+}
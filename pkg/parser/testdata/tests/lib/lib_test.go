@@ -0,0 +1,8 @@
+package lib
+
+import "example.com/tests/dep"
+
+func TestHelper() {
+	m := dep.NewMock(helper())
+	_ = m.Name
+}
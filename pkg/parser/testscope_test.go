@@ -0,0 +1,225 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
+	allocglobal "github.com/gofed/symbols-extractor/pkg/parser/alloctable/global"
+	contractglobal "github.com/gofed/symbols-extractor/pkg/parser/contracts/global"
+)
+
+// testdataResolver locates packages in the testdata directory
+type testdataResolver map[string]string
+
+func (r testdataResolver) PackageDir(pkg string) (string, error) {
+	if dir, ok := r[pkg]; ok {
+		return filepath.Abs(dir)
+	}
+	return "", fmt.Errorf("Package %q not in testdata", pkg)
+}
+
+// dumpAllocated lists symbols allocated in the package, one per line
+func dumpAllocated(table *alloctable.Table) []string {
+	var lines []string
+	for pkg, symbols := range table.Symbols {
+		for _, item := range symbols.Datatypes {
+			lines = append(lines, fmt.Sprintf("datatype %v.%v %v", pkg, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Functions {
+			lines = append(lines, fmt.Sprintf("function %v.%v %v", pkg, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Variables {
+			lines = append(lines, fmt.Sprintf("variable %v.%v %v", pkg, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Methods {
+			lines = append(lines, fmt.Sprintf("method %v.%v.%v %v", pkg, item.Parent, item.Name, item.Pos))
+		}
+		for _, item := range symbols.Structfields {
+			lines = append(lines, fmt.Sprintf("structfield %v.%v.%v %v", pkg, item.Parent, item.Field, item.Pos))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+const (
+	testsLib = "example.com/tests/lib"
+	testsDep = "example.com/tests/dep"
+)
+
+// newTestsParser creates a parser extracting the tests testdata packages (including _test.go files)
+func newTestsParser(t *testing.T, dir string) *ProjectParser {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	pp, err := New(dir, "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pp.SetTests(true).SetResolver(testdataResolver{
+		testsLib: "testdata/tests/lib",
+		testsDep: "testdata/tests/dep",
+	})
+}
+
+func TestCreateTestPackageContexts(t *testing.T) {
+	pp := newTestsParser(t, t.TempDir())
+
+	contexts, err := pp.createTestPackageContexts(testsLib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contexts) != 2 {
+		t.Fatalf("Expected contexts of the package and of the external _test package, got %v", len(contexts))
+	}
+
+	// the package is extended with its _test.go files, the external _test package consists of the test files only
+	expected := []struct {
+		packagePath string
+		files       []string
+	}{
+		{packagePath: testsLib, files: []string{"lib.go", "lib_test.go"}},
+		{packagePath: testsLib + "_test", files: []string{"ext_test.go"}},
+	}
+	testFiles := map[string]struct{}{"lib_test.go": {}, "ext_test.go": {}}
+	contractTable := contexts[0].Files[1].ContractTable
+	for i, c := range contexts {
+		var files []string
+		for _, fc := range c.Files {
+			files = append(files, fc.Filename)
+		}
+		if c.PackagePath != expected[i].packagePath || !reflect.DeepEqual(files, expected[i].files) {
+			t.Errorf("Expected %v package with %v files, got %v with %v", expected[i].packagePath, expected[i].files, c.PackagePath, files)
+		}
+		if c.TestedPackage != testsLib {
+			t.Errorf("Expected %v tested package, got %q", testsLib, c.TestedPackage)
+		}
+		if !reflect.DeepEqual(c.TestFiles, testFiles) {
+			t.Errorf("Expected %v test files, got %v", testFiles, c.TestFiles)
+		}
+
+		for _, fc := range c.Files {
+			_, isTest := testFiles[fc.Filename]
+			// contracts of all test files are collected in one table
+			if isTest != (fc.ContractTable == contractTable) {
+				t.Errorf("Expected %v to share the contract table of test files: %v", fc.Filename, isTest)
+			}
+			scope := alloctable.ProductionScope
+			if isTest {
+				scope = alloctable.TestScope
+			}
+			if fc.AllocatedSymbolsTable.Scope != scope {
+				t.Errorf("Expected %v in the %q scope, got %q", fc.Filename, scope, fc.AllocatedSymbolsTable.Scope)
+			}
+			if isTest && fc.AllocatedSymbolsTable.Package != testsLib {
+				t.Errorf("Expected symbols of %v allocated under %v, got %v", fc.Filename, testsLib, fc.AllocatedSymbolsTable.Package)
+			}
+		}
+	}
+
+	// a package without test files
+	contexts, err = pp.createTestPackageContexts(testsDep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contexts != nil {
+		t.Errorf("Expected no test contexts of %v, got %v", testsDep, len(contexts))
+	}
+}
+
+func TestProcessTests(t *testing.T) {
+	dir := t.TempDir()
+	pp := newTestsParser(t, dir)
+	if err := pp.Parse(testsLib, true); err != nil {
+		t.Fatalf("Unable to parse %v: %v", testsLib, err)
+	}
+
+	// symbols of test files are not part of the package API
+	table, err := pp.GlobalSymbolTable().Lookup(testsLib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"TestHelper", "ExampleRun"} {
+		if _, err := table.LookupFunction(name); err == nil {
+			t.Errorf("Expected %v not to be part of the %v API", name, testsLib)
+		}
+	}
+	if _, err := table.LookupFunction("Run"); err != nil {
+		t.Errorf("Expected Run to be part of the %v API: %v", testsLib, err)
+	}
+
+	// allocated symbols and contracts are loaded from the stored files.
+	// Only file names of the allocated positions are compared.
+	// Symbols allocated while the contracts are evaluated are not stored.
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	allocated := func(table *allocglobal.Table, scope string) map[string][]string {
+		files, err := table.LookupPackage(testsLib)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := make(map[string][]string)
+		for file, ftable := range files {
+			if ftable.Scope != scope {
+				t.Errorf("Expected %v allocated in the %q scope, got %q", file, scope, ftable.Scope)
+			}
+			for _, line := range dumpAllocated(ftable) {
+				lines[file] = append(lines[file], strings.Split(line, ":")[0])
+			}
+		}
+		return lines
+	}
+
+	// allocated.json
+	expected := map[string][]string{
+		"lib.go": {
+			"datatype builtin.int lib.go",
+			"datatype builtin.int lib.go",
+			"datatype builtin.string lib.go",
+			"datatype example.com/tests/lib.Config lib.go",
+			"function example.com/tests/dep.Run lib.go",
+		},
+	}
+	if actual := allocated(allocglobal.New(dir, goVersion, nil), alloctable.ProductionScope); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected allocated.json:\n%v\ngot:\n%v", expected, actual)
+	}
+
+	// allocated_test.json
+	expected = map[string][]string{
+		"lib_test.go": {
+			"function example.com/tests/dep.NewMock lib_test.go",
+		},
+		"ext_test.go": {
+			"datatype example.com/tests/lib.Config ext_test.go",
+			"function example.com/tests/lib.Run ext_test.go",
+		},
+	}
+	if actual := allocated(allocglobal.NewTest(dir, goVersion, nil), alloctable.TestScope); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected allocated_test.json:\n%v\ngot:\n%v", expected, actual)
+	}
+
+	// contracts.json and contracts_test.json
+	contractPrefixes := func(table *contractglobal.Table) []string {
+		ctable, err := table.Load(testsLib)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var prefixes []string
+		for prefix, list := range ctable.Contracts {
+			if len(list) > 0 {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		sort.Strings(prefixes)
+		return prefixes
+	}
+	if prefixes, expected := contractPrefixes(contractglobal.New(dir, goVersion, nil)), []string{"Run"}; !reflect.DeepEqual(prefixes, expected) {
+		t.Errorf("Expected contracts of %v in contracts.json, got %v", expected, prefixes)
+	}
+	if prefixes, expected := contractPrefixes(contractglobal.NewTest(dir, goVersion, nil)), []string{"ExampleRun", "TestHelper"}; !reflect.DeepEqual(prefixes, expected) {
+		t.Errorf("Expected contracts of %v in contracts_test.json, got %v", expected, prefixes)
+	}
+}
@@ -0,0 +1,5 @@
+package other
+
+type Reader interface {
+	Read() string
+}
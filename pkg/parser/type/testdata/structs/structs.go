@@ -0,0 +1,21 @@
+package structs
+
+import "example.com/other"
+
+type Base struct {
+	ID int
+}
+
+type Wrapper[T any] struct {
+	Value T
+}
+
+// Record has named and embedded fields with and without tags
+type Record struct {
+	Name  string `json:"name" yaml:"name"`
+	*Base `json:",inline"`
+	other.Reader
+	X, Y int `json:"coord"`
+	Wrapper[string]
+	private bool
+}
@@ -3,6 +3,8 @@ package typeparser
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"strconv"
 
 	"k8s.io/klog/v2"
 
@@ -57,7 +59,7 @@ func (p *Parser) parseIdentifier(typedExpr *ast.Ident) (gotypes.DataType, error)
 				//&gotypes.Identifier{Package: "builtin", Def: typedExpr.Name},
 				"builtin",
 				typedExpr.Name,
-				fmt.Sprintf("%v:%v", p.Config.FileName, typedExpr.Pos()),
+				p.Config.SymbolPosition(typedExpr),
 			)
 			table, err := p.GlobalSymbolTable.Lookup("builtin")
 			if err != nil {
@@ -72,19 +74,28 @@ func (p *Parser) parseIdentifier(typedExpr *ast.Ident) (gotypes.DataType, error)
 		return nil, fmt.Errorf("Unable to find symbol %v in the symbol table", typedExpr.Name)
 	}
 
+	// Type parameters are local to a generic data type or function,
+	// there is nothing to allocate.
+	if typeParam, ok := def.Def.(*gotypes.Typeparam); ok {
+		return &gotypes.Typeparam{
+			Name:       typeParam.Name,
+			Constraint: typeParam.Constraint,
+		}, nil
+	}
+
 	// TODO(jchaloup): consider if we should count the recursive use of a data type into its allocation count
 	switch defType {
 	case symbols.DataTypeSymbol:
 		p.AllocatedSymbolsTable.AddDataType(
 			def.Package,
 			typedExpr.Name,
-			fmt.Sprintf("%v:%v", p.Config.FileName, typedExpr.Pos()),
+			p.Config.SymbolPosition(typedExpr),
 		)
 	case symbols.FunctionSymbol:
 		p.AllocatedSymbolsTable.AddFunction(
 			def.Package,
 			typedExpr.Name,
-			fmt.Sprintf("%v:%v", p.Config.FileName, typedExpr.Pos()),
+			p.Config.SymbolPosition(typedExpr),
 		)
 	case symbols.VariableSymbol:
 		// TODO(jchaloup): allocated variable as well
@@ -167,7 +178,7 @@ func (p *Parser) parseSelector(typedExpr *ast.SelectorExpr) (*gotypes.Selector,
 		p.AllocatedSymbolsTable.AddDataType(
 			qid.Path,
 			typedExpr.Sel.Name,
-			fmt.Sprintf("%v:%v", p.Config.FileName, typedExpr.Pos()),
+			p.Config.SymbolPosition(typedExpr),
 		)
 		return &gotypes.Selector{
 			Item:   typedExpr.Sel.Name,
@@ -195,6 +206,13 @@ func (p *Parser) parseStruct(typedExpr *ast.StructType) (*gotypes.Struct, error)
 	}
 
 	for _, field := range typedExpr.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			var err error
+			if tag, err = strconv.Unquote(field.Tag.Value); err != nil {
+				return nil, fmt.Errorf("Unable to unquote %v field tag: %v", field.Tag.Value, err)
+			}
+		}
 		// anonymous field?
 		klog.V(2).Infof("Processing StructType.field: %#v\n", field)
 		if field.Names == nil {
@@ -204,8 +222,11 @@ func (p *Parser) parseStruct(typedExpr *ast.StructType) (*gotypes.Struct, error)
 			}
 
 			item := gotypes.StructFieldsItem{
-				Name: "",
-				Def:  def,
+				Name:     embeddedFieldName(field.Type),
+				Tag:      tag,
+				Embedded: true,
+				Order:    len(structType.Fields),
+				Def:      def,
 			}
 			klog.V(2).Infof("Processing StructType.item: %#v\n", item)
 
@@ -219,8 +240,10 @@ func (p *Parser) parseStruct(typedExpr *ast.StructType) (*gotypes.Struct, error)
 				}
 
 				item := gotypes.StructFieldsItem{
-					Name: name.Name,
-					Def:  def,
+					Name:  name.Name,
+					Tag:   tag,
+					Order: len(structType.Fields),
+					Def:   def,
 				}
 				structType.Fields = append(structType.Fields, item)
 			}
@@ -229,6 +252,23 @@ func (p *Parser) parseStruct(typedExpr *ast.StructType) (*gotypes.Struct, error)
 	return structType, nil
 }
 
+// embeddedFieldName returns a name of an embedded field, i.e. the (unqualified) data type name
+func embeddedFieldName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(e.X)
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedFieldName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(e.X)
+	}
+	return ""
+}
+
 func (p *Parser) parseMap(typedExpr *ast.MapType) (*gotypes.Map, error) {
 	klog.V(2).Infof("Processing MapType: %#v\n", typedExpr)
 	keyDef, keyErr := p.Parse(typedExpr.Key)
@@ -307,6 +347,18 @@ func (p *Parser) parseFunction(typedExpr *ast.FuncType) (*gotypes.Function, erro
 	klog.V(2).Infof("Processing FuncType: %#v\n", typedExpr)
 	functionType := &gotypes.Function{Package: p.PackageName}
 
+	// Type parameters are visible in the function signature only.
+	// The function body pushes them into its own block of the symbol table.
+	if typedExpr.TypeParams != nil {
+		p.SymbolTable.Push()
+		defer p.SymbolTable.Pop()
+		typeParams, err := p.ParseTypeParams(typedExpr.TypeParams)
+		if err != nil {
+			return nil, err
+		}
+		functionType.Typeparams = typeParams
+	}
+
 	var params []gotypes.DataType
 	var results []gotypes.DataType
 
@@ -360,6 +412,117 @@ func (p *Parser) parseFunction(typedExpr *ast.FuncType) (*gotypes.Function, erro
 	return functionType, nil
 }
 
+// ParseTypeParams parses a list of type parameters (e.g. [K comparable, V any])
+// and stores each type parameter into the top most block of the symbol table.
+// It is up to the caller to push (and later pop) the block.
+func (p *Parser) ParseTypeParams(typedExpr *ast.FieldList) ([]gotypes.DataType, error) {
+	klog.V(2).Infof("Processing TypeParams: %#v\n", typedExpr)
+	if typedExpr == nil {
+		return nil, nil
+	}
+
+	// Store all the type parameters first as a constraint can refer
+	// to any other type parameter from the list, e.g. [S ~[]E, E any]
+	var typeParams []*gotypes.Typeparam
+	for _, field := range typedExpr.List {
+		for _, name := range field.Names {
+			typeParam := &gotypes.Typeparam{Name: name.Name}
+			if err := p.SymbolTable.AddDataType(&symbols.SymbolDef{
+				Name:     name.Name,
+				Pos:      p.Config.SymbolPos(name.Pos()),
+				Position: p.Config.SymbolPosition(name),
+				Def:      typeParam,
+			}); err != nil {
+				return nil, err
+			}
+			typeParams = append(typeParams, typeParam)
+		}
+	}
+
+	var defs []gotypes.DataType
+	idx := 0
+	for _, field := range typedExpr.List {
+		constraint, err := p.Parse(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		for range field.Names {
+			typeParams[idx].Constraint = constraint
+			defs = append(defs, typeParams[idx])
+			idx++
+		}
+	}
+
+	return defs, nil
+}
+
+// parseInstance parses an instantiation of a generic data type, e.g. List[int] or Map[K, V]
+func (p *Parser) parseInstance(x ast.Expr, indices []ast.Expr) (*gotypes.Instance, error) {
+	klog.V(2).Infof("Processing generic type instance: %#v\n", x)
+	def, err := p.Parse(x)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &gotypes.Instance{
+		Def: def,
+	}
+	for _, index := range indices {
+		arg, err := p.Parse(index)
+		if err != nil {
+			return nil, err
+		}
+		instance.Args = append(instance.Args, arg)
+	}
+
+	return instance, nil
+}
+
+func (p *Parser) parseUnionTerms(typedExpr ast.Expr) ([]gotypes.UnionTermsItem, error) {
+	switch d := typedExpr.(type) {
+	case *ast.BinaryExpr:
+		if d.Op != token.OR {
+			return nil, fmt.Errorf("Expected | operator in a type union, got %v instead", d.Op)
+		}
+		xTerms, err := p.parseUnionTerms(d.X)
+		if err != nil {
+			return nil, err
+		}
+		yTerms, err := p.parseUnionTerms(d.Y)
+		if err != nil {
+			return nil, err
+		}
+		return append(xTerms, yTerms...), nil
+	case *ast.UnaryExpr:
+		if d.Op != token.TILDE {
+			return nil, fmt.Errorf("Expected ~ operator in a type term, got %v instead", d.Op)
+		}
+		def, err := p.Parse(d.X)
+		if err != nil {
+			return nil, err
+		}
+		return []gotypes.UnionTermsItem{{Tilde: true, Def: def}}, nil
+	}
+
+	def, err := p.Parse(typedExpr)
+	if err != nil {
+		return nil, err
+	}
+	return []gotypes.UnionTermsItem{{Def: def}}, nil
+}
+
+// parseUnion parses a union of type terms of a constraint, e.g. ~int | ~string
+func (p *Parser) parseUnion(typedExpr ast.Expr) (*gotypes.Union, error) {
+	klog.V(2).Infof("Processing type union: %#v\n", typedExpr)
+	terms, err := p.parseUnionTerms(typedExpr)
+	if err != nil {
+		return nil, err
+	}
+	return &gotypes.Union{
+		Terms: terms,
+	}, nil
+}
+
 func (p *Parser) parseParen(typedExpr *ast.ParenExpr) (gotypes.DataType, error) {
 	klog.V(2).Infof("Processing ParentExpr: %#v\n", typedExpr)
 	return p.Parse(typedExpr.X)
@@ -389,6 +552,12 @@ func (p *Parser) Parse(expr ast.Expr) (gotypes.DataType, error) {
 		return p.parseFunction(typedExpr)
 	case *ast.ParenExpr:
 		return p.parseParen(typedExpr)
+	case *ast.IndexExpr:
+		return p.parseInstance(typedExpr.X, []ast.Expr{typedExpr.Index})
+	case *ast.IndexListExpr:
+		return p.parseInstance(typedExpr.X, typedExpr.Indices)
+	case *ast.BinaryExpr, *ast.UnaryExpr:
+		return p.parseUnion(typedExpr)
 	}
 	return nil, fmt.Errorf("ast.Expr (%#v) not recognized when parsing a type definition", expr)
 }
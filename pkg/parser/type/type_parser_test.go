@@ -0,0 +1,78 @@
+package typeparser_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/parser"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+// testdataResolver locates packages in the testdata directory
+type testdataResolver map[string]string
+
+func (r testdataResolver) PackageDir(pkg string) (string, error) {
+	if dir, ok := r[pkg]; ok {
+		return filepath.Abs(dir)
+	}
+	return "", fmt.Errorf("Package %q not in testdata", pkg)
+}
+
+func TestStructFields(t *testing.T) {
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(t.TempDir(), "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetResolver(testdataResolver{
+		"example.com/structs": "testdata/structs",
+		"example.com/other":   "testdata/other",
+	})
+	if err := p.Parse("example.com/structs", false); err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := p.GlobalSymbolTable().Lookup("example.com/structs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := table.LookupDataType("Record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, ok := def.Def.(*gotypes.Struct)
+	if !ok {
+		t.Fatalf("Expected Record to be a struct, got %#v", def.Def)
+	}
+
+	// embedded fields are named by their (unqualified) data types
+	expected := []gotypes.StructFieldsItem{
+		{Name: "Name", Tag: `json:"name" yaml:"name"`, Order: 0},
+		{Name: "Base", Tag: `json:",inline"`, Embedded: true, Order: 1},
+		{Name: "Reader", Embedded: true, Order: 2},
+		{Name: "X", Tag: `json:"coord"`, Order: 3},
+		{Name: "Y", Tag: `json:"coord"`, Order: 4},
+		{Name: "Wrapper", Embedded: true, Order: 5},
+		{Name: "private", Order: 6},
+	}
+	var fields []gotypes.StructFieldsItem
+	for _, field := range record.Fields {
+		field.Def = nil
+		fields = append(fields, field)
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, fields)
+	}
+
+	// definitions of embedded fields keep the pointer and the package qualifier
+	if _, ok := record.Fields[1].Def.(*gotypes.Pointer); !ok {
+		t.Errorf("Expected *Base pointer, got %#v", record.Fields[1].Def)
+	}
+	if _, ok := record.Fields[2].Def.(*gotypes.Selector); !ok {
+		t.Errorf("Expected other.Reader selector, got %#v", record.Fields[2].Def)
+	}
+}
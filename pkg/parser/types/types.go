@@ -8,6 +8,7 @@ import (
 	"github.com/gofed/symbols-extractor/pkg/parser/alloctable"
 	contracttable "github.com/gofed/symbols-extractor/pkg/parser/contracts/table"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	"github.com/gofed/symbols-extractor/pkg/symbols"
 	"github.com/gofed/symbols-extractor/pkg/symbols/accessors"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
@@ -19,6 +20,8 @@ import (
 // TypeParser implementation is responsible for Go data type parsing/processing
 type TypeParser interface {
 	Parse(d ast.Expr) (gotypes.DataType, error)
+	// ParseTypeParams parses a list of type parameters and stores them into the top most block of the symbol table
+	ParseTypeParams(list *ast.FieldList) ([]gotypes.DataType, error)
 }
 
 type ExprAttribute struct {
@@ -71,6 +74,8 @@ type Config struct {
 	PackageName string
 	// file
 	FileName string
+	// per package file set (all package files are parsed into it)
+	FileSet *token.FileSet
 	// per file symbol table
 	SymbolTable *stack.Stack
 	// per subset of packages symbol table
@@ -91,6 +96,20 @@ type Config struct {
 	SymbolsAccessor *accessors.Accessor
 }
 
+// SymbolPos returns a position in the "<file>:<line>:<column>" form.
+// If no file set is configured, the position is in the "<file>:<offset>" form.
 func (c *Config) SymbolPos(pos token.Pos) string {
-	return fmt.Sprintf("%v:%v", c.FileName, pos)
+	if c.FileSet == nil {
+		return fmt.Sprintf("%v:%v", c.FileName, pos)
+	}
+	p := c.FileSet.Position(pos)
+	return fmt.Sprintf("%v:%v:%v", c.FileName, p.Line, p.Column)
+}
+
+// SymbolPosition returns a position of a node (including its end)
+func (c *Config) SymbolPosition(node ast.Node) *positions.Position {
+	if c.FileSet == nil {
+		return positions.Parse(c.SymbolPos(node.Pos()))
+	}
+	return positions.FromNode(c.FileSet, c.FileName, node)
 }
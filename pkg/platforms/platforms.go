@@ -0,0 +1,120 @@
+package platforms
+
+import (
+	"fmt"
+	"go/build"
+	"strings"
+)
+
+// Known operating systems (see `go tool dist list`)
+var GOOS = map[string]struct{}{
+	"aix":       {},
+	"android":   {},
+	"darwin":    {},
+	"dragonfly": {},
+	"freebsd":   {},
+	"illumos":   {},
+	"ios":       {},
+	"js":        {},
+	"linux":     {},
+	"netbsd":    {},
+	"openbsd":   {},
+	"plan9":     {},
+	"solaris":   {},
+	"wasip1":    {},
+	"windows":   {},
+}
+
+// Known architectures (see `go tool dist list`)
+var GOARCH = map[string]struct{}{
+	"386":      {},
+	"amd64":    {},
+	"arm":      {},
+	"arm64":    {},
+	"loong64":  {},
+	"mips":     {},
+	"mipsle":   {},
+	"mips64":   {},
+	"mips64le": {},
+	"ppc64":    {},
+	"ppc64le":  {},
+	"riscv64":  {},
+	"s390x":    {},
+	"wasm":     {},
+}
+
+// Platform is a build target the package files are selected for
+type Platform struct {
+	GOOS       string
+	GOARCH     string
+	CgoEnabled bool
+}
+
+// Parse parses a GOOS/GOARCH platform, e.g. linux/amd64.
+// A /nocgo suffix (e.g. linux/amd64/nocgo) disables cgo (CGO_ENABLED=0).
+func Parse(spec string) (*Platform, error) {
+	parts := strings.Split(spec, "/")
+	if len(parts) == 3 && parts[2] == "nocgo" {
+		parts = parts[:2]
+	} else if len(parts) != 2 {
+		return nil, fmt.Errorf("Expected platform in a GOOS/GOARCH[/nocgo] form, got %q", spec)
+	}
+	if _, ok := GOOS[parts[0]]; !ok {
+		return nil, fmt.Errorf("Unknown GOOS %q of %q platform", parts[0], spec)
+	}
+	if _, ok := GOARCH[parts[1]]; !ok {
+		return nil, fmt.Errorf("Unknown GOARCH %q of %q platform", parts[1], spec)
+	}
+	return &Platform{
+		GOOS:       parts[0],
+		GOARCH:     parts[1],
+		CgoEnabled: !strings.HasSuffix(spec, "/nocgo"),
+	}, nil
+}
+
+// ParseList parses a comma separated list of platforms
+func ParseList(list string) ([]*Platform, error) {
+	var platforms []*Platform
+	for _, spec := range strings.Split(list, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		p, err := Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p)
+	}
+	return platforms, nil
+}
+
+func (p *Platform) String() string {
+	if p.CgoEnabled {
+		return fmt.Sprintf("%v/%v", p.GOOS, p.GOARCH)
+	}
+	return fmt.Sprintf("%v/%v/nocgo", p.GOOS, p.GOARCH)
+}
+
+// Dir is a directory name the platform symbol tables are stored under (e.g. linux_amd64)
+func (p *Platform) Dir() string {
+	return strings.Replace(p.String(), "/", "_", -1)
+}
+
+// Context is the default build context with GOOS, GOARCH and CgoEnabled of the platform
+func (p *Platform) Context() *build.Context {
+	ctx := build.Default
+	ctx.GOOS = p.GOOS
+	ctx.GOARCH = p.GOARCH
+	ctx.CgoEnabled = p.CgoEnabled
+	return &ctx
+}
+
+// Host is the platform of the default build context
+func Host() *Platform {
+	return &Platform{
+		GOOS:       build.Default.GOOS,
+		GOARCH:     build.Default.GOARCH,
+		CgoEnabled: build.Default.CgoEnabled,
+	}
+}
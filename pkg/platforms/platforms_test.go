@@ -0,0 +1,81 @@
+package platforms
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		list      string
+		platforms []*Platform
+	}{
+		{
+			list: "",
+		},
+		{
+			list:      "linux/amd64",
+			platforms: []*Platform{{GOOS: "linux", GOARCH: "amd64", CgoEnabled: true}},
+		},
+		{
+			list: " linux/amd64, windows/386/nocgo,,darwin/arm64 ",
+			platforms: []*Platform{
+				{GOOS: "linux", GOARCH: "amd64", CgoEnabled: true},
+				{GOOS: "windows", GOARCH: "386"},
+				{GOOS: "darwin", GOARCH: "arm64", CgoEnabled: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		platforms, err := ParseList(test.list)
+		if err != nil {
+			t.Errorf("Unable to parse %q: %v", test.list, err)
+			continue
+		}
+		if !reflect.DeepEqual(platforms, test.platforms) {
+			t.Errorf("Expected %q to be parsed into %v, got %v", test.list, test.platforms, platforms)
+		}
+	}
+
+	for _, list := range []string{
+		"linux",
+		"linux/amd64/cgo",
+		"linux/amd64/nocgo/x",
+		"plan10/amd64",
+		"linux/amd65",
+		"linux/amd64,linux",
+	} {
+		if platforms, err := ParseList(list); err == nil {
+			t.Errorf("Expected %q to be invalid, got %v", list, platforms)
+		}
+	}
+}
+
+func TestPlatformString(t *testing.T) {
+	tests := []struct {
+		spec string
+		dir  string
+	}{
+		{spec: "linux/amd64", dir: "linux_amd64"},
+		{spec: "windows/386/nocgo", dir: "windows_386_nocgo"},
+	}
+
+	for _, test := range tests {
+		p, err := Parse(test.spec)
+		if err != nil {
+			t.Errorf("Unable to parse %q: %v", test.spec, err)
+			continue
+		}
+		if p.String() != test.spec {
+			t.Errorf("Expected %q, got %q", test.spec, p.String())
+		}
+		if p.Dir() != test.dir {
+			t.Errorf("Expected %q dir, got %q", test.dir, p.Dir())
+		}
+		ctx := p.Context()
+		if ctx.GOOS != p.GOOS || ctx.GOARCH != p.GOARCH || ctx.CgoEnabled != p.CgoEnabled {
+			t.Errorf("Expected %v build context, got %v/%v cgo=%v", test.spec, ctx.GOOS, ctx.GOARCH, ctx.CgoEnabled)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package positions
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Position of a symbol in a package file.
+// The file is relative to the package directory.
+type Position struct {
+	File      string `json:"file"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	EndLine   int    `json:"endline,omitempty"`
+	EndColumn int    `json:"endcolumn,omitempty"`
+	// Byte offset of positions stored in the "<file>:<offset>" form (set for older artefacts only)
+	Offset int `json:"offset,omitempty"`
+}
+
+// FromNode turns a node of a file (parsed into the fset) into a position
+func FromNode(fset *token.FileSet, file string, node ast.Node) *Position {
+	start := fset.Position(node.Pos())
+	end := fset.Position(node.End())
+	return &Position{
+		File:      file,
+		Line:      start.Line,
+		Column:    start.Column,
+		EndLine:   end.Line,
+		EndColumn: end.Column,
+	}
+}
+
+// Parse parses a position in the "<file>:<line>:<column>" form.
+// Positions of older artefacts in the "<file>:<offset>" form are parsed as well.
+// Nil is returned if the position is in neither form.
+func Parse(pos string) *Position {
+	parts := strings.Split(pos, ":")
+	if len(parts) < 2 {
+		return nil
+	}
+	var numbers []int
+	for len(parts) > 1 && len(numbers) < 2 {
+		n, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			break
+		}
+		numbers = append([]int{n}, numbers...)
+		parts = parts[:len(parts)-1]
+	}
+	file := strings.Join(parts, ":")
+	switch len(numbers) {
+	case 1:
+		return &Position{File: file, Offset: numbers[0]}
+	case 2:
+		return &Position{File: file, Line: numbers[0], Column: numbers[1]}
+	}
+	return nil
+}
+
+// String returns the position in the "<file>:<line>:<column>" form
+// (or in the "<file>:<offset>" form for older artefacts).
+func (p *Position) String() string {
+	if p == nil {
+		return ""
+	}
+	if p.Line == 0 {
+		return fmt.Sprintf("%v:%v", p.File, p.Offset)
+	}
+	return fmt.Sprintf("%v:%v:%v", p.File, p.Line, p.Column)
+}
+
+// Or returns the position if set, otherwise the pos is parsed
+func (p *Position) Or(pos string) *Position {
+	if p != nil {
+		return p
+	}
+	return Parse(pos)
+}
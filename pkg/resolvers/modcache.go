@@ -0,0 +1,61 @@
+package resolvers
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+	"golang.org/x/mod/module"
+)
+
+// ModuleCache locates packages inside $GOMODCACHE/<module>@<version>
+// given a module snapshot.
+type ModuleCache struct {
+	dir      string
+	snapshot *gomod.GoMod
+}
+
+// NewModuleCache creates a module cache resolver.
+// If dir is empty, the GOMODCACHE is taken from `go env GOMODCACHE`.
+func NewModuleCache(dir string, snapshot *gomod.GoMod) (*ModuleCache, error) {
+	if dir == "" {
+		output, err := exec.Command("go", "env", "GOMODCACHE").CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get GOMODCACHE env: %v", err)
+		}
+		dir = strings.Split(string(output), "\n")[0]
+		if dir == "" {
+			return nil, fmt.Errorf("GOMODCACHE not set")
+		}
+	}
+	return &ModuleCache{
+		dir:      dir,
+		snapshot: snapshot,
+	}, nil
+}
+
+func (m *ModuleCache) PackageDir(pkg string) (string, error) {
+	mod, err := m.snapshot.Lookup(pkg)
+	if err != nil {
+		return "", err
+	}
+	if mod.Excluded {
+		return "", fmt.Errorf("Module %q of package %q is excluded", mod.Path, pkg)
+	}
+	if mod.Dir != "" {
+		return modulePackageDir(pkg, mod.Path, mod.Dir)
+	}
+
+	// Upper case letters are escaped in the module cache (e.g. github.com/!burnt!sushi/toml)
+	escapedPath, err := module.EscapePath(mod.Replacement)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return "", err
+	}
+	return modulePackageDir(pkg, mod.Path, path.Join(m.dir, fmt.Sprintf("%v@%v", escapedPath, escapedVersion)))
+}
@@ -0,0 +1,105 @@
+package resolvers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+)
+
+func TestModuleCachePackageDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"project/go.mod": `module example.com/project
+
+go 1.21
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	golang.org/x/text v0.14.0
+	example.com/Local v1.0.0
+	example.com/excluded v1.2.0
+	example.com/Upper v1.0.0-RC1
+)
+
+replace golang.org/x/text => github.com/Fork/text v0.15.0
+
+replace example.com/Local => ../local
+
+exclude example.com/excluded v1.2.0
+`,
+		"local/go.mod":     "module example.com/Local\n",
+		"local/sub/sub.go": "package sub\n",
+		"cache/github.com/!burnt!sushi/toml@v1.3.2/toml.go":       "package toml\n",
+		"cache/github.com/!burnt!sushi/toml@v1.3.2/internal/i.go": "package internal\n",
+		"cache/github.com/!fork/text@v0.15.0/unicode/norm/n.go":   "package norm\n",
+		"cache/example.com/!upper@v1.0.0-!r!c1/u.go":              "package upper\n",
+		"cache/example.com/excluded@v1.2.0/e.go":                  "package excluded\n",
+	})
+
+	snapshot, err := gomod.FromFile(filepath.Join(dir, "project/go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModuleCache(filepath.Join(dir, "cache"), snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		pkg  string
+		dir  string
+	}{
+		{
+			name: "case-escaped module path",
+			pkg:  "github.com/BurntSushi/toml",
+			dir:  "cache/github.com/!burnt!sushi/toml@v1.3.2",
+		},
+		{
+			name: "case-escaped module path, subpackage",
+			pkg:  "github.com/BurntSushi/toml/internal",
+			dir:  "cache/github.com/!burnt!sushi/toml@v1.3.2/internal",
+		},
+		{
+			name: "case-escaped version",
+			pkg:  "example.com/Upper",
+			dir:  "cache/example.com/!upper@v1.0.0-!r!c1",
+		},
+		{
+			name: "module replaced by another module",
+			pkg:  "golang.org/x/text/unicode/norm",
+			dir:  "cache/github.com/!fork/text@v0.15.0/unicode/norm",
+		},
+		{
+			name: "module replaced by a local directory",
+			pkg:  "example.com/Local/sub",
+			dir:  "local/sub",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := m.PackageDir(test.pkg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := filepath.Join(dir, test.dir); filepath.Clean(actual) != expected {
+				t.Errorf("Expected %q in %v, got %v", test.pkg, expected, actual)
+			}
+		})
+	}
+
+	errors := map[string]string{
+		"excluded module":              "example.com/excluded",
+		"unknown module":               "example.com/unknown",
+		"package missing in the cache": "github.com/BurntSushi/toml/missing",
+	}
+	for name, pkg := range errors {
+		t.Run(name, func(t *testing.T) {
+			if dir, err := m.PackageDir(pkg); err == nil {
+				t.Errorf("Expected %q not to be found, got %v", pkg, dir)
+			}
+		})
+	}
+}
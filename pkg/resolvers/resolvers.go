@@ -0,0 +1,80 @@
+package resolvers
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"strings"
+
+	"github.com/gofed/symbols-extractor/pkg/loader"
+	"github.com/gofed/symbols-extractor/pkg/platforms"
+)
+
+// Resolver locates a directory with package source files
+type Resolver interface {
+	PackageDir(pkg string) (string, error)
+}
+
+// Chain tries each resolver in order until a package directory is found
+type Chain []Resolver
+
+func (c Chain) PackageDir(pkg string) (string, error) {
+	var errs []string
+	for _, r := range c {
+		dir, err := r.PackageDir(pkg)
+		if err == nil {
+			return dir, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return "", fmt.Errorf("Unable to resolve %q package:\n\t\t%v\n", pkg, strings.Join(errs, "\n\t\t"))
+}
+
+// PackageFiles lists Go (including cgo) files of a package in a directory.
+// Files not matching the build context (GOOS, GOARCH, build tags) are skipped.
+// If platform is nil, the host build context is used.
+func PackageFiles(dir string, platform *platforms.Platform) ([]string, error) {
+	pkg, err := importDir(dir, platform)
+	if err != nil || pkg == nil {
+		return nil, err
+	}
+	var files []string
+	files = append(files, pkg.GoFiles...)
+	files = append(files, pkg.CgoFiles...)
+	return files, nil
+}
+
+// PackageTestFiles lists _test.go files of a package in a directory.
+// Files of the package itself and files of the external _test package are listed separately.
+func PackageTestFiles(dir string, platform *platforms.Platform) (testFiles, xtestFiles []string, err error) {
+	pkg, err := importDir(dir, platform)
+	if err != nil || pkg == nil {
+		return nil, nil, err
+	}
+	return pkg.TestGoFiles, pkg.XTestGoFiles, nil
+}
+
+func importDir(dir string, platform *platforms.Platform) (*build.Package, error) {
+	pkg, err := loader.For(platform).ImportDir(dir)
+	if err != nil {
+		if _, ok := err.(*build.NoGoError); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Unable to list %v directory: %v", dir, err)
+	}
+	return pkg, nil
+}
+
+func isDir(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// modulePackageDir turns a package of a module into a package directory
+func modulePackageDir(pkg, modulePath, moduleDir string) (string, error) {
+	dir := moduleDir + strings.TrimPrefix(pkg, modulePath)
+	if !isDir(dir) {
+		return "", fmt.Errorf("Package %q not found in %v", pkg, dir)
+	}
+	return dir, nil
+}
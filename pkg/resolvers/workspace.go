@@ -0,0 +1,91 @@
+package resolvers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Workspace locates packages inside modules of a go.work workspace
+type Workspace struct {
+	// module path -> module directory
+	Modules map[string]string
+}
+
+// WorkspaceFromFile reads use directives of a go.work file.
+// Other directives (e.g. replace) are ignored.
+func WorkspaceFromFile(file string) (*Workspace, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load file %v: %v", file, err)
+	}
+
+	var dirs []string
+	inUse := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch {
+		case inUse && fields[0] == ")":
+			inUse = false
+		case inUse:
+			dirs = append(dirs, strings.Trim(fields[0], "\""))
+		case fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			inUse = true
+		case fields[0] == "use" && len(fields) > 1:
+			dirs = append(dirs, strings.Trim(fields[1], "\""))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	w := &Workspace{
+		Modules: make(map[string]string),
+	}
+	for _, dir := range dirs {
+		if !path.IsAbs(dir) {
+			dir = path.Join(path.Dir(file), dir)
+		}
+		gomodFile := path.Join(dir, "go.mod")
+		data, err := ioutil.ReadFile(gomodFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load file %v: %v", gomodFile, err)
+		}
+		modulePath := modfile.ModulePath(data)
+		if modulePath == "" {
+			return nil, fmt.Errorf("Module path not found in %v", gomodFile)
+		}
+		w.Modules[modulePath] = dir
+	}
+
+	return w, nil
+}
+
+func (w *Workspace) PackageDir(pkg string) (string, error) {
+	var modulePath string
+	for mPath := range w.Modules {
+		if pkg != mPath && !strings.HasPrefix(pkg, mPath+"/") {
+			continue
+		}
+		if len(mPath) > len(modulePath) {
+			modulePath = mPath
+		}
+	}
+	if modulePath == "" {
+		return "", fmt.Errorf("Package %q not found in any workspace module", pkg)
+	}
+	return modulePackageDir(pkg, modulePath, w.Modules[modulePath])
+}
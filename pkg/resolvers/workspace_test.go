@@ -0,0 +1,141 @@
+package resolvers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates files (with parent directories) relative to the dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for file, content := range files {
+		file = filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWorkspaceFromFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"ws/a/go.mod":         "module example.com/a\n",
+		"ws/b/go.mod":         "module example.com/b\n",
+		"ws/nested/c/go.mod":  "module example.com/a/c\n",
+		"ws/nested/c/d/d.go":  "package d\n",
+		"ws/b/pkg/pkg.go":     "package pkg\n",
+		"ws/a/a.go":           "package a\n",
+		"outside/e/go.mod":    "// comment\nmodule \"example.com/e\"\n",
+		"outside/e/sub/e.go":  "package sub\n",
+		"ws/unused/go.mod":    "module example.com/unused\n",
+		"ws/commented/go.mod": "module example.com/commented\n",
+	})
+
+	tests := []struct {
+		name    string
+		gowork  string
+		modules map[string]string
+	}{
+		{
+			name:   "single use directive",
+			gowork: "go 1.21\n\nuse ./a\n",
+			modules: map[string]string{
+				"example.com/a": filepath.Join(dir, "ws/a"),
+			},
+		},
+		{
+			name: "use block with comments, quoted and relative paths",
+			gowork: `go 1.21
+
+// modules of the workspace
+use (
+	./a // the main module
+	"./b"
+	// ./commented
+	nested/c
+	../outside/e
+)
+
+replace example.com/x => ./unused
+`,
+			modules: map[string]string{
+				"example.com/a":   filepath.Join(dir, "ws/a"),
+				"example.com/b":   filepath.Join(dir, "ws/b"),
+				"example.com/a/c": filepath.Join(dir, "ws/nested/c"),
+				"example.com/e":   filepath.Join(dir, "outside/e"),
+			},
+		},
+		{
+			name:   "absolute path",
+			gowork: "go 1.21\nuse " + filepath.Join(dir, "ws/b") + "\n",
+			modules: map[string]string{
+				"example.com/b": filepath.Join(dir, "ws/b"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeFiles(t, dir, map[string]string{"ws/go.work": test.gowork})
+			w, err := WorkspaceFromFile(filepath.Join(dir, "ws/go.work"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(w.Modules, test.modules) {
+				t.Errorf("Expected %v modules, got %v", test.modules, w.Modules)
+			}
+		})
+	}
+
+	writeFiles(t, dir, map[string]string{"ws/go.work": "go 1.21\nuse ./missing\n"})
+	if _, err := WorkspaceFromFile(filepath.Join(dir, "ws/go.work")); err == nil {
+		t.Errorf("Expected a module without go.mod to be an error")
+	}
+}
+
+func TestWorkspacePackageDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.work":         "go 1.21\nuse (\n\t./a\n\t./c\n)\n",
+		"a/go.mod":        "module example.com/a\n",
+		"a/a.go":          "package a\n",
+		"a/b/b.go":        "package b\n",
+		"c/go.mod":        "module example.com/a/c\n",
+		"c/c.go":          "package c\n",
+		"c/d/d.go":        "package d\n",
+		"unlisted/go.mod": "module example.com/unlisted\n",
+	})
+	w, err := WorkspaceFromFile(filepath.Join(dir, "go.work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"example.com/a":   filepath.Join(dir, "a"),
+		"example.com/a/b": filepath.Join(dir, "a/b"),
+		// the longest module path wins
+		"example.com/a/c":   filepath.Join(dir, "c"),
+		"example.com/a/c/d": filepath.Join(dir, "c/d"),
+	}
+	for pkg, expected := range tests {
+		actual, err := w.PackageDir(pkg)
+		if err != nil {
+			t.Errorf("Unable to locate %q: %v", pkg, err)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("Expected %q in %v, got %v", pkg, expected, actual)
+		}
+	}
+
+	for _, pkg := range []string{"example.com/unlisted", "example.com/ab", "example.com/a/missing"} {
+		if dir, err := w.PackageDir(pkg); err == nil {
+			t.Errorf("Expected %q not to be found, got %v", pkg, dir)
+		}
+	}
+}
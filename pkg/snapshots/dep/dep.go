@@ -0,0 +1,159 @@
+package dep
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Project is a locked dependency of the [[projects]] table of Gopkg.lock
+type Project struct {
+	Name     string
+	Source   string
+	Packages []string
+	Revision string
+	Version  string
+	Branch   string
+}
+
+type Dep struct {
+	Projects []Project
+
+	importsList   map[string]string
+	mainPkg       string
+	mainPkgCommit string
+}
+
+// FromFile reads Gopkg.lock file generated by dep.
+// Only the [[projects]] tables are read, the [solve-meta] table is ignored.
+func FromFile(file string) (*Dep, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load file %v: %v", file, err)
+	}
+
+	projects, err := parseLock(data)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse file %v: %v", file, err)
+	}
+
+	dep := &Dep{
+		Projects:    projects,
+		importsList: make(map[string]string),
+	}
+	for _, item := range dep.Projects {
+		dep.importsList[item.Name] = item.Revision
+		for _, pkg := range item.Packages {
+			dep.importsList[path.Join(item.Name, pkg)] = item.Revision
+		}
+	}
+
+	return dep, nil
+}
+
+// parseLock parses the subset of TOML the Gopkg.lock is generated in,
+// i.e. tables with key = "string" and key = ["string", ...] entries
+// (arrays can span multiple lines).
+func parseLock(data []byte) ([]Project, error) {
+	var projects []Project
+	var project *Project
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if project != nil {
+				projects = append(projects, *project)
+				project = nil
+			}
+			if line == "[[projects]]" {
+				project = &Project{}
+			}
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %v: expected key = value, got %q", lineno, line)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		// multi-line array
+		if strings.HasPrefix(value, "[") {
+			for !strings.HasSuffix(value, "]") && scanner.Scan() {
+				lineno++
+				value += strings.TrimSpace(scanner.Text())
+			}
+		}
+		if project == nil {
+			continue
+		}
+
+		switch key {
+		case "name", "source", "revision", "version", "branch":
+			s, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: expected a string value of %q, got %v", lineno, key, value)
+			}
+			switch key {
+			case "name":
+				project.Name = s
+			case "source":
+				project.Source = s
+			case "revision":
+				project.Revision = s
+			case "version":
+				project.Version = s
+			case "branch":
+				project.Branch = s
+			}
+		case "packages":
+			if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+				return nil, fmt.Errorf("line %v: expected an array value of %q, got %v", lineno, key, value)
+			}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				item = strings.TrimSpace(item)
+				if item == "" {
+					continue
+				}
+				s, err := strconv.Unquote(item)
+				if err != nil {
+					return nil, fmt.Errorf("line %v: expected a string item of %q, got %v", lineno, key, item)
+				}
+				project.Packages = append(project.Packages, s)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if project != nil {
+		projects = append(projects, *project)
+	}
+	return projects, nil
+}
+
+func (d *Dep) Commit(pkg string) (string, error) {
+	if d.mainPkg != "" && d.mainPkgCommit != "" && (pkg == d.mainPkg || strings.HasPrefix(pkg, d.mainPkg+"/")) {
+		return d.mainPkgCommit, nil
+	}
+	if commit, ok := d.importsList[pkg]; ok {
+		return commit, nil
+	}
+	return "", fmt.Errorf("Commit for package %q not found", pkg)
+}
+
+func (d *Dep) MainPackageCommit(pkg string, commit string) {
+	d.mainPkg = pkg
+	d.mainPkgCommit = commit
+
+	d.importsList[pkg] = commit
+}
@@ -0,0 +1,101 @@
+package dep
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromFile(t *testing.T) {
+	d, err := FromFile("testdata/Gopkg.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Project{
+		{
+			Name:     "github.com/pkg/errors",
+			Packages: []string{"."},
+			Revision: "645ef00459ed84a119197bfb8d8205042c6df63d",
+			Version:  "v0.8.0",
+		},
+		{
+			Name:     "golang.org/x/net",
+			Packages: []string{"context", "http2", "http2/hpack"},
+			Revision: "1c05540f6879653db88113bc4a2b70aec4bd491f",
+			Branch:   "master",
+		},
+		{
+			Name:     "github.com/sirupsen/logrus",
+			Source:   "https://github.com/sirupsen/logrus.git",
+			Packages: []string{"hooks/syslog"},
+			Revision: "d682213848ed68c0a260ca37d6dd5ace8423f5ba",
+			Version:  "v1.0.4",
+		},
+	}
+	if !reflect.DeepEqual(d.Projects, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, d.Projects)
+	}
+}
+
+func TestParseLock(t *testing.T) {
+	tests := []struct {
+		name string
+		lock string
+		err  bool
+	}{
+		{"not a key value", "[[projects]]\n  name\n", true},
+		{"not a string", "[[projects]]\n  name = github.com/pkg/errors\n", true},
+		{"not an array", "[[projects]]\n  packages = \".\"\n", true},
+		{"not a string item", "[[projects]]\n  packages = [context]\n", true},
+		{"unknown keys", "[[projects]]\n  pruneopts = \"UT\"\n  digest = 1\n", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseLock([]byte(test.lock))
+			if test.err && err == nil {
+				t.Errorf("Expected %q to be rejected", test.lock)
+			}
+			if !test.err && err != nil {
+				t.Errorf("Expected %q to be parsed, got %v", test.lock, err)
+			}
+		})
+	}
+}
+
+func TestCommit(t *testing.T) {
+	d, err := FromFile("testdata/Gopkg.lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.MainPackageCommit("example.com/main", "0123456789")
+
+	tests := []struct {
+		pkg    string
+		commit string
+	}{
+		{"example.com/main", "0123456789"},
+		{"example.com/main/sub", "0123456789"},
+		{"example.com/mainly", ""},
+		{"github.com/pkg/errors", "645ef00459ed84a119197bfb8d8205042c6df63d"},
+		{"golang.org/x/net", "1c05540f6879653db88113bc4a2b70aec4bd491f"},
+		{"golang.org/x/net/http2/hpack", "1c05540f6879653db88113bc4a2b70aec4bd491f"},
+		{"golang.org/x/net/html", ""},
+		{"github.com/sirupsen/logrus/hooks/syslog", "d682213848ed68c0a260ca37d6dd5ace8423f5ba"},
+		{"example.com/unknown", ""},
+	}
+	for _, test := range tests {
+		commit, err := d.Commit(test.pkg)
+		if test.commit == "" {
+			if err == nil {
+				t.Errorf("Expected no commit of %v, got %v", test.pkg, commit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unable to find commit of %v: %v", test.pkg, err)
+			continue
+		}
+		if commit != test.commit {
+			t.Errorf("Expected %v to be of %v commit, got %v", test.pkg, test.commit, commit)
+		}
+	}
+}
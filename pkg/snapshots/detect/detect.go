@@ -0,0 +1,48 @@
+package detect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/dep"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/glide"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/godeps"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/govendor"
+)
+
+type lockFile struct {
+	// path relative to the project root
+	path  string
+	build func(file string) (snapshots.MainPackageSnapshot, error)
+}
+
+// lock files in the order of priority
+var lockFiles = []lockFile{
+	{"go.mod", func(file string) (snapshots.MainPackageSnapshot, error) { return gomod.FromFile(file) }},
+	{"glide.lock", func(file string) (snapshots.MainPackageSnapshot, error) { return glide.GlideFromFile(file) }},
+	{"Godeps/Godeps.json", func(file string) (snapshots.MainPackageSnapshot, error) { return godeps.FromFile(file) }},
+	{"Gopkg.lock", func(file string) (snapshots.MainPackageSnapshot, error) { return dep.FromFile(file) }},
+	{"vendor/vendor.json", func(file string) (snapshots.MainPackageSnapshot, error) { return govendor.FromFile(file) }},
+}
+
+// FromProjectRoot finds the first lock file (go.mod, glide.lock, Godeps/Godeps.json,
+// Gopkg.lock, vendor/vendor.json) under the project root and builds its snapshot.
+// The lock file used is returned as well.
+func FromProjectRoot(root string) (snapshots.MainPackageSnapshot, string, error) {
+	for _, lock := range lockFiles {
+		file := filepath.Join(root, filepath.FromSlash(lock.path))
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		sn, err := lock.build(file)
+		if err != nil {
+			return nil, "", err
+		}
+		return sn, file, nil
+	}
+	return nil, "", fmt.Errorf("No go.mod, glide.lock, Godeps/Godeps.json, Gopkg.lock or vendor/vendor.json found under %v", root)
+}
@@ -0,0 +1,117 @@
+package detect
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots/dep"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/glide"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/godeps"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/govendor"
+)
+
+// lock files locking github.com/pkg/errors
+var locks = map[string]string{
+	"go.mod": "module example.com/main\n\nrequire github.com/pkg/errors v0.8.0\n",
+	"glide.lock": `hash: 1b5146add898
+imports:
+- name: github.com/pkg/errors
+  version: 645ef00459ed84a119197bfb8d8205042c6df63d
+`,
+	"Godeps/Godeps.json": `{
+	"ImportPath": "example.com/main",
+	"Deps": [{"ImportPath": "github.com/pkg/errors", "Rev": "645ef00459ed84a119197bfb8d8205042c6df63d"}]
+}`,
+	"Gopkg.lock": `[[projects]]
+  name = "github.com/pkg/errors"
+  packages = ["."]
+  revision = "645ef00459ed84a119197bfb8d8205042c6df63d"
+`,
+	"vendor/vendor.json": `{
+	"package": [{"path": "github.com/pkg/errors", "revision": "645ef00459ed84a119197bfb8d8205042c6df63d"}]
+}`,
+}
+
+// projectRoot creates a project root with the lock files
+func projectRoot(t *testing.T, files ...string) string {
+	root := t.TempDir()
+	for _, file := range files {
+		name := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name, []byte(locks[file]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFromProjectRoot(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		lock     string
+		snapshot interface{}
+	}{
+		{"go.mod", []string{"go.mod"}, "go.mod", &gomod.GoMod{}},
+		{"glide", []string{"glide.lock"}, "glide.lock", &glide.Glide{}},
+		{"godeps", []string{"Godeps/Godeps.json"}, "Godeps/Godeps.json", &godeps.Godeps{}},
+		{"dep", []string{"Gopkg.lock"}, "Gopkg.lock", &dep.Dep{}},
+		{"govendor", []string{"vendor/vendor.json"}, "vendor/vendor.json", &govendor.GoVendor{}},
+		{"go.mod first", []string{"vendor/vendor.json", "Gopkg.lock", "glide.lock", "go.mod"}, "go.mod", &gomod.GoMod{}},
+		{"glide before godeps", []string{"Godeps/Godeps.json", "glide.lock"}, "glide.lock", &glide.Glide{}},
+		{"dep before govendor", []string{"vendor/vendor.json", "Gopkg.lock"}, "Gopkg.lock", &dep.Dep{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := projectRoot(t, test.files...)
+			sn, file, err := FromProjectRoot(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := filepath.Join(root, filepath.FromSlash(test.lock)); file != expected {
+				t.Errorf("Expected %v lock file, got %v", expected, file)
+			}
+			if reflect.TypeOf(sn) != reflect.TypeOf(test.snapshot) {
+				t.Errorf("Expected %T snapshot, got %T", test.snapshot, sn)
+			}
+			// the main package commit is configurable
+			sn.MainPackageCommit("example.com/main", "0123456789")
+			if commit, err := sn.Commit("example.com/main/sub"); err != nil || commit != "0123456789" {
+				t.Errorf("Expected the main package commit, got %q (%v)", commit, err)
+			}
+			if _, err := sn.Commit("github.com/pkg/errors"); err != nil {
+				t.Errorf("Expected github.com/pkg/errors to be locked: %v", err)
+			}
+		})
+	}
+}
+
+func TestFromProjectRootErrors(t *testing.T) {
+	if _, _, err := FromProjectRoot(projectRoot(t)); err == nil {
+		t.Errorf("Expected no lock file to be found")
+	}
+
+	// a directory of a lock file name is skipped
+	root := projectRoot(t, "Gopkg.lock")
+	if err := os.Mkdir(filepath.Join(root, "go.mod"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, file, err := FromProjectRoot(root); err != nil || filepath.Base(file) != "Gopkg.lock" {
+		t.Errorf("Expected Gopkg.lock to be found, got %v (%v)", file, err)
+	}
+
+	// a malformed lock file is reported
+	root = projectRoot(t)
+	if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := FromProjectRoot(root); err == nil {
+		t.Errorf("Expected the malformed go.mod to be reported")
+	}
+}
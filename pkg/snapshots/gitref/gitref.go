@@ -0,0 +1,149 @@
+package gitref
+
+import (
+	"fmt"
+	"go/build"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/gomod"
+	"k8s.io/klog/v2"
+)
+
+var fullCommitRE = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Resolver turns refs (tags, branches, short hashes, semver tags and pseudo-versions)
+// into full commits using local git clones of repositories (no network access needed).
+// A clone of a repository is expected under <dir>/<repository import path>.
+type Resolver struct {
+	dirs []string
+
+	mutex sync.Mutex
+	cache map[string]string
+}
+
+// New creates a resolver looking for clones in the directories (in the given order)
+func New(dirs ...string) *Resolver {
+	return &Resolver{
+		dirs:  dirs,
+		cache: make(map[string]string),
+	}
+}
+
+// FromGOPATH creates a resolver looking for clones in the mirror directory (if set)
+// and in src directories of GOPATH
+func FromGOPATH(mirror string) *Resolver {
+	var dirs []string
+	if mirror != "" {
+		dirs = append(dirs, mirror)
+	}
+	for _, dir := range filepath.SplitList(build.Default.GOPATH) {
+		dirs = append(dirs, filepath.Join(dir, "src"))
+	}
+	return New(dirs...)
+}
+
+// IsFullCommit checks the ref is a full (40 characters long) commit hash
+func IsFullCommit(ref string) bool {
+	return fullCommitRE.MatchString(ref)
+}
+
+// clone finds a git clone of the repository providing the package
+// (the longest package path prefix with .git inside).
+// The package directory relative to the clone is returned as well.
+func (r *Resolver) clone(pkg string) (string, string, error) {
+	for _, dir := range r.dirs {
+		for p := pkg; p != "." && p != "/"; p = path.Dir(p) {
+			repo := filepath.Join(dir, filepath.FromSlash(p))
+			if _, err := os.Stat(filepath.Join(repo, ".git")); err == nil {
+				rel, _ := filepath.Rel(repo, filepath.Join(dir, filepath.FromSlash(pkg)))
+				return repo, filepath.ToSlash(rel), nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("No git clone of %q found in %v", pkg, strings.Join(r.dirs, ", "))
+}
+
+func revParse(repo, ref string) (string, bool) {
+	output, err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(output)), true
+}
+
+// Resolve turns a ref of a repository providing the package into a full commit.
+// A ref can be a full or short commit hash, a tag, a branch (local or of the origin remote),
+// or a module version (a semver tag, incl. <module subdirectory>/<tag> tags, or a pseudo-version).
+func (r *Resolver) Resolve(pkg, ref string) (string, error) {
+	if IsFullCommit(ref) {
+		return ref, nil
+	}
+
+	repo, subdir, err := r.clone(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	key := repo + "@" + ref
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if commit, ok := r.cache[key]; ok {
+		return commit, nil
+	}
+
+	// pseudo-version -> short hash, v1.2.3+incompatible -> v1.2.3
+	short := gomod.VersionToCommit(ref)
+	candidates := []string{
+		ref,
+		short,
+		"refs/tags/" + short,
+		"refs/heads/" + ref,
+		"refs/remotes/origin/" + ref,
+	}
+	// tags of a module in a repository subdirectory are prefixed with the subdirectory
+	for dir := subdir; dir != "." && dir != ""; dir = path.Dir(dir) {
+		candidates = append(candidates, "refs/tags/"+dir+"/"+short)
+	}
+
+	for _, candidate := range candidates {
+		if commit, ok := revParse(repo, candidate); ok {
+			klog.V(2).Infof("Ref %q of %q resolved to %v", ref, pkg, commit)
+			r.cache[key] = commit
+			return commit, nil
+		}
+	}
+	return "", fmt.Errorf("Unable to resolve ref %q of %q in %v", ref, pkg, repo)
+}
+
+// Snapshot resolves commits of a snapshot (e.g. tags of glide.lock or versions of go.mod)
+// into full commits. If a commit can not be resolved (e.g. there is no local clone),
+// the commit of the snapshot is kept.
+type Snapshot struct {
+	snapshot snapshots.Snapshot
+	resolver *Resolver
+}
+
+func NewSnapshot(snapshot snapshots.Snapshot, resolver *Resolver) *Snapshot {
+	return &Snapshot{
+		snapshot: snapshot,
+		resolver: resolver,
+	}
+}
+
+func (s *Snapshot) Commit(pkg string) (string, error) {
+	commit, err := s.snapshot.Commit(pkg)
+	if err != nil {
+		return "", err
+	}
+	if full, err := s.resolver.Resolve(pkg, commit); err == nil {
+		return full, nil
+	}
+	return commit, nil
+}
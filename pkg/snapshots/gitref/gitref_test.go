@@ -0,0 +1,151 @@
+package gitref
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git runs the git command in the repository
+func git(t *testing.T, repo string, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_NOSYSTEM=1", "HOME="+repo,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// clone creates a git clone of github.com/example/repo under the directory with commits:
+// - first tagged v1.0.0, v2.0.0 and sub/v0.1.0
+// - second on the master branch and the feature branch of the origin remote
+func clone(t *testing.T, dir string) (string, string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	repo := filepath.Join(dir, "github.com", "example", "repo")
+	if err := os.MkdirAll(filepath.Join(repo, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	git(t, repo, "init", "-q", "-b", "master")
+	git(t, repo, "commit", "-q", "--allow-empty", "-m", "first")
+	first := git(t, repo, "rev-parse", "HEAD")
+	git(t, repo, "tag", "v1.0.0")
+	git(t, repo, "tag", "-a", "-m", "v2", "v2.0.0")
+	git(t, repo, "tag", "sub/v0.1.0")
+	git(t, repo, "commit", "-q", "--allow-empty", "-m", "second")
+	second := git(t, repo, "rev-parse", "HEAD")
+	git(t, repo, "update-ref", "refs/remotes/origin/feature", second)
+	return first, second
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	first, second := clone(t, dir)
+	r := New(filepath.Join(t.TempDir(), "empty"), dir)
+
+	pseudoVersion := fmt.Sprintf("v0.0.0-20200101000000-%v", first[:12])
+	tests := []struct {
+		name   string
+		pkg    string
+		ref    string
+		commit string
+	}{
+		{"full commit", "example.com/not/cloned", second, second},
+		{"short commit", "github.com/example/repo", first[:7], first},
+		{"tag", "github.com/example/repo", "v1.0.0", first},
+		{"annotated tag", "github.com/example/repo", "v2.0.0", first},
+		{"incompatible version", "github.com/example/repo", "v2.0.0+incompatible", first},
+		{"pseudo-version", "github.com/example/repo", pseudoVersion, first},
+		{"branch", "github.com/example/repo", "master", second},
+		{"origin branch", "github.com/example/repo", "feature", second},
+		{"tag of a module subdirectory", "github.com/example/repo/sub/pkg", "v0.1.0", first},
+		{"unknown ref", "github.com/example/repo", "v3.0.0", ""},
+		{"no clone", "github.com/example/other", "v1.0.0", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commit, err := r.Resolve(test.pkg, test.ref)
+			if test.commit == "" {
+				if err == nil {
+					t.Errorf("Expected %v of %v not to be resolved, got %v", test.ref, test.pkg, commit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commit != test.commit {
+				t.Errorf("Expected %v commit, got %v", test.commit, commit)
+			}
+		})
+	}
+}
+
+// lock is a snapshot of locked refs
+type lock map[string]string
+
+func (l lock) Commit(pkg string) (string, error) {
+	if commit, ok := l[pkg]; ok {
+		return commit, nil
+	}
+	return "", fmt.Errorf("Commit for package %q not found", pkg)
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	first, _ := clone(t, dir)
+	s := NewSnapshot(lock{
+		"github.com/example/repo":  "v1.0.0",
+		"github.com/example/other": "v1.0.0",
+	}, New(dir))
+
+	tests := []struct {
+		pkg    string
+		commit string
+	}{
+		{"github.com/example/repo", first},
+		// no clone, the ref is kept
+		{"github.com/example/other", "v1.0.0"},
+		{"github.com/example/unknown", ""},
+	}
+	for _, test := range tests {
+		commit, err := s.Commit(test.pkg)
+		if test.commit == "" {
+			if err == nil {
+				t.Errorf("Expected no commit of %v, got %v", test.pkg, commit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unable to find commit of %v: %v", test.pkg, err)
+			continue
+		}
+		if commit != test.commit {
+			t.Errorf("Expected %v to be of %v commit, got %v", test.pkg, test.commit, commit)
+		}
+	}
+}
+
+func TestIsFullCommit(t *testing.T) {
+	tests := map[string]bool{
+		"1c05540f6879653db88113bc4a2b70aec4bd491f":  true,
+		"1C05540F6879653DB88113BC4A2B70AEC4BD491F":  false,
+		"1c05540f6879653db88113bc4a2b70aec4bd491f0": false,
+		"1c05540f": false,
+		"v1.0.0":   false,
+	}
+	for ref, full := range tests {
+		if actual := IsFullCommit(ref); actual != full {
+			t.Errorf("Expected %v to be a full commit: %v, got %v", ref, full, actual)
+		}
+	}
+}
@@ -50,7 +50,7 @@ func GlideFromFile(file string) (*Glide, error) {
 }
 
 func (g *Glide) Commit(pkg string) (string, error) {
-	if g.mainPkg != "" && g.mainPkgCommit != "" && strings.HasPrefix(pkg, g.mainPkg) {
+	if g.mainPkg != "" && g.mainPkgCommit != "" && (pkg == g.mainPkg || strings.HasPrefix(pkg, g.mainPkg+"/")) {
 		return g.mainPkgCommit, nil
 	}
 	if commit, ok := g.importsList[pkg]; ok {
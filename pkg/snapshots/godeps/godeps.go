@@ -46,7 +46,7 @@ func FromFile(file string) (*Godeps, error) {
 }
 
 func (g *Godeps) Commit(pkg string) (string, error) {
-	if g.mainPkg != "" && g.mainPkgCommit != "" && strings.HasPrefix(pkg, g.mainPkg) {
+	if g.mainPkg != "" && g.mainPkgCommit != "" && (pkg == g.mainPkg || strings.HasPrefix(pkg, g.mainPkg+"/")) {
 		return g.mainPkgCommit, nil
 	}
 	if commit, ok := g.importsList[pkg]; ok {
@@ -0,0 +1,227 @@
+package gomod
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module is a module required by the main module (after replacements are applied)
+type Module struct {
+	// Module path the packages are imported from
+	Path string
+	// Module path the packages are actually provided by (differs from Path if replaced)
+	Replacement string
+	// Version of the module (empty if replaced by a local directory)
+	Version string
+	// Local directory the module is replaced with (relative paths are resolved against the go.mod directory)
+	Dir string
+	// go.sum hash of the module (if available)
+	Sum string
+	// Is the required version excluded?
+	Excluded bool
+}
+
+type GoMod struct {
+	// Main module path
+	ModulePath string
+	GoVersion  string
+	Requires   []*modfile.Require
+	Replaces   []*modfile.Replace
+	Excludes   []*modfile.Exclude
+	// go.sum entries in a "path@version" -> hash form
+	Sums map[string]string
+
+	// directory with the go.mod file
+	dir           string
+	modules       map[string]*Module
+	excluded      map[string]struct{}
+	mainPkg       string
+	mainPkgCommit string
+}
+
+// FromFile reads go.mod file and go.sum file next to it (if present)
+func FromFile(file string) (*GoMod, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load file %v: %v", file, err)
+	}
+
+	f, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse file %v: %v", file, err)
+	}
+
+	gomod := &GoMod{
+		Requires: f.Require,
+		Replaces: f.Replace,
+		Excludes: f.Exclude,
+		Sums:     make(map[string]string),
+		dir:      path.Dir(file),
+		modules:  make(map[string]*Module),
+		excluded: make(map[string]struct{}),
+	}
+	if f.Module != nil {
+		gomod.ModulePath = f.Module.Mod.Path
+	}
+	if f.Go != nil {
+		gomod.GoVersion = f.Go.Version
+	}
+
+	sumfile := path.Join(gomod.dir, "go.sum")
+	if _, err := os.Stat(sumfile); err == nil {
+		if err := gomod.readSums(sumfile); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range gomod.Excludes {
+		gomod.excluded[item.Mod.String()] = struct{}{}
+	}
+
+	for _, item := range gomod.Requires {
+		gomod.modules[item.Mod.Path] = gomod.resolve(item.Mod.Path, item.Mod.Version)
+	}
+
+	// Dependencies of dependencies are not always listed in go.mod (e.g. go < 1.17).
+	// Use go.sum to resolve them as long as there is a single version listed.
+	versions := make(map[string][]string)
+	for key := range gomod.Sums {
+		parts := strings.Split(key, "@")
+		if strings.HasSuffix(parts[1], "/go.mod") {
+			continue
+		}
+		versions[parts[0]] = append(versions[parts[0]], parts[1])
+	}
+	for modulePath, list := range versions {
+		if _, ok := gomod.modules[modulePath]; ok || len(list) != 1 {
+			continue
+		}
+		gomod.modules[modulePath] = gomod.resolve(modulePath, list[0])
+	}
+
+	return gomod, nil
+}
+
+func (g *GoMod) readSums(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Unable to load file %v: %v", file, err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return fmt.Errorf("Malformed go.sum line in %v: %q", file, scanner.Text())
+		}
+		g.Sums[fmt.Sprintf("%v@%v", fields[0], fields[1])] = fields[2]
+	}
+	return scanner.Err()
+}
+
+// resolve applies replace directives on a required module
+func (g *GoMod) resolve(modulePath, version string) *Module {
+	mod := &Module{
+		Path:        modulePath,
+		Replacement: modulePath,
+		Version:     version,
+	}
+	if _, ok := g.excluded[fmt.Sprintf("%v@%v", modulePath, version)]; ok {
+		mod.Excluded = true
+	}
+
+	// A replacement of a specific version has a priority over a replacement of all versions
+	var replace *modfile.Replace
+	for _, item := range g.Replaces {
+		if item.Old.Path != modulePath {
+			continue
+		}
+		if item.Old.Version == version {
+			replace = item
+			break
+		}
+		if item.Old.Version == "" {
+			replace = item
+		}
+	}
+
+	if replace != nil {
+		if replace.New.Version == "" {
+			// replaced by a local directory (relative to the go.mod file)
+			mod.Replacement = ""
+			mod.Version = ""
+			mod.Dir = replace.New.Path
+			if !path.IsAbs(mod.Dir) {
+				mod.Dir = path.Join(g.dir, mod.Dir)
+			}
+			return mod
+		}
+		mod.Replacement = replace.New.Path
+		mod.Version = replace.New.Version
+	}
+
+	mod.Sum = g.Sums[fmt.Sprintf("%v@%v", mod.Replacement, mod.Version)]
+	return mod
+}
+
+// Lookup finds a module providing a package (by the longest module path prefix)
+func (g *GoMod) Lookup(pkg string) (*Module, error) {
+	var found *Module
+	for modulePath, mod := range g.modules {
+		if pkg != modulePath && !strings.HasPrefix(pkg, modulePath+"/") {
+			continue
+		}
+		if found == nil || len(modulePath) > len(found.Path) {
+			found = mod
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("Module for package %q not found", pkg)
+	}
+	return found, nil
+}
+
+func (g *GoMod) Commit(pkg string) (string, error) {
+	if g.mainPkg != "" && g.mainPkgCommit != "" && (pkg == g.mainPkg || strings.HasPrefix(pkg, g.mainPkg+"/")) {
+		return g.mainPkgCommit, nil
+	}
+	mod, err := g.Lookup(pkg)
+	if err != nil {
+		return "", fmt.Errorf("Commit for package %q not found", pkg)
+	}
+	if mod.Dir != "" {
+		return "", fmt.Errorf("Commit for package %q not found, module %q replaced by %q directory", pkg, mod.Path, mod.Dir)
+	}
+	if mod.Excluded {
+		return "", fmt.Errorf("Commit for package %q not found, module %q version is excluded", pkg, mod.Path)
+	}
+	return VersionToCommit(mod.Version), nil
+}
+
+func (g *GoMod) MainPackageCommit(pkg string, commit string) {
+	g.mainPkg = pkg
+	g.mainPkgCommit = commit
+}
+
+// E.g. v0.0.0-20191011141410-1b5146add898, v1.2.4-0.20191011141410-1b5146add898 or v1.2.3-pre.0.20191011141410-1b5146add898
+var pseudoVersionRE = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-([A-Za-z0-9]+)(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// VersionToCommit turns a module version into a commit.
+// A pseudo-version is turned into its revision (e.g. 1b5146add898).
+// A release version is turned into its tag (without +incompatible suffix).
+func VersionToCommit(version string) string {
+	if m := pseudoVersionRE.FindStringSubmatch(version); m != nil {
+		return m[3]
+	}
+	return strings.TrimSuffix(version, "+incompatible")
+}
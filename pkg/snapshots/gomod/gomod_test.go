@@ -0,0 +1,142 @@
+package gomod
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionToCommit(t *testing.T) {
+	tests := map[string]string{
+		"v1.2.3":                                   "v1.2.3",
+		"v2.0.0+incompatible":                      "v2.0.0",
+		"v0.0.0-20191011141410-1b5146add898":       "1b5146add898",
+		"v1.2.4-0.20191011141410-1b5146add898":     "1b5146add898",
+		"v1.2.3-pre.0.20191011141410-1b5146add898": "1b5146add898",
+		"v1.2.3-pre":                               "v1.2.3-pre",
+	}
+	for version, commit := range tests {
+		if actual := VersionToCommit(version); actual != commit {
+			t.Errorf("Expected %v to be turned into %v, got %v", version, commit, actual)
+		}
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	g, err := FromFile("testdata/go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.ModulePath != "example.com/main" || g.GoVersion != "1.20" {
+		t.Errorf("Expected example.com/main module of go 1.20, got %v module of go %v", g.ModulePath, g.GoVersion)
+	}
+
+	tests := []struct {
+		name   string
+		pkg    string
+		module *Module
+	}{
+		{
+			name:   "required module",
+			pkg:    "github.com/pkg/errors",
+			module: &Module{Path: "github.com/pkg/errors", Replacement: "github.com/pkg/errors", Version: "v0.9.1", Sum: "h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4="},
+		},
+		{
+			name:   "package of a module",
+			pkg:    "golang.org/x/net/context",
+			module: &Module{Path: "golang.org/x/net", Replacement: "golang.org/x/net", Version: "v0.0.0-20191011141410-1b5146add898"},
+		},
+		{
+			name:   "longest module path",
+			pkg:    "github.com/google/go-cmp/cmp/extra/sub",
+			module: &Module{Path: "github.com/google/go-cmp/cmp/extra", Replacement: "github.com/google/go-cmp/cmp/extra", Version: "v1.0.0"},
+		},
+		{
+			name:   "module path is not a prefix of a path component",
+			pkg:    "github.com/pkg/errorsx",
+			module: nil,
+		},
+		{
+			name: "replaced by a directory",
+			pkg:  "example.com/local/sub",
+			// ../local relative to the testdata directory
+			module: &Module{Path: "example.com/local", Dir: "local"},
+		},
+		{
+			name:   "replacement of the version wins",
+			pkg:    "example.com/forked",
+			module: &Module{Path: "example.com/forked", Replacement: "example.com/fork", Version: "v1.1.1", Sum: "h1:forkhash="},
+		},
+		{
+			name:   "excluded version",
+			pkg:    "example.com/excluded",
+			module: &Module{Path: "example.com/excluded", Replacement: "example.com/excluded", Version: "v1.0.0", Excluded: true},
+		},
+		{
+			name:   "dependency listed in go.sum only",
+			pkg:    "example.com/indirect/sub",
+			module: &Module{Path: "example.com/indirect", Replacement: "example.com/indirect", Version: "v0.3.0", Sum: "h1:indirecthash="},
+		},
+		{
+			name:   "several versions listed in go.sum",
+			pkg:    "example.com/ambiguous",
+			module: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			module, err := g.Lookup(test.pkg)
+			if test.module == nil {
+				if err == nil {
+					t.Errorf("Expected no module of %v, got %#v", test.pkg, module)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(module, test.module) {
+				t.Errorf("Expected %#v, got %#v", test.module, module)
+			}
+		})
+	}
+}
+
+func TestCommit(t *testing.T) {
+	g, err := FromFile("testdata/go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.MainPackageCommit("example.com/main", "0123456789")
+
+	tests := []struct {
+		pkg    string
+		commit string
+	}{
+		{"example.com/main", "0123456789"},
+		{"example.com/main/sub", "0123456789"},
+		{"example.com/mainly", ""},
+		{"github.com/pkg/errors", "v0.9.1"},
+		{"golang.org/x/net/context", "1b5146add898"},
+		{"github.com/stretchr/testify/assert", "v1.3.0"},
+		{"example.com/forked", "v1.1.1"},
+		{"example.com/local", ""},
+		{"example.com/excluded", ""},
+		{"example.com/unknown", ""},
+	}
+	for _, test := range tests {
+		commit, err := g.Commit(test.pkg)
+		if test.commit == "" {
+			if err == nil {
+				t.Errorf("Expected no commit of %v, got %v", test.pkg, commit)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unable to find commit of %v: %v", test.pkg, err)
+			continue
+		}
+		if commit != test.commit {
+			t.Errorf("Expected %v to be of %v commit, got %v", test.pkg, test.commit, commit)
+		}
+	}
+}
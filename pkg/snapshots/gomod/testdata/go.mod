@@ -0,0 +1,23 @@
+module example.com/main
+
+go 1.20
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/net v0.0.0-20191011141410-1b5146add898
+	gopkg.in/yaml.v2 v2.4.0
+	github.com/google/go-cmp v0.5.0
+	github.com/google/go-cmp/cmp/extra v1.0.0
+	github.com/stretchr/testify v1.3.0+incompatible
+	example.com/local v1.0.0
+	example.com/forked v1.1.0
+	example.com/excluded v1.0.0
+)
+
+replace (
+	example.com/local => ../local
+	example.com/forked v1.1.0 => example.com/fork v1.1.1
+	example.com/forked => example.com/other v2.0.0
+)
+
+exclude example.com/excluded v1.0.0
@@ -0,0 +1,84 @@
+package govendor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Package is a vendored package of the vendor.json
+type Package struct {
+	Path         string `json:"path"`
+	Origin       string `json:"origin"`
+	Revision     string `json:"revision"`
+	RevisionTime string `json:"revisionTime"`
+	Version      string `json:"version"`
+	VersionExact string `json:"versionExact"`
+	ChecksumSHA1 string `json:"checksumSHA1"`
+	// All packages under the path are vendored
+	Tree bool `json:"tree"`
+}
+
+type GoVendor struct {
+	Comment  string    `json:"comment"`
+	Ignore   string    `json:"ignore"`
+	RootPath string    `json:"rootPath"`
+	Package  []Package `json:"package"`
+
+	importsList   map[string]string
+	treesList     map[string]string
+	mainPkg       string
+	mainPkgCommit string
+}
+
+// FromFile reads vendor/vendor.json file generated by govendor
+func FromFile(file string) (*GoVendor, error) {
+	text, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load file %v: %v", file, err)
+	}
+
+	var govendor GoVendor
+	if err := json.Unmarshal(text, &govendor); err != nil {
+		return nil, fmt.Errorf("Unable to parse file %v: %v", file, err)
+	}
+
+	govendor.importsList = make(map[string]string)
+	govendor.treesList = make(map[string]string)
+	for _, item := range govendor.Package {
+		govendor.importsList[item.Path] = item.Revision
+		if item.Tree {
+			govendor.treesList[item.Path] = item.Revision
+		}
+	}
+
+	return &govendor, nil
+}
+
+func (g *GoVendor) Commit(pkg string) (string, error) {
+	if g.mainPkg != "" && g.mainPkgCommit != "" && (pkg == g.mainPkg || strings.HasPrefix(pkg, g.mainPkg+"/")) {
+		return g.mainPkgCommit, nil
+	}
+	if commit, ok := g.importsList[pkg]; ok {
+		return commit, nil
+	}
+	// the longest vendored tree the package is part of
+	found := ""
+	for tree := range g.treesList {
+		if strings.HasPrefix(pkg, tree+"/") && len(tree) > len(found) {
+			found = tree
+		}
+	}
+	if found != "" {
+		return g.treesList[found], nil
+	}
+	return "", fmt.Errorf("Commit for package %q not found", pkg)
+}
+
+func (g *GoVendor) MainPackageCommit(pkg string, commit string) {
+	g.mainPkg = pkg
+	g.mainPkgCommit = commit
+
+	g.importsList[pkg] = commit
+}
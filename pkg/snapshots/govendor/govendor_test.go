@@ -0,0 +1,50 @@
+package govendor
+
+import (
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	g, err := FromFile("testdata/vendor.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.RootPath != "example.com/main" || len(g.Package) != 4 {
+		t.Errorf("Expected 4 packages of example.com/main, got %v packages of %v", len(g.Package), g.RootPath)
+	}
+	g.MainPackageCommit("example.com/main", "0123456789")
+
+	tests := []struct {
+		name   string
+		pkg    string
+		commit string
+	}{
+		{"main package", "example.com/main", "0123456789"},
+		{"main package subpackage", "example.com/main/sub", "0123456789"},
+		{"main package prefix of another package", "example.com/mainly", ""},
+		{"vendored package", "github.com/pkg/errors", "645ef00459ed84a119197bfb8d8205042c6df63d"},
+		{"subpackage of a vendored package", "github.com/pkg/errors/sub", ""},
+		{"parent of a vendored package", "golang.org/x/net", ""},
+		{"vendored tree", "github.com/golang/protobuf", "130e6b02ab059e7b717a096f397c5b60111cae74"},
+		{"package of a vendored tree", "github.com/golang/protobuf/proto", "130e6b02ab059e7b717a096f397c5b60111cae74"},
+		{"package vendored within a tree", "github.com/golang/protobuf/ptypes/any", "1e59b77b52bf8e4b449a57e6f79f21226d571845"},
+		{"tree is not a prefix of a path component", "github.com/golang/protobufx", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commit, err := g.Commit(test.pkg)
+			if test.commit == "" {
+				if err == nil {
+					t.Errorf("Expected no commit of %v, got %v", test.pkg, commit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commit != test.commit {
+				t.Errorf("Expected %v commit, got %v", test.commit, commit)
+			}
+		})
+	}
+}
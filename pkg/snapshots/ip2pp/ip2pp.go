@@ -0,0 +1,156 @@
+package ip2pp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"k8s.io/klog/v2"
+)
+
+// Entry maps an import path prefix to a prefix of the repository providing it
+type Entry struct {
+	IPPrefix       string `json:"ipprefix"`
+	ProviderPrefix string `json:"provider_prefix"`
+}
+
+// Mapping of import path prefixes to provider prefixes (e.g. ip2pp_mapping.json)
+type Mapping []Entry
+
+func MappingFromFile(file string) (Mapping, error) {
+	text, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load file %v: %v", file, err)
+	}
+
+	var mapping Mapping
+	if err := json.Unmarshal(text, &mapping); err != nil {
+		return nil, fmt.Errorf("Unable to parse file %v: %v", file, err)
+	}
+	return mapping, nil
+}
+
+// hasPrefix checks the prefix is the pkg or its parent directory
+func hasPrefix(pkg, prefix string) bool {
+	return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+}
+
+// E.g. gopkg.in/yaml.v2/sub, gopkg.in/square/go-jose.v2/sub
+var gopkginRE = regexp.MustCompile(`^gopkg\.in/(?:([^/.]+)/)?([^/.]+)\.v[0-9]+(/.*)?$`)
+
+// E.g. golang.org/x/net/context
+var golangxRE = regexp.MustCompile(`^golang\.org/x/([^/]+)(/.*)?$`)
+
+// vanity turns a vanity import path into the import path of its repository
+func vanity(pkg string) string {
+	if m := gopkginRE.FindStringSubmatch(pkg); m != nil {
+		user := m[1]
+		if user == "" {
+			user = "go-" + m[2]
+		}
+		return "github.com/" + user + "/" + m[2] + m[3]
+	}
+	if m := golangxRE.FindStringSubmatch(pkg); m != nil {
+		return "github.com/golang/" + m[1] + m[2]
+	}
+	return ""
+}
+
+// Snapshot resolves commits of packages imported under a different path than their
+// snapshot entry (e.g. a vanity import path, a provider path of a mapped import path
+// or a subpackage of a locked repository).
+type Snapshot struct {
+	snapshot snapshots.Snapshot
+	mapping  Mapping
+}
+
+// New wraps a snapshot with the resolver. The mapping can be nil.
+func New(snapshot snapshots.Snapshot, mapping Mapping) *Snapshot {
+	return &Snapshot{
+		snapshot: snapshot,
+		mapping:  mapping,
+	}
+}
+
+// aliases lists import paths the package is provided under directly (through the mapping or a vanity rule)
+func (s *Snapshot) aliases(pkg string) []string {
+	var paths []string
+
+	// import path prefix -> provider prefix (the longest prefix wins)
+	var found *Entry
+	for i, entry := range s.mapping {
+		if hasPrefix(pkg, entry.IPPrefix) && (found == nil || len(entry.IPPrefix) > len(found.IPPrefix)) {
+			found = &s.mapping[i]
+		}
+	}
+	if found != nil {
+		paths = append(paths, found.ProviderPrefix+strings.TrimPrefix(pkg, found.IPPrefix))
+	}
+
+	if v := vanity(pkg); v != "" {
+		paths = append(paths, v)
+	}
+
+	// provider prefix -> import path prefixes (all of the longest provider prefix)
+	longest := ""
+	for _, entry := range s.mapping {
+		if hasPrefix(pkg, entry.ProviderPrefix) && len(entry.ProviderPrefix) > len(longest) {
+			longest = entry.ProviderPrefix
+		}
+	}
+	if longest != "" {
+		for _, entry := range s.mapping {
+			if entry.ProviderPrefix == longest {
+				paths = append(paths, entry.IPPrefix+strings.TrimPrefix(pkg, longest))
+			}
+		}
+	}
+
+	return paths
+}
+
+// importPaths lists all import paths the package can be locked under (the package itself first),
+// e.g. code.google.com/p/go.net -> github.com/golang/net -> golang.org/x/net
+func (s *Snapshot) importPaths(pkg string) []string {
+	paths := []string{pkg}
+	seen := map[string]struct{}{pkg: {}}
+	for i := 0; i < len(paths); i++ {
+		for _, alias := range s.aliases(paths[i]) {
+			if _, ok := seen[alias]; !ok {
+				seen[alias] = struct{}{}
+				paths = append(paths, alias)
+			}
+		}
+	}
+	return paths
+}
+
+// Locate finds the import path the package is locked under (of any import path of the package
+// with the longest locked prefix, i.e. repository root) and the commit of the path.
+// Packages imported under different paths of the same locked repository are located under the same path.
+func (s *Snapshot) Locate(pkg string) (string, string, error) {
+	for _, ip := range s.importPaths(pkg) {
+		// host/path at least
+		for p := ip; strings.Contains(p, "/"); p = path.Dir(p) {
+			commit, err := s.snapshot.Commit(p)
+			if err != nil {
+				continue
+			}
+			if p != pkg {
+				klog.V(2).Infof("Commit of %q resolved through %q", pkg, p)
+			}
+			return ip, commit, nil
+		}
+	}
+	return "", "", fmt.Errorf("Commit for package %q not found", pkg)
+}
+
+// Commit finds a commit of the longest locked prefix (repository root) of any import path of the package
+func (s *Snapshot) Commit(pkg string) (string, error) {
+	_, commit, err := s.Locate(pkg)
+	return commit, err
+}
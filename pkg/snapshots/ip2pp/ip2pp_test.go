@@ -0,0 +1,125 @@
+package ip2pp
+
+import (
+	"fmt"
+	"testing"
+)
+
+// lock is a snapshot of locked repository roots
+type lock map[string]string
+
+func (l lock) Commit(pkg string) (string, error) {
+	if commit, ok := l[pkg]; ok {
+		return commit, nil
+	}
+	return "", fmt.Errorf("Commit for package %q not found", pkg)
+}
+
+func TestMappingFromFile(t *testing.T) {
+	for _, file := range []string{"testdata/ip2pp_mapping.json", "../../../ip2pp_mapping.json"} {
+		mapping, err := MappingFromFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mapping) == 0 {
+			t.Errorf("Expected %v mapping to have entries", file)
+		}
+		for _, entry := range mapping {
+			if entry.IPPrefix == "" || entry.ProviderPrefix == "" {
+				t.Errorf("Expected %v mapping entries to have both prefixes, got %#v", file, entry)
+			}
+		}
+	}
+}
+
+func TestCommit(t *testing.T) {
+	mapping, err := MappingFromFile("testdata/ip2pp_mapping.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(lock{
+		"github.com/pkg/errors":            "errors",
+		"github.com/go-yaml/yaml":          "yaml",
+		"github.com/square/go-jose":        "jose",
+		"code.google.com/p/go.net":         "gonet",
+		"github.com/kubernetes/kubernetes": "kubernetes",
+		"github.com/kubernetes/staging":    "staging",
+		"bitbucket.org/ww/goautoneg":       "goautoneg",
+	}, mapping)
+
+	tests := []struct {
+		name   string
+		pkg    string
+		commit string
+	}{
+		{"locked package", "github.com/pkg/errors", "errors"},
+		{"package of a locked repository", "github.com/pkg/errors/sub", "errors"},
+		{"locked repository is not a prefix of a path component", "github.com/pkg/errorsx", ""},
+		{"gopkg.in", "gopkg.in/yaml.v2", "yaml"},
+		{"gopkg.in of a user", "gopkg.in/square/go-jose.v2/jwt", "jose"},
+		{"golang.org/x through its provider and the mapping", "golang.org/x/net/context", "gonet"},
+		{"import path to provider", "k8s.io/kubernetes/pkg/api", "kubernetes"},
+		{"longest import path prefix", "k8s.io/kubernetes/staging/src/k8s.io/api", "staging"},
+		{"import path prefix is not a prefix of a path component", "k8s.io/kubernetesx/pkg", ""},
+		{"provider to import path", "github.com/munnerz/goautoneg", "goautoneg"},
+		{"unknown package", "example.com/unknown", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			commit, err := s.Commit(test.pkg)
+			if test.commit == "" {
+				if err == nil {
+					t.Errorf("Expected no commit of %v, got %v", test.pkg, commit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commit != test.commit {
+				t.Errorf("Expected %v commit, got %v", test.commit, commit)
+			}
+		})
+	}
+}
+
+func TestCommitWithNoMapping(t *testing.T) {
+	s := New(lock{"github.com/golang/net": "net"}, nil)
+	if commit, err := s.Commit("golang.org/x/net/context"); err != nil || commit != "net" {
+		t.Errorf("Expected golang.org/x/net/context of net commit, got %q (%v)", commit, err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	mapping, err := MappingFromFile("testdata/ip2pp_mapping.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(lock{
+		"github.com/golang/net":            "net",
+		"github.com/kubernetes/kubernetes": "kubernetes",
+	}, mapping)
+
+	tests := []struct {
+		pkg string
+		ip  string
+	}{
+		{"github.com/golang/net/context", "github.com/golang/net/context"},
+		{"golang.org/x/net/context", "github.com/golang/net/context"},
+		{"code.google.com/p/go.net/context", "github.com/golang/net/context"},
+		{"k8s.io/kubernetes/pkg/api", "github.com/kubernetes/kubernetes/pkg/api"},
+		{"github.com/kubernetes/kubernetes/pkg/api", "github.com/kubernetes/kubernetes/pkg/api"},
+	}
+	for _, test := range tests {
+		ip, commit, err := s.Locate(test.pkg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ip != test.ip || commit == "" {
+			t.Errorf("Expected %v to be located under %v, got %v (commit %q)", test.pkg, test.ip, ip, commit)
+		}
+	}
+	if ip, _, err := s.Locate("example.com/unknown"); err == nil {
+		t.Errorf("Expected example.com/unknown not to be located, got %v", ip)
+	}
+}
@@ -3,3 +3,9 @@ package snapshots
 type Snapshot interface {
 	Commit(pkg string) (string, error)
 }
+
+// MainPackageSnapshot is a snapshot with a configurable commit of the main (processed) project
+type MainPackageSnapshot interface {
+	Snapshot
+	MainPackageCommit(pkg string, commit string)
+}
@@ -0,0 +1,234 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The binary encoding is a compact form of a JSON document. The document is stored
+// as a sequence of tokens, each string (incl. object keys) is stored only once
+// and referenced by its index afterwards. As the artefacts repeat the same package paths,
+// identifiers and entry names over and over, the encoding is several times smaller than JSON.
+var binaryMagic = []byte("GOFEDBIN\x01")
+
+const (
+	binNull byte = iota
+	binFalse
+	binTrue
+	// zigzag encoded varint
+	binInt
+	// number in its JSON form (stored as a string)
+	binNumber
+	// new string (uvarint length and bytes)
+	binString
+	// already stored string (uvarint index)
+	binStringRef
+	binArray
+	binObject
+	// end of the last array or object
+	binEnd
+)
+
+// EncodeBinary encodes a JSON document into the binary encoding
+func EncodeBinary(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	interned := make(map[string]uint64)
+	varint := make([]byte, binary.MaxVarintLen64)
+
+	writeString := func(s string) {
+		if index, ok := interned[s]; ok {
+			buf.WriteByte(binStringRef)
+			buf.Write(varint[:binary.PutUvarint(varint, index)])
+			return
+		}
+		interned[s] = uint64(len(interned))
+		buf.WriteByte(binString)
+		buf.Write(varint[:binary.PutUvarint(varint, uint64(len(s)))])
+		buf.WriteString(s)
+	}
+
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Unable to encode JSON: %v", err)
+		}
+		switch t := token.(type) {
+		case nil:
+			buf.WriteByte(binNull)
+		case bool:
+			if t {
+				buf.WriteByte(binTrue)
+			} else {
+				buf.WriteByte(binFalse)
+			}
+		case json.Number:
+			if i, err := strconv.ParseInt(string(t), 10, 64); err == nil && strconv.FormatInt(i, 10) == string(t) {
+				buf.WriteByte(binInt)
+				buf.Write(varint[:binary.PutVarint(varint, i)])
+				continue
+			}
+			buf.WriteByte(binNumber)
+			writeString(string(t))
+		case string:
+			writeString(t)
+		case json.Delim:
+			switch t {
+			case '[':
+				buf.WriteByte(binArray)
+			case '{':
+				buf.WriteByte(binObject)
+			default:
+				buf.WriteByte(binEnd)
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeBinary decodes the binary encoding into a JSON document
+func DecodeBinary(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, binaryMagic) {
+		return nil, fmt.Errorf("Not a binary artefact")
+	}
+	r := bufio.NewReader(bytes.NewReader(data[len(binaryMagic):]))
+
+	var out bytes.Buffer
+	var interned []string
+	// for each open array or object: is it an object, number of its items (incl. keys)
+	type level struct {
+		object bool
+		items  int
+	}
+	var levels []level
+
+	readString := func(tag byte) (string, error) {
+		switch tag {
+		case binString:
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return "", err
+			}
+			s := make([]byte, length)
+			if _, err := io.ReadFull(r, s); err != nil {
+				return "", err
+			}
+			interned = append(interned, string(s))
+			return string(s), nil
+		case binStringRef:
+			index, err := binary.ReadUvarint(r)
+			if err != nil {
+				return "", err
+			}
+			if index >= uint64(len(interned)) {
+				return "", fmt.Errorf("Invalid string reference %v", index)
+			}
+			return interned[index], nil
+		}
+		return "", fmt.Errorf("Expected a string, got %v tag", tag)
+	}
+
+	for {
+		tag, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tag == binEnd {
+			if len(levels) == 0 {
+				return nil, fmt.Errorf("Unexpected end of an array or object")
+			}
+			if levels[len(levels)-1].object {
+				out.WriteByte('}')
+			} else {
+				out.WriteByte(']')
+			}
+			levels = levels[:len(levels)-1]
+			if len(levels) > 0 {
+				levels[len(levels)-1].items++
+			}
+			continue
+		}
+
+		// separator of the value
+		key := false
+		if len(levels) > 0 {
+			l := levels[len(levels)-1]
+			switch {
+			case l.object && l.items%2 == 1:
+				out.WriteByte(':')
+			case l.items > 0:
+				out.WriteByte(',')
+			}
+			key = l.object && l.items%2 == 0
+		}
+
+		switch tag {
+		case binNull:
+			out.WriteString("null")
+		case binFalse:
+			out.WriteString("false")
+		case binTrue:
+			out.WriteString("true")
+		case binInt:
+			i, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(strconv.FormatInt(i, 10))
+		case binNumber:
+			t, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			s, err := readString(t)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(s)
+		case binString, binStringRef:
+			s, err := readString(tag)
+			if err != nil {
+				return nil, err
+			}
+			quoted, err := json.Marshal(s)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(quoted)
+		case binArray:
+			out.WriteByte('[')
+			levels = append(levels, level{})
+			continue
+		case binObject:
+			out.WriteByte('{')
+			levels = append(levels, level{object: true})
+			continue
+		default:
+			return nil, fmt.Errorf("Unknown tag %v", tag)
+		}
+		if key && tag != binString && tag != binStringRef {
+			return nil, fmt.Errorf("Expected an object key, got %v tag", tag)
+		}
+		if len(levels) > 0 {
+			levels[len(levels)-1].items++
+		}
+	}
+	if len(levels) != 0 {
+		return nil, fmt.Errorf("Unexpected end of a binary artefact")
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Directory stores each artefact in a file under the root directory (see Key.Path)
+type Directory struct {
+	root string
+}
+
+func NewDirectory(root string) *Directory {
+	return &Directory{root: root}
+}
+
+func (d *Directory) file(key Key) string {
+	return filepath.Join(d.root, filepath.FromSlash(key.Path()))
+}
+
+func (d *Directory) Exists(key Key) bool {
+	if key.Kind == AnyKind {
+		info, err := os.Stat(filepath.Join(d.root, filepath.FromSlash(key.Dir())))
+		return err == nil && info.IsDir()
+	}
+	_, err := os.Stat(d.file(key))
+	return err == nil
+}
+
+func (d *Directory) Load(key Key) ([]byte, error) {
+	return ioutil.ReadFile(d.file(key))
+}
+
+func (d *Directory) Save(key Key, data []byte) error {
+	dir := filepath.Join(d.root, filepath.FromSlash(key.Dir()))
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return fmt.Errorf("Unable to create package path %v: %v", dir, err)
+	}
+	return ioutil.WriteFile(d.file(key), data, 0644)
+}
+
+func (d *Directory) Drop(key Key) error {
+	kinds := []Kind{key.Kind}
+	if key.Kind == AnyKind {
+		kinds = Kinds
+	}
+	for _, kind := range kinds {
+		key.Kind = kind
+		if err := os.Remove(d.file(key)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Directory) List(pkg string) ([]Key, error) {
+	var keys []Key
+	var add func(dir string, depth int) error
+	add = func(dir string, depth int) error {
+		entries, err := ioutil.ReadDir(filepath.Join(d.root, filepath.FromSlash(dir)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, entry := range entries {
+			rel := path.Join(dir, entry.Name())
+			if entry.IsDir() {
+				if depth > 0 {
+					if err := add(rel, depth-1); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if key, ok := matchKey(pkg, rel); ok {
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	}
+
+	// <pkg> and <pkg>/<commit>
+	if err := add(pkg, 1); err != nil {
+		return nil, err
+	}
+	// golang/<version>/<pkg>
+	versions, err := ioutil.ReadDir(filepath.Join(d.root, "golang"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, version := range versions {
+		if version.IsDir() {
+			if err := add(path.Join("golang", version.Name(), pkg), 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (d *Directory) Walk(fn func(key Key) error) error {
+	// collect the keys first so the fn can modify the directory
+	var keys []Key
+	err := filepath.Walk(d.root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && file == d.root {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(d.root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if key, ok := matchKey(path.Dir(rel), rel); ok {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
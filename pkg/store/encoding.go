@@ -0,0 +1,207 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Encode encodes a JSON artefact into the format
+func Encode(data []byte, format Format) ([]byte, error) {
+	switch format {
+	case "", JSONFormat:
+		return data, nil
+	case GzipFormat:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case BinaryFormat:
+		return EncodeBinary(data)
+	}
+	return nil, fmt.Errorf("Unknown artefact format %q", format)
+}
+
+// Decode turns an artefact into JSON. The format is detected from the data
+// so artefacts are loaded no matter what extension they are stored under.
+func Decode(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		raw, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decompress artefact: %v", err)
+		}
+		// binary artefacts can be compressed as well
+		return Decode(raw)
+	case bytes.HasPrefix(data, zstdMagic):
+		return nil, fmt.Errorf("zstd compressed artefacts are not supported, use gzip instead")
+	case bytes.HasPrefix(data, binaryMagic):
+		return DecodeBinary(data)
+	}
+	return data, nil
+}
+
+// Encoded saves artefacts (given in JSON) in a format and loads them in any format.
+// Only one format of each artefact is stored. Artefacts are saved with the schema header
+// and loaded without it (migrated to the current schema version, see WithoutHeader).
+type Encoded struct {
+	Store
+	format Format
+}
+
+// NewEncoded creates a store saving artefacts into the store in the format
+func NewEncoded(s Store, format Format) *Encoded {
+	return &Encoded{Store: s, format: format}
+}
+
+// formatOf returns a format the artefact of the kind is saved in.
+// The binary encoding is used for allocated and contracts artefacts only,
+// other artefacts (e.g. api) are compressed instead.
+func (e *Encoded) formatOf(kind Kind) Format {
+	if e.format != BinaryFormat {
+		return e.format
+	}
+	switch kind {
+	case AllocatedKind, AllocatedTestKind, ContractsKind, ContractsTestKind:
+		return BinaryFormat
+	}
+	return GzipFormat
+}
+
+// formats lists formats the artefact is looked up in (the format it is saved in goes first)
+func (e *Encoded) formats(kind Kind) []Format {
+	formats := []Format{e.formatOf(kind)}
+	for _, format := range Formats {
+		if format != formats[0] {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// find finds the artefact in any format
+func (e *Encoded) find(key Key) (Key, bool) {
+	for _, format := range e.formats(key.Kind) {
+		key.Format = format
+		if e.Store.Exists(key) {
+			return key, true
+		}
+	}
+	return key, false
+}
+
+func (e *Encoded) Exists(key Key) bool {
+	if key.Kind == AnyKind {
+		return e.Store.Exists(key)
+	}
+	_, ok := e.find(key)
+	return ok
+}
+
+func (e *Encoded) Load(key Key) ([]byte, error) {
+	found, ok := e.find(key)
+	if !ok {
+		key.Format = e.formatOf(key.Kind)
+		return nil, fmt.Errorf("Artefact %v not found", key.Path())
+	}
+	data, err := e.Store.Load(found)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode %v: %v", found.Path(), err)
+	}
+	decoded, err = WithoutHeader(key.Kind, decoded)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load %v: %v", found.Path(), err)
+	}
+	return decoded, nil
+}
+
+func (e *Encoded) Save(key Key, data []byte) error {
+	key.Format = e.formatOf(key.Kind)
+	data, err := WithHeader(key.Kind, data)
+	if err != nil {
+		return fmt.Errorf("Unable to save %v: %v", key.Path(), err)
+	}
+	encoded, err := Encode(data, key.Format)
+	if err != nil {
+		return fmt.Errorf("Unable to encode %v: %v", key.Path(), err)
+	}
+	if err := e.Store.Save(key, encoded); err != nil {
+		return err
+	}
+	// drop the artefact stored in other formats
+	for _, format := range Formats {
+		if format != key.Format {
+			other := key
+			other.Format = format
+			if err := e.Store.Drop(other); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Encoded) Drop(key Key) error {
+	for _, format := range Formats {
+		key.Format = format
+		if err := e.Store.Drop(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert re-encodes all artefacts of the from store into the format and saves them into the to store.
+// Both stores keep artefacts as they are given (see RawFor), they can be the same store.
+// A number of converted artefacts is returned.
+func Convert(from, to Store, format Format) (int, error) {
+	encoded := NewEncoded(to, format)
+	converted := 0
+	err := from.Walk(func(key Key) error {
+		if from == to && key.Format == encoded.formatOf(key.Kind) {
+			return nil
+		}
+		// already converted under another format
+		if !from.Exists(key) {
+			return nil
+		}
+		data, err := from.Load(key)
+		if err != nil {
+			return fmt.Errorf("Unable to load %v: %v", key.Path(), err)
+		}
+		data, err = Decode(data)
+		if err != nil {
+			return fmt.Errorf("Unable to decode %v: %v", key.Path(), err)
+		}
+		data, err = WithoutHeader(key.Kind, data)
+		if err != nil {
+			return fmt.Errorf("Unable to load %v: %v", key.Path(), err)
+		}
+		if err := encoded.Save(key, data); err != nil {
+			return fmt.Errorf("Unable to save %v: %v", key.Path(), err)
+		}
+		converted++
+		return nil
+	})
+	return converted, err
+}
@@ -0,0 +1,166 @@
+package store_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/parser"
+	"github.com/gofed/symbols-extractor/pkg/store"
+)
+
+// generate extracts artefacts of a standard library package into the directory
+// and returns the (JSON) allocated and contracts artefacts
+func generate(t *testing.T, dir string) map[store.Key][]byte {
+	pkg := "container/list"
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	p, err := parser.New(dir, "", goVersion, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Parse(pkg, true); err != nil {
+		t.Skipf("Unable to parse %q: %v", pkg, err)
+	}
+
+	artefacts := make(map[store.Key][]byte)
+	err = store.RawFor(dir).Walk(func(key store.Key) error {
+		if key.Kind != store.AllocatedKind && key.Kind != store.ContractsKind {
+			return nil
+		}
+		data, err := store.For(dir).Load(key)
+		if err != nil {
+			return err
+		}
+		artefacts[key] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artefacts) == 0 {
+		t.Fatalf("No allocated and contracts artefacts generated")
+	}
+	return artefacts
+}
+
+// decodeJSON decodes a JSON document so documents can be compared regardless of their formatting
+func decodeJSON(t *testing.T, data []byte) interface{} {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Unable to decode %s: %v", data, err)
+	}
+	return v
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for key, data := range generate(t, t.TempDir()) {
+		t.Run(key.Path(), func(t *testing.T) {
+			encoded, err := store.EncodeBinary(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(encoded) >= len(data) {
+				t.Errorf("Expected the binary encoding to be smaller than %v bytes of JSON, got %v bytes", len(data), len(encoded))
+			}
+			decoded, err := store.DecodeBinary(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decodeJSON(t, decoded), decodeJSON(t, data)) {
+				t.Errorf("Expected %s, got %s", data, decoded)
+			}
+		})
+	}
+
+	// values of all the token types
+	data := []byte(`{"null":null,"bools":[true,false],"ints":[0,-1,1,9223372036854775807],"numbers":[1.5,-2e10],"strings":["a","a","",{"a":"a"}],"empty":{"array":[],"object":{}}}`)
+	encoded, err := store.EncodeBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := store.DecodeBinary(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decodeJSON(t, decoded), decodeJSON(t, data)) {
+		t.Errorf("Expected %s, got %s", data, decoded)
+	}
+}
+
+func TestDecodeDetectsFormat(t *testing.T) {
+	data := []byte(`{"schema_version":1,"kind":"contracts","data":{"functions":{"f":[{"type":"propagatesTo"}]}}}`)
+	binary, err := store.EncodeBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressedBinary, err := store.Encode(binary, store.GzipFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		encoded func() ([]byte, error)
+	}{
+		{"json", func() ([]byte, error) { return store.Encode(data, store.JSONFormat) }},
+		{"gzip", func() ([]byte, error) { return store.Encode(data, store.GzipFormat) }},
+		{"binary", func() ([]byte, error) { return store.Encode(data, store.BinaryFormat) }},
+		{"compressed binary", func() ([]byte, error) { return compressedBinary, nil }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := test.encoded()
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := store.Decode(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decodeJSON(t, decoded), decodeJSON(t, data)) {
+				t.Errorf("Expected %s, got %s", data, decoded)
+			}
+		})
+	}
+
+	if _, err := store.Decode([]byte{0x28, 0xb5, 0x2f, 0xfd, 0}); err == nil {
+		t.Errorf("Expected zstd compressed artefacts to be rejected")
+	}
+}
+
+func TestEncodedLoadsAnyFormat(t *testing.T) {
+	raw := store.NewDirectory(t.TempDir())
+	key := store.Key{Package: "example.com/a", Commit: "abcdef", Kind: store.ContractsKind}
+	data := []byte(`{"functions":{"f":[]}}`)
+	if err := store.NewEncoded(raw, store.JSONFormat).Save(key, data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range store.Formats {
+		loaded, err := store.NewEncoded(raw, format).Load(key)
+		if err != nil {
+			t.Fatalf("Unable to load %v artefact by %v store: %v", store.JSONFormat, format, err)
+		}
+		if !reflect.DeepEqual(decodeJSON(t, loaded), decodeJSON(t, data)) {
+			t.Errorf("Expected %s, got %s", data, loaded)
+		}
+	}
+
+	// the artefact saved in another format replaces the previous one
+	if err := store.NewEncoded(raw, store.BinaryFormat).Save(key, data); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := raw.List(key.Package)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Format = store.BinaryFormat
+	if expected := []store.Key{key}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, keys)
+	}
+}
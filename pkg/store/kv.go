@@ -0,0 +1,382 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The key-value file is a log of records appended after the header
+// in the `<op byte> <uvarint key length> <key> [<uvarint value length> <value>]` form
+// where op is either 'S' (the key is set to the value) or 'D' (the key is dropped).
+// The last record of a key wins. The file is indexed when first used,
+// a partially written record at the end of the file (e.g. of an interrupted run) is ignored
+// and truncated before the file is written.
+const (
+	kvHeader = "gofed-symbols-kv/1\n"
+	kvSet    = 'S'
+	kvDrop   = 'D'
+)
+
+type kvEntry struct {
+	offset int64
+	length int64
+}
+
+// kvFile is a key-value file shared by all the KV stores of the file
+type kvFile struct {
+	name string
+
+	mutex    sync.Mutex
+	err      error
+	f        *os.File
+	writable bool
+	size     int64
+	index    map[string]kvEntry
+}
+
+func newKVFile(name string) *kvFile {
+	return &kvFile{name: name}
+}
+
+// open opens the file and indexes its records.
+// The file is opened read-only until it is about to be written. Only then a missing
+// file is created (until then it is taken as an empty store) and a partially written
+// record at the end of the file is truncated (until then it is ignored).
+// The caller must hold the mutex.
+func (f *kvFile) open(write bool) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.f != nil {
+		if write && !f.writable {
+			return f.reopen()
+		}
+		return nil
+	}
+	if !write {
+		if _, err := os.Stat(f.name); os.IsNotExist(err) {
+			f.index = make(map[string]kvEntry)
+			return nil
+		}
+	}
+
+	flag := os.O_RDONLY
+	if write {
+		flag = os.O_RDWR | os.O_CREATE
+	}
+	file, err := os.OpenFile(f.name, flag, 0644)
+	if err != nil {
+		f.err = fmt.Errorf("Unable to open %v: %v", f.name, err)
+		return f.err
+	}
+	f.f = file
+	f.writable = write
+	f.index = make(map[string]kvEntry)
+	f.size = 0
+
+	info, err := file.Stat()
+	if err != nil {
+		f.err = err
+		return err
+	}
+	if info.Size() > 0 {
+		r := bufio.NewReader(io.NewSectionReader(file, 0, info.Size()))
+		header := make([]byte, len(kvHeader))
+		if _, err := io.ReadFull(r, header); err != nil || string(header) != kvHeader {
+			f.err = fmt.Errorf("%v is not a key-value store", f.name)
+			return f.err
+		}
+
+		offset := int64(len(kvHeader))
+		for {
+			end, err := f.readRecord(r, offset)
+			if err != nil {
+				// partially written record
+				break
+			}
+			offset = end
+		}
+		f.size = offset
+	}
+
+	if write {
+		return f.prepareAppend()
+	}
+	return nil
+}
+
+// reopen reopens the file opened read-only for writing
+func (f *kvFile) reopen() error {
+	f.f.Close()
+	file, err := os.OpenFile(f.name, os.O_RDWR, 0644)
+	if err != nil {
+		f.f = nil
+		f.err = fmt.Errorf("Unable to open %v: %v", f.name, err)
+		return f.err
+	}
+	f.f = file
+	f.writable = true
+	return f.prepareAppend()
+}
+
+// prepareAppend writes the header of an empty file, resp. truncates
+// a partially written record so new records can be appended
+func (f *kvFile) prepareAppend() error {
+	if f.size == 0 {
+		if _, err := f.f.WriteAt([]byte(kvHeader), 0); err != nil {
+			f.err = fmt.Errorf("Unable to write to %v: %v", f.name, err)
+			return f.err
+		}
+		f.size = int64(len(kvHeader))
+	}
+	if err := f.f.Truncate(f.size); err != nil {
+		f.err = fmt.Errorf("Unable to truncate %v: %v", f.name, err)
+		return f.err
+	}
+	return nil
+}
+
+// readRecord indexes a record at the offset, the offset of the next record is returned
+func (f *kvFile) readRecord(r *bufio.Reader, offset int64) (int64, error) {
+	op, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	offset++
+	key, n, err := readChunk(r)
+	if err != nil {
+		return 0, err
+	}
+	offset += n
+	switch op {
+	case kvDrop:
+		delete(f.index, string(key))
+		return offset, nil
+	case kvSet:
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return 0, err
+		}
+		offset += int64(uvarintLen(length))
+		if _, err := r.Discard(int(length)); err != nil {
+			return 0, err
+		}
+		f.index[string(key)] = kvEntry{offset: offset, length: int64(length)}
+		return offset + int64(length), nil
+	}
+	return 0, fmt.Errorf("Unknown record %q", op)
+}
+
+func readChunk(r *bufio.Reader) ([]byte, int64, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	chunk := make([]byte, length)
+	if _, err := io.ReadFull(r, chunk); err != nil {
+		return nil, 0, err
+	}
+	return chunk, int64(uvarintLen(length)) + int64(length), nil
+}
+
+func uvarintLen(x uint64) int {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return binary.PutUvarint(buf, x)
+}
+
+func appendChunk(buf *bytes.Buffer, chunk []byte) {
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	buf.Write(lenBuf[:binary.PutUvarint(lenBuf, uint64(len(chunk)))])
+	buf.Write(chunk)
+}
+
+func (f *kvFile) write(record []byte) error {
+	if _, err := f.f.WriteAt(record, f.size); err != nil {
+		return fmt.Errorf("Unable to write to %v: %v", f.name, err)
+	}
+	f.size += int64(len(record))
+	return nil
+}
+
+func (f *kvFile) set(key string, value []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.open(true); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(kvSet)
+	appendChunk(&buf, []byte(key))
+	appendChunk(&buf, value)
+	offset := f.size + int64(buf.Len()-len(value))
+	if err := f.write(buf.Bytes()); err != nil {
+		return err
+	}
+	f.index[key] = kvEntry{offset: offset, length: int64(len(value))}
+	return nil
+}
+
+func (f *kvFile) get(key string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.open(false); err != nil {
+		return nil, err
+	}
+
+	entry, ok := f.index[key]
+	if !ok {
+		return nil, fmt.Errorf("%v not found in %v", key, f.name)
+	}
+	value := make([]byte, entry.length)
+	if _, err := f.f.ReadAt(value, entry.offset); err != nil {
+		return nil, fmt.Errorf("Unable to read %v from %v: %v", key, f.name, err)
+	}
+	return value, nil
+}
+
+func (f *kvFile) drop(key string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.open(false); err != nil {
+		return err
+	}
+	if _, ok := f.index[key]; !ok {
+		return nil
+	}
+	// the file can be opened for reading only so far
+	if err := f.open(true); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(kvDrop)
+	appendChunk(&buf, []byte(key))
+	if err := f.write(buf.Bytes()); err != nil {
+		return err
+	}
+	delete(f.index, key)
+	return nil
+}
+
+func (f *kvFile) has(key string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.open(false); err != nil {
+		return false
+	}
+	_, ok := f.index[key]
+	return ok
+}
+
+// keys lists all keys with the prefix (sorted)
+func (f *kvFile) keys(prefix string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.open(false); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for key := range f.index {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// KV stores all artefacts in a single file (keyed by Key.Path) so a scan of a whole
+// distribution does not produce millions of small files
+type KV struct {
+	file *kvFile
+	// prefix of all keys (e.g. golang/1.15 for the standard library)
+	prefix string
+}
+
+// NewKV creates a store of the key-value file (the file is created when first used)
+func NewKV(file string) *KV {
+	return &KV{file: newKVFile(file)}
+}
+
+func (s *KV) key(rel string) string {
+	return path.Join(s.prefix, rel)
+}
+
+func (s *KV) Exists(key Key) bool {
+	if key.Kind == AnyKind {
+		keys, err := s.file.keys(s.key(key.Dir()) + "/")
+		return err == nil && len(keys) > 0
+	}
+	return s.file.has(s.key(key.Path()))
+}
+
+func (s *KV) Load(key Key) ([]byte, error) {
+	return s.file.get(s.key(key.Path()))
+}
+
+func (s *KV) Save(key Key, data []byte) error {
+	return s.file.set(s.key(key.Path()), data)
+}
+
+func (s *KV) Drop(key Key) error {
+	kinds := []Kind{key.Kind}
+	if key.Kind == AnyKind {
+		kinds = Kinds
+	}
+	for _, kind := range kinds {
+		key.Kind = kind
+		if err := s.file.drop(s.key(key.Path())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *KV) List(pkg string) ([]Key, error) {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+	all, err := s.file.keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	var keys []Key
+	for _, k := range all {
+		if key, ok := matchKey(pkg, strings.TrimPrefix(k, prefix)); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *KV) Walk(fn func(key Key) error) error {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+	// the keys are listed first so the fn can modify the store
+	all, err := s.file.keys(prefix)
+	if err != nil {
+		return err
+	}
+	for _, k := range all {
+		rel := strings.TrimPrefix(k, prefix)
+		if key, ok := matchKey(path.Dir(rel), rel); ok {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
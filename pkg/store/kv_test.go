@@ -0,0 +1,202 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func kvKey(pkg string, kind Kind) Key {
+	return Key{Package: pkg, Commit: "abcdef", Kind: kind}
+}
+
+func loadKV(t *testing.T, s Store, key Key) string {
+	data, err := s.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func saveKV(t *testing.T, s Store, key Key, value string) {
+	if err := s.Save(key, []byte(value)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fileSize(t *testing.T, name string) int64 {
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestKVReplay(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "artefacts.kv")
+	s := NewKV(file)
+	if s.Exists(kvKey("example.com/a", APIKind)) {
+		t.Errorf("Expected an empty store")
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("Expected %v not to be created by reads, got %v", file, err)
+	}
+	saveKV(t, s, kvKey("example.com/a", APIKind), "api of a")
+	saveKV(t, s, kvKey("example.com/a", ContractsKind), "contracts of a")
+	saveKV(t, s, kvKey("example.com/b", APIKind), "")
+
+	// the records are replayed by another store of the file
+	replayed := NewKV(file)
+	for key, value := range map[Key]string{
+		kvKey("example.com/a", APIKind):       "api of a",
+		kvKey("example.com/a", ContractsKind): "contracts of a",
+		kvKey("example.com/b", APIKind):       "",
+	} {
+		if actual := loadKV(t, replayed, key); actual != value {
+			t.Errorf("Expected %v to be %q, got %q", key.Path(), value, actual)
+		}
+	}
+	if !replayed.Exists(Key{Package: "example.com/a", Commit: "abcdef"}) {
+		t.Errorf("Expected example.com/a to be stored")
+	}
+	keys, err := replayed.List("example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Key{
+		{Package: "example.com/a", Commit: "abcdef", Kind: APIKind, Format: JSONFormat},
+		{Package: "example.com/a", Commit: "abcdef", Kind: ContractsKind, Format: JSONFormat},
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, keys)
+	}
+}
+
+func TestKVLastRecordWins(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "artefacts.kv")
+	s := NewKV(file)
+	key := kvKey("example.com/a", APIKind)
+	saveKV(t, s, key, "first")
+	saveKV(t, s, key, "second, longer")
+	saveKV(t, s, key, "third")
+	if actual := loadKV(t, s, key); actual != "third" {
+		t.Errorf("Expected %q, got %q", "third", actual)
+	}
+	if actual := loadKV(t, NewKV(file), key); actual != "third" {
+		t.Errorf("Expected %q to be replayed, got %q", "third", actual)
+	}
+}
+
+func TestKVDrop(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "artefacts.kv")
+	s := NewKV(file)
+	saveKV(t, s, kvKey("example.com/a", APIKind), "api of a")
+	saveKV(t, s, kvKey("example.com/a", ContractsKind), "contracts of a")
+	saveKV(t, s, kvKey("example.com/b", APIKind), "api of b")
+	if err := s.Drop(kvKey("example.com/a", AnyKind)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Drop(kvKey("example.com/c", APIKind)); err != nil {
+		t.Errorf("Expected a missing key to be dropped, got %v", err)
+	}
+
+	for _, kv := range []Store{s, NewKV(file)} {
+		if kv.Exists(kvKey("example.com/a", AnyKind)) {
+			t.Errorf("Expected example.com/a to be dropped")
+		}
+		if _, err := kv.Load(kvKey("example.com/a", APIKind)); err == nil {
+			t.Errorf("Expected the dropped api of example.com/a not to be loaded")
+		}
+		if actual := loadKV(t, kv, kvKey("example.com/b", APIKind)); actual != "api of b" {
+			t.Errorf("Expected %q, got %q", "api of b", actual)
+		}
+	}
+
+	// a key set again after it is dropped
+	saveKV(t, s, kvKey("example.com/a", APIKind), "api of a again")
+	if actual := loadKV(t, NewKV(file), kvKey("example.com/a", APIKind)); actual != "api of a again" {
+		t.Errorf("Expected %q, got %q", "api of a again", actual)
+	}
+}
+
+func TestKVDropAfterReopen(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "artefacts.kv")
+	saveKV(t, NewKV(file), kvKey("example.com/a", APIKind), "api of a")
+	saveKV(t, NewKV(file), kvKey("example.com/b", APIKind), "api of b")
+
+	// the file is opened for reading first
+	s := NewKV(file)
+	if !s.Exists(kvKey("example.com/a", APIKind)) {
+		t.Fatalf("Expected example.com/a to be stored")
+	}
+	if err := s.Drop(kvKey("example.com/a", APIKind)); err != nil {
+		t.Fatal(err)
+	}
+	// the file is not opened at all before the drop
+	if err := NewKV(file).Drop(kvKey("example.com/b", AnyKind)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []Key{kvKey("example.com/a", APIKind), kvKey("example.com/b", APIKind)} {
+		if NewKV(file).Exists(key) {
+			t.Errorf("Expected %v to be dropped", key.Path())
+		}
+	}
+}
+
+func TestKVTornRecord(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "artefacts.kv")
+	s := NewKV(file)
+	saveKV(t, s, kvKey("example.com/a", APIKind), "api of a")
+	saveKV(t, s, kvKey("example.com/b", APIKind), "api of b")
+	size := fileSize(t, file)
+
+	// a set record of an interrupted run with the value cut off
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{kvSet, 1, 'c', 10, 'v', 'a', 'l'}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	torn := fileSize(t, file)
+
+	// reads ignore the torn record and leave the file as it is
+	reader := NewKV(file)
+	if actual := loadKV(t, reader, kvKey("example.com/b", APIKind)); actual != "api of b" {
+		t.Errorf("Expected %q, got %q", "api of b", actual)
+	}
+	keys, err := reader.List("example.com/c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Expected no keys of the torn record, got %#v", keys)
+	}
+	if actual := fileSize(t, file); actual != torn {
+		t.Errorf("Expected reads to keep the file of %v bytes, got %v bytes", torn, actual)
+	}
+
+	// the first write truncates the torn record
+	saveKV(t, reader, kvKey("example.com/c", APIKind), "api of c")
+	if actual := loadKV(t, reader, kvKey("example.com/c", APIKind)); actual != "api of c" {
+		t.Errorf("Expected %q, got %q", "api of c", actual)
+	}
+	replayed := NewKV(file)
+	for key, value := range map[Key]string{
+		kvKey("example.com/a", APIKind): "api of a",
+		kvKey("example.com/b", APIKind): "api of b",
+		kvKey("example.com/c", APIKind): "api of c",
+	} {
+		if actual := loadKV(t, replayed, key); actual != value {
+			t.Errorf("Expected %v to be %q, got %q", key.Path(), value, actual)
+		}
+	}
+	// op, key and value (both shorter than 128 bytes with one byte length)
+	record := int64(1 + 1 + len(kvKey("example.com/c", APIKind).Path()) + 1 + len("api of c"))
+	if actual := fileSize(t, file); actual != size+record {
+		t.Errorf("Expected the torn record to be truncated to %v bytes, got %v bytes", size+record, actual)
+	}
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is a version of the artefacts JSON schema (of the gotypes, the allocated symbols and the contracts).
+// Every change of the schema (e.g. a new field) increases the version and registers a migration
+// of artefacts of the previous version.
+const SchemaVersion = 1
+
+// Every artefact is saved with a header in the {"schema_version": <version>, "kind": <kind>, "data": <artefact>} form.
+// Artefacts generated before the header was introduced are of version 0. They lack the metadata
+// of the version 1 (struct field tags, embedded fields and order of members, type aliases,
+// constant values and line:column positions) that can not be recovered from the artefacts,
+// so they are rejected and need to be regenerated.
+type header struct {
+	SchemaVersion int             `json:"schema_version"`
+	Kind          Kind            `json:"kind"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// migrations upgrade an artefact of a version to the next version
+// (there is no migration of artefacts of version 0, see header)
+var migrations = map[int]func(kind Kind, data []byte) ([]byte, error){}
+
+// WithHeader wraps the JSON artefact of the kind with the header of the current schema version
+func WithHeader(kind Kind, data []byte) ([]byte, error) {
+	return json.Marshal(&header{
+		SchemaVersion: SchemaVersion,
+		Kind:          kind,
+		Data:          data,
+	})
+}
+
+// Version returns a schema version of the JSON artefact (0 for artefacts with no header)
+func Version(data []byte) (int, error) {
+	h, err := readHeader(data)
+	if err != nil {
+		return 0, err
+	}
+	return h.SchemaVersion, nil
+}
+
+func readHeader(data []byte) (*header, error) {
+	var h header
+	// artefacts of version 0 are either JSON objects with no schema_version entry, or null
+	if err := json.Unmarshal(data, &h); err != nil || h.SchemaVersion == 0 {
+		return &header{Data: data}, nil
+	}
+	if h.Data == nil {
+		return nil, fmt.Errorf("Artefact of schema version %v has no data", h.SchemaVersion)
+	}
+	return &h, nil
+}
+
+// WithoutHeader strips the header of the JSON artefact of the kind.
+// An artefact of an older schema version is migrated to the current version,
+// an artefact of a newer version (or of a different kind) is rejected.
+func WithoutHeader(kind Kind, data []byte) ([]byte, error) {
+	h, err := readHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if h.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("Artefact of schema version %v is not supported (the latest supported version is %v), regenerate it", h.SchemaVersion, SchemaVersion)
+	}
+	if h.SchemaVersion == 0 {
+		return nil, fmt.Errorf("Artefact with no schema version header lacks metadata of the schema version %v, regenerate it", SchemaVersion)
+	}
+	if h.Kind != "" && kind != AnyKind && h.Kind != kind {
+		return nil, fmt.Errorf("Expected %v artefact, got %v", kind, h.Kind)
+	}
+	data = h.Data
+	for version := h.SchemaVersion; version < SchemaVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("No migration of artefacts of schema version %v", version)
+		}
+		if data, err = migrate(kind, data); err != nil {
+			return nil, fmt.Errorf("Unable to migrate artefact of schema version %v: %v", version, err)
+		}
+	}
+	return data, nil
+}
+
+// Migrate upgrades all artefacts of the store (keeping artefacts as they are given, see RawFor)
+// to the current schema version. Each artefact is kept in its format.
+// Artefacts that can not be migrated (e.g. of version 0) are reported as an error.
+// A number of migrated artefacts is returned.
+func Migrate(s Store) (int, error) {
+	migrated := 0
+	err := s.Walk(func(key Key) error {
+		raw, err := s.Load(key)
+		if err != nil {
+			return fmt.Errorf("Unable to load %v: %v", key.Path(), err)
+		}
+		data, err := Decode(raw)
+		if err != nil {
+			return fmt.Errorf("Unable to decode %v: %v", key.Path(), err)
+		}
+		version, err := Version(data)
+		if err != nil {
+			return fmt.Errorf("Unable to read %v: %v", key.Path(), err)
+		}
+		if version == SchemaVersion {
+			return nil
+		}
+		if data, err = WithoutHeader(key.Kind, data); err != nil {
+			return fmt.Errorf("Unable to migrate %v: %v", key.Path(), err)
+		}
+		if data, err = WithHeader(key.Kind, data); err != nil {
+			return err
+		}
+		if raw, err = Encode(data, key.Format); err != nil {
+			return fmt.Errorf("Unable to encode %v: %v", key.Path(), err)
+		}
+		if err := s.Save(key, raw); err != nil {
+			return fmt.Errorf("Unable to save %v: %v", key.Path(), err)
+		}
+		migrated++
+		return nil
+	})
+	return migrated, err
+}
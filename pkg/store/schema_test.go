@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/store"
+)
+
+func TestMigrate(t *testing.T) {
+	raw := store.NewDirectory(t.TempDir())
+	data := []byte(`{"functions":{"f":[]}}`)
+	keys := []store.Key{
+		{Package: "example.com/a", Commit: "abcdef", Kind: store.ContractsKind, Format: store.JSONFormat},
+		{Package: "example.com/b", Commit: "abcdef", Kind: store.ContractsKind, Format: store.GzipFormat},
+		{Package: "example.com/c", Commit: "abcdef", Kind: store.ContractsKind, Format: store.BinaryFormat},
+	}
+	// artefacts of the current version
+	for _, key := range keys {
+		if err := store.NewEncoded(raw, key.Format).Save(key, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if migrated, err := store.Migrate(raw); err != nil || migrated != 0 {
+		t.Errorf("Expected no artefacts to be migrated, got %v (%v)", migrated, err)
+	}
+	for _, key := range keys {
+		loaded, err := store.NewEncoded(raw, store.JSONFormat).Load(key)
+		if err != nil {
+			t.Fatalf("Expected %v to be kept in its format: %v", key.Path(), err)
+		}
+		if !reflect.DeepEqual(decodeJSON(t, loaded), decodeJSON(t, data)) {
+			t.Errorf("Expected %s, got %s", data, loaded)
+		}
+	}
+
+	// an artefact generated before the schema header was introduced can not be migrated
+	old := store.Key{Package: "example.com/d", Commit: "abcdef", Kind: store.ContractsKind, Format: store.GzipFormat}
+	encoded, err := store.Encode(data, old.Format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := raw.Save(old, encoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Migrate(raw); err == nil || !strings.Contains(err.Error(), old.Path()) {
+		t.Errorf("Expected %v to be rejected, got %v", old.Path(), err)
+	}
+	if loaded, err := raw.Load(old); err != nil || !reflect.DeepEqual(loaded, encoded) {
+		t.Errorf("Expected %v to be left as it is, got %v", old.Path(), err)
+	}
+}
+
+func TestWithoutHeader(t *testing.T) {
+	data := []byte(`{"functions":{}}`)
+	tests := []struct {
+		name     string
+		artefact string
+		kind     store.Kind
+		err      bool
+	}{
+		{"no header", `{"functions":{}}`, store.ContractsKind, true},
+		{"null", `null`, store.ContractsKind, true},
+		{"current version", `{"schema_version":1,"kind":"contracts","data":{"functions":{}}}`, store.ContractsKind, false},
+		{"any kind", `{"schema_version":1,"kind":"contracts","data":{"functions":{}}}`, store.AnyKind, false},
+		{"different kind", `{"schema_version":1,"kind":"api","data":{"functions":{}}}`, store.ContractsKind, true},
+		{"newer version", `{"schema_version":1000,"kind":"contracts","data":{"functions":{}}}`, store.ContractsKind, true},
+		{"no data", `{"schema_version":1,"kind":"contracts"}`, store.ContractsKind, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stripped, err := store.WithoutHeader(test.kind, []byte(test.artefact))
+			if test.err {
+				if err == nil {
+					t.Errorf("Expected %s to be rejected", test.artefact)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decodeJSON(t, stripped), decodeJSON(t, data)) {
+				t.Errorf("Expected %s, got %s", data, stripped)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package store
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots"
+)
+
+// Kind of a package artefact
+type Kind string
+
+const (
+	// Kind of any artefact (used to check a package is stored at all)
+	AnyKind           Kind = ""
+	APIKind           Kind = "api"
+	AllocatedKind     Kind = "allocated"
+	AllocatedTestKind Kind = "allocated_test"
+	ContractsKind     Kind = "contracts"
+	ContractsTestKind Kind = "contracts_test"
+	// Kind of a record of the artefacts inputs (see the manifest package)
+	ManifestKind Kind = "manifest"
+)
+
+// Kinds lists all artefact kinds
+var Kinds = []Kind{APIKind, AllocatedKind, AllocatedTestKind, ContractsKind, ContractsTestKind, ManifestKind}
+
+// Format an artefact is encoded in (given by the artefact file extension)
+type Format string
+
+const (
+	JSONFormat Format = "json"
+	// gzip compressed JSON
+	GzipFormat Format = "json.gz"
+	// compact binary encoding (see EncodeBinary)
+	BinaryFormat Format = "bin"
+)
+
+// Formats lists all artefact formats
+var Formats = []Format{JSONFormat, GzipFormat, BinaryFormat}
+
+// ParseFormat parses a format given by its name (json, gzip or binary) or by its extension
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "", "json":
+		return JSONFormat, nil
+	case "gzip", "json.gz":
+		return GzipFormat, nil
+	case "binary", "bin":
+		return BinaryFormat, nil
+	}
+	return "", fmt.Errorf("Unknown artefact format %q, expected json, gzip or binary", name)
+}
+
+// Filename of the artefact kind encoded in the format (e.g. api.json, contracts.bin).
+// An empty format is JSONFormat.
+func (k Kind) Filename(format Format) string {
+	if format == "" {
+		format = JSONFormat
+	}
+	return string(k) + "." + string(format)
+}
+
+func kindOf(filename string) (Kind, Format, bool) {
+	for _, kind := range Kinds {
+		for _, format := range Formats {
+			if kind.Filename(format) == filename {
+				return kind, format, true
+			}
+		}
+	}
+	return AnyKind, "", false
+}
+
+// Key identifies an artefact of a package
+type Key struct {
+	Package string
+	// Commit the package is extracted at (empty if not known)
+	Commit string
+	// Go version of a standard library package (empty for other packages)
+	GoVersion string
+	Kind      Kind
+	// Format the artefact is encoded in (empty for JSONFormat)
+	Format Format
+}
+
+// Dir is a location of the package artefacts relative to the store root:
+// - golang/<GoVersion>/<Package> for the standard library packages
+// - <Package>/<Commit> for packages with a known commit
+// - <Package> otherwise
+func (k Key) Dir() string {
+	if k.GoVersion != "" {
+		return path.Join("golang", k.GoVersion, k.Package)
+	}
+	if k.Commit != "" {
+		return path.Join(k.Package, k.Commit)
+	}
+	return k.Package
+}
+
+// Path is a location of the artefact relative to the store root
+func (k Key) Path() string {
+	return path.Join(k.Dir(), k.Kind.Filename(k.Format))
+}
+
+// matchKey turns a location of an artefact into a key of the package artefact.
+// A subdirectory of the package with artefacts is taken as a commit.
+func matchKey(pkg, rel string) (Key, bool) {
+	kind, format, ok := kindOf(path.Base(rel))
+	if !ok {
+		return Key{}, false
+	}
+	dir := path.Dir(rel)
+	if dir == pkg {
+		return Key{Package: pkg, Kind: kind, Format: format}, true
+	}
+	if path.Dir(dir) == pkg {
+		return Key{Package: pkg, Commit: path.Base(dir), Kind: kind, Format: format}, true
+	}
+	if parts := strings.SplitN(dir, "/", 3); len(parts) == 3 && parts[0] == "golang" && parts[2] == pkg {
+		return Key{Package: pkg, GoVersion: parts[1], Kind: kind, Format: format}, true
+	}
+	return Key{}, false
+}
+
+// locator is a snapshot locating packages imported under a different path
+// than their lock entries (see ip2pp.Snapshot)
+type locator interface {
+	Locate(pkg string) (string, string, error)
+}
+
+// PackageKey is a key of the package artefact.
+// If the package is a stored standard library package (of the go version),
+// the standard library key is returned. Otherwise the commit is taken from the snapshot (if set).
+// If the snapshot locates the package under a different import path (e.g. golang.org/x/net
+// locked as github.com/golang/net), the artefact is keyed by the located path.
+func PackageKey(s Store, pkg, goVersion string, snapshot snapshots.Snapshot, kind Kind) Key {
+	if key := (Key{Package: pkg, GoVersion: goVersion}); s.Exists(key) {
+		key.Kind = kind
+		return key
+	}
+
+	key := Key{Package: pkg, Kind: kind}
+	if l, ok := snapshot.(locator); ok {
+		if ip, commit, err := l.Locate(pkg); err == nil {
+			key.Package, key.Commit = ip, commit
+		}
+	} else if snapshot != nil {
+		if commit, err := snapshot.Commit(pkg); err == nil {
+			key.Commit = commit
+		}
+	}
+	return key
+}
+
+// Store keeps artefacts (api.json, allocated.json, contracts.json, ...) of extracted packages
+type Store interface {
+	// Exists checks the artefact is stored (any artefact of the package in case of AnyKind)
+	Exists(key Key) bool
+	Load(key Key) ([]byte, error)
+	// Save stores the artefact (an already stored artefact is replaced)
+	Save(key Key, data []byte) error
+	// Drop removes the artefact (all artefacts of the package in case of AnyKind)
+	Drop(key Key) error
+	// List lists artefacts of a package (of all commits and go versions)
+	List(pkg string) ([]Key, error)
+	// Walk calls the fn for every stored artefact. The package of the key is the whole
+	// location of the artefact (i.e. neither commit nor go version is split off).
+	Walk(fn func(key Key) error) error
+}
+
+// KVExtension is an extension of the single-file key-value store
+const KVExtension = ".kv"
+
+var (
+	storesMutex sync.Mutex
+	stores      = make(map[string]Store)
+	kvFiles     = make(map[string]*kvFile)
+	format      = JSONFormat
+)
+
+// SetFormat sets a format artefacts are saved in by stores returned by For
+// (artefacts are loaded in any format)
+func SetFormat(f Format) {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+	format = f
+}
+
+// For returns a store shared by everyone using the location.
+// If a component of the location has the .kv extension, the component is a single-file key-value store
+// (and the rest of the location a prefix of the keys, e.g. artefacts.kv/golang/1.15).
+// Otherwise the location is a directory the artefacts are stored under.
+// Artefacts are saved in the format set by SetFormat.
+func For(location string) Store {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+
+	return NewEncoded(rawFor(location), format)
+}
+
+// RawFor returns a store of the location keeping artefacts as they are given
+// (i.e. neither encoded nor decoded)
+func RawFor(location string) Store {
+	storesMutex.Lock()
+	defer storesMutex.Unlock()
+
+	return rawFor(location)
+}
+
+// The caller must hold the storesMutex.
+func rawFor(location string) Store {
+	if s, ok := stores[location]; ok {
+		return s
+	}
+
+	var s Store = NewDirectory(location)
+	parts := strings.Split(filepath.ToSlash(location), "/")
+	for i, part := range parts {
+		if !strings.HasSuffix(part, KVExtension) {
+			continue
+		}
+		file := filepath.FromSlash(strings.Join(parts[:i+1], "/"))
+		f, ok := kvFiles[file]
+		if !ok {
+			f = newKVFile(file)
+			kvFiles[file] = f
+		}
+		s = &KV{file: f, prefix: path.Join(parts[i+1:]...)}
+		break
+	}
+	stores[location] = s
+	return s
+}
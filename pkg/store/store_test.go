@@ -0,0 +1,87 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/snapshots/ip2pp"
+)
+
+// lock is a snapshot of locked repository roots
+type lock map[string]string
+
+func (l lock) Commit(pkg string) (string, error) {
+	if commit, ok := l[pkg]; ok {
+		return commit, nil
+	}
+	return "", fmt.Errorf("Commit for package %q not found", pkg)
+}
+
+func TestPackageKey(t *testing.T) {
+	s := NewDirectory(t.TempDir())
+	saveKV(t, s, Key{Package: "errors", GoVersion: "1.15", Kind: APIKind}, "{}")
+
+	snapshot := lock{
+		"github.com/golang/net":       "net",
+		"github.com/golang/net/trace": "trace",
+	}
+	mapping := ip2pp.Mapping{{IPPrefix: "code.google.com/p/go.net", ProviderPrefix: "github.com/golang/net"}}
+	located := ip2pp.New(snapshot, mapping)
+
+	tests := []struct {
+		name     string
+		pkg      string
+		snapshot snapshots.Snapshot
+		key      Key
+	}{
+		{
+			name:     "standard library package",
+			pkg:      "errors",
+			snapshot: located,
+			key:      Key{Package: "errors", GoVersion: "1.15", Kind: AllocatedKind},
+		},
+		{
+			name: "no snapshot",
+			pkg:  "golang.org/x/net/context",
+			key:  Key{Package: "golang.org/x/net/context", Kind: AllocatedKind},
+		},
+		{
+			name:     "locked package",
+			pkg:      "github.com/golang/net/trace",
+			snapshot: snapshot,
+			key:      Key{Package: "github.com/golang/net/trace", Commit: "trace", Kind: AllocatedKind},
+		},
+		{
+			name:     "package not locked",
+			pkg:      "github.com/golang/net/context",
+			snapshot: snapshot,
+			key:      Key{Package: "github.com/golang/net/context", Kind: AllocatedKind},
+		},
+		{
+			name:     "package of a locked repository",
+			pkg:      "github.com/golang/net/context",
+			snapshot: located,
+			key:      Key{Package: "github.com/golang/net/context", Commit: "net", Kind: AllocatedKind},
+		},
+		{
+			name:     "vanity import path",
+			pkg:      "golang.org/x/net/context",
+			snapshot: located,
+			key:      Key{Package: "github.com/golang/net/context", Commit: "net", Kind: AllocatedKind},
+		},
+		{
+			name:     "mapped import path",
+			pkg:      "code.google.com/p/go.net/context",
+			snapshot: located,
+			key:      Key{Package: "github.com/golang/net/context", Commit: "net", Kind: AllocatedKind},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if key := PackageKey(s, test.pkg, "1.15", test.snapshot, AllocatedKind); key != test.key {
+				t.Errorf("Expected %#v, got %#v", test.key, key)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package accessors
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/ast"
+	"reflect"
 	"strings"
 
 	"github.com/gofed/symbols-extractor/pkg/symbols"
@@ -187,32 +189,87 @@ func (a *Accessor) Lookup(ident *gotypes.Identifier) (*symbols.SymbolDef, symbol
 	return sd, st, table, err
 }
 
+// aliasTarget returns an identifier of a data type the ident is an alias of.
+// If the ident is not an alias (type A = B), nil is returned.
+func (a *Accessor) aliasTarget(ident *gotypes.Identifier) *gotypes.Identifier {
+	def, _, err := a.LookupDataType(ident)
+	if err != nil || def == nil || !def.Alias || def.Def == nil {
+		return nil
+	}
+	switch d := def.Def.(type) {
+	case *gotypes.Identifier:
+		if d.Package == "" {
+			return &gotypes.Identifier{Package: ident.Package, Def: d.Def}
+		}
+		return d
+	case *gotypes.Selector:
+		if qid, ok := d.Prefix.(*gotypes.Packagequalifier); ok {
+			return &gotypes.Identifier{Package: qid.Path, Def: d.Item}
+		}
+	}
+	return nil
+}
+
+// LookupMethod retrieves a method of a data type.
+// Methods of an alias are methods of the aliased data type.
 func (a *Accessor) LookupMethod(ident *gotypes.Identifier, method string) (*symbols.SymbolDef, error) {
 	if ident.Package == "" {
 		return nil, fmt.Errorf("Identifier %#v does not set its Package field", ident)
 	}
+	var def *symbols.SymbolDef
+	var err error
 	if ident.Package == a.packageName {
-		return a.symbolTable.LookupMethod(ident.Def, method)
+		def, err = a.symbolTable.LookupMethod(ident.Def, method)
+	} else {
+		table, tErr := a.globalSymbolTable.Lookup(ident.Package)
+		if tErr != nil {
+			return nil, tErr
+		}
+		def, err = table.LookupMethod(ident.Def, method)
 	}
-	table, err := a.globalSymbolTable.Lookup(ident.Package)
 	if err != nil {
-		return nil, err
+		if target := a.aliasTarget(ident); target != nil {
+			return a.LookupMethod(target, method)
+		}
 	}
-	return table.LookupMethod(ident.Def, method)
+	return def, err
 }
 
+// LookupAllMethods retrieves all methods of a data type.
+// Methods of an alias are methods of the aliased data type.
 func (a *Accessor) LookupAllMethods(ident *gotypes.Identifier) (map[string]*symbols.SymbolDef, error) {
 	if ident.Package == "" {
 		return nil, fmt.Errorf("Identifier %#v does not set its Package field", ident)
 	}
+	var methods map[string]*symbols.SymbolDef
+	var err error
 	if ident.Package == a.packageName {
-		return a.symbolTable.LookupAllMethods(ident.Def)
+		methods, err = a.symbolTable.LookupAllMethods(ident.Def)
+	} else {
+		table, tErr := a.globalSymbolTable.Lookup(ident.Package)
+		if tErr != nil {
+			return nil, tErr
+		}
+		methods, err = table.LookupAllMethods(ident.Def)
 	}
-	table, err := a.globalSymbolTable.Lookup(ident.Package)
-	if err != nil {
-		return nil, err
+	if target := a.aliasTarget(ident); target != nil {
+		targetMethods, tErr := a.LookupAllMethods(target)
+		if tErr != nil {
+			return methods, err
+		}
+		if err != nil || methods == nil {
+			return targetMethods, nil
+		}
+		merged := make(map[string]*symbols.SymbolDef, len(methods)+len(targetMethods))
+		for name, def := range targetMethods {
+			merged[name] = def
+		}
+		for name, def := range methods {
+			merged[name] = def
+		}
+		return merged, nil
 	}
-	return table.LookupAllMethods(ident.Def)
+	return methods, err
 }
 
 func (a *Accessor) LookupDataType(ident *gotypes.Identifier) (*symbols.SymbolDef, symbols.SymbolLookable, error) {
@@ -284,11 +341,140 @@ func (a *Accessor) RetrieveQidDataType(qidprefix gotypes.DataType, item *ast.Ide
 	return qidst, dataTypeDef, nil
 }
 
+// LookupGenericDataType retrieves a symbol definition of a generic data type
+func (a *Accessor) LookupGenericDataType(typeDef gotypes.DataType) (*symbols.SymbolDef, error) {
+	var symbolDef *symbols.SymbolDef
+	switch typeDefType := typeDef.(type) {
+	case *gotypes.Selector:
+		_, def, err := a.RetrieveQidDataType(typeDefType.Prefix, &ast.Ident{Name: typeDefType.Item})
+		if err != nil {
+			return nil, err
+		}
+		symbolDef = def
+	case *gotypes.Identifier:
+		def, _, err := a.LookupDataType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		symbolDef = def
+	default:
+		return nil, fmt.Errorf("Expected generic data type identifier, got %#v instead", typeDef)
+	}
+	if symbolDef.Def == nil {
+		return nil, fmt.Errorf("Symbol %q not yet fully processed", symbolDef.Name)
+	}
+	return symbolDef, nil
+}
+
+// InstantiateDataType returns a definition of a generic data type
+// with type parameters substituted by type arguments of the instance.
+func (a *Accessor) InstantiateDataType(inst *gotypes.Instance) (gotypes.DataType, error) {
+	symbolDef, err := a.LookupGenericDataType(inst.Def)
+	if err != nil {
+		return nil, err
+	}
+	return gotypes.Substitute(symbolDef.Def, symbolDef.Typeparams, inst.Args), nil
+}
+
+// ErrNoCoreType is returned when types of a type set of a type parameter
+// do not share the same underlying type, e.g. for [T ~int | ~string].
+var ErrNoCoreType = errors.New("no core type")
+
+// TypeparamCoreType returns a data type a type parameter is restricted to by its constraint.
+// E.g. []E for [S ~[]E], int for [T ~int | MyInt], []byte for [T string | []byte].
+// The constraint itself is returned if it does not restrict the type set by a union.
+// Any type (empty interface) if the constraint is omitted.
+func (a *Accessor) TypeparamCoreType(typeParam *gotypes.Typeparam) (gotypes.DataType, error) {
+	if typeParam.Constraint == nil {
+		return &gotypes.Interface{}, nil
+	}
+	union, err := a.constraintUnion(typeParam.Constraint)
+	if err != nil {
+		return nil, err
+	}
+	if union == nil {
+		return typeParam.Constraint, nil
+	}
+	if len(union.Terms) == 1 {
+		return union.Terms[0].Def, nil
+	}
+	var core gotypes.DataType
+	shared, byteString := true, true
+	for _, term := range union.Terms {
+		def, err := a.FindFirstNonidDataType(term.Def)
+		if err != nil {
+			return nil, err
+		}
+		byteString = byteString && isByteString(def)
+		if core == nil {
+			core = def
+			continue
+		}
+		shared = shared && reflect.DeepEqual(core, def)
+	}
+	if shared {
+		return core, nil
+	}
+	// string and []byte are both indexed (and sliced) by bytes
+	if byteString {
+		return &gotypes.Slice{Elmtype: &gotypes.Identifier{Package: "builtin", Def: "byte"}}, nil
+	}
+	return nil, fmt.Errorf("type parameter %v: %w", typeParam.Name, ErrNoCoreType)
+}
+
+// constraintUnion returns a union of type terms of a constraint, if any.
+// E.g. ~int | ~string of interface{ ~int | ~string; String() string }
+func (a *Accessor) constraintUnion(constraint gotypes.DataType) (*gotypes.Union, error) {
+	if union, ok := constraint.(*gotypes.Union); ok {
+		return union, nil
+	}
+	def, err := a.FindFirstNonidDataType(constraint)
+	if err != nil {
+		return nil, err
+	}
+	iface, ok := def.(*gotypes.Interface)
+	if !ok {
+		return nil, nil
+	}
+	for _, item := range iface.Methods {
+		if item.Name != "" {
+			continue
+		}
+		union, err := a.constraintUnion(item.Def)
+		if err != nil {
+			return nil, err
+		}
+		if union != nil {
+			return union, nil
+		}
+	}
+	return nil, nil
+}
+
+func isByteString(def gotypes.DataType) bool {
+	switch d := def.(type) {
+	case *gotypes.Identifier:
+		return d.Package == "builtin" && d.Def == "string"
+	case *gotypes.Slice:
+		elm, ok := d.Elmtype.(*gotypes.Identifier)
+		return ok && elm.Package == "builtin" && (elm.Def == "byte" || elm.Def == "uint8")
+	}
+	return false
+}
+
 func (a *Accessor) IsDataTypeInterface(typeDef gotypes.DataType) (bool, error) {
 	var symbolDef *symbols.SymbolDef
 	switch typeDefType := typeDef.(type) {
 	case *gotypes.Interface:
 		return true, nil
+	case *gotypes.Instance:
+		return a.IsDataTypeInterface(typeDefType.Def)
+	case *gotypes.Typeparam:
+		core, err := a.TypeparamCoreType(typeDefType)
+		if err != nil {
+			return false, err
+		}
+		return a.IsDataTypeInterface(core)
 	case *gotypes.Selector:
 		_, def, err := a.RetrieveQidDataType(typeDefType.Prefix, &ast.Ident{Name: typeDefType.Item})
 		if err != nil {
@@ -297,8 +483,9 @@ func (a *Accessor) IsDataTypeInterface(typeDef gotypes.DataType) (bool, error) {
 		symbolDef = def
 	case *gotypes.Identifier:
 		if typeDefType.Package == "builtin" {
-			// error interface
-			if typeDefType.Def == "error" {
+			// error interface, resp. constraints
+			switch typeDefType.Def {
+			case "error", "any", "comparable":
 				return true, nil
 			}
 			return false, nil
@@ -339,6 +526,18 @@ func (a *Accessor) FindFirstNonidDataType(typeDef gotypes.DataType) (gotypes.Dat
 			return nil, err
 		}
 		symbolDef = def
+	case *gotypes.Instance:
+		def, err := a.InstantiateDataType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		return a.FindFirstNonidDataType(def)
+	case *gotypes.Typeparam:
+		core, err := a.TypeparamCoreType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		return a.FindFirstNonidDataType(core)
 	default:
 		return typeDef, nil
 	}
@@ -370,6 +569,18 @@ func (a *Accessor) FindFirstNonidVariable(typeDef gotypes.DataType) (gotypes.Dat
 			return nil, err
 		}
 		symbolDef = def
+	case *gotypes.Instance:
+		def, err := a.InstantiateDataType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		return a.FindFirstNonidVariable(def)
+	case *gotypes.Typeparam:
+		core, err := a.TypeparamCoreType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		return a.FindFirstNonidVariable(core)
 	default:
 		return typeDef, nil
 	}
@@ -401,6 +612,18 @@ func (a *Accessor) FindFirstNonIdSymbol(typeDef gotypes.DataType) (gotypes.DataT
 			return nil, err
 		}
 		symbolDef = def
+	case *gotypes.Instance:
+		def, err := a.InstantiateDataType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		return a.FindFirstNonIdSymbol(def)
+	case *gotypes.Typeparam:
+		core, err := a.TypeparamCoreType(typeDefType)
+		if err != nil {
+			return nil, err
+		}
+		return a.FindFirstNonIdSymbol(core)
 	default:
 		return typeDef, nil
 	}
@@ -507,7 +730,8 @@ func (a *Accessor) ResolveToUnderlyingType(typeDef gotypes.DataType) (*Underlyin
 		}
 
 		if ident.Package == "builtin" {
-			if ident.Def == "error" {
+			// error interface, resp. any alias of interface{}
+			if ident.Def == "error" || ident.Def == "any" {
 				def, _, _, err := a.Lookup(ident)
 				if err != nil {
 					return nil, err
@@ -515,7 +739,7 @@ func (a *Accessor) ResolveToUnderlyingType(typeDef gotypes.DataType) (*Underlyin
 				return &UnderlyingType{
 					SymbolType: gotypes.InterfaceType,
 					Package:    "builtin",
-					Id:         "error",
+					Id:         ident.Def,
 					Def:        def.Def,
 				}, nil
 			}
@@ -581,6 +805,25 @@ func (a *Accessor) ResolveToUnderlyingType(typeDef gotypes.DataType) (*Underlyin
 			}, nil
 		}
 		return a.ResolveToUnderlyingType(def.Def)
+	case *gotypes.Instance:
+		ut, err := a.ResolveToUnderlyingType(d.Def)
+		if err != nil {
+			return nil, err
+		}
+		if ut.Def != nil {
+			symbolDef, err := a.LookupGenericDataType(d.Def)
+			if err != nil {
+				return nil, err
+			}
+			ut.Def = gotypes.Substitute(ut.Def, symbolDef.Typeparams, d.Args)
+		}
+		return ut, nil
+	case *gotypes.Typeparam:
+		core, err := a.TypeparamCoreType(d)
+		if err != nil {
+			return nil, err
+		}
+		return a.ResolveToUnderlyingType(core)
 	default:
 		panic(fmt.Errorf("Unrecognized underlying type %#v", typeDef))
 	}
@@ -814,6 +1057,8 @@ type FieldAttribute struct {
 	Origin []*symbols.SymbolDef
 	// is the field a method?
 	IsMethod bool
+	// struct field tag
+	Tag string
 }
 
 // Get a struct's field.
@@ -888,11 +1133,12 @@ func (a *Accessor) RetrieveDataTypeField(accessor *FieldAccessor) (*FieldAttribu
 			if err != nil {
 				return nil, err
 			}
+			// With 'type A = B' methods of type B are methods of A
 			fieldAccessor, err := a.RetrieveDataTypeField(&FieldAccessor{
 				symbolTable:    pkgST,
 				dataTypeDef:    def,
 				field:          accessor.field,
-				fieldsOnly:     true,
+				fieldsOnly:     !accessor.dataTypeDef.Alias || accessor.fieldsOnly,
 				dropFieldsOnly: true,
 			})
 			if err != nil {
@@ -929,7 +1175,7 @@ func (a *Accessor) RetrieveDataTypeField(accessor *FieldAccessor) (*FieldAttribu
 				symbolTable:    st,
 				dataTypeDef:    sd,
 				field:          accessor.field,
-				fieldsOnly:     true,
+				fieldsOnly:     !accessor.dataTypeDef.Alias || accessor.fieldsOnly,
 				dropFieldsOnly: true,
 			})
 			if err != nil {
@@ -949,6 +1195,8 @@ func (a *Accessor) RetrieveDataTypeField(accessor *FieldAccessor) (*FieldAttribu
 	type embeddedDataTypesItem struct {
 		symbolTable symbols.SymbolLookable
 		symbolDef   *symbols.SymbolDef
+		// embedded instance of a generic data type
+		instance *gotypes.Instance
 	}
 	var embeddedDataTypes []embeddedDataTypesItem
 
@@ -957,8 +1205,9 @@ func (a *Accessor) RetrieveDataTypeField(accessor *FieldAccessor) (*FieldAttribu
 ITEMS_LOOP:
 	for _, item := range accessor.dataTypeDef.Def.(*gotypes.Struct).Fields {
 		fieldName := item.Name
-		// anonymous field (can be embedded struct as well)
-		if fieldName == "" {
+		// embedded field (can be embedded struct as well),
+		// embedded fields of older artefacts have no name (nor the embedded flag)
+		if item.Embedded || fieldName == "" {
 			itemExpr := item.Def
 			if itemExpr.GetType() == gotypes.PointerType {
 				itemExpr = itemExpr.(*gotypes.Pointer).Def
@@ -1016,8 +1265,45 @@ ITEMS_LOOP:
 
 				embeddedDataTypes = append(embeddedDataTypes, embeddedDataTypesItem{symbolTable: st, symbolDef: sd})
 				continue
+			case *gotypes.Instance:
+				// e.g. struct{ List[int] }
+				var st symbols.SymbolLookable
+				var sd *symbols.SymbolDef
+				switch d := fieldType.Def.(type) {
+				case *gotypes.Identifier:
+					if !accessor.methodsOnly && d.Def == accessor.field.String() {
+						fieldItem = &item
+						break ITEMS_LOOP
+					}
+					def, err := accessor.symbolTable.LookupDataType(d.Def)
+					if err != nil {
+						return nil, fmt.Errorf("Unable to retrieve %q type definition when retrieving a field", d.Def)
+					}
+					st, sd = accessor.symbolTable, def
+				case *gotypes.Selector:
+					if !accessor.methodsOnly && d.Item == accessor.field.String() {
+						fieldItem = &item
+						break ITEMS_LOOP
+					}
+					table, def, err := a.RetrieveQidDataType(d.Prefix, &ast.Ident{Name: d.Item})
+					if err != nil {
+						return nil, err
+					}
+					st, sd = table, def
+				default:
+					return nil, fmt.Errorf("Unknown generic data type anonymous field type %#v", itemExpr)
+				}
+				if sd.Def == nil {
+					return nil, fmt.Errorf("Symbol %q not yet fully processed", sd.Name)
+				}
+				// fields of the instance with the type parameters substituted,
+				// methods get substituted once retrieved
+				instanceDef := *sd
+				instanceDef.Def = gotypes.Substitute(sd.Def, sd.Typeparams, fieldType.Args)
+				embeddedDataTypes = append(embeddedDataTypes, embeddedDataTypesItem{symbolTable: st, symbolDef: &instanceDef, instance: fieldType})
+				continue
 			default:
-				panic(fmt.Errorf("Unknown data type anonymous field type %#v", itemExpr))
+				return nil, fmt.Errorf("Unknown data type anonymous field type %#v", itemExpr)
 			}
 		}
 		if !accessor.methodsOnly && fieldName == accessor.field.String() {
@@ -1034,6 +1320,7 @@ ITEMS_LOOP:
 			DataType:    fieldItem.Def,
 			SymbolTable: accessor.symbolTable,
 			Origin:      []*symbols.SymbolDef{accessor.dataTypeDef},
+			Tag:         fieldItem.Tag,
 		}, nil
 	}
 
@@ -1064,6 +1351,10 @@ ITEMS_LOOP:
 				methodsOnly: accessor.methodsOnly,
 				fieldsOnly:  accessor.fieldsOnly,
 			}); err == nil {
+				// A method receiver can rename type parameters of its data type
+				if method, ok := fieldDefAttr.DataType.(*gotypes.Method); ok && item.instance != nil {
+					fieldDefAttr.DataType = gotypes.Substitute(method, method.Typeparams, item.instance.Args)
+				}
 				fieldDefAttr.Origin = append(fieldDefAttr.Origin, item.symbolDef)
 				return fieldDefAttr, nil
 			}
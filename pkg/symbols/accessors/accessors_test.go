@@ -0,0 +1,151 @@
+package accessors
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/symbols"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
+	"github.com/gofed/symbols-extractor/pkg/symbols/tables/global"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+const (
+	netPkg = "example.com/net"
+	appPkg = "example.com/app"
+)
+
+func addDataType(t *testing.T, table *tables.Table, pkg, name string, alias bool, def gotypes.DataType) {
+	if err := table.AddDataType(&symbols.SymbolDef{Name: name, Package: pkg, Def: def, Alias: alias}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func addMethod(t *testing.T, table *tables.Table, pkg, receiver, name string) {
+	if err := table.AddFunction(&symbols.SymbolDef{
+		Name:    name,
+		Package: pkg,
+		Def: &gotypes.Method{
+			Receiver: &gotypes.Identifier{Package: pkg, Def: receiver},
+			Def:      &gotypes.Function{},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newAliasAccessor creates an accessor of the example.com/app package:
+//
+//	package net
+//	type Conn struct{}
+//	func (c Conn) Read()
+//	func (c Conn) Close()
+//	type Handle = Conn
+//
+//	package app
+//	type Conn = net.Conn
+//	type Stream = Conn
+//	type Defined net.Conn
+//	type impl struct{}
+//	func (i impl) Run()
+//	type Impl = impl
+//	func (i Impl) Stop()
+func newAliasAccessor(t *testing.T) *Accessor {
+	netTable := tables.NewTable()
+	addDataType(t, netTable, netPkg, "Conn", false, &gotypes.Struct{})
+	addMethod(t, netTable, netPkg, "Conn", "Read")
+	addMethod(t, netTable, netPkg, "Conn", "Close")
+	// the package of an identifier in the same package is not always set
+	addDataType(t, netTable, netPkg, "Handle", true, &gotypes.Identifier{Def: "Conn"})
+
+	netConn := &gotypes.Selector{Prefix: &gotypes.Packagequalifier{Path: netPkg, Name: "net"}, Item: "Conn"}
+	appTable := tables.NewTable()
+	addDataType(t, appTable, appPkg, "Conn", true, netConn)
+	addDataType(t, appTable, appPkg, "Stream", true, &gotypes.Identifier{Package: appPkg, Def: "Conn"})
+	addDataType(t, appTable, appPkg, "Defined", false, netConn)
+	addDataType(t, appTable, appPkg, "impl", false, &gotypes.Struct{})
+	addMethod(t, appTable, appPkg, "impl", "Run")
+	addDataType(t, appTable, appPkg, "Impl", true, &gotypes.Identifier{Package: appPkg, Def: "impl"})
+	addMethod(t, appTable, appPkg, "Impl", "Stop")
+
+	gtable := global.New("", "", nil)
+	if err := gtable.Add(netPkg, netTable, false); err != nil {
+		t.Fatal(err)
+	}
+	return NewAccessor(gtable).SetCurrentTable(appPkg, appTable)
+}
+
+func TestAliasTarget(t *testing.T) {
+	a := newAliasAccessor(t)
+	tests := []struct {
+		ident  *gotypes.Identifier
+		target *gotypes.Identifier
+	}{
+		{ident: &gotypes.Identifier{Package: netPkg, Def: "Handle"}, target: &gotypes.Identifier{Package: netPkg, Def: "Conn"}},
+		{ident: &gotypes.Identifier{Package: appPkg, Def: "Conn"}, target: &gotypes.Identifier{Package: netPkg, Def: "Conn"}},
+		{ident: &gotypes.Identifier{Package: appPkg, Def: "Stream"}, target: &gotypes.Identifier{Package: appPkg, Def: "Conn"}},
+		{ident: &gotypes.Identifier{Package: appPkg, Def: "Impl"}, target: &gotypes.Identifier{Package: appPkg, Def: "impl"}},
+		// not aliases
+		{ident: &gotypes.Identifier{Package: netPkg, Def: "Conn"}},
+		{ident: &gotypes.Identifier{Package: appPkg, Def: "Defined"}},
+		{ident: &gotypes.Identifier{Package: appPkg, Def: "Unknown"}},
+	}
+	for _, test := range tests {
+		if target := a.aliasTarget(test.ident); !reflect.DeepEqual(target, test.target) {
+			t.Errorf("Expected %v.%v to be an alias of %#v, got %#v", test.ident.Package, test.ident.Def, test.target, target)
+		}
+	}
+}
+
+func TestLookupMethodsOfAlias(t *testing.T) {
+	a := newAliasAccessor(t)
+	tests := []struct {
+		name    string
+		ident   *gotypes.Identifier
+		methods []string
+	}{
+		{name: "defined type", ident: &gotypes.Identifier{Package: netPkg, Def: "Conn"}, methods: []string{"Close", "Read"}},
+		{name: "alias in the same package", ident: &gotypes.Identifier{Package: netPkg, Def: "Handle"}, methods: []string{"Close", "Read"}},
+		{name: "alias of a type from another package", ident: &gotypes.Identifier{Package: appPkg, Def: "Conn"}, methods: []string{"Close", "Read"}},
+		{name: "alias of an alias", ident: &gotypes.Identifier{Package: appPkg, Def: "Stream"}, methods: []string{"Close", "Read"}},
+		{name: "methods declared on the alias", ident: &gotypes.Identifier{Package: appPkg, Def: "Impl"}, methods: []string{"Run", "Stop"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			methods, err := a.LookupAllMethods(test.ident)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for name := range methods {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, test.methods) {
+				t.Errorf("Expected %v methods, got %v", test.methods, names)
+			}
+			for _, name := range test.methods {
+				if _, err := a.LookupMethod(test.ident, name); err != nil {
+					t.Errorf("Unable to find %v method: %v", name, err)
+				}
+			}
+		})
+	}
+
+	// methods of the aliased type are not methods of a defined type
+	defined := &gotypes.Identifier{Package: appPkg, Def: "Defined"}
+	if _, err := a.LookupMethod(defined, "Read"); err == nil {
+		t.Errorf("Expected Read not to be a method of %v", defined.Def)
+	}
+	if methods, err := a.LookupAllMethods(defined); err == nil {
+		t.Errorf("Expected no methods of %v, got %v", defined.Def, methods)
+	}
+	// methods declared on the alias are not methods of the aliased type
+	if _, err := a.LookupMethod(&gotypes.Identifier{Package: appPkg, Def: "impl"}, "Stop"); err == nil {
+		t.Errorf("Expected Stop not to be a method of impl")
+	}
+	if _, err := a.LookupMethod(&gotypes.Identifier{Package: appPkg, Def: "Conn"}, "Write"); err == nil {
+		t.Errorf("Expected Write not to be a method of Conn")
+	}
+}
@@ -2,15 +2,25 @@ package symbols
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 )
 
 type SymbolDef struct {
-	Pos     string           `json:"pos"`
-	Name    string           `json:"name"`
-	Package string           `json:"package"`
-	Def     gotypes.DataType `json:"def"`
-	Block   int              `json:"block"`
+	Pos string `json:"pos"`
+	// Structured position (file, line, column and end position)
+	Position   *positions.Position `json:"position,omitempty"`
+	Name       string              `json:"name"`
+	Package    string              `json:"package"`
+	Def        gotypes.DataType    `json:"def"`
+	Block      int                 `json:"block"`
+	Typeparams []gotypes.DataType  `json:"typeparams,omitempty"`
+	// Data type is an alias (type A = B) of its definition
+	Alias bool `json:"alias,omitempty"`
+	// Platforms the symbol is defined on (set in a merged per-platform view only)
+	Platforms []string `json:"platforms,omitempty"`
 }
 
 func (o *SymbolDef) UnmarshalJSON(b []byte) error {
@@ -24,6 +34,15 @@ func (o *SymbolDef) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	// older artefacts have the pos only
+	if objMap["position"] != nil {
+		if err := json.Unmarshal(*objMap["position"], &o.Position); err != nil {
+			return err
+		}
+	} else {
+		o.Position = positions.Parse(o.Pos)
+	}
+
 	if err := json.Unmarshal(*objMap["name"], &o.Name); err != nil {
 		return err
 	}
@@ -38,6 +57,32 @@ func (o *SymbolDef) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	if objMap["typeparams"] != nil {
+		var l []*json.RawMessage
+		if err := json.Unmarshal(*objMap["typeparams"], &l); err != nil {
+			return err
+		}
+		for _, item := range l {
+			r := &gotypes.Typeparam{}
+			if err := json.Unmarshal(*item, &r); err != nil {
+				return err
+			}
+			o.Typeparams = append(o.Typeparams, r)
+		}
+	}
+
+	if objMap["alias"] != nil {
+		if err := json.Unmarshal(*objMap["alias"], &o.Alias); err != nil {
+			return err
+		}
+	}
+
+	if objMap["platforms"] != nil {
+		if err := json.Unmarshal(*objMap["platforms"], &o.Platforms); err != nil {
+			return err
+		}
+	}
+
 	var m map[string]interface{}
 	if err := json.Unmarshal(*objMap["def"], &m); err != nil {
 		return err
@@ -150,12 +195,39 @@ func (o *SymbolDef) UnmarshalJSON(b []byte) error {
 		}
 		o.Def = r
 
+	case gotypes.TypeparamType:
+		r := &gotypes.Typeparam{}
+		if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+			return err
+		}
+		o.Def = r
+
+	case gotypes.InstanceType:
+		r := &gotypes.Instance{}
+		if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+			return err
+		}
+		o.Def = r
+
+	case gotypes.UnionType:
+		r := &gotypes.Union{}
+		if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+			return err
+		}
+		o.Def = r
+
 	case gotypes.NilType:
 		r := &gotypes.Nil{}
 		if err := json.Unmarshal(*objMap["def"], &r); err != nil {
 			return err
 		}
 		o.Def = r
+
+	case nil:
+		// no definition
+
+	default:
+		return fmt.Errorf("Unknown type %q of %q symbol definition (generated by a newer version?)", dataType, o.Name)
 	}
 
 	return nil
@@ -106,8 +106,9 @@ func processDataType(m map[interface{}]interface{}) gotypes.DataType {
 		for _, field := range structType.([]interface{}) {
 			fieldExpr := field.(map[interface{}]interface{})
 			item := gotypes.StructFieldsItem{
-				Name: fieldExpr["name"].(string),
-				Def:  processDataType(fieldExpr),
+				Name:  fieldExpr["name"].(string),
+				Order: len(fields),
+				Def:   processDataType(fieldExpr),
 			}
 			fields = append(fields, item)
 		}
@@ -3,11 +3,10 @@ package global
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path"
+	"sync"
 
 	"github.com/gofed/symbols-extractor/pkg/snapshots"
+	"github.com/gofed/symbols-extractor/pkg/store"
 	"github.com/gofed/symbols-extractor/pkg/symbols"
 	"github.com/gofed/symbols-extractor/pkg/symbols/tables"
 	"k8s.io/klog/v2"
@@ -15,26 +14,22 @@ import (
 
 type Table struct {
 	symbolTableDir string
+	artefacts      store.Store
 	goVersion      string
 	glide          snapshots.Snapshot
 	tables         map[string]symbols.SymbolTable
 	fromFile       map[string]struct{}
+	// guards the tables (used by concurrent package extractions)
+	mutex sync.Mutex
 }
 
-func (t *Table) getPackagePath(pkg string) string {
-	packagePath := path.Join(t.symbolTableDir, "golang", t.goVersion, pkg)
-	if _, err := os.Stat(packagePath); err == nil {
-		return packagePath
-	}
-
-	packagePath = path.Join(t.symbolTableDir, pkg)
-	if t.glide != nil {
-		if commit, err := t.glide.Commit(pkg); err == nil {
-			return path.Join(packagePath, commit)
-		}
-	}
+func (t *Table) key(pkg string) store.Key {
+	return store.PackageKey(t.artefacts, pkg, t.goVersion, t.glide, store.APIKind)
+}
 
-	return packagePath
+// Stdlib checks if a package is provided by the extracted standard library
+func (t *Table) Stdlib(pkg string) bool {
+	return t.artefacts.Exists(store.Key{Package: pkg, GoVersion: t.goVersion})
 }
 
 func (t *Table) loadFromFile(pkg string) (symbols.SymbolTable, error) {
@@ -42,10 +37,11 @@ func (t *Table) loadFromFile(pkg string) (symbols.SymbolTable, error) {
 		return nil, fmt.Errorf("Unable to load %q, symbol table dir not set", pkg)
 	}
 
-	file := path.Join(t.getPackagePath(pkg), "api.json")
+	key := t.key(pkg)
+	file := key.Path()
 	klog.V(2).Infof("Global symbol table %q loading", file)
 
-	raw, err := ioutil.ReadFile(file)
+	raw, err := t.artefacts.Load(key)
 	if err != nil {
 		klog.V(2).Infof("Global symbol table %q loading failed: %v", file, err)
 		return nil, fmt.Errorf("Unable to load %q symbol table from %q: %v", pkg, file, err)
@@ -61,6 +57,9 @@ func (t *Table) loadFromFile(pkg string) (symbols.SymbolTable, error) {
 }
 
 func (t *Table) Lookup(pkg string) (symbols.SymbolTable, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	if table, ok := t.tables[pkg]; ok {
 		klog.V(2).Infof("Global symbol table %q found", pkg)
 		return table, nil
@@ -79,20 +78,22 @@ func (t *Table) Lookup(pkg string) (symbols.SymbolTable, error) {
 }
 
 func (t *Table) Exists(pkg string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	_, ok := t.tables[pkg]
 	if ok {
 		return true
 	}
 
 	// check if the symbol table is available locally
-	if _, err := os.Stat(path.Join(t.getPackagePath(pkg), "api.json")); err == nil {
-		return true
-	}
-
-	return false
+	return t.artefacts.Exists(t.key(pkg))
 }
 
 func (t *Table) Add(pkg string, table symbols.SymbolTable, store bool) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	if _, ok := t.tables[pkg]; ok {
 		return fmt.Errorf("Symbol table for %q already exist in the global symbol table", pkg)
 	}
@@ -109,11 +110,29 @@ func (t *Table) Add(pkg string, table symbols.SymbolTable, store bool) error {
 	return nil
 }
 
+// AddTransient adds (or replaces) a package symbol table that is never stored
+// (e.g. a package extended with its _test.go files)
+func (t *Table) AddTransient(pkg string, table symbols.SymbolTable) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.tables[pkg] = table
+	t.fromFile[pkg] = struct{}{}
+}
+
 func (t *Table) Drop(pkg string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	delete(t.tables, pkg)
+	// the package can be processed again (and stored)
+	delete(t.fromFile, pkg)
 }
 
 func (t *Table) Packages() []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
 	var keys []string
 	for key, _ := range t.tables {
 		keys = append(keys, key)
@@ -124,6 +143,7 @@ func (t *Table) Packages() []string {
 func New(symbolTableDir, goVersion string, snapshot snapshots.Snapshot) *Table {
 	return &Table{
 		symbolTableDir: symbolTableDir,
+		artefacts:      store.For(symbolTableDir),
 		goVersion:      goVersion,
 		glide:          snapshot,
 		tables:         make(map[string]symbols.SymbolTable, 0),
@@ -137,15 +157,8 @@ func (t *Table) store(pkg string, table *tables.Table) error {
 		return nil
 	}
 
-	packagePath := t.getPackagePath(pkg)
-
-	pErr := os.MkdirAll(packagePath, 0777)
-	if pErr != nil {
-		return fmt.Errorf("Unable to create package path %v: %v", packagePath, pErr)
-	}
-
-	file := path.Join(packagePath, "api.json")
-	if _, err := os.Stat(file); err == nil {
+	key := t.key(pkg)
+	if t.artefacts.Exists(key) {
 		return nil
 	}
 
@@ -154,18 +167,14 @@ func (t *Table) store(pkg string, table *tables.Table) error {
 		return fmt.Errorf("Unable to save %q symbol table: %v", pkg, err)
 	}
 
-	if err := ioutil.WriteFile(file, byteSlice, 0644); err != nil {
-		return err
-	}
-	return nil
+	return t.artefacts.Save(key, byteSlice)
 }
 
+// Save stores all symbol tables not loaded from the store
+// (the store creates the package locations itself)
 func (t *Table) Save(symboltabledir string) error {
-	// create the dir if it does not exist
-	err := os.MkdirAll(symboltabledir, 0777)
-	if err != nil {
-		return fmt.Errorf("Unable to create directory path %v: %v", symboltabledir, err)
-	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 
 	for key, symbolTable := range t.tables {
 		st, ok := symbolTable.(*tables.Table)
@@ -3,6 +3,7 @@ package tables
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gofed/symbols-extractor/pkg/symbols"
@@ -110,6 +111,8 @@ func (t *Table) addSymbol(symbolType string, name string, sym *symbols.SymbolDef
 			return fmt.Errorf("Symbol '%s' already exists", name)
 		}
 		def.Def = sym.Def
+		def.Typeparams = sym.Typeparams
+		def.Alias = sym.Alias
 		return nil
 	}
 
@@ -247,4 +250,54 @@ func (t *Table) Lookup(key string) (*symbols.SymbolDef, symbols.SymbolType, erro
 	return nil, symbols.SymbolType(""), fmt.Errorf("Symbol `%v` not found", key)
 }
 
+// MergePlatforms merges symbol tables of a package extracted for individual platforms.
+// Each symbol of the merged table lists platforms it is defined on.
+// If a symbol is defined differently on various platforms, the definition
+// of the first platform (in the alphabetical order) is kept.
+func MergePlatforms(platformTables map[string]*Table) (*Table, error) {
+	var platforms []string
+	for platform := range platformTables {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	merged := NewTable()
+	imports := make(map[string]struct{})
+	for _, platform := range platforms {
+		t := platformTables[platform]
+		if merged.PackageQID == "" {
+			merged.PackageQID = t.PackageQID
+		}
+		for _, item := range t.Imports {
+			imports[item] = struct{}{}
+		}
+		for _, symbolType := range symbols.SymbolTypes {
+			// methods are stored under <TYPE>.<METHOD> key
+			keys := make(map[*symbols.SymbolDef]string)
+			for key, sym := range t.symbols[symbolType] {
+				keys[sym] = key
+			}
+			for _, sym := range t.Symbols[symbolType] {
+				key := keys[sym]
+				if def, ok := merged.symbols[symbolType][key]; ok {
+					def.Platforms = append(def.Platforms, platform)
+					continue
+				}
+				def := *sym
+				def.Platforms = []string{platform}
+				if err := merged.addSymbol(symbolType, key, &def); err != nil {
+					return nil, fmt.Errorf("Unable to merge %q symbol of %v platform: %v", key, platform, err)
+				}
+			}
+		}
+	}
+
+	for item := range imports {
+		merged.Imports = append(merged.Imports, item)
+	}
+	sort.Strings(merged.Imports)
+
+	return merged, nil
+}
+
 var _ symbols.SymbolLookable = &Table{}
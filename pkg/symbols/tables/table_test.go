@@ -0,0 +1,133 @@
+package tables
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofed/symbols-extractor/pkg/symbols"
+	gotypes "github.com/gofed/symbols-extractor/pkg/types"
+)
+
+func TestMergePlatforms(t *testing.T) {
+	newTable := func(imports []string, variables, datatypes, functions []*symbols.SymbolDef) *Table {
+		table := NewTable()
+		table.PackageQID = "example.com/pkg"
+		table.Imports = imports
+		for _, sym := range variables {
+			if err := table.AddVariable(sym); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, sym := range datatypes {
+			if err := table.AddDataType(sym); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, sym := range functions {
+			if err := table.AddFunction(sym); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return table
+	}
+
+	intType := &gotypes.Identifier{Def: "int", Package: "builtin"}
+	int64Type := &gotypes.Identifier{Def: "int64", Package: "builtin"}
+	handle := func(def gotypes.DataType) *symbols.SymbolDef {
+		return &symbols.SymbolDef{Name: "Handle", Package: "example.com/pkg", Def: def}
+	}
+	fd := func() *symbols.SymbolDef {
+		return &symbols.SymbolDef{
+			Name:    "Fd",
+			Package: "example.com/pkg",
+			Def: &gotypes.Method{
+				Receiver: &gotypes.Pointer{Def: &gotypes.Identifier{Def: "File", Package: "example.com/pkg"}},
+				Def:      &gotypes.Function{Package: "example.com/pkg", Results: []gotypes.DataType{intType}},
+			},
+		}
+	}
+	version := func() *symbols.SymbolDef {
+		return &symbols.SymbolDef{Name: "Version", Package: "example.com/pkg", Def: intType}
+	}
+	file := func() *symbols.SymbolDef {
+		return &symbols.SymbolDef{Name: "File", Package: "example.com/pkg", Def: &gotypes.Struct{}}
+	}
+
+	merged, err := MergePlatforms(map[string]*Table{
+		"windows/amd64": newTable(
+			[]string{"syscall", "unsafe"},
+			[]*symbols.SymbolDef{version()},
+			[]*symbols.SymbolDef{file(), handle(int64Type)},
+			nil,
+		),
+		"linux/amd64": newTable(
+			[]string{"syscall"},
+			[]*symbols.SymbolDef{version()},
+			[]*symbols.SymbolDef{file(), handle(intType)},
+			[]*symbols.SymbolDef{fd()},
+		),
+		"darwin/arm64": newTable(
+			[]string{"os"},
+			[]*symbols.SymbolDef{version()},
+			[]*symbols.SymbolDef{file()},
+			[]*symbols.SymbolDef{fd()},
+		),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if merged.PackageQID != "example.com/pkg" {
+		t.Errorf("Expected example.com/pkg package, got %q", merged.PackageQID)
+	}
+	if expected := []string{"os", "syscall", "unsafe"}; !reflect.DeepEqual(merged.Imports, expected) {
+		t.Errorf("Expected %v imports, got %v", expected, merged.Imports)
+	}
+
+	lookups := []struct {
+		name      string
+		lookup    func() (*symbols.SymbolDef, error)
+		platforms []string
+		def       gotypes.DataType
+	}{
+		{
+			name:      "Version",
+			lookup:    func() (*symbols.SymbolDef, error) { return merged.LookupVariable("Version") },
+			platforms: []string{"darwin/arm64", "linux/amd64", "windows/amd64"},
+			def:       intType,
+		},
+		{
+			name:      "File",
+			lookup:    func() (*symbols.SymbolDef, error) { return merged.LookupDataType("File") },
+			platforms: []string{"darwin/arm64", "linux/amd64", "windows/amd64"},
+			def:       &gotypes.Struct{},
+		},
+		{
+			// the definition of the first platform in the alphabetical order is kept
+			name:      "Handle",
+			lookup:    func() (*symbols.SymbolDef, error) { return merged.LookupDataType("Handle") },
+			platforms: []string{"linux/amd64", "windows/amd64"},
+			def:       intType,
+		},
+		{
+			name:      "File.Fd",
+			lookup:    func() (*symbols.SymbolDef, error) { return merged.LookupMethod("File", "Fd") },
+			platforms: []string{"darwin/arm64", "linux/amd64"},
+			def:       fd().Def,
+		},
+	}
+
+	for _, l := range lookups {
+		sym, err := l.lookup()
+		if err != nil {
+			t.Errorf("Unable to find %q: %v", l.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(sym.Platforms, l.platforms) {
+			t.Errorf("Expected %q to be defined on %v, got %v", l.name, l.platforms, sym.Platforms)
+		}
+		if !reflect.DeepEqual(sym.Def, l.def) {
+			t.Errorf("Expected %q to be defined as %#v, got %#v", l.name, l.def, sym.Def)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package types
+
+// Substitute returns a copy of a data type definition with all type parameters
+// listed in params replaced by the corresponding type arguments.
+// E.g. given 'type List[T any] struct { items []T }' instantiated as List[int],
+// the definition of List gets substituted into 'struct { items []int }'.
+// Type parameters not listed in params are kept as they are.
+func Substitute(def DataType, params, args []DataType) DataType {
+	mapping := make(map[string]DataType)
+	for i, param := range params {
+		if i >= len(args) {
+			break
+		}
+		if tp, ok := param.(*Typeparam); ok {
+			mapping[tp.Name] = args[i]
+		}
+	}
+	if len(mapping) == 0 {
+		return def
+	}
+	return substitute(def, mapping)
+}
+
+func substituteList(list []DataType, mapping map[string]DataType) []DataType {
+	if list == nil {
+		return nil
+	}
+	items := make([]DataType, 0, len(list))
+	for _, item := range list {
+		items = append(items, substitute(item, mapping))
+	}
+	return items
+}
+
+// remainingTypeparams drops all type parameters that got substituted
+func remainingTypeparams(list []DataType, mapping map[string]DataType) []DataType {
+	var items []DataType
+	for _, item := range list {
+		if tp, ok := item.(*Typeparam); ok {
+			if _, ok := mapping[tp.Name]; ok {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func substitute(def DataType, mapping map[string]DataType) DataType {
+	switch d := def.(type) {
+	case *Typeparam:
+		if arg, ok := mapping[d.Name]; ok {
+			return arg
+		}
+		return d
+	case *Pointer:
+		return &Pointer{Def: substitute(d.Def, mapping)}
+	case *Slice:
+		return &Slice{Elmtype: substitute(d.Elmtype, mapping)}
+	case *Array:
+		return &Array{Elmtype: substitute(d.Elmtype, mapping), Len: d.Len}
+	case *Map:
+		return &Map{
+			Keytype:   substitute(d.Keytype, mapping),
+			Valuetype: substitute(d.Valuetype, mapping),
+		}
+	case *Channel:
+		return &Channel{Dir: d.Dir, Value: substitute(d.Value, mapping)}
+	case *Ellipsis:
+		return &Ellipsis{Def: substitute(d.Def, mapping)}
+	case *Function:
+		return &Function{
+			Package:    d.Package,
+			Params:     substituteList(d.Params, mapping),
+			Results:    substituteList(d.Results, mapping),
+			Typeparams: remainingTypeparams(d.Typeparams, mapping),
+		}
+	case *Method:
+		return &Method{
+			Receiver:   d.Receiver,
+			Def:        substitute(d.Def, mapping),
+			Typeparams: remainingTypeparams(d.Typeparams, mapping),
+		}
+	case *Struct:
+		fields := make([]StructFieldsItem, 0, len(d.Fields))
+		for _, field := range d.Fields {
+			field.Def = substitute(field.Def, mapping)
+			fields = append(fields, field)
+		}
+		return &Struct{Fields: fields}
+	case *Interface:
+		if d.Methods == nil {
+			return d
+		}
+		methods := make([]InterfaceMethodsItem, 0, len(d.Methods))
+		for _, method := range d.Methods {
+			methods = append(methods, InterfaceMethodsItem{Name: method.Name, Def: substitute(method.Def, mapping)})
+		}
+		return &Interface{Methods: methods}
+	case *Instance:
+		return &Instance{Def: d.Def, Args: substituteList(d.Args, mapping)}
+	case *Union:
+		terms := make([]UnionTermsItem, 0, len(d.Terms))
+		for _, term := range d.Terms {
+			terms = append(terms, UnionTermsItem{Tilde: term.Tilde, Def: substitute(term.Def, mapping)})
+		}
+		return &Union{Terms: terms}
+	default:
+		// identifiers, selectors, builtins, constants, ... carry no type parameters
+		return def
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // DataType is
 type DataType interface {
@@ -12,8 +15,9 @@ const FunctionType = "function"
 type Function struct {
 	Package string `json:"package"`
 
-	Params  []DataType `json:"params"`
-	Results []DataType `json:"results"`
+	Params     []DataType `json:"params"`
+	Results    []DataType `json:"results"`
+	Typeparams []DataType `json:"typeparams"`
 }
 
 func (o *Function) GetType() string {
@@ -150,6 +154,25 @@ func (o *Function) UnmarshalJSON(b []byte) error {
 					}
 					o.Params = append(o.Params, r)
 
+				case TypeparamType:
+					r := &Typeparam{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Params = append(o.Params, r)
+
+				case InstanceType:
+					r := &Instance{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Params = append(o.Params, r)
+
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Function.Params (generated by a newer version?)", dataType)
 				}
 			}
 		}
@@ -255,6 +278,58 @@ func (o *Function) UnmarshalJSON(b []byte) error {
 					}
 					o.Results = append(o.Results, r)
 
+				case TypeparamType:
+					r := &Typeparam{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Results = append(o.Results, r)
+
+				case InstanceType:
+					r := &Instance{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Results = append(o.Results, r)
+
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Function.Results (generated by a newer version?)", dataType)
+				}
+			}
+		}
+	}
+
+	// block for Typeparams field
+	{
+		if objMap["typeparams"] != nil {
+			var l []*json.RawMessage
+			if err := json.Unmarshal(*objMap["typeparams"], &l); err != nil {
+				return err
+			}
+
+			o.Typeparams = make([]DataType, 0)
+			for _, item := range l {
+				var m map[string]interface{}
+				if err := json.Unmarshal(*item, &m); err != nil {
+					return err
+				}
+				switch dataType := m["type"]; dataType {
+
+				case TypeparamType:
+					r := &Typeparam{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Typeparams = append(o.Typeparams, r)
+
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Function.Typeparams (generated by a newer version?)", dataType)
 				}
 			}
 		}
@@ -386,6 +461,25 @@ func (o *Map) UnmarshalJSON(b []byte) error {
 				}
 				o.Keytype = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["keytype"], &r); err != nil {
+					return err
+				}
+				o.Keytype = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["keytype"], &r); err != nil {
+					return err
+				}
+				o.Keytype = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Map.Keytype (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -484,6 +578,25 @@ func (o *Map) UnmarshalJSON(b []byte) error {
 				}
 				o.Valuetype = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["valuetype"], &r); err != nil {
+					return err
+				}
+				o.Valuetype = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["valuetype"], &r); err != nil {
+					return err
+				}
+				o.Valuetype = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Map.Valuetype (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -613,6 +726,25 @@ func (o *Slice) UnmarshalJSON(b []byte) error {
 				}
 				o.Elmtype = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["elmtype"], &r); err != nil {
+					return err
+				}
+				o.Elmtype = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["elmtype"], &r); err != nil {
+					return err
+				}
+				o.Elmtype = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Slice.Elmtype (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -627,6 +759,7 @@ type Constant struct {
 	Literal string `json:"literal"`
 	Def     string `json:"def"`
 	Package string `json:"package"`
+	Value   string `json:"value,omitempty"`
 }
 
 func (o *Constant) GetType() string {
@@ -671,13 +804,23 @@ func (o *Constant) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	// optional field (not set in older artefacts)
+	if objMap["value"] != nil {
+		if err := json.Unmarshal(*objMap["value"], &o.Value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 const StructFieldsItemType = "structfieldsitem"
 
 type StructFieldsItem struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Tag      string `json:"tag,omitempty"`
+	Embedded bool   `json:"embedded,omitempty"`
+	Order    int    `json:"order,omitempty"`
 
 	Def DataType `json:"def"`
 }
@@ -709,6 +852,27 @@ func (o *StructFieldsItem) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
+	// optional field (not set in older artefacts)
+	if objMap["tag"] != nil {
+		if err := json.Unmarshal(*objMap["tag"], &o.Tag); err != nil {
+			return err
+		}
+	}
+
+	// optional field (not set in older artefacts)
+	if objMap["embedded"] != nil {
+		if err := json.Unmarshal(*objMap["embedded"], &o.Embedded); err != nil {
+			return err
+		}
+	}
+
+	// optional field (not set in older artefacts)
+	if objMap["order"] != nil {
+		if err := json.Unmarshal(*objMap["order"], &o.Order); err != nil {
+			return err
+		}
+	}
+
 	// block for Def field
 	{
 		if objMap["def"] != nil {
@@ -803,6 +967,25 @@ func (o *StructFieldsItem) UnmarshalJSON(b []byte) error {
 				}
 				o.Def = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of StructFieldsItem.Def (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -861,6 +1044,11 @@ func (o *Struct) UnmarshalJSON(b []byte) error {
 					}
 					o.Fields = append(o.Fields, *r)
 
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Struct.Fields (generated by a newer version?)", dataType)
 				}
 			}
 		}
@@ -991,6 +1179,25 @@ func (o *Pointer) UnmarshalJSON(b []byte) error {
 				}
 				o.Def = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Pointer.Def (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -1057,6 +1264,11 @@ func (o *Selector) UnmarshalJSON(b []byte) error {
 				}
 				o.Prefix = r
 
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Selector.Prefix (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -1192,6 +1404,25 @@ func (o *InterfaceMethodsItem) UnmarshalJSON(b []byte) error {
 				}
 				o.Def = r
 
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case UnionType:
+				r := &Union{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of InterfaceMethodsItem.Def (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -1250,6 +1481,11 @@ func (o *Interface) UnmarshalJSON(b []byte) error {
 					}
 					o.Methods = append(o.Methods, *r)
 
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Interface.Methods (generated by a newer version?)", dataType)
 				}
 			}
 		}
@@ -1380,6 +1616,25 @@ func (o *Ellipsis) UnmarshalJSON(b []byte) error {
 				}
 				o.Def = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Ellipsis.Def (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -1516,6 +1771,25 @@ func (o *Array) UnmarshalJSON(b []byte) error {
 				}
 				o.Elmtype = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["elmtype"], &r); err != nil {
+					return err
+				}
+				o.Elmtype = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["elmtype"], &r); err != nil {
+					return err
+				}
+				o.Elmtype = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Array.Elmtype (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -1610,8 +1884,9 @@ func (o *Packagequalifier) UnmarshalJSON(b []byte) error {
 const MethodType = "method"
 
 type Method struct {
-	Def      DataType `json:"def"`
-	Receiver DataType `json:"receiver"`
+	Def        DataType   `json:"def"`
+	Receiver   DataType   `json:"receiver"`
+	Typeparams []DataType `json:"typeparams"`
 }
 
 func (o *Method) GetType() string {
@@ -1653,6 +1928,11 @@ func (o *Method) UnmarshalJSON(b []byte) error {
 				}
 				o.Def = r
 
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Method.Def (generated by a newer version?)", dataType)
 			}
 		}
 	}
@@ -1695,6 +1975,44 @@ func (o *Method) UnmarshalJSON(b []byte) error {
 				}
 				o.Receiver = r
 
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Method.Receiver (generated by a newer version?)", dataType)
+			}
+		}
+	}
+
+	// block for Typeparams field
+	{
+		if objMap["typeparams"] != nil {
+			var l []*json.RawMessage
+			if err := json.Unmarshal(*objMap["typeparams"], &l); err != nil {
+				return err
+			}
+
+			o.Typeparams = make([]DataType, 0)
+			for _, item := range l {
+				var m map[string]interface{}
+				if err := json.Unmarshal(*item, &m); err != nil {
+					return err
+				}
+				switch dataType := m["type"]; dataType {
+
+				case TypeparamType:
+					r := &Typeparam{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Typeparams = append(o.Typeparams, r)
+
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Method.Typeparams (generated by a newer version?)", dataType)
+				}
 			}
 		}
 	}
@@ -1831,6 +2149,596 @@ func (o *Channel) UnmarshalJSON(b []byte) error {
 				}
 				o.Value = r
 
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["value"], &r); err != nil {
+					return err
+				}
+				o.Value = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["value"], &r); err != nil {
+					return err
+				}
+				o.Value = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Channel.Value (generated by a newer version?)", dataType)
+			}
+		}
+	}
+
+	return nil
+}
+
+const TypeparamType = "typeparam"
+
+type Typeparam struct {
+	Name string `json:"name"`
+
+	Constraint DataType `json:"constraint"`
+}
+
+func (o *Typeparam) GetType() string {
+	return TypeparamType
+}
+
+func (o *Typeparam) MarshalJSON() (b []byte, e error) {
+	type Copy Typeparam
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*Copy
+	}{
+		Type: TypeparamType,
+		Copy: (*Copy)(o),
+	})
+}
+
+func (o *Typeparam) UnmarshalJSON(b []byte) error {
+	var objMap map[string]*json.RawMessage
+
+	if err := json.Unmarshal(b, &objMap); err != nil {
+		return err
+	}
+
+	// TODO(jchaloup): check the objMap["name"] actually exists
+	if err := json.Unmarshal(*objMap["name"], &o.Name); err != nil {
+		return err
+	}
+
+	// block for Constraint field
+	{
+		if objMap["constraint"] != nil {
+			var m map[string]interface{}
+			if err := json.Unmarshal(*objMap["constraint"], &m); err != nil {
+				return err
+			}
+
+			switch dataType := m["type"]; dataType {
+
+			case IdentifierType:
+				r := &Identifier{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case BuiltinType:
+				r := &Builtin{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case SelectorType:
+				r := &Selector{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case ChannelType:
+				r := &Channel{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case SliceType:
+				r := &Slice{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case ArrayType:
+				r := &Array{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case MapType:
+				r := &Map{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case PointerType:
+				r := &Pointer{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case FunctionType:
+				r := &Function{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case MethodType:
+				r := &Method{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case InterfaceType:
+				r := &Interface{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case StructType:
+				r := &Struct{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case UnionType:
+				r := &Union{}
+				if err := json.Unmarshal(*objMap["constraint"], &r); err != nil {
+					return err
+				}
+				o.Constraint = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Typeparam.Constraint (generated by a newer version?)", dataType)
+			}
+		}
+	}
+
+	return nil
+}
+
+const InstanceType = "instance"
+
+type Instance struct {
+	Def DataType `json:"def"`
+
+	Args []DataType `json:"args"`
+}
+
+func (o *Instance) GetType() string {
+	return InstanceType
+}
+
+func (o *Instance) MarshalJSON() (b []byte, e error) {
+	type Copy Instance
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*Copy
+	}{
+		Type: InstanceType,
+		Copy: (*Copy)(o),
+	})
+}
+
+func (o *Instance) UnmarshalJSON(b []byte) error {
+	var objMap map[string]*json.RawMessage
+
+	if err := json.Unmarshal(b, &objMap); err != nil {
+		return err
+	}
+
+	// block for Def field
+	{
+		if objMap["def"] != nil {
+			var m map[string]interface{}
+			if err := json.Unmarshal(*objMap["def"], &m); err != nil {
+				return err
+			}
+
+			switch dataType := m["type"]; dataType {
+
+			case IdentifierType:
+				r := &Identifier{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case SelectorType:
+				r := &Selector{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of Instance.Def (generated by a newer version?)", dataType)
+			}
+		}
+	}
+
+	// block for Args field
+	{
+		if objMap["args"] != nil {
+			var l []*json.RawMessage
+			if err := json.Unmarshal(*objMap["args"], &l); err != nil {
+				return err
+			}
+
+			o.Args = make([]DataType, 0)
+			for _, item := range l {
+				var m map[string]interface{}
+				if err := json.Unmarshal(*item, &m); err != nil {
+					return err
+				}
+				switch dataType := m["type"]; dataType {
+
+				case IdentifierType:
+					r := &Identifier{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case BuiltinType:
+					r := &Builtin{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case SelectorType:
+					r := &Selector{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case ChannelType:
+					r := &Channel{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case SliceType:
+					r := &Slice{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case ArrayType:
+					r := &Array{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case MapType:
+					r := &Map{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case PointerType:
+					r := &Pointer{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case FunctionType:
+					r := &Function{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case MethodType:
+					r := &Method{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case InterfaceType:
+					r := &Interface{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case StructType:
+					r := &Struct{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case TypeparamType:
+					r := &Typeparam{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case InstanceType:
+					r := &Instance{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Args = append(o.Args, r)
+
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Instance.Args (generated by a newer version?)", dataType)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+const UnionTermsItemType = "uniontermsitem"
+
+type UnionTermsItem struct {
+	Tilde bool `json:"tilde"`
+
+	Def DataType `json:"def"`
+}
+
+func (o *UnionTermsItem) GetType() string {
+	return UnionTermsItemType
+}
+
+func (o *UnionTermsItem) MarshalJSON() (b []byte, e error) {
+	type Copy UnionTermsItem
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*Copy
+	}{
+		Type: UnionTermsItemType,
+		Copy: (*Copy)(o),
+	})
+}
+
+func (o *UnionTermsItem) UnmarshalJSON(b []byte) error {
+	var objMap map[string]*json.RawMessage
+
+	if err := json.Unmarshal(b, &objMap); err != nil {
+		return err
+	}
+
+	// TODO(jchaloup): check the objMap["tilde"] actually exists
+	if err := json.Unmarshal(*objMap["tilde"], &o.Tilde); err != nil {
+		return err
+	}
+
+	// block for Def field
+	{
+		if objMap["def"] != nil {
+			var m map[string]interface{}
+			if err := json.Unmarshal(*objMap["def"], &m); err != nil {
+				return err
+			}
+
+			switch dataType := m["type"]; dataType {
+
+			case IdentifierType:
+				r := &Identifier{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case BuiltinType:
+				r := &Builtin{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case SelectorType:
+				r := &Selector{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case ChannelType:
+				r := &Channel{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case SliceType:
+				r := &Slice{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case ArrayType:
+				r := &Array{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case MapType:
+				r := &Map{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case PointerType:
+				r := &Pointer{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case FunctionType:
+				r := &Function{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case MethodType:
+				r := &Method{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case InterfaceType:
+				r := &Interface{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case StructType:
+				r := &Struct{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case TypeparamType:
+				r := &Typeparam{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case InstanceType:
+				r := &Instance{}
+				if err := json.Unmarshal(*objMap["def"], &r); err != nil {
+					return err
+				}
+				o.Def = r
+
+			case nil:
+				// no data type
+
+			default:
+				return fmt.Errorf("Unknown type %q of UnionTermsItem.Def (generated by a newer version?)", dataType)
+			}
+		}
+	}
+
+	return nil
+}
+
+const UnionType = "union"
+
+type Union struct {
+	Terms []UnionTermsItem `json:"terms"`
+}
+
+func (o *Union) GetType() string {
+	return UnionType
+}
+
+func (o *Union) MarshalJSON() (b []byte, e error) {
+	type Copy Union
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		*Copy
+	}{
+		Type: UnionType,
+		Copy: (*Copy)(o),
+	})
+}
+
+func (o *Union) UnmarshalJSON(b []byte) error {
+	var objMap map[string]*json.RawMessage
+
+	if err := json.Unmarshal(b, &objMap); err != nil {
+		return err
+	}
+
+	// block for Terms field
+	{
+		if objMap["terms"] != nil {
+			var l []*json.RawMessage
+			if err := json.Unmarshal(*objMap["terms"], &l); err != nil {
+				return err
+			}
+
+			o.Terms = make([]UnionTermsItem, 0)
+			for _, item := range l {
+				var m map[string]interface{}
+				if err := json.Unmarshal(*item, &m); err != nil {
+					return err
+				}
+				switch dataType := m["type"]; dataType {
+
+				case UnionTermsItemType:
+					r := &UnionTermsItem{}
+					if err := json.Unmarshal(*item, &r); err != nil {
+						return err
+					}
+					o.Terms = append(o.Terms, *r)
+
+				case nil:
+					// no data type
+
+				default:
+					return fmt.Errorf("Unknown type %q of an item of Union.Terms (generated by a newer version?)", dataType)
+				}
 			}
 		}
 	}
@@ -16,6 +16,10 @@ func TestMarshalUnmarshal(t *testing.T) {
 	slice := &Slice{
 		Elmtype: channel,
 	}
+	typeParam := &Typeparam{
+		Name:       "T",
+		Constraint: id,
+	}
 
 	tests := []struct {
 		name     string
@@ -50,6 +54,42 @@ func TestMarshalUnmarshal(t *testing.T) {
 			},
 			empty: &Function{},
 		},
+		// Generic function
+		{
+			name: "Generic function",
+			value: &Function{
+				Params:     []DataType{typeParam},
+				Results:    []DataType{slice},
+				Typeparams: []DataType{typeParam},
+			},
+			empty: &Function{},
+		},
+		// Type parameter
+		{
+			name:  "Typeparam",
+			value: typeParam,
+			empty: &Typeparam{},
+		},
+		// Instance of a generic data type
+		{
+			name: "Instance",
+			value: &Instance{
+				Def:  id,
+				Args: []DataType{id, slice},
+			},
+			empty: &Instance{},
+		},
+		// Union of type terms
+		{
+			name: "Union",
+			value: &Union{
+				Terms: []UnionTermsItem{
+					{Tilde: true, Def: id},
+					{Tilde: false, Def: slice},
+				},
+			},
+			empty: &Union{},
+		},
 	}
 
 	for _, test := range tests {
@@ -97,3 +137,67 @@ func TestMarshalUnmarshal(t *testing.T) {
 		// }
 	}
 }
+
+func TestSubstitute(t *testing.T) {
+	typeParam := &Typeparam{Name: "T"}
+	intIdent := &Identifier{Package: "builtin", Def: "int"}
+
+	def := &Struct{
+		Fields: []StructFieldsItem{
+			{Name: "items", Def: &Slice{Elmtype: typeParam}},
+			{Name: "next", Def: &Pointer{Def: &Instance{Def: &Identifier{Def: "List"}, Args: []DataType{typeParam}}}},
+		},
+	}
+	expected := &Struct{
+		Fields: []StructFieldsItem{
+			{Name: "items", Def: &Slice{Elmtype: intIdent}},
+			{Name: "next", Def: &Pointer{Def: &Instance{Def: &Identifier{Def: "List"}, Args: []DataType{intIdent}}}},
+		},
+	}
+
+	if got := Substitute(def, []DataType{typeParam}, []DataType{intIdent}); !reflect.DeepEqual(expected, got) {
+		t.Errorf("%#v != %#v", expected, got)
+	}
+}
+
+func TestUnmarshalUnknownType(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		empty DataType
+	}{
+		{
+			name:  "Slice element",
+			data:  `{"type":"slice","elmtype":{"type":"future"}}`,
+			empty: &Slice{},
+		},
+		{
+			name:  "Function parameter",
+			data:  `{"type":"function","package":"p","params":[{"type":"identifier","def":"int","package":"builtin"},{"type":"future"}]}`,
+			empty: &Function{},
+		},
+		{
+			name:  "nested Map value",
+			data:  `{"type":"pointer","def":{"type":"map","keytype":{"type":"identifier","def":"int","package":"builtin"},"valuetype":{"type":"future"}}}`,
+			empty: &Pointer{},
+		},
+		{
+			name:  "Struct field",
+			data:  `{"type":"struct","fields":[{"type":"structfieldsitem","name":"a","def":{"type":"future"}}]}`,
+			empty: &Struct{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := json.Unmarshal([]byte(test.data), test.empty); err == nil {
+				t.Errorf("Expected an unknown type to be rejected, got %#v", test.empty)
+			}
+		})
+	}
+
+	// no data type
+	var pointer Pointer
+	if err := json.Unmarshal([]byte(`{"type":"pointer","def":null}`), &pointer); err != nil || pointer.Def != nil {
+		t.Errorf("Expected a pointer with no data type, got %#v (%v)", pointer, err)
+	}
+}
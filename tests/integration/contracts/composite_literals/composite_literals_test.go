@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -36,11 +37,11 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(1)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(1)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Slice{
@@ -62,7 +63,7 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("list", vars["list"]),
+				Y: typevars.MakeLocalVar("list", positions.Parse(vars["list"])),
 			},
 			// mapV := map[string]int{
 			// 	"3": 3,
@@ -75,19 +76,19 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapKey(typevars.MakeVirtualVar(2)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\""}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\"", Value: "\"3\""}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapValue(typevars.MakeVirtualVar(2)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3", Value: "3"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapKey(typevars.MakeVirtualVar(2)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"4\""}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"4\"", Value: "\"4\""}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapValue(typevars.MakeVirtualVar(2)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "4"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "4", Value: "4"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Map{
@@ -105,7 +106,7 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("mapV", vars["mapV"]),
+				Y: typevars.MakeLocalVar("mapV", positions.Parse(vars["mapV"])),
 			},
 			//
 			// structV := struct {
@@ -122,16 +123,16 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 				Field: "key1",
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(3), "key1", 0, ""),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"key1\""}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(3), "key1", 0, nil),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"key1\"", Value: "\"key1\""}),
 			},
 			&contracts.HasField{
 				X:     typevars.MakeVirtualVar(3),
 				Field: "key2",
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(3), "key2", 0, ""),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(3), "key2", 0, nil),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			// structV <-> struct {
 			// 	key1 string
@@ -145,8 +146,9 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 							Def:  &gotypes.Identifier{Package: "builtin", Def: "string"},
 						},
 						gotypes.StructFieldsItem{
-							Name: "key2",
-							Def:  &gotypes.Identifier{Package: "builtin", Def: "int"},
+							Name:  "key2",
+							Order: 1,
+							Def:   &gotypes.Identifier{Package: "builtin", Def: "int"},
 						},
 					},
 				}),
@@ -160,8 +162,9 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 							Def:  &gotypes.Identifier{Package: "builtin", Def: "string"},
 						},
 						gotypes.StructFieldsItem{
-							Name: "key2",
-							Def:  &gotypes.Identifier{Package: "builtin", Def: "int"},
+							Name:  "key2",
+							Order: 1,
+							Def:   &gotypes.Identifier{Package: "builtin", Def: "int"},
 						},
 					},
 				}),
@@ -169,7 +172,7 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(3),
-				Y: typevars.MakeLocalVar("structV", vars["structV"]),
+				Y: typevars.MakeLocalVar("structV", positions.Parse(vars["structV"])),
 			},
 			//
 			// structV2 := struct {
@@ -186,16 +189,16 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 				Index: 0,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(4), "", 0, ""),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"key1\""}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(4), "", 0, nil),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"key1\"", Value: "\"key1\""}),
 			},
 			&contracts.HasField{
 				X:     typevars.MakeVirtualVar(4),
 				Index: 1,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(4), "", 1, ""),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(4), "", 1, nil),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			// structV2 <-> struct {
 			// 	key1 string
@@ -209,8 +212,9 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 							Def:  &gotypes.Identifier{Package: "builtin", Def: "string"},
 						},
 						gotypes.StructFieldsItem{
-							Name: "key2",
-							Def:  &gotypes.Identifier{Package: "builtin", Def: "int"},
+							Name:  "key2",
+							Order: 1,
+							Def:   &gotypes.Identifier{Package: "builtin", Def: "int"},
 						},
 					},
 				}),
@@ -224,8 +228,9 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 							Def:  &gotypes.Identifier{Package: "builtin", Def: "string"},
 						},
 						gotypes.StructFieldsItem{
-							Name: "key2",
-							Def:  &gotypes.Identifier{Package: "builtin", Def: "int"},
+							Name:  "key2",
+							Order: 1,
+							Def:   &gotypes.Identifier{Package: "builtin", Def: "int"},
 						},
 					},
 				}),
@@ -233,7 +238,7 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(4),
-				Y: typevars.MakeLocalVar("structV2", vars["structV2"]),
+				Y: typevars.MakeLocalVar("structV2", positions.Parse(vars["structV2"])),
 			},
 			//
 			// listV2 := [][]int{
@@ -255,11 +260,11 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(6)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(6)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(5)),
@@ -270,11 +275,11 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(7)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3", Value: "3"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(7)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "4"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "4", Value: "4"}),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(5)),
@@ -298,7 +303,7 @@ func TestCompositeLiteralsContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(5),
-				Y: typevars.MakeLocalVar("listV2", vars["listV2"]),
+				Y: typevars.MakeLocalVar("listV2", positions.Parse(vars["listV2"])),
 			},
 		})
 }
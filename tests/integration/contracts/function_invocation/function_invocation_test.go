@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -28,79 +29,79 @@ func TestFunctionInvocationContracts(t *testing.T) {
 		"../../testdata/function_invocation.go",
 		[]contracts.Contract{
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\""}),
-				Y: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\"", Value: "\"ahoj\""}),
+				Y: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			//
 			// aa := a + g1(a)
 			//
 			&contracts.IsInvocable{
-				F:         typevars.MakeVar(packageName, "g1", vars["g1"]),
+				F:         typevars.MakeVar(packageName, "g1", positions.Parse(vars["g1"])),
 				ArgsCount: 1,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("a", vars["a"]),
-				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g1", vars["g1"]), 0),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g1", positions.Parse(vars["g1"])), 0),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeLocalVar("a", vars["a"]),
-				Y:       typevars.MakeReturn(typevars.MakeVar(packageName, "g1", vars["g1"]), 0),
+				X:       typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y:       typevars.MakeReturn(typevars.MakeVar(packageName, "g1", positions.Parse(vars["g1"])), 0),
 				Z:       typevars.MakeVirtualVar(1),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("aa", vars["aa"]),
+				Y: typevars.MakeLocalVar("aa", positions.Parse(vars["aa"])),
 			},
 			//
 			// ab := a + g2(a)
 			//
 			&contracts.IsInvocable{
-				F:         typevars.MakeVar(packageName, "g2", vars["g2"]),
+				F:         typevars.MakeVar(packageName, "g2", positions.Parse(vars["g2"])),
 				ArgsCount: 1,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("a", vars["a"]),
-				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g2", vars["g2"]), 0),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g2", positions.Parse(vars["g2"])), 0),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeLocalVar("a", vars["a"]),
-				Y:       typevars.MakeReturn(typevars.MakeVar(packageName, "g2", vars["g2"]), 0),
+				X:       typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y:       typevars.MakeReturn(typevars.MakeVar(packageName, "g2", positions.Parse(vars["g2"])), 0),
 				Z:       typevars.MakeVirtualVar(2),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("ab", vars["ab"]),
+				Y: typevars.MakeLocalVar("ab", positions.Parse(vars["ab"])),
 			},
 			//
 			// b := a + g(a, a, a)
 			//
 			&contracts.IsInvocable{
-				F:         typevars.MakeVar(packageName, "g", vars["g"]),
+				F:         typevars.MakeVar(packageName, "g", positions.Parse(vars["g"])),
 				ArgsCount: 3,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("a", vars["a"]),
-				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g", vars["g"]), 0),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g", positions.Parse(vars["g"])), 0),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("a", vars["a"]),
-				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g", vars["g"]), 1),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g", positions.Parse(vars["g"])), 1),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("a", vars["a"]),
-				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g", vars["g"]), 2),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y: typevars.MakeArgument(typevars.MakeVar(packageName, "g", positions.Parse(vars["g"])), 2),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeLocalVar("a", vars["a"]),
-				Y:       typevars.MakeReturn(typevars.MakeVar(packageName, "g", vars["g"]), 0),
+				X:       typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
+				Y:       typevars.MakeReturn(typevars.MakeVar(packageName, "g", positions.Parse(vars["g"])), 0),
 				Z:       typevars.MakeVirtualVar(3),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(3),
-				Y: typevars.MakeLocalVar("b", vars["b"]),
+				Y: typevars.MakeLocalVar("b", positions.Parse(vars["b"])),
 			},
 		})
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -25,7 +26,7 @@ func TestFunctionLiteralsContracts(t *testing.T) {
 		[]contracts.Contract{
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeLocalVar("a", vars["a"]),
+				Y: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Function{
@@ -41,23 +42,23 @@ func TestFunctionLiteralsContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("ffA", vars["ffA"]),
+				Y: typevars.MakeLocalVar("ffA", positions.Parse(vars["ffA"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeLocalVar("ffA", vars["ffA"]),
-				Y: typevars.MakeLocalVar("ffA", vars["ffA2"]),
+				X: typevars.MakeLocalVar("ffA", positions.Parse(vars["ffA"])),
+				Y: typevars.MakeLocalVar("ffA", positions.Parse(vars["ffA2"])),
 			},
 			&contracts.IsInvocable{
-				F:         typevars.MakeLocalVar("ffA", vars["ffA2"]),
+				F:         typevars.MakeLocalVar("ffA", positions.Parse(vars["ffA2"])),
 				ArgsCount: 1,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeArgument(typevars.MakeLocalVar("ffA", vars["ffA2"]), 0),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeArgument(typevars.MakeLocalVar("ffA", positions.Parse(vars["ffA2"])), 0),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeReturn(typevars.MakeLocalVar("ffA", vars["ffA2"]), 0),
-				Y: typevars.MakeLocalVar("ffB", vars["ffB"]),
+				X: typevars.MakeReturn(typevars.MakeLocalVar("ffA", positions.Parse(vars["ffA2"])), 0),
+				Y: typevars.MakeLocalVar("ffB", positions.Parse(vars["ffB"])),
 			},
 		})
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -58,96 +59,96 @@ func TestGeneralContracts(t *testing.T) {
 		"../../testdata/general.go",
 		[]contracts.Contract{
 			&contracts.BinaryOp{
-				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Z:       typevars.MakeVirtualVar(1),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeVar(packageName, "e", vars["e"]),
+				Y: typevars.MakeVar(packageName, "e", positions.Parse(vars["e"])),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeVar(packageName, "d", vars["d"]),
-				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X:       typevars.MakeVar(packageName, "d", positions.Parse(vars["d"])),
+				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Z:       typevars.MakeVirtualVar(2),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeVar(packageName, "f", vars["f"]),
+				Y: typevars.MakeVar(packageName, "f", positions.Parse(vars["f"])),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeVar(packageName, "e", vars["e"]),
-				Y:       typevars.MakeVar(packageName, "f", vars["f"]),
+				X:       typevars.MakeVar(packageName, "e", positions.Parse(vars["e"])),
+				Y:       typevars.MakeVar(packageName, "f", positions.Parse(vars["f"])),
 				Z:       typevars.MakeVirtualVar(3),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(3),
-				Y: typevars.MakeVar(packageName, "ff", vars["ff"]),
+				Y: typevars.MakeVar(packageName, "ff", positions.Parse(vars["ff"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeVar(packageName, "j", vars["j0"]),
+				Y: typevars.MakeVar(packageName, "j", positions.Parse(vars["j0"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeVar(packageName, "k", vars["k0"]),
+				Y: typevars.MakeVar(packageName, "k", positions.Parse(vars["k0"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeVar(packageName, "l", vars["l0"]),
+				Y: typevars.MakeVar(packageName, "l", positions.Parse(vars["l0"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Y: typevars.MakeVar(packageName, "a", vars["a"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
+				Y: typevars.MakeVar(packageName, "a", positions.Parse(vars["a"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeVar(packageName, "b", vars["b"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeVar(packageName, "b", positions.Parse(vars["b"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Y: typevars.MakeVar(packageName, "ta", vars["ta"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
+				Y: typevars.MakeVar(packageName, "ta", positions.Parse(vars["ta"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant("builtin", &gotypes.Identifier{Package: "builtin", Def: "float32"}),
-				Y: typevars.MakeVar(packageName, "ta", vars["ta"]),
+				Y: typevars.MakeVar(packageName, "ta", positions.Parse(vars["ta"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeVar(packageName, "tb", vars["tb"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeVar(packageName, "tb", positions.Parse(vars["tb"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant("builtin", &gotypes.Identifier{Package: "builtin", Def: "float32"}),
-				Y: typevars.MakeVar(packageName, "tb", vars["tb"]),
+				Y: typevars.MakeVar(packageName, "tb", positions.Parse(vars["tb"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeVar(packageName, "c", vars["c"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3"}),
-				Y: typevars.MakeVar(packageName, "d", vars["d"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3", Value: "3"}),
+				Y: typevars.MakeVar(packageName, "d", positions.Parse(vars["d"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeVar(packageName, "d", vars["d"]),
+				Y: typevars.MakeVar(packageName, "d", positions.Parse(vars["d"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Channel{Dir: "3", Value: &gotypes.Identifier{Package: "builtin", Def: "int"}}),
-				Y: typevars.MakeLocalVar("cv", vars["cv"]),
+				Y: typevars.MakeLocalVar("cv", positions.Parse(vars["cv"])),
 			},
 			&contracts.IsSendableTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeLocalVar("cv", vars["cv"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeLocalVar("cv", positions.Parse(vars["cv"])),
 			},
 			&contracts.IsIncDecable{
-				X: typevars.MakeVar(packageName, "c", vars["c"]),
+				X: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
 			},
 			&contracts.IsIncDecable{
-				X: typevars.MakeVar(packageName, "c", vars["c"]),
+				X: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
 			},
 			// l := []string{}
 			// v, ok := l[1]
@@ -168,22 +169,22 @@ func TestGeneralContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(4),
-				Y: typevars.MakeLocalVar("l", vars["l"]),
+				Y: typevars.MakeLocalVar("l", positions.Parse(vars["l"])),
 			},
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("l", vars["l"]),
+				X: typevars.MakeLocalVar("l", positions.Parse(vars["l"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeListValue(typevars.MakeLocalVar("l", vars["l"])),
-				Y: typevars.MakeLocalVar("v", vars["v"]),
+				X: typevars.MakeListValue(typevars.MakeLocalVar("l", positions.Parse(vars["l"]))),
+				Y: typevars.MakeLocalVar("v", positions.Parse(vars["v"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "bool"}),
-				Y: typevars.MakeLocalVar("ok", vars["ok"]),
+				Y: typevars.MakeLocalVar("ok", positions.Parse(vars["ok"])),
 			},
 			// m := map[int]string{}
 			// mv, mok := m[1]
@@ -206,49 +207,45 @@ func TestGeneralContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(5),
-				Y: typevars.MakeLocalVar("m", vars["m"]),
+				Y: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("m", vars["m"]),
-			},
-			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Y: typevars.MakeVirtualVar(6),
+				X: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Y: typevars.MakeMapKey(typevars.MakeVirtualVar(6)),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
+				Y: typevars.MakeMapKey(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeMapValue(typevars.MakeLocalVar("m", vars["m"])),
-				Y: typevars.MakeLocalVar("mv", vars["mv"]),
+				X: typevars.MakeMapValue(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
+				Y: typevars.MakeLocalVar("mv", positions.Parse(vars["mv"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "bool"}),
-				Y: typevars.MakeLocalVar("mok", vars["mok"]),
+				Y: typevars.MakeLocalVar("mok", positions.Parse(vars["mok"])),
 			},
 			// id := interface{}(&c)
 			// intD, intOk := id.(*int)
 			&contracts.IsReferenceable{
-				X: typevars.MakeVar(packageName, "c", vars["c"]),
+				X: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
 			},
 			&contracts.ReferenceOf{
-				X: typevars.MakeVar(packageName, "c", vars["c"]),
-				Y: typevars.MakeVirtualVar(7),
+				X: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
+				Y: typevars.MakeVirtualVar(6),
 			},
 			&contracts.TypecastsTo{
-				X: typevars.MakeVirtualVar(7),
-				Y: typevars.MakeVirtualVar(8),
+				X: typevars.MakeVirtualVar(6),
+				Y: typevars.MakeVirtualVar(7),
 				Type: typevars.MakeConstant(packageName, &gotypes.Interface{
 					Methods: nil,
 				}),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeVirtualVar(8),
-				Y: typevars.MakeLocalVar("id", vars["id"]),
+				X: typevars.MakeVirtualVar(7),
+				Y: typevars.MakeLocalVar("id", positions.Parse(vars["id"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("id", vars["id"]),
+				X: typevars.MakeLocalVar("id", positions.Parse(vars["id"])),
 				Y: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "int"},
 				}),
@@ -257,36 +254,32 @@ func TestGeneralContracts(t *testing.T) {
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "int"},
 				}),
-				Y: typevars.MakeLocalVar("intD", vars["intD"]),
+				Y: typevars.MakeLocalVar("intD", positions.Parse(vars["intD"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "bool"}),
-				Y: typevars.MakeLocalVar("intOk", vars["intOk"]),
+				Y: typevars.MakeLocalVar("intOk", positions.Parse(vars["intOk"])),
 			},
 			// m[0] = "ahoj"
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("m", vars["m"]),
-			},
-			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
-				Y: typevars.MakeVirtualVar(9),
+				X: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
-				Y: typevars.MakeMapKey(typevars.MakeVirtualVar(9)),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
+				Y: typevars.MakeMapKey(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\""}),
-				Y: typevars.MakeMapValue(typevars.MakeLocalVar("m", vars["m"])),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\"", Value: "\"ahoj\""}),
+				Y: typevars.MakeMapValue(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
 			},
 			// s := struct{ a int }{a: 2}
 			&contracts.HasField{
-				X:     typevars.MakeVirtualVar(10),
+				X:     typevars.MakeVirtualVar(8),
 				Field: "a",
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(10), "a", 0, ""),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(8), "a", 0, nil),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Struct{
@@ -297,7 +290,7 @@ func TestGeneralContracts(t *testing.T) {
 						},
 					},
 				}),
-				Y: typevars.MakeVirtualVar(10),
+				Y: typevars.MakeVirtualVar(8),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Struct{
@@ -308,65 +301,65 @@ func TestGeneralContracts(t *testing.T) {
 						},
 					},
 				}),
-				Y: typevars.MakeVirtualVar(10),
+				Y: typevars.MakeVirtualVar(8),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeVirtualVar(10),
-				Y: typevars.MakeLocalVar("s", vars["s"]),
+				X: typevars.MakeVirtualVar(8),
+				Y: typevars.MakeLocalVar("s", positions.Parse(vars["s"])),
 			},
 			// s.a = 2
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("s", vars["s"]),
+				X:     typevars.MakeLocalVar("s", positions.Parse(vars["s"])),
 				Field: "a",
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeField(typevars.MakeLocalVar("s", vars["s"]), "a", 0, ""),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeField(typevars.MakeLocalVar("s", positions.Parse(vars["s"])), "a", 0, nil),
 			},
 			// *(&c) = 2
 			&contracts.IsReferenceable{
-				X: typevars.MakeVar(packageName, "c", vars["c"]),
+				X: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
 			},
 			&contracts.ReferenceOf{
-				X: typevars.MakeVar(packageName, "c", vars["c"]),
-				Y: typevars.MakeVirtualVar(11),
+				X: typevars.MakeVar(packageName, "c", positions.Parse(vars["c"])),
+				Y: typevars.MakeVirtualVar(9),
 			},
 			&contracts.IsDereferenceable{
-				X: typevars.MakeVirtualVar(11),
+				X: typevars.MakeVirtualVar(9),
 			},
 			&contracts.DereferenceOf{
-				X: typevars.MakeVirtualVar(11),
-				Y: typevars.MakeVirtualVar(12),
+				X: typevars.MakeVirtualVar(9),
+				Y: typevars.MakeVirtualVar(10),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Y: typevars.MakeVirtualVar(12),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Y: typevars.MakeVirtualVar(10),
 			},
 			// go func() {}()
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Function{
 					Package: packageName,
 				}),
-				Y: typevars.MakeVirtualVar(13),
+				Y: typevars.MakeVirtualVar(11),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeVirtualVar(13),
-				Y: typevars.MakeVirtualVar(13),
+				X: typevars.MakeVirtualVar(11),
+				Y: typevars.MakeVirtualVar(11),
 			},
 			&contracts.IsInvocable{
-				F:         typevars.MakeVirtualVar(13),
+				F:         typevars.MakeVirtualVar(11),
 				ArgsCount: 0,
 			},
 			// switch swA := 1; swA {
 			// case a:
 			// }
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Y: typevars.MakeLocalVar("swA", vars["swA"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
+				Y: typevars.MakeLocalVar("swA", positions.Parse(vars["swA"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar(packageName, "a", vars["a"]),
-				Y: typevars.MakeLocalVar("swA", vars["swA"]),
+				X: typevars.MakeVar(packageName, "a", positions.Parse(vars["a"])),
+				Y: typevars.MakeLocalVar("swA", positions.Parse(vars["swA"])),
 			},
 			// switch d := id.(type) {
 			// case *int:
@@ -376,32 +369,32 @@ func TestGeneralContracts(t *testing.T) {
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "int"},
 				}),
-				Y:    typevars.MakeLocalVar("id", vars["id"]),
+				Y:    typevars.MakeLocalVar("id", positions.Parse(vars["id"])),
 				Weak: true,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "int"},
 				}),
-				Y: typevars.MakeLocalVar("sd", vars["sd:c1"]),
+				Y: typevars.MakeLocalVar("sd", positions.Parse(vars["sd:c1"])),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "string"},
 				}),
-				Y:    typevars.MakeLocalVar("id", vars["id"]),
+				Y:    typevars.MakeLocalVar("id", positions.Parse(vars["id"])),
 				Weak: true,
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "float32"},
 				}),
-				Y:    typevars.MakeLocalVar("id", vars["id"]),
+				Y:    typevars.MakeLocalVar("id", positions.Parse(vars["id"])),
 				Weak: true,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Interface{}),
-				Y: typevars.MakeLocalVar("sd", vars["sd:c2"]),
+				Y: typevars.MakeLocalVar("sd", positions.Parse(vars["sd:c2"])),
 			},
 			// switch id.(type) {
 			// case *int:
@@ -410,7 +403,7 @@ func TestGeneralContracts(t *testing.T) {
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
 					Def: &gotypes.Identifier{Package: "builtin", Def: "int"},
 				}),
-				Y:    typevars.MakeLocalVar("id", vars["id"]),
+				Y:    typevars.MakeLocalVar("id", positions.Parse(vars["id"])),
 				Weak: true,
 			},
 			// var c1 chan string
@@ -424,93 +417,93 @@ func TestGeneralContracts(t *testing.T) {
 			// }
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Channel{Dir: "3", Value: &gotypes.Identifier{Package: "builtin", Def: "string"}}),
-				Y: typevars.MakeLocalVar("c1", vars["c1"]),
+				Y: typevars.MakeLocalVar("c1", positions.Parse(vars["c1"])),
 			},
 			&contracts.IsReceiveableFrom{
-				X: typevars.MakeLocalVar("c1", vars["c1"]),
-				Y: typevars.MakeVirtualVar(14),
+				X: typevars.MakeLocalVar("c1", positions.Parse(vars["c1"])),
+				Y: typevars.MakeVirtualVar(12),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeVirtualVar(14),
-				Y: typevars.MakeLocalVar("msg1", vars["msg1"]),
+				X: typevars.MakeVirtualVar(12),
+				Y: typevars.MakeLocalVar("msg1", positions.Parse(vars["msg1"])),
 			},
 			&contracts.IsReceiveableFrom{
-				X: typevars.MakeLocalVar("c1", vars["c1"]),
-				Y: typevars.MakeVirtualVar(15),
+				X: typevars.MakeLocalVar("c1", positions.Parse(vars["c1"])),
+				Y: typevars.MakeVirtualVar(13),
 			},
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("l", vars["l"]),
+				X: typevars.MakeLocalVar("l", positions.Parse(vars["l"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVirtualVar(15),
-				Y: typevars.MakeListValue(typevars.MakeLocalVar("l", vars["l"])),
+				X: typevars.MakeVirtualVar(13),
+				Y: typevars.MakeListValue(typevars.MakeLocalVar("l", positions.Parse(vars["l"]))),
 			},
 			&contracts.IsReceiveableFrom{
-				X: typevars.MakeLocalVar("c1", vars["c1"]),
-				Y: typevars.MakeVirtualVar(16),
+				X: typevars.MakeLocalVar("c1", positions.Parse(vars["c1"])),
+				Y: typevars.MakeVirtualVar(14),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeVirtualVar(16),
-				Y: typevars.MakeLocalVar("msg1", vars["msg1:2"]),
+				X: typevars.MakeVirtualVar(14),
+				Y: typevars.MakeLocalVar("msg1", positions.Parse(vars["msg1:2"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "bool"}),
-				Y: typevars.MakeLocalVar("msg1Ok", vars["msg1Ok"]),
+				Y: typevars.MakeLocalVar("msg1Ok", positions.Parse(vars["msg1Ok"])),
 			},
 			&contracts.IsReceiveableFrom{
-				X: typevars.MakeLocalVar("c1", vars["c1"]),
-				Y: typevars.MakeVirtualVar(17),
+				X: typevars.MakeLocalVar("c1", positions.Parse(vars["c1"])),
+				Y: typevars.MakeVirtualVar(15),
 			},
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("l", vars["l"]),
+				X: typevars.MakeLocalVar("l", positions.Parse(vars["l"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVirtualVar(17),
-				Y: typevars.MakeListValue(typevars.MakeLocalVar("l", vars["l"])),
+				X: typevars.MakeVirtualVar(15),
+				Y: typevars.MakeListValue(typevars.MakeLocalVar("l", positions.Parse(vars["l"]))),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "bool"}),
-				Y: typevars.MakeLocalVar("ok", vars["ok"]),
+				Y: typevars.MakeLocalVar("ok", positions.Parse(vars["ok"])),
 			},
 			&contracts.IsSendableTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"a\""}),
-				Y: typevars.MakeLocalVar("c1", vars["c1"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"a\"", Value: "\"a\""}),
+				Y: typevars.MakeLocalVar("c1", positions.Parse(vars["c1"])),
 			},
 			// for i := 0; i < 1; i++ {
 			// }
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
-				Y: typevars.MakeLocalVar("i", vars["i"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
+				Y: typevars.MakeLocalVar("i", positions.Parse(vars["i"])),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeLocalVar("i", vars["i"]),
-				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Z:       typevars.MakeVirtualVar(18),
+				X:       typevars.MakeLocalVar("i", positions.Parse(vars["i"])),
+				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
+				Z:       typevars.MakeVirtualVar(16),
 				OpToken: token.LSS,
 			},
 			&contracts.IsIncDecable{
-				X: typevars.MakeLocalVar("i", vars["i"]),
+				X: typevars.MakeLocalVar("i", positions.Parse(vars["i"])),
 			},
 			// for ii, vv := range m {
 			// }
 			&contracts.IsRangeable{
-				X: typevars.MakeLocalVar("m", vars["m"]),
+				X: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeRangeKey(typevars.MakeLocalVar("m", vars["m"])),
-				Y: typevars.MakeLocalVar("ii", vars["ii"]),
+				X: typevars.MakeRangeKey(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
+				Y: typevars.MakeLocalVar("ii", positions.Parse(vars["ii"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeRangeValue(typevars.MakeLocalVar("m", vars["m"])),
-				Y: typevars.MakeLocalVar("vv", vars["vv"]),
+				X: typevars.MakeRangeValue(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
+				Y: typevars.MakeLocalVar("vv", positions.Parse(vars["vv"])),
 			},
 			// var fI int
 			// var fV string
@@ -518,59 +511,59 @@ func TestGeneralContracts(t *testing.T) {
 			// }
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeLocalVar("fI", vars["fI"]),
+				Y: typevars.MakeLocalVar("fI", positions.Parse(vars["fI"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "string"}),
-				Y: typevars.MakeLocalVar("fV", vars["fV"]),
+				Y: typevars.MakeLocalVar("fV", positions.Parse(vars["fV"])),
 			},
 			&contracts.IsRangeable{
-				X: typevars.MakeLocalVar("m", vars["m"]),
+				X: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeRangeKey(typevars.MakeLocalVar("m", vars["m"])),
-				Y: typevars.MakeLocalVar("fI", vars["fI"]),
+				X: typevars.MakeRangeKey(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
+				Y: typevars.MakeLocalVar("fI", positions.Parse(vars["fI"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeRangeValue(typevars.MakeLocalVar("m", vars["m"])),
-				Y: typevars.MakeLocalVar("fV", vars["fV"]),
+				X: typevars.MakeRangeValue(typevars.MakeLocalVar("m", positions.Parse(vars["m"]))),
+				Y: typevars.MakeLocalVar("fV", positions.Parse(vars["fV"])),
 			},
 			// for _, _ = range m {
 			// }
 			&contracts.IsRangeable{
-				X: typevars.MakeLocalVar("m", vars["m"]),
+				X: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			// for range m {
 			// }
 			&contracts.IsRangeable{
-				X: typevars.MakeLocalVar("m", vars["m"]),
+				X: typevars.MakeLocalVar("m", positions.Parse(vars["m"])),
 			},
 			// defer func() {}()
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Function{
 					Package: packageName,
 				}),
-				Y: typevars.MakeVirtualVar(19),
+				Y: typevars.MakeVirtualVar(17),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeVirtualVar(19),
-				Y: typevars.MakeVirtualVar(19),
+				X: typevars.MakeVirtualVar(17),
+				Y: typevars.MakeVirtualVar(17),
 			},
 			&contracts.IsInvocable{
-				F:         typevars.MakeVirtualVar(19),
+				F:         typevars.MakeVirtualVar(17),
 				ArgsCount: 0,
 			},
 			// if ifI := 1; ifI < 2 {
 			// } else {
 			// }
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-				Y: typevars.MakeLocalVar("ifI", vars["ifI"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
+				Y: typevars.MakeLocalVar("ifI", positions.Parse(vars["ifI"])),
 			},
 			&contracts.BinaryOp{
-				X:       typevars.MakeLocalVar("ifI", vars["ifI"]),
-				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-				Z:       typevars.MakeVirtualVar(20),
+				X:       typevars.MakeLocalVar("ifI", positions.Parse(vars["ifI"])),
+				Y:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
+				Z:       typevars.MakeVirtualVar(18),
 				OpToken: token.LSS,
 			},
 		})
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -36,7 +37,7 @@ func TestIndexableContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(1)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Slice{
@@ -59,7 +60,7 @@ func TestIndexableContracts(t *testing.T) {
 
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("list", vars["list"]),
+				Y: typevars.MakeLocalVar("list", positions.Parse(vars["list"])),
 			},
 			//
 			// mapV := map[string]int{
@@ -71,11 +72,11 @@ func TestIndexableContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapKey(typevars.MakeVirtualVar(2)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\""}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\"", Value: "\"3\""}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapValue(typevars.MakeVirtualVar(2)),
-				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3"}),
+				Y: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3", Value: "3"}),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Map{
@@ -93,66 +94,62 @@ func TestIndexableContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("mapV", vars["mapV"]),
+				Y: typevars.MakeLocalVar("mapV", positions.Parse(vars["mapV"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("list", vars["list"]),
+				X: typevars.MakeLocalVar("list", positions.Parse(vars["list"])),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeLocalVar("list", vars["list"]),
-				Y: typevars.MakeLocalVar("la", vars["la"]),
+				X: typevars.MakeLocalVar("list", positions.Parse(vars["list"])),
+				Y: typevars.MakeLocalVar("la", positions.Parse(vars["la"])),
 			},
 			//lb := la[3]
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("la", vars["la"]),
+				X: typevars.MakeLocalVar("la", positions.Parse(vars["la"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "3", Value: "3"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeListValue(typevars.MakeLocalVar("la", vars["la"])),
-				Y: typevars.MakeLocalVar("lb", vars["lb"]),
+				X: typevars.MakeListValue(typevars.MakeLocalVar("la", positions.Parse(vars["la"]))),
+				Y: typevars.MakeLocalVar("lb", positions.Parse(vars["lb"])),
 			},
 			// ma := mapV["3"]
 			&contracts.IsIndexable{
-				X: typevars.MakeLocalVar("mapV", vars["mapV"]),
-			},
-			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\""}),
-				Y: typevars.MakeVirtualVar(3),
+				X: typevars.MakeLocalVar("mapV", positions.Parse(vars["mapV"])),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\""}),
-				Y: typevars.MakeMapKey(typevars.MakeVirtualVar(3)),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"3\"", Value: "\"3\""}),
+				Y: typevars.MakeMapKey(typevars.MakeLocalVar("mapV", positions.Parse(vars["mapV"]))),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeMapValue(typevars.MakeLocalVar("mapV", vars["mapV"])),
-				Y: typevars.MakeLocalVar("ma", vars["ma"]),
+				X: typevars.MakeMapValue(typevars.MakeLocalVar("mapV", positions.Parse(vars["mapV"]))),
+				Y: typevars.MakeLocalVar("ma", positions.Parse(vars["ma"])),
 			},
 			//sa := "ahoj"[0]
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\""}),
-				Y: typevars.MakeVirtualVar(4),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\"", Value: "\"ahoj\""}),
+				Y: typevars.MakeVirtualVar(3),
 			},
 			&contracts.IsIndexable{
-				X: typevars.MakeVirtualVar(4),
+				X: typevars.MakeVirtualVar(3),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
 				Y: typevars.MakeListKey(),
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeListValue(typevars.MakeVirtualVar(4)),
-				Y: typevars.MakeLocalVar("sa", vars["sa"]),
+				X: typevars.MakeListValue(typevars.MakeVirtualVar(3)),
+				Y: typevars.MakeLocalVar("sa", positions.Parse(vars["sa"])),
 			},
 		})
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	conutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 	"github.com/gofed/symbols-extractor/tests/integration/utils"
@@ -56,7 +57,7 @@ func TestMultiPackageContracts(t *testing.T) {
 			// a := &pkgA.A{}
 			// a.method(1, 2)
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar("pkgA", "A", ""),
+				X: typevars.MakeVar("pkgA", "A", positions.Parse("")),
 				Y: typevars.MakeVirtualVar(1),
 			},
 			&contracts.PropagatesTo{
@@ -72,14 +73,14 @@ func TestMultiPackageContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("a", vars["a"]),
+				Y: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("a", vars["a"]),
+				X:     typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 				Field: "method",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("a", vars["a"]), "method", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("a", positions.Parse(vars["a"])), "method", 0, nil),
 				Y: typevars.MakeVirtualVar(3),
 			},
 			&contracts.IsInvocable{
@@ -87,11 +88,11 @@ func TestMultiPackageContracts(t *testing.T) {
 				ArgsCount: 2,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y: typevars.MakeArgument(typevars.MakeVirtualVar(3), 0),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				X: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 				Y: typevars.MakeArgument(typevars.MakeVirtualVar(3), 1),
 			},
 			// b := pkgA.B{
@@ -106,12 +107,12 @@ func TestMultiPackageContracts(t *testing.T) {
 				Index: 0,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(5), "", 0, ""),
-				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(5), "", 0, nil),
+				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 			},
 			// The first item has its type given explicitly
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar("pkgA", "A", ""),
+				X: typevars.MakeVar("pkgA", "A", positions.Parse("")),
 				Y: typevars.MakeVirtualVar(5),
 			},
 			&contracts.PropagatesTo{
@@ -127,8 +128,8 @@ func TestMultiPackageContracts(t *testing.T) {
 				Index: 0,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(6), "", 0, ""),
-				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(6), "", 0, nil),
+				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			// The second item has not its type given explicitly => no contract
 			&contracts.PropagatesTo{
@@ -136,7 +137,7 @@ func TestMultiPackageContracts(t *testing.T) {
 				Y: typevars.MakeVirtualVar(6),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar("pkgA", "B", ""),
+				X: typevars.MakeVar("pkgA", "B", positions.Parse("")),
 				Y: typevars.MakeVirtualVar(4),
 			},
 			&contracts.PropagatesTo{
@@ -145,7 +146,7 @@ func TestMultiPackageContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(4),
-				Y: typevars.MakeLocalVar("b", vars["b"]),
+				Y: typevars.MakeLocalVar("b", positions.Parse(vars["b"])),
 			},
 			// c := pkgA.C{
 			// 	0: pkgA.B{
@@ -161,7 +162,7 @@ func TestMultiPackageContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapKey(typevars.MakeVirtualVar(7)),
-				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0", Value: "0"}),
 			},
 			&contracts.IsIndexable{
 				X: typevars.MakeVirtualVar(8),
@@ -171,8 +172,8 @@ func TestMultiPackageContracts(t *testing.T) {
 				Index: 0,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(9), "", 0, ""),
-				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(9), "", 0, nil),
+				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeListValue(typevars.MakeVirtualVar(8)),
@@ -183,7 +184,7 @@ func TestMultiPackageContracts(t *testing.T) {
 				Y: typevars.MakeVirtualVar(10),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar("pkgA", "B", ""),
+				X: typevars.MakeVar("pkgA", "B", positions.Parse("")),
 				Y: typevars.MakeVirtualVar(8),
 			},
 			&contracts.PropagatesTo{
@@ -196,7 +197,7 @@ func TestMultiPackageContracts(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeMapKey(typevars.MakeVirtualVar(7)),
-				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 			},
 			&contracts.IsIndexable{
 				X: typevars.MakeVirtualVar(11),
@@ -206,11 +207,11 @@ func TestMultiPackageContracts(t *testing.T) {
 				Field: "f",
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(12), "f", 0, ""),
-				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+				X: typevars.MakeField(typevars.MakeVirtualVar(12), "f", 0, nil),
+				Y: typevars.MakeConstant("pkgB", &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"}),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar("pkgA", "A", ""),
+				X: typevars.MakeVar("pkgA", "A", positions.Parse("")),
 				Y: typevars.MakeVirtualVar(12),
 			},
 			&contracts.PropagatesTo{
@@ -226,7 +227,7 @@ func TestMultiPackageContracts(t *testing.T) {
 				Y: typevars.MakeVirtualVar(11),
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeVar("pkgA", "C", ""),
+				X: typevars.MakeVar("pkgA", "C", positions.Parse("")),
 				Y: typevars.MakeVirtualVar(7),
 			},
 			&contracts.PropagatesTo{
@@ -235,7 +236,7 @@ func TestMultiPackageContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(7),
-				Y: typevars.MakeLocalVar("c", vars["c"]),
+				Y: typevars.MakeLocalVar("c", positions.Parse(vars["c"])),
 			},
 		},
 	)
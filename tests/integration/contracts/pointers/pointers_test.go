@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -24,32 +25,32 @@ func TestPointersContracts(t *testing.T) {
 		[]contracts.Contract{
 			// a := "ahoj"
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\""}),
-				Y: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\"", Value: "\"ahoj\""}),
+				Y: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			// ra := &a
 			&contracts.IsReferenceable{
-				X: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			&contracts.ReferenceOf{
-				X: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 				Y: typevars.MakeVirtualVar(1),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("ra", vars["ra"]),
+				Y: typevars.MakeLocalVar("ra", positions.Parse(vars["ra"])),
 			},
 			// da := *ra
 			&contracts.IsDereferenceable{
-				X: typevars.MakeLocalVar("ra", vars["ra"]),
+				X: typevars.MakeLocalVar("ra", positions.Parse(vars["ra"])),
 			},
 			&contracts.DereferenceOf{
-				X: typevars.MakeLocalVar("ra", vars["ra"]),
+				X: typevars.MakeLocalVar("ra", positions.Parse(vars["ra"])),
 				Y: typevars.MakeVirtualVar(2),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("da", vars["da"]),
+				Y: typevars.MakeLocalVar("da", positions.Parse(vars["da"])),
 			},
 		})
 }
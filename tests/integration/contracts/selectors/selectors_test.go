@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -39,20 +40,21 @@ func TestSelectorsTypes(t *testing.T) {
 		packageName,
 		"../../testdata/selectors.go",
 		[]contracts.Contract{
-			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Identifier{
-					Def:     "D",
-					Package: packageName,
+			// frA := (*D).method
+			&contracts.MethodValue{
+				X: typevars.MakeConstant(packageName, &gotypes.Pointer{
+					Def: &gotypes.Identifier{
+						Def:     "D",
+						Package: packageName,
+					},
 				}),
-				Y: typevars.MakeVirtualVar(1),
-			},
-			&contracts.HasField{
-				X:     typevars.MakeVirtualVar(1),
-				Field: "method",
+				Method:     "method",
+				Y:          typevars.MakeVirtualVar(1),
+				Expression: true,
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeVirtualVar(1), "method", 0, ""),
-				Y: typevars.MakeLocalVar("frA", vars["frA"]),
+				X: typevars.MakeVirtualVar(1),
+				Y: typevars.MakeLocalVar("frA", positions.Parse(vars["frA"])),
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{
@@ -70,14 +72,14 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("mA", vars["mA"]),
+				Y: typevars.MakeLocalVar("mA", positions.Parse(vars["mA"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("mA", vars["mA"]),
+				X:     typevars.MakeLocalVar("mA", positions.Parse(vars["mA"])),
 				Field: "method",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("mA", vars["mA"]), "method", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("mA", positions.Parse(vars["mA"])), "method", 0, nil),
 				Y: typevars.MakeVirtualVar(3),
 			},
 			&contracts.IsInvocable{
@@ -86,7 +88,7 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeReturn(typevars.MakeVirtualVar(3), 0),
-				Y: typevars.MakeLocalVar("mB", vars["mB"]),
+				Y: typevars.MakeLocalVar("mB", positions.Parse(vars["mB"])),
 			},
 			// var ia D2 = &D3{}
 			// ib := ia.imethod()
@@ -113,21 +115,21 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.IsCompatibleWith{
 				X: typevars.MakeVirtualVar(5),
-				Y: typevars.MakeLocalVar("ia", vars["ia"]),
+				Y: typevars.MakeLocalVar("ia", positions.Parse(vars["ia"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{
 					Def:     "D2",
 					Package: packageName,
 				}),
-				Y: typevars.MakeLocalVar("ia", vars["ia"]),
+				Y: typevars.MakeLocalVar("ia", positions.Parse(vars["ia"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("ia", vars["ia"]),
+				X:     typevars.MakeLocalVar("ia", positions.Parse(vars["ia"])),
 				Field: "imethod",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("ia", vars["ia"]), "imethod", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("ia", positions.Parse(vars["ia"])), "imethod", 0, nil),
 				Y: typevars.MakeVirtualVar(6),
 			},
 			&contracts.IsInvocable{
@@ -136,7 +138,7 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeReturn(typevars.MakeVirtualVar(6), 0),
-				Y: typevars.MakeLocalVar("ib", vars["ib"]),
+				Y: typevars.MakeLocalVar("ib", positions.Parse(vars["ib"])),
 			},
 			// type D4 D3
 			// func (d *D4) imethod() int { return 0 }
@@ -158,14 +160,14 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(7),
-				Y: typevars.MakeLocalVar("ida", vars["ida"]),
+				Y: typevars.MakeLocalVar("ida", positions.Parse(vars["ida"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("ida", vars["ida"]),
+				X:     typevars.MakeLocalVar("ida", positions.Parse(vars["ida"])),
 				Field: "imethod",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("ida", vars["ida"]), "imethod", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("ida", positions.Parse(vars["ida"])), "imethod", 0, nil),
 				Y: typevars.MakeVirtualVar(8),
 			},
 			&contracts.IsInvocable{
@@ -174,27 +176,27 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeReturn(typevars.MakeVirtualVar(8), 0),
-				Y: typevars.MakeLocalVar("idb", vars["idb"]),
+				Y: typevars.MakeLocalVar("idb", positions.Parse(vars["idb"])),
 			},
 			// idc := &ida
 			// idd := idc.imethod()
 			&contracts.IsReferenceable{
-				X: typevars.MakeLocalVar("ida", vars["ida"]),
+				X: typevars.MakeLocalVar("ida", positions.Parse(vars["ida"])),
 			},
 			&contracts.ReferenceOf{
-				X: typevars.MakeLocalVar("ida", vars["ida"]),
+				X: typevars.MakeLocalVar("ida", positions.Parse(vars["ida"])),
 				Y: typevars.MakeVirtualVar(9),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(9),
-				Y: typevars.MakeLocalVar("idc", vars["idc"]),
+				Y: typevars.MakeLocalVar("idc", positions.Parse(vars["idc"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("idc", vars["idc"]),
+				X:     typevars.MakeLocalVar("idc", positions.Parse(vars["idc"])),
 				Field: "imethod",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("idc", vars["idc"]), "imethod", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("idc", positions.Parse(vars["idc"])), "imethod", 0, nil),
 				Y: typevars.MakeVirtualVar(10),
 			},
 			&contracts.IsInvocable{
@@ -203,7 +205,7 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeReturn(typevars.MakeVirtualVar(10), 0),
-				Y: typevars.MakeLocalVar("idd", vars["idd"]),
+				Y: typevars.MakeLocalVar("idd", positions.Parse(vars["idd"])),
 			},
 			// ide := &struct{ d int }{2}
 			// idf := ide.d
@@ -212,9 +214,9 @@ func TestSelectorsTypes(t *testing.T) {
 				Index: 0,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(11), "", 0, ""),
+				X: typevars.MakeField(typevars.MakeVirtualVar(11), "", 0, nil),
 				Y: typevars.MakeConstant(packageName,
-					&gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"},
+					&gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"},
 				),
 			},
 			&contracts.IsCompatibleWith{
@@ -248,22 +250,22 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(12),
-				Y: typevars.MakeLocalVar("ide", vars["ide"]),
+				Y: typevars.MakeLocalVar("ide", positions.Parse(vars["ide"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("ide", vars["ide"]),
+				X:     typevars.MakeLocalVar("ide", positions.Parse(vars["ide"])),
 				Field: "d",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("ide", vars["ide"]), "d", 0, ""),
-				Y: typevars.MakeLocalVar("idf", vars["idf"]),
+				X: typevars.MakeField(typevars.MakeLocalVar("ide", positions.Parse(vars["ide"])), "d", 0, nil),
+				Y: typevars.MakeLocalVar("idf", positions.Parse(vars["idf"])),
 			},
 			// type D6 string
 			// func (d *D6) imethod() int { return 0 }
 			// idg := D6("string")
 			// idh := idg.imethod()
 			&contracts.TypecastsTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"string\""}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"string\"", Value: "\"string\""}),
 				Y: typevars.MakeVirtualVar(13),
 				Type: typevars.MakeConstant(packageName, &gotypes.Identifier{
 					Def:     "D6",
@@ -272,14 +274,14 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(13),
-				Y: typevars.MakeLocalVar("idg", vars["idg"]),
+				Y: typevars.MakeLocalVar("idg", positions.Parse(vars["idg"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("idg", vars["idg"]),
+				X:     typevars.MakeLocalVar("idg", positions.Parse(vars["idg"])),
 				Field: "imethod",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("idg", vars["idg"]), "imethod", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("idg", positions.Parse(vars["idg"])), "imethod", 0, nil),
 				Y: typevars.MakeVirtualVar(14),
 			},
 			&contracts.IsInvocable{
@@ -288,7 +290,7 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeReturn(typevars.MakeVirtualVar(14), 0),
-				Y: typevars.MakeLocalVar("idh", vars["idh"]),
+				Y: typevars.MakeLocalVar("idh", positions.Parse(vars["idh"])),
 			},
 			// idi := struct{ d int }{2}
 			// idj := idi.d
@@ -297,9 +299,9 @@ func TestSelectorsTypes(t *testing.T) {
 				Index: 0,
 			},
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeField(typevars.MakeVirtualVar(15), "", 0, ""),
+				X: typevars.MakeField(typevars.MakeVirtualVar(15), "", 0, nil),
 				Y: typevars.MakeConstant(packageName,
-					&gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"},
+					&gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2", Value: "2"},
 				),
 			},
 			&contracts.IsCompatibleWith{
@@ -326,15 +328,15 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(15),
-				Y: typevars.MakeLocalVar("idi", vars["idi"]),
+				Y: typevars.MakeLocalVar("idi", positions.Parse(vars["idi"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("idi", vars["idi"]),
+				X:     typevars.MakeLocalVar("idi", positions.Parse(vars["idi"])),
 				Field: "d",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("idi", vars["idi"]), "d", 0, ""),
-				Y: typevars.MakeLocalVar("idj", vars["idj"]),
+				X: typevars.MakeField(typevars.MakeLocalVar("idi", positions.Parse(vars["idi"])), "d", 0, nil),
+				Y: typevars.MakeLocalVar("idj", positions.Parse(vars["idj"])),
 			},
 			// idk := (interface {
 			// 	imethod() int
@@ -380,14 +382,14 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(18),
-				Y: typevars.MakeLocalVar("idk", vars["idk"]),
+				Y: typevars.MakeLocalVar("idk", positions.Parse(vars["idk"])),
 			},
 			&contracts.HasField{
-				X:     typevars.MakeLocalVar("idk", vars["idk"]),
+				X:     typevars.MakeLocalVar("idk", positions.Parse(vars["idk"])),
 				Field: "imethod",
 			},
 			&contracts.PropagatesTo{
-				X: typevars.MakeField(typevars.MakeLocalVar("idk", vars["idk"]), "imethod", 0, ""),
+				X: typevars.MakeField(typevars.MakeLocalVar("idk", positions.Parse(vars["idk"])), "imethod", 0, nil),
 				Y: typevars.MakeVirtualVar(19),
 			},
 			&contracts.IsInvocable{
@@ -396,23 +398,23 @@ func TestSelectorsTypes(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeReturn(typevars.MakeVirtualVar(19), 0),
-				Y: typevars.MakeLocalVar("idl", vars["idl"]),
+				Y: typevars.MakeLocalVar("idl", positions.Parse(vars["idl"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{Def: &gotypes.Identifier{Package: packageName, Def: "D3"}}),
-				Y: typevars.MakeLocalVar("d", vars["d3"]),
+				Y: typevars.MakeLocalVar("d", positions.Parse(vars["d3"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{Def: &gotypes.Identifier{Package: packageName, Def: "D4"}}),
-				Y: typevars.MakeLocalVar("d", vars["d4"]),
+				Y: typevars.MakeLocalVar("d", positions.Parse(vars["d4"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{Def: &gotypes.Identifier{Package: packageName, Def: "D6"}}),
-				Y: typevars.MakeLocalVar("d", vars["d6"]),
+				Y: typevars.MakeLocalVar("d", positions.Parse(vars["d6"])),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Pointer{Def: &gotypes.Identifier{Package: packageName, Def: "D"}}),
-				Y: typevars.MakeLocalVar("d", vars["d"]),
+				Y: typevars.MakeLocalVar("d", positions.Parse(vars["d"])),
 			},
 		})
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -23,7 +24,7 @@ func TestTypeCastingContracts(t *testing.T) {
 		[]contracts.Contract{
 			// asA := Int(1)
 			&contracts.TypecastsTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y: typevars.MakeVirtualVar(1),
 				Type: typevars.MakeConstant(packageName, &gotypes.Identifier{
 					Def:     "Int",
@@ -32,16 +33,16 @@ func TestTypeCastingContracts(t *testing.T) {
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("asA", vars["asA"]),
+				Y: typevars.MakeLocalVar("asA", positions.Parse(vars["asA"])),
 			},
 			// asB := asA.(int)
 			&contracts.IsCompatibleWith{
-				X: typevars.MakeLocalVar("asA", vars["asA"]),
+				X: typevars.MakeLocalVar("asA", positions.Parse(vars["asA"])),
 				Y: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeConstant(packageName, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-				Y: typevars.MakeLocalVar("asB", vars["asB"]),
+				Y: typevars.MakeLocalVar("asB", positions.Parse(vars["asB"])),
 			},
 		})
 }
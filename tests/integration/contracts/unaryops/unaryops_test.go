@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts"
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	utils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -30,28 +31,28 @@ func TestUnaryOpContracts(t *testing.T) {
 		[]contracts.Contract{
 			// a := "ahoj"
 			&contracts.PropagatesTo{
-				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\""}),
-				Y: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "string", Literal: "\"ahoj\"", Value: "\"ahoj\""}),
+				Y: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			//
 			// ra := &a
 			//
 			&contracts.IsReferenceable{
-				X: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 			},
 			&contracts.ReferenceOf{
-				X: typevars.MakeLocalVar("a", vars["a"]),
+				X: typevars.MakeLocalVar("a", positions.Parse(vars["a"])),
 				Y: typevars.MakeVirtualVar(1),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(1),
-				Y: typevars.MakeLocalVar("ra", vars["ra"]),
+				Y: typevars.MakeLocalVar("ra", positions.Parse(vars["ra"])),
 			},
 			//
 			// chanA := make(chan int)
 			//
 			&contracts.IsInvocable{
-				F:         typevars.MakeVar("builtin", "make", ":72"),
+				F:         typevars.MakeVar("builtin", "make", positions.Parse(":72")),
 				ArgsCount: 1,
 			},
 			&contracts.PropagatesTo{
@@ -59,66 +60,66 @@ func TestUnaryOpContracts(t *testing.T) {
 					Dir:   "3",
 					Value: &gotypes.Identifier{Package: "builtin", Def: "int"},
 				}),
-				Y: typevars.MakeLocalVar("chanA", vars["chanA"]),
+				Y: typevars.MakeLocalVar("chanA", positions.Parse(vars["chanA"])),
 			},
 			//
 			// chanValA := <-chanA
 			//
 			&contracts.IsReceiveableFrom{
-				X: typevars.MakeLocalVar("chanA", vars["chanA"]),
+				X: typevars.MakeLocalVar("chanA", positions.Parse(vars["chanA"])),
 				Y: typevars.MakeVirtualVar(2),
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(2),
-				Y: typevars.MakeLocalVar("chanValA", vars["chanValA"]),
+				Y: typevars.MakeLocalVar("chanValA", positions.Parse(vars["chanValA"])),
 			},
 			//
 			// uopa := ^1
 			//
 			&contracts.UnaryOp{
-				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y:       typevars.MakeVirtualVar(3),
 				OpToken: token.XOR,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(3),
-				Y: typevars.MakeLocalVar("uopa", vars["uopa"]),
+				Y: typevars.MakeLocalVar("uopa", positions.Parse(vars["uopa"])),
 			},
 			//
 			// uopb := -1
 			//
 			&contracts.UnaryOp{
-				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y:       typevars.MakeVirtualVar(4),
 				OpToken: token.SUB,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(4),
-				Y: typevars.MakeLocalVar("uopb", vars["uopb"]),
+				Y: typevars.MakeLocalVar("uopb", positions.Parse(vars["uopb"])),
 			},
 			//
 			// uopc := !true
 			//
 			&contracts.UnaryOp{
-				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "bool", Literal: "true"}),
+				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "bool", Literal: "true", Value: "true"}),
 				Y:       typevars.MakeVirtualVar(5),
 				OpToken: token.NOT,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(5),
-				Y: typevars.MakeLocalVar("uopc", vars["uopc"]),
+				Y: typevars.MakeLocalVar("uopc", positions.Parse(vars["uopc"])),
 			},
 			//
 			// uopd := +1
 			//
 			&contracts.UnaryOp{
-				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+				X:       typevars.MakeConstant(packageName, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 				Y:       typevars.MakeVirtualVar(6),
 				OpToken: token.ADD,
 			},
 			&contracts.PropagatesTo{
 				X: typevars.MakeVirtualVar(6),
-				Y: typevars.MakeLocalVar("uopd", vars["uopd"]),
+				Y: typevars.MakeLocalVar("uopd", positions.Parse(vars["uopd"])),
 			},
 		})
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -31,27 +32,27 @@ func TestSelfTypePropagation(t *testing.T) {
 		"../../testdata/basic.go",
 		[]cutils.VarTableTest{
 			{
-				Name:     typevars.MakeLocalVar("a", vars["a"]).String(),
+				Name:     typevars.MakeLocalVar("a", positions.Parse(vars["a"])).String(),
 				DataType: ptrA,
 			},
 			{
-				Name:     typevars.MakeLocalVar("x", vars["x"]).String(),
+				Name:     typevars.MakeLocalVar("x", positions.Parse(vars["x"])).String(),
 				DataType: bInt,
 			},
 			{
-				Name:     typevars.MakeLocalVar("y", vars["y"]).String(),
+				Name:     typevars.MakeLocalVar("y", positions.Parse(vars["y"])).String(),
 				DataType: bInt,
 			},
 			{
-				Name:     typevars.MakeVar(gopkg, "C", vars["C"]).String(),
+				Name:     typevars.MakeVar(gopkg, "C", positions.Parse(vars["C"])).String(),
 				DataType: ptrA,
 			},
 			{
-				Name:     typevars.MakeLocalVar("a", vars["a2"]).String(),
+				Name:     typevars.MakeLocalVar("a", positions.Parse(vars["a2"])).String(),
 				DataType: ptrA,
 			},
 			{
-				Name:     typevars.MakeLocalVar("b", vars["b2"]).String(),
+				Name:     typevars.MakeLocalVar("b", positions.Parse(vars["b2"])).String(),
 				DataType: bInt,
 			},
 			// virtual variables
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -33,7 +34,7 @@ func TestSelfTypePropagation(t *testing.T) {
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
 		return cutils.VarTableTest{
-			Name:     typevars.MakeLocalVar(name, vars[name]).String(),
+			Name:     typevars.MakeLocalVar(name, positions.Parse(vars[name])).String(),
 			DataType: dataType,
 		}
 	}
@@ -100,7 +101,7 @@ func TestShiftRightTypePropagation(t *testing.T) {
 		"../../testdata/shiftright.go",
 		[]cutils.VarTableTest{
 			cutils.VarTableTest{
-				Name:     typevars.MakeLocalVar("j", ":49").String(),
+				Name:     typevars.MakeLocalVar("j", positions.Parse(":49")).String(),
 				DataType: &gotypes.Identifier{Def: "int", Package: "builtin"},
 			},
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -22,7 +23,7 @@ func TestSelfTypePropagation(t *testing.T) {
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
 		return cutils.VarTableTest{
-			Name:     typevars.MakeLocalVar(name, vars[name]).String(),
+			Name:     typevars.MakeLocalVar(name, positions.Parse(vars[name])).String(),
 			DataType: dataType,
 		}
 	}
@@ -48,8 +49,9 @@ func TestSelfTypePropagation(t *testing.T) {
 				Def:  &gotypes.Identifier{Package: "builtin", Def: "string"},
 			},
 			{
-				Name: "key2",
-				Def:  &gotypes.Identifier{Package: "builtin", Def: "int"},
+				Name:  "key2",
+				Order: 1,
+				Def:   &gotypes.Identifier{Package: "builtin", Def: "int"},
 			},
 		},
 	}
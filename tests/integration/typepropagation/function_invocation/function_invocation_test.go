@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,19 +14,19 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/function_invocation"
 
 	var vars = map[string]string{
-		"g":    typevars.MakeVar(gopkg, "g", ":330").String(),
-		"g_a":  typevars.MakeLocalVar("a", ":27").String(),
-		"g_b":  typevars.MakeLocalVar("b", ":30").String(),
-		"g_c":  typevars.MakeLocalVar("c", ":33").String(),
-		"g1":   typevars.MakeVar(gopkg, "g1", ":297").String(),
-		"g1_a": typevars.MakeLocalVar("a", ":77").String(),
-		"g2":   typevars.MakeVar(gopkg, "g2", ":314").String(),
-		"g2_a": typevars.MakeLocalVar("a", ":121").String(),
-		"g2_b": typevars.MakeLocalVar("b", ":131").String(),
-		"a":    typevars.MakeLocalVar("a", ":179").String(),
-		"aa":   typevars.MakeLocalVar("aa", ":287").String(),
-		"ab":   typevars.MakeLocalVar("ab", ":304").String(),
-		"b":    typevars.MakeLocalVar("b", ":321").String(),
+		"g":    typevars.MakeVar(gopkg, "g", positions.Parse(":330")).String(),
+		"g_a":  typevars.MakeLocalVar("a", positions.Parse(":27")).String(),
+		"g_b":  typevars.MakeLocalVar("b", positions.Parse(":30")).String(),
+		"g_c":  typevars.MakeLocalVar("c", positions.Parse(":33")).String(),
+		"g1":   typevars.MakeVar(gopkg, "g1", positions.Parse(":297")).String(),
+		"g1_a": typevars.MakeLocalVar("a", positions.Parse(":77")).String(),
+		"g2":   typevars.MakeVar(gopkg, "g2", positions.Parse(":314")).String(),
+		"g2_a": typevars.MakeLocalVar("a", positions.Parse(":121")).String(),
+		"g2_b": typevars.MakeLocalVar("b", positions.Parse(":131")).String(),
+		"a":    typevars.MakeLocalVar("a", positions.Parse(":179")).String(),
+		"aa":   typevars.MakeLocalVar("aa", positions.Parse(":287")).String(),
+		"ab":   typevars.MakeLocalVar("ab", positions.Parse(":304")).String(),
+		"b":    typevars.MakeLocalVar("b", positions.Parse(":321")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,10 +14,10 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/function_literals"
 
 	var vars = map[string]string{
-		"ffA":  typevars.MakeLocalVar("ffA", ":33").String(),
-		"ffA2": typevars.MakeLocalVar("ffA", ":77").String(),
-		"a":    typevars.MakeLocalVar("a", ":45").String(),
-		"ffB":  typevars.MakeLocalVar("ffB", ":70").String(),
+		"ffA":  typevars.MakeLocalVar("ffA", positions.Parse(":33")).String(),
+		"ffA2": typevars.MakeLocalVar("ffA", positions.Parse(":77")).String(),
+		"a":    typevars.MakeLocalVar("a", positions.Parse(":45")).String(),
+		"ffB":  typevars.MakeLocalVar("ffB", positions.Parse(":70")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
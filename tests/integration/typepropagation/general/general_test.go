@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,42 +14,42 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/general"
 
 	var vars = map[string]string{
-		"c1":      typevars.MakeLocalVar("c1", ":722").String(),
-		"cv":      typevars.MakeLocalVar("cv", ":307").String(),
-		"fI":      typevars.MakeLocalVar("fI", ":932").String(),
-		"fV":      typevars.MakeLocalVar("fV", ":944").String(),
-		"i":       typevars.MakeLocalVar("i", ":873").String(),
-		"id":      typevars.MakeLocalVar("id", ":446").String(),
-		"ifI":     typevars.MakeLocalVar("ifI", ":1051").String(),
-		"ii":      typevars.MakeLocalVar("ii", ":903").String(),
-		"intD":    typevars.MakeLocalVar("intD", ":469").String(),
-		"intOk":   typevars.MakeLocalVar("intOk", ":475").String(),
-		"l":       typevars.MakeLocalVar("l", ":372").String(),
-		"m":       typevars.MakeLocalVar("m", ":405").String(),
-		"mok":     typevars.MakeLocalVar("mok", ":432").String(),
-		"msg1_c1": typevars.MakeLocalVar("msg1", ":753").String(),
-		"msg1_c3": typevars.MakeLocalVar("msg1", ":792").String(),
-		"msg1Ok":  typevars.MakeLocalVar("msg1Ok", ":798").String(),
-		"mv":      typevars.MakeLocalVar("mv", ":428").String(),
-		"ok":      typevars.MakeLocalVar("ok", ":392").String(),
-		"s":       typevars.MakeLocalVar("s", ":511").String(),
-		"sd_c1":   typevars.MakeLocalVar("sd", ":641").String(),
-		"sd_c2":   typevars.MakeLocalVar("sd", ":653").String(),
-		"swA":     typevars.MakeLocalVar("swA", ":585").String(),
-		"v":       typevars.MakeLocalVar("v", ":389").String(),
-		"vv":      typevars.MakeLocalVar("vv", ":907").String(),
-		"a":       typevars.MakeVar(gopkg, "a", ":218").String(),
-		"b":       typevars.MakeVar(gopkg, "b", ":221").String(),
-		"c":       typevars.MakeVar(gopkg, "c", ":48").String(),
-		"d":       typevars.MakeVar(gopkg, "d", ":82").String(),
-		"e":       typevars.MakeVar(gopkg, "e", ":121").String(),
-		"f":       typevars.MakeVar(gopkg, "f", ":136").String(),
-		"ff":      typevars.MakeVar(gopkg, "ff", ":151").String(),
-		"j":       typevars.MakeVar(gopkg, "j", ":201").String(),
-		"k":       typevars.MakeVar(gopkg, "k", ":204").String(),
-		"g_l":     typevars.MakeVar(gopkg, "l", ":207").String(),
-		"ta":      typevars.MakeVar(gopkg, "ta", ":235").String(),
-		"tb":      typevars.MakeVar(gopkg, "tb", ":239").String(),
+		"c1":      typevars.MakeLocalVar("c1", positions.Parse(":722")).String(),
+		"cv":      typevars.MakeLocalVar("cv", positions.Parse(":307")).String(),
+		"fI":      typevars.MakeLocalVar("fI", positions.Parse(":932")).String(),
+		"fV":      typevars.MakeLocalVar("fV", positions.Parse(":944")).String(),
+		"i":       typevars.MakeLocalVar("i", positions.Parse(":873")).String(),
+		"id":      typevars.MakeLocalVar("id", positions.Parse(":446")).String(),
+		"ifI":     typevars.MakeLocalVar("ifI", positions.Parse(":1051")).String(),
+		"ii":      typevars.MakeLocalVar("ii", positions.Parse(":903")).String(),
+		"intD":    typevars.MakeLocalVar("intD", positions.Parse(":469")).String(),
+		"intOk":   typevars.MakeLocalVar("intOk", positions.Parse(":475")).String(),
+		"l":       typevars.MakeLocalVar("l", positions.Parse(":372")).String(),
+		"m":       typevars.MakeLocalVar("m", positions.Parse(":405")).String(),
+		"mok":     typevars.MakeLocalVar("mok", positions.Parse(":432")).String(),
+		"msg1_c1": typevars.MakeLocalVar("msg1", positions.Parse(":753")).String(),
+		"msg1_c3": typevars.MakeLocalVar("msg1", positions.Parse(":792")).String(),
+		"msg1Ok":  typevars.MakeLocalVar("msg1Ok", positions.Parse(":798")).String(),
+		"mv":      typevars.MakeLocalVar("mv", positions.Parse(":428")).String(),
+		"ok":      typevars.MakeLocalVar("ok", positions.Parse(":392")).String(),
+		"s":       typevars.MakeLocalVar("s", positions.Parse(":511")).String(),
+		"sd_c1":   typevars.MakeLocalVar("sd", positions.Parse(":641")).String(),
+		"sd_c2":   typevars.MakeLocalVar("sd", positions.Parse(":653")).String(),
+		"swA":     typevars.MakeLocalVar("swA", positions.Parse(":585")).String(),
+		"v":       typevars.MakeLocalVar("v", positions.Parse(":389")).String(),
+		"vv":      typevars.MakeLocalVar("vv", positions.Parse(":907")).String(),
+		"a":       typevars.MakeVar(gopkg, "a", positions.Parse(":218")).String(),
+		"b":       typevars.MakeVar(gopkg, "b", positions.Parse(":221")).String(),
+		"c":       typevars.MakeVar(gopkg, "c", positions.Parse(":48")).String(),
+		"d":       typevars.MakeVar(gopkg, "d", positions.Parse(":82")).String(),
+		"e":       typevars.MakeVar(gopkg, "e", positions.Parse(":121")).String(),
+		"f":       typevars.MakeVar(gopkg, "f", positions.Parse(":136")).String(),
+		"ff":      typevars.MakeVar(gopkg, "ff", positions.Parse(":151")).String(),
+		"j":       typevars.MakeVar(gopkg, "j", positions.Parse(":201")).String(),
+		"k":       typevars.MakeVar(gopkg, "k", positions.Parse(":204")).String(),
+		"g_l":     typevars.MakeVar(gopkg, "l", positions.Parse(":207")).String(),
+		"ta":      typevars.MakeVar(gopkg, "ta", positions.Parse(":235")).String(),
+		"tb":      typevars.MakeVar(gopkg, "tb", positions.Parse(":239")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
@@ -119,27 +120,25 @@ func TestSelfTypePropagation(t *testing.T) {
 			makeVirtual(3, &gotypes.Identifier{Package: "builtin", Def: "int"}),
 			makeVirtual(4, &gotypes.Slice{Elmtype: &gotypes.Identifier{Package: "builtin", Def: "string"}}),
 			makeVirtual(5, &gotypes.Map{Keytype: &gotypes.Identifier{Package: "builtin", Def: "int"}, Valuetype: &gotypes.Identifier{Package: "builtin", Def: "string"}}),
-			makeVirtual(6, &gotypes.Constant{Package: "builtin", Def: "int", Untyped: true, Literal: "1"}),
-			makeVirtual(7, &gotypes.Pointer{Def: &gotypes.Identifier{Package: "builtin", Def: "int"}}),
-			makeVirtual(8, &gotypes.Interface{}),
-			makeVirtual(9, &gotypes.Constant{Package: "builtin", Def: "int", Untyped: true, Literal: "0"}),
-			makeVirtual(10, &gotypes.Struct{
+			makeVirtual(6, &gotypes.Pointer{Def: &gotypes.Identifier{Package: "builtin", Def: "int"}}),
+			makeVirtual(7, &gotypes.Interface{}),
+			makeVirtual(8, &gotypes.Struct{
 				Fields: []gotypes.StructFieldsItem{
 					{
 						Name: "a",
 						Def:  &gotypes.Identifier{Package: "builtin", Def: "int"},
 					},
 				}}),
-			makeVirtual(11, &gotypes.Pointer{Def: &gotypes.Identifier{Package: "builtin", Def: "int"}}),
-			makeVirtual(12, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-			makeVirtual(13, &gotypes.Function{Package: gopkg}),
+			makeVirtual(9, &gotypes.Pointer{Def: &gotypes.Identifier{Package: "builtin", Def: "int"}}),
+			makeVirtual(10, &gotypes.Identifier{Package: "builtin", Def: "int"}),
+			makeVirtual(11, &gotypes.Function{Package: gopkg}),
+			makeVirtual(12, &gotypes.Identifier{Package: "builtin", Def: "string"}),
+			makeVirtual(13, &gotypes.Identifier{Package: "builtin", Def: "string"}),
 			makeVirtual(14, &gotypes.Identifier{Package: "builtin", Def: "string"}),
 			makeVirtual(15, &gotypes.Identifier{Package: "builtin", Def: "string"}),
-			makeVirtual(16, &gotypes.Identifier{Package: "builtin", Def: "string"}),
-			makeVirtual(17, &gotypes.Identifier{Package: "builtin", Def: "string"}),
+			makeVirtual(16, &gotypes.Builtin{Def: "bool", Untyped: true}),
+			makeVirtual(17, &gotypes.Function{Package: gopkg}),
 			makeVirtual(18, &gotypes.Builtin{Def: "bool", Untyped: true}),
-			makeVirtual(19, &gotypes.Function{Package: gopkg}),
-			makeVirtual(20, &gotypes.Builtin{Def: "bool", Untyped: true}),
 		},
 	)
 }
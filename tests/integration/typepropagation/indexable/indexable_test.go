@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,12 +14,12 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/indexable"
 
 	var vars = map[string]string{
-		"la":   typevars.MakeLocalVar("la", ":107").String(),
-		"lb":   typevars.MakeLocalVar("lb", ":124").String(),
-		"list": typevars.MakeLocalVar("list", ":45").String(),
-		"ma":   typevars.MakeLocalVar("ma", ":137").String(),
-		"mapV": typevars.MakeLocalVar("mapV", ":63").String(),
-		"sa":   typevars.MakeLocalVar("sa", ":154").String(),
+		"la":   typevars.MakeLocalVar("la", positions.Parse(":107")).String(),
+		"lb":   typevars.MakeLocalVar("lb", positions.Parse(":124")).String(),
+		"list": typevars.MakeLocalVar("list", positions.Parse(":45")).String(),
+		"ma":   typevars.MakeLocalVar("ma", positions.Parse(":137")).String(),
+		"mapV": typevars.MakeLocalVar("mapV", positions.Parse(":63")).String(),
+		"sa":   typevars.MakeLocalVar("sa", positions.Parse(":154")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
@@ -55,8 +56,7 @@ func TestSelfTypePropagation(t *testing.T) {
 			makeLocal("sa", &gotypes.Identifier{Package: "builtin", Def: "uint8"}),
 			makeVirtual(1, Slice),
 			makeVirtual(2, Map),
-			makeVirtual(3, &gotypes.Constant{Package: "builtin", Def: "string", Literal: "\"3\"", Untyped: true}),
-			makeVirtual(4, &gotypes.Constant{Package: "builtin", Def: "string", Literal: "\"ahoj\"", Untyped: true}),
+			makeVirtual(3, &gotypes.Constant{Package: "builtin", Def: "string", Literal: "\"ahoj\"", Untyped: true, Value: "\"ahoj\""}),
 		},
 	)
 }
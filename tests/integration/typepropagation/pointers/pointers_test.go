@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,9 +14,9 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/pointers"
 
 	var vars = map[string]string{
-		"a":  typevars.MakeLocalVar("a", ":39").String(),
-		"da": typevars.MakeLocalVar("da", ":75").String(),
-		"ra": typevars.MakeLocalVar("ra", ":52").String(),
+		"a":  typevars.MakeLocalVar("a", positions.Parse(":39")).String(),
+		"da": typevars.MakeLocalVar("da", positions.Parse(":75")).String(),
+		"ra": typevars.MakeLocalVar("ra", positions.Parse(":52")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
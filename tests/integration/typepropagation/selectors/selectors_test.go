@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,27 +14,27 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/selectors"
 
 	var vars = map[string]string{
-		"dD":  typevars.MakeLocalVar("d", ":42").String(),
-		"dD3": typevars.MakeLocalVar("d", ":247").String(),
-		"dD4": typevars.MakeLocalVar("d", ":356").String(),
-		"dD6": typevars.MakeLocalVar("d", ":606").String(),
-		"frA": typevars.MakeLocalVar("frA", ":110").String(),
-		"ia":  typevars.MakeLocalVar("ia", ":301").String(),
-		"ib":  typevars.MakeLocalVar("ib", ":316").String(),
-		"ida": typevars.MakeLocalVar("ida", ":406").String(),
-		"idb": typevars.MakeLocalVar("idb", ":419").String(),
-		"idc": typevars.MakeLocalVar("idc", ":442").String(),
-		"idd": typevars.MakeLocalVar("idd", ":455").String(),
-		"ide": typevars.MakeLocalVar("ide", ":540").String(),
-		"idf": typevars.MakeLocalVar("idf", ":568").String(),
-		"idg": typevars.MakeLocalVar("idg", ":656").String(),
-		"idh": typevars.MakeLocalVar("idh", ":677").String(),
-		"idi": typevars.MakeLocalVar("idi", ":700").String(),
-		"idj": typevars.MakeLocalVar("idj", ":727").String(),
-		"idk": typevars.MakeLocalVar("idk", ":742").String(),
-		"idl": typevars.MakeLocalVar("idl", ":790").String(),
-		"mA":  typevars.MakeLocalVar("mA", ":153").String(),
-		"mB":  typevars.MakeLocalVar("mB", ":164").String(),
+		"dD":  typevars.MakeLocalVar("d", positions.Parse(":42")).String(),
+		"dD3": typevars.MakeLocalVar("d", positions.Parse(":247")).String(),
+		"dD4": typevars.MakeLocalVar("d", positions.Parse(":356")).String(),
+		"dD6": typevars.MakeLocalVar("d", positions.Parse(":606")).String(),
+		"frA": typevars.MakeLocalVar("frA", positions.Parse(":110")).String(),
+		"ia":  typevars.MakeLocalVar("ia", positions.Parse(":301")).String(),
+		"ib":  typevars.MakeLocalVar("ib", positions.Parse(":316")).String(),
+		"ida": typevars.MakeLocalVar("ida", positions.Parse(":406")).String(),
+		"idb": typevars.MakeLocalVar("idb", positions.Parse(":419")).String(),
+		"idc": typevars.MakeLocalVar("idc", positions.Parse(":442")).String(),
+		"idd": typevars.MakeLocalVar("idd", positions.Parse(":455")).String(),
+		"ide": typevars.MakeLocalVar("ide", positions.Parse(":540")).String(),
+		"idf": typevars.MakeLocalVar("idf", positions.Parse(":568")).String(),
+		"idg": typevars.MakeLocalVar("idg", positions.Parse(":656")).String(),
+		"idh": typevars.MakeLocalVar("idh", positions.Parse(":677")).String(),
+		"idi": typevars.MakeLocalVar("idi", positions.Parse(":700")).String(),
+		"idj": typevars.MakeLocalVar("idj", positions.Parse(":727")).String(),
+		"idk": typevars.MakeLocalVar("idk", positions.Parse(":742")).String(),
+		"idl": typevars.MakeLocalVar("idl", positions.Parse(":790")).String(),
+		"mA":  typevars.MakeLocalVar("mA", positions.Parse(":153")).String(),
+		"mB":  typevars.MakeLocalVar("mB", positions.Parse(":164")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
@@ -59,6 +60,12 @@ func TestSelfTypePropagation(t *testing.T) {
 				&gotypes.Identifier{Package: "builtin", Def: "int"},
 			},
 		}}
+	// (*D).method with the receiver as the first parameter
+	MethodExpr := &gotypes.Function{
+		Package: gopkg,
+		Params:  []gotypes.DataType{DPointer},
+		Results: Method.Def.(*gotypes.Function).Results,
+	}
 
 	cutils.ParseAndCompareVarTable(
 		t,
@@ -69,7 +76,7 @@ func TestSelfTypePropagation(t *testing.T) {
 			makeLocal("dD3", &gotypes.Pointer{Def: &gotypes.Identifier{Def: "D3", Package: gopkg}}),
 			makeLocal("dD4", &gotypes.Pointer{Def: &gotypes.Identifier{Def: "D4", Package: gopkg}}),
 			makeLocal("dD6", &gotypes.Pointer{Def: &gotypes.Identifier{Def: "D6", Package: gopkg}}),
-			makeLocal("frA", Method),
+			makeLocal("frA", MethodExpr),
 			makeLocal("ia", &gotypes.Identifier{Def: "D2", Package: gopkg}),
 			makeLocal("ib", &gotypes.Identifier{Package: "builtin", Def: "int"}),
 			makeLocal("ida", &gotypes.Identifier{Def: "D4", Package: gopkg}),
@@ -111,7 +118,7 @@ func TestSelfTypePropagation(t *testing.T) {
 			makeLocal("idl", &gotypes.Identifier{Package: "builtin", Def: "int"}),
 			makeLocal("mA", &gotypes.Identifier{Def: "D", Package: gopkg}),
 			makeLocal("mB", &gotypes.Identifier{Package: "builtin", Def: "int"}),
-			makeVirtual(1, &gotypes.Identifier{Def: "D", Package: gopkg}),
+			makeVirtual(1, MethodExpr),
 			makeVirtual(2, &gotypes.Identifier{Def: "D", Package: gopkg}),
 			makeVirtual(3, Method),
 			makeVirtual(4, &gotypes.Identifier{Def: "D3", Package: gopkg}),
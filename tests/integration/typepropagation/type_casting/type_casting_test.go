@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,8 +14,8 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/type_casting"
 
 	var vars = map[string]string{
-		"asA": typevars.MakeLocalVar("asA", ":64").String(),
-		"asB": typevars.MakeLocalVar("asB", ":79").String(),
+		"asA": typevars.MakeLocalVar("asA", positions.Parse(":64")).String(),
+		"asB": typevars.MakeLocalVar("asB", positions.Parse(":79")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
@@ -60,85 +61,85 @@ func TestUntypedToUintPropagation(t *testing.T) {
 		"../../testdata/untyped.go",
 		[]cutils.VarTableTest{
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "pallocChunksL1Bits", ":28").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13"},
+				Name:     typevars.MakeVar(gopkg, "pallocChunksL1Bits", positions.Parse(":28")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13", Value: "13"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "_PageShift", ":54").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13"},
+				Name:     typevars.MakeVar(gopkg, "_PageShift", positions.Parse(":54")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13", Value: "13"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "GoosDarwin", ":80").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0"},
+				Name:     typevars.MakeVar(gopkg, "GoosDarwin", positions.Parse(":80")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0", Value: "0"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "GoarchArm64", ":105").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0"},
+				Name:     typevars.MakeVar(gopkg, "GoarchArm64", positions.Parse(":105")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0", Value: "0"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "GoarchMipsle", ":130").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0"},
+				Name:     typevars.MakeVar(gopkg, "GoarchMipsle", positions.Parse(":130")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0", Value: "0"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "GoarchMips", ":155").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0"},
+				Name:     typevars.MakeVar(gopkg, "GoarchMips", positions.Parse(":155")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0", Value: "0"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "GoarchWasm", ":180").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0"},
+				Name:     typevars.MakeVar(gopkg, "GoarchWasm", positions.Parse(":180")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "0", Value: "0"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "_64bit", ":205").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "2"},
+				Name:     typevars.MakeVar(gopkg, "_64bit", positions.Parse(":205")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "1"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "pageShift", ":257").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13"},
+				Name:     typevars.MakeVar(gopkg, "pageShift", positions.Parse(":257")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13", Value: "13"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "logPallocChunkPages", ":291").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "9"},
+				Name:     typevars.MakeVar(gopkg, "logPallocChunkPages", positions.Parse(":291")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "9", Value: "9"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "logPallocChunkBytes", ":316").String(),
+				Name:     typevars.MakeVar(gopkg, "logPallocChunkBytes", positions.Parse(":316")).String(),
 				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "22"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "heapAddrBits", ":371").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "64"},
+				Name:     typevars.MakeVar(gopkg, "heapAddrBits", positions.Parse(":371")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "48"},
 			},
 			cutils.VarTableTest{
-				Name:     typevars.MakeVar(gopkg, "pallocChunksL2Bits", ":531").String(),
-				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "29"},
+				Name:     typevars.MakeVar(gopkg, "pallocChunksL2Bits", positions.Parse(":531")).String(),
+				DataType: &gotypes.Constant{Def: "int", Package: "builtin", Untyped: true, Literal: "13"},
 			},
 
 			makeVirtual(1, &gotypes.Constant{Package: "builtin", Untyped: false, Def: "uintptr", Literal: "0"}),
 			makeVirtual(2, &gotypes.Constant{Package: "builtin", Untyped: false, Def: "uintptr", Literal: "18446744073709551615"}),
-			makeVirtual(3, &gotypes.Constant{Package: "builtin", Untyped: false, Def: "uintptr", Literal: "2"}),
-			makeVirtual(4, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "4"}),
-			makeVirtual(5, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+			makeVirtual(3, &gotypes.Constant{Package: "builtin", Untyped: false, Def: "uintptr", Literal: "1"}),
+			makeVirtual(4, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+			makeVirtual(5, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
 			makeVirtual(6, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "22"}),
 			makeVirtual(7, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-			makeVirtual(8, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
+			makeVirtual(8, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
 			makeVirtual(9, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
 			makeVirtual(10, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
-			makeVirtual(11, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "2"}),
-			makeVirtual(12, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "96"}),
-			makeVirtual(13, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "-1"}),
-			makeVirtual(14, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "-1"}),
+			makeVirtual(11, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+			makeVirtual(12, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "48"}),
+			makeVirtual(13, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+			makeVirtual(14, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
 			makeVirtual(15, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
 			makeVirtual(16, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "32"}),
-			makeVirtual(17, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "-32"}),
-			makeVirtual(18, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "64"}),
+			makeVirtual(17, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
+			makeVirtual(18, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "48"}),
 			makeVirtual(19, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
 			makeVirtual(20, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "0"}),
-			makeVirtual(21, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "64"}),
-			makeVirtual(22, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "42"}),
-			makeVirtual(23, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "29"}),
+			makeVirtual(21, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "48"}),
+			makeVirtual(22, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "26"}),
+			makeVirtual(23, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "13"}),
 
 			makeVirtual(24, &gotypes.Constant{Package: "builtin", Untyped: false, Def: "uint", Literal: "1"}),
-			makeVirtual(25, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "536870912"}),
-			makeVirtual(26, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "536870911"}),
+			makeVirtual(25, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "8192"}),
+			makeVirtual(26, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "8191"}),
 			makeVirtual(27, &gotypes.Constant{Package: "builtin", Untyped: false, Def: "uint", Literal: "1"}),
 		},
 	)
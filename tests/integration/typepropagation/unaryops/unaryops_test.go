@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gofed/symbols-extractor/pkg/parser/contracts/typevars"
+	"github.com/gofed/symbols-extractor/pkg/positions"
 	gotypes "github.com/gofed/symbols-extractor/pkg/types"
 	cutils "github.com/gofed/symbols-extractor/tests/integration/contracts"
 )
@@ -13,15 +14,15 @@ func TestSelfTypePropagation(t *testing.T) {
 	gopkg := "github.com/gofed/symbols-extractor/tests/integration/typepropagation/unaryops"
 
 	var vars = map[string]string{
-		"a":        typevars.MakeLocalVar("a", ":39").String(),
-		"chanA":    typevars.MakeLocalVar("chanA", ":63").String(),
-		"chanValA": typevars.MakeLocalVar("chanValA", ":88").String(),
-		"ra":       typevars.MakeLocalVar("ra", ":52").String(),
-		"uopa":     typevars.MakeLocalVar("uopa", ":110").String(),
-		"uopb":     typevars.MakeLocalVar("uopb", ":122").String(),
-		"uopc":     typevars.MakeLocalVar("uopc", ":134").String(),
-		"uopd":     typevars.MakeLocalVar("uopd", ":149").String(),
-		"make":     typevars.MakeVar("builtin", "make", ":72").String(),
+		"a":        typevars.MakeLocalVar("a", positions.Parse(":39")).String(),
+		"chanA":    typevars.MakeLocalVar("chanA", positions.Parse(":63")).String(),
+		"chanValA": typevars.MakeLocalVar("chanValA", positions.Parse(":88")).String(),
+		"ra":       typevars.MakeLocalVar("ra", positions.Parse(":52")).String(),
+		"uopa":     typevars.MakeLocalVar("uopa", positions.Parse(":110")).String(),
+		"uopb":     typevars.MakeLocalVar("uopb", positions.Parse(":122")).String(),
+		"uopc":     typevars.MakeLocalVar("uopc", positions.Parse(":134")).String(),
+		"uopd":     typevars.MakeLocalVar("uopd", positions.Parse(":149")).String(),
+		"make":     typevars.MakeVar("builtin", "make", positions.Parse(":72")).String(),
 	}
 
 	makeLocal := func(name string, dataType gotypes.DataType) cutils.VarTableTest {
@@ -65,10 +66,10 @@ func TestSelfTypePropagation(t *testing.T) {
 			}),
 			makeVirtual(1, &gotypes.Pointer{Def: &gotypes.Identifier{Package: "builtin", Def: "string"}}),
 			makeVirtual(2, &gotypes.Identifier{Package: "builtin", Def: "int"}),
-			makeVirtual(3, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+			makeVirtual(3, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "-2"}),
 			makeVirtual(4, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "-1"}),
-			makeVirtual(5, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "bool", Literal: "true"}),
-			makeVirtual(6, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1"}),
+			makeVirtual(5, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "bool", Literal: "true", Value: "true"}),
+			makeVirtual(6, &gotypes.Constant{Package: "builtin", Untyped: true, Def: "int", Literal: "1", Value: "1"}),
 		},
 	)
 }
@@ -172,7 +172,7 @@ func CompareContracts(t *testing.T, expected, tested contracts.Contract) {
 		CompareTypeVars(t, x.X, y.X)
 		CompareTypeVars(t, x.Y, y.Y)
 		if x.Weak != y.Weak {
-			t.Errorf("Expected IsCompatibleWith.Week %q, got %q instead", x.Weak, y.Weak)
+			t.Errorf("Expected IsCompatibleWith.Weak %v, got %v instead", x.Weak, y.Weak)
 		}
 	case *contracts.BinaryOp:
 		y := tested.(*contracts.BinaryOp)
@@ -242,6 +242,16 @@ func CompareContracts(t *testing.T, expected, tested contracts.Contract) {
 		CompareTypeVars(t, x.X, y.X)
 		CompareTypeVars(t, x.Y, y.Y)
 		CompareTypeVars(t, x.Type, y.Type)
+	case *contracts.MethodValue:
+		y := tested.(*contracts.MethodValue)
+		CompareTypeVars(t, x.X, y.X)
+		CompareTypeVars(t, x.Y, y.Y)
+		if x.Method != y.Method {
+			t.Errorf("Expected MethodValue.Method %q, got %q instead", x.Method, y.Method)
+		}
+		if x.Expression != y.Expression {
+			t.Errorf("Expected MethodValue.Expression %v, got %v instead", x.Expression, y.Expression)
+		}
 	default:
 		t.Errorf("Contract %#v not recognized", expected)
 	}